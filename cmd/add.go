@@ -1,12 +1,15 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
+	"pass-cli/internal/storage"
 	"pass-cli/internal/vault"
 )
 
@@ -16,6 +19,13 @@ var (
 	addCategory string
 	addURL      string
 	addNotes    string
+	addFields   []string
+	addSecrets  []string
+	addTOTP     string
+	addTags     []string
+	addExpires  string
+	addRotate   string
+	addType     string
 )
 
 var addCmd = &cobra.Command{
@@ -27,9 +37,20 @@ You will be prompted for the username and password. The password input will be
 hidden for security. If you want to provide these values via flags, use:
   --username (-u) for the username
   --password (-p) for the password (not recommended for security)
+  --type for a credential template: login (default), api-key, ssh-key, database, secure-note
   --category (-c) for organizing credentials (e.g., 'Cloud', 'Databases')
+  --tag (-t) for labels; a credential can have many (repeatable or comma-separated)
   --url for the service URL (e.g., login page URL)
   --notes for additional information
+  --expires for an expiry date (YYYY-MM-DD or a duration like 90d)
+  --rotate-every for a rotation interval (e.g., 90d); due dates count from the last password change
+  --field key=value for custom fields (repeatable)
+  --secret-field key=value for custom fields masked like the password (repeatable)
+  --totp for a 2FA seed (otpauth:// URI or base32 secret, "-" to prompt)
+
+Typed credentials prompt for the fields their template expects (for example
+host, port and database for database credentials); values given with --field
+are not prompted again. Secure notes have no username or password.
 
 The service name should be descriptive and unique (e.g., "github", "aws-prod", "db-staging").`,
 	Example: `  # Add a credential with prompts
@@ -41,11 +62,26 @@ The service name should be descriptive and unique (e.g., "github", "aws-prod", "
   # Add with category and URL
   pass-cli add github -u user@example.com -c "Version Control" --url "https://github.com"
 
+  # Add with tags
+  pass-cli add aws-prod --tag prod,aws --tag billing
+
+  # Add an API key that must be rotated every 90 days
+  pass-cli add stripe-key --rotate-every 90d
+
+  # Add a database credential (prompts for host, port and database)
+  pass-cli add orders-db --type database
+
   # Add with notes
   pass-cli add github --notes "My GitHub account"
 
   # Add with all metadata fields
-  pass-cli add github -u user@example.com -c "Version Control" --url "https://github.com" --notes "Work account"`,
+  pass-cli add github -u user@example.com -c "Version Control" --url "https://github.com" --notes "Work account"
+
+  # Add an API key with custom fields
+  pass-cli add aws-prod --field client_id=AKIA123 --field region=us-east-1 --secret-field session_token=abc
+
+  # Add with a TOTP seed from an otpauth:// URI (prompted to keep it out of history)
+  pass-cli add github -u user@example.com --totp -`,
 	Args: cobra.ExactArgs(1),
 	RunE: runAdd,
 }
@@ -54,9 +90,16 @@ func init() {
 	rootCmd.AddCommand(addCmd)
 	addCmd.Flags().StringVarP(&addUsername, "username", "u", "", "username for the credential")
 	addCmd.Flags().StringVarP(&addPassword, "password", "p", "", "password for the credential (not recommended, use prompt instead)")
+	addCmd.Flags().StringVar(&addType, "type", "", "credential type: login, api-key, ssh-key, database, secure-note")
 	addCmd.Flags().StringVarP(&addCategory, "category", "c", "", "category for organizing credentials (e.g., 'Cloud', 'Databases')")
+	addCmd.Flags().StringSliceVarP(&addTags, "tag", "t", nil, "tag for grouping credentials (repeatable or comma-separated)")
 	addCmd.Flags().StringVar(&addURL, "url", "", "URL associated with the credential (e.g., login page)")
 	addCmd.Flags().StringVar(&addNotes, "notes", "", "optional notes about the credential")
+	addCmd.Flags().StringVar(&addExpires, "expires", "", "expiry date (YYYY-MM-DD) or duration from now (e.g., 90d)")
+	addCmd.Flags().StringVar(&addRotate, "rotate-every", "", "rotation interval (e.g., 90d, 12w)")
+	addCmd.Flags().StringArrayVar(&addFields, "field", nil, "custom field as key=value (repeatable)")
+	addCmd.Flags().StringArrayVar(&addSecrets, "secret-field", nil, "secret custom field as key=value, masked in output (repeatable)")
+	addCmd.Flags().StringVar(&addTOTP, "totp", "", "TOTP seed as otpauth:// URI or base32 secret (\"-\" to prompt)")
 }
 
 func runAdd(cmd *cobra.Command, args []string) error {
@@ -68,6 +111,43 @@ func runAdd(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("service name cannot be empty")
 	}
 
+	// Parse custom fields before prompting so typos fail fast
+	plainFields, err := parseFieldFlags(addFields, false)
+	if err != nil {
+		return err
+	}
+	secretFields, err := parseFieldFlags(addSecrets, true)
+	if err != nil {
+		return err
+	}
+	customFields := append(plainFields, secretFields...)
+
+	credType, err := vault.ParseCredentialType(addType)
+	if err != nil {
+		return err
+	}
+	template, _ := vault.GetTemplate(credType)
+
+	var totp *vault.TOTPConfig
+	if addTOTP != "" {
+		if totp, err = parseTOTPFlag(addTOTP); err != nil {
+			return err
+		}
+	}
+
+	var expiresAt time.Time
+	if addExpires != "" {
+		if expiresAt, err = parseExpiryFlag(addExpires); err != nil {
+			return err
+		}
+	}
+	var rotationDays int
+	if addRotate != "" {
+		if rotationDays, err = parseRotationFlag(addRotate); err != nil {
+			return err
+		}
+	}
+
 	vaultPath := GetVaultPath()
 
 	// Check if vault exists
@@ -87,60 +167,133 @@ func runAdd(cmd *cobra.Command, args []string) error {
 	}
 	defer vaultService.Lock()
 
-	// Get username if not provided
-	if addUsername == "" {
-		fmt.Print("Username: ")
-		if _, err := fmt.Scanln(&addUsername); err != nil {
-			return fmt.Errorf("failed to read username: %w", err)
+	// Get username if not provided (labelled by the credential type)
+	if addUsername == "" && template.UsernameLabel != "" {
+		if template.UsernameRequired {
+			fmt.Printf("%s: ", template.UsernameLabel)
+			if _, err := fmt.Scanln(&addUsername); err != nil {
+				return fmt.Errorf("failed to read %s: %w", strings.ToLower(template.UsernameLabel), err)
+			}
+		} else {
+			fmt.Printf("%s (optional): ", template.UsernameLabel)
+			if addUsername, err = readLine(); err != nil {
+				return fmt.Errorf("failed to read %s: %w", strings.ToLower(template.UsernameLabel), err)
+			}
 		}
 		addUsername = strings.TrimSpace(addUsername)
 	}
 
 	// Get password if not provided
-	if addPassword == "" {
-		fmt.Print("Password: ")
+	if addPassword == "" && template.PasswordLabel != "" {
+		fmt.Printf("%s: ", template.PasswordLabel)
 		password, err := readPassword()
-		if err != nil {
-			return fmt.Errorf("failed to read password: %w", err)
+		if err != nil && template.PasswordRequired {
+			return fmt.Errorf("failed to read %s: %w", strings.ToLower(template.PasswordLabel), err)
 		}
 		fmt.Println() // newline after password input
 		addPassword = string(password) // TODO: Remove string conversion in Phase 3 (T020d)
 	}
 
 	// Validate password is not empty
-	if addPassword == "" {
-		return fmt.Errorf("password cannot be empty")
+	if addPassword == "" && template.PasswordRequired {
+		return fmt.Errorf("%s cannot be empty", strings.ToLower(template.PasswordLabel))
+	}
+
+	// Prompt for template fields not given with --field
+	for _, field := range template.Fields {
+		if _, exists := vault.FindCustomField(customFields, field.Key); exists {
+			continue
+		}
+		if field.Required {
+			fmt.Printf("%s: ", field.Label)
+		} else {
+			fmt.Printf("%s (optional): ", field.Label)
+		}
+		value, err := readLine()
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", strings.ToLower(field.Label), err)
+		}
+		if value == "" {
+			if field.Required {
+				return fmt.Errorf("%s is required for %s credentials", strings.ToLower(field.Label), credType)
+			}
+			continue
+		}
+		customFields = append(customFields, vault.CustomField{Key: field.Key, Value: value, Secret: field.Secret})
+	}
+
+	// Secure notes keep their content in the notes field
+	if credType == vault.TypeSecureNote && addNotes == "" {
+		fmt.Print("Note: ")
+		if addNotes, err = readLine(); err != nil {
+			return fmt.Errorf("failed to read note: %w", err)
+		}
 	}
 
 	// T020d: Convert string password to []byte for vault
 	passwordBytes := []byte(addPassword)
 
 	// Add credential to vault with all metadata fields
-	if err := vaultService.AddCredential(service, addUsername, passwordBytes, addCategory, addURL, addNotes); err != nil {
+	opts := vault.AddOpts{
+		Type:         credType,
+		CustomFields: customFields,
+		TOTP:         totp,
+		Tags:         addTags,
+		ExpiresAt:    expiresAt,
+		RotationDays: rotationDays,
+	}
+	if err := vaultService.AddCredentialWithOpts(service, addUsername, passwordBytes, addCategory, addURL, addNotes, opts); err != nil {
 		return fmt.Errorf("failed to add credential: %w", err)
 	}
 
 	// Success message
 	fmt.Printf("✅ Credential added successfully!\n")
 	fmt.Printf("📝 Service: %s\n", service)
+	if credType != vault.TypeLogin {
+		fmt.Printf("🗂️  Type: %s\n", template.Label)
+	}
 	if addUsername != "" {
 		fmt.Printf("👤 Username: %s\n", addUsername)
 	}
 	if addCategory != "" {
 		fmt.Printf("🏷️  Category: %s\n", addCategory)
 	}
+	if len(addTags) > 0 {
+		fmt.Printf("🔖 Tags: %s\n", strings.Join(addTags, ", "))
+	}
 	if addURL != "" {
 		fmt.Printf("🔗 URL: %s\n", addURL)
 	}
 	if addNotes != "" {
 		fmt.Printf("📋 Notes: %s\n", addNotes)
 	}
+	for _, field := range customFields {
+		if field.Secret {
+			fmt.Printf("🔒 %s: %s\n", field.Key, strings.Repeat("*", len(field.Value)))
+		} else {
+			fmt.Printf("🧩 %s: %s\n", field.Key, field.Value)
+		}
+	}
+	if !expiresAt.IsZero() {
+		fmt.Printf("⌛ Expires: %s\n", expiresAt.Format("2006-01-02"))
+	}
+	if rotationDays > 0 {
+		fmt.Printf("🔄 Rotate every: %d days\n", rotationDays)
+	}
+	if totp != nil {
+		fmt.Printf("⏱️  TOTP: configured (%d digits, %ds period)\n", totp.Digits, totp.Period)
+	}
 
 	return nil
 }
 
-// unlockVault attempts to unlock the vault with keychain or prompts for password
+// unlockVault attempts to unlock the vault with the key file (--key-file or
+// the config default) or keychain, or prompts for password
 func unlockVault(vaultService *vault.VaultService) error {
+	if unlocked, err := unlockWithKeyFile(vaultService); err != nil || unlocked {
+		return err
+	}
+
 	// Try keychain first
 	if err := vaultService.UnlockWithKeychain(); err == nil {
 		if IsVerbose() {
@@ -163,3 +316,27 @@ func unlockVault(vaultService *vault.VaultService) error {
 
 	return nil
 }
+
+// unlockWithKeyFile hands the key file (--key-file or the config default) to
+// the vault service and tries it alone. It reports false when the vault still
+// needs the master password, e.g. because the key file is a second factor.
+func unlockWithKeyFile(vaultService *vault.VaultService) (bool, error) {
+	path := GetKeyFile()
+	if path == "" {
+		return false, nil
+	}
+	vaultService.SetKeyFile(path)
+
+	err := vaultService.UnlockWithKeyFile(path)
+	if err == nil {
+		if IsVerbose() {
+			fmt.Fprintln(os.Stderr, "🔓 Unlocked vault using key file")
+		}
+		return true, nil
+	}
+	// An explicit --key-file that cannot unlock anything is an error
+	if keyFile != "" && !errors.Is(err, storage.ErrNoMatchingSlot) {
+		return false, fmt.Errorf("failed to unlock vault: %w", err)
+	}
+	return false, nil
+}
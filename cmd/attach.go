@@ -0,0 +1,286 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"pass-cli/internal/crypto"
+	"pass-cli/internal/storage"
+	"pass-cli/internal/vault"
+)
+
+var (
+	attachName  string
+	attachForce bool
+
+	attachmentOut   string
+	attachmentForce bool
+)
+
+var attachCmd = &cobra.Command{
+	Use:   "attach <service> <file>",
+	Short: "Store a file encrypted with a credential",
+	Long: `Attach stores the contents of a file (SSH key, certificate, kubeconfig, ...)
+encrypted inside the vault, together with its file name and permission bits.
+
+Attachments are limited to 10 MiB each. Use 'pass-cli attachment get' to
+write a file back out.`,
+	Example: `  # Attach an SSH private key
+  pass-cli attach github ~/.ssh/id_ed25519
+
+  # Attach under a different name
+  pass-cli attach prod-cluster ./kubeconfig.yaml --name kubeconfig
+
+  # Replace an existing attachment
+  pass-cli attach prod-cluster ./kubeconfig.yaml --name kubeconfig --force`,
+	Args: cobra.ExactArgs(2),
+	RunE: runAttach,
+}
+
+var attachmentCmd = &cobra.Command{
+	Use:   "attachment",
+	Short: "Manage files attached to credentials",
+	Long:  `Attachment retrieves, lists, and removes files stored with 'pass-cli attach'.`,
+}
+
+var attachmentGetCmd = &cobra.Command{
+	Use:   "get <service> <name>",
+	Short: "Write an attachment to a file",
+	Long: `Get decrypts an attachment and writes it to --out (default: the attachment
+name in the current directory). Files are always written with 0600 permissions.
+Use --out - to write to stdout.`,
+	Example: `  # Restore an SSH key
+  pass-cli attachment get github id_ed25519 --out ~/.ssh/id_ed25519
+
+  # Pipe a kubeconfig to kubectl
+  pass-cli attachment get prod-cluster kubeconfig --out - | kubectl --kubeconfig /dev/stdin get pods`,
+	Args: cobra.ExactArgs(2),
+	RunE: runAttachmentGet,
+}
+
+var attachmentListCmd = &cobra.Command{
+	Use:   "list <service>",
+	Short: "List attachments of a credential",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runAttachmentList,
+}
+
+var attachmentRemoveCmd = &cobra.Command{
+	Use:     "remove <service> <name>",
+	Aliases: []string{"rm"},
+	Short:   "Remove an attachment from a credential",
+	Args:    cobra.ExactArgs(2),
+	RunE:    runAttachmentRemove,
+}
+
+func init() {
+	rootCmd.AddCommand(attachCmd)
+	attachCmd.Flags().StringVar(&attachName, "name", "", "attachment name (default: file name)")
+	attachCmd.Flags().BoolVar(&attachForce, "force", false, "replace an existing attachment with the same name")
+
+	rootCmd.AddCommand(attachmentCmd)
+	attachmentCmd.AddCommand(attachmentGetCmd)
+	attachmentCmd.AddCommand(attachmentListCmd)
+	attachmentCmd.AddCommand(attachmentRemoveCmd)
+	attachmentGetCmd.Flags().StringVarP(&attachmentOut, "out", "o", "", "output path (\"-\" for stdout)")
+	attachmentGetCmd.Flags().BoolVar(&attachmentForce, "force", false, "overwrite an existing output file")
+}
+
+func runAttach(cmd *cobra.Command, args []string) error {
+	service := strings.TrimSpace(args[0])
+	if service == "" {
+		return fmt.Errorf("service name cannot be empty")
+	}
+	path := args[1]
+
+	// Check size before reading so large files fail fast
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if info.IsDir() {
+		return fmt.Errorf("%s is a directory", path)
+	}
+	if err := storage.CheckAttachmentSize(info.Size()); err != nil {
+		return err
+	}
+
+	name := attachName
+	if name == "" {
+		name = filepath.Base(path)
+	}
+
+	data, err := os.ReadFile(path) // #nosec G304 -- User-specified file to attach
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	defer crypto.ClearBytes(data)
+
+	vaultService, err := openUnlockedVault()
+	if err != nil {
+		return err
+	}
+	defer vaultService.Lock()
+
+	if err := vaultService.AddAttachment(service, name, data, info.Mode(), attachForce); err != nil {
+		return fmt.Errorf("failed to attach file: %w", err)
+	}
+
+	fmt.Printf("✅ Attached %s to %s (%s)\n", name, service, formatSize(int64(len(data))))
+	return nil
+}
+
+func runAttachmentGet(cmd *cobra.Command, args []string) error {
+	service, name := strings.TrimSpace(args[0]), args[1]
+
+	out := attachmentOut
+	if out == "" {
+		out = name
+	}
+	if out != "-" && !attachmentForce {
+		if _, err := os.Stat(out); err == nil {
+			return fmt.Errorf("%s already exists (use --force to overwrite)", out)
+		}
+	}
+
+	vaultService, err := openUnlockedVault()
+	if err != nil {
+		return err
+	}
+	defer vaultService.Lock()
+
+	attachment, err := vaultService.GetAttachment(service, name)
+	if err != nil {
+		return fmt.Errorf("failed to get attachment: %w", err)
+	}
+	defer crypto.ClearBytes(attachment.Data)
+
+	if out == "-" {
+		if _, err := os.Stdout.Write(attachment.Data); err != nil {
+			return fmt.Errorf("failed to write attachment: %w", err)
+		}
+	} else {
+		if err := writePrivateFile(out, attachment.Data); err != nil {
+			return err
+		}
+		fmt.Printf("✅ Wrote %s to %s (%s, mode 0600)\n", name, out, formatSize(attachment.Size()))
+	}
+
+	// Track attachment access
+	if err := vaultService.RecordFieldAccess(service, "attachment:"+name); err != nil {
+		// Log warning but don't fail the operation
+		fmt.Fprintf(os.Stderr, "Warning: failed to track attachment access: %v\n", err)
+	}
+
+	return nil
+}
+
+func runAttachmentList(cmd *cobra.Command, args []string) error {
+	service := strings.TrimSpace(args[0])
+
+	vaultService, err := openUnlockedVault()
+	if err != nil {
+		return err
+	}
+	defer vaultService.Lock()
+
+	cred, err := vaultService.GetCredential(service, false)
+	if err != nil {
+		return fmt.Errorf("failed to get credential: %w", err)
+	}
+
+	if len(cred.Attachments) == 0 {
+		fmt.Printf("No attachments for %s\n", service)
+		return nil
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	var data [][]string
+	for _, attachment := range cred.Attachments {
+		data = append(data, []string{
+			attachment.Name,
+			formatSize(attachment.Size()),
+			fmt.Sprintf("%04o", attachment.Mode),
+			formatRelativeTime(attachment.AddedAt),
+		})
+		crypto.ClearBytes(attachment.Data)
+	}
+	table.Header([]string{"Name", "Size", "Mode", "Added"})
+	_ = table.Bulk(data)
+	_ = table.Render()
+
+	return nil
+}
+
+func runAttachmentRemove(cmd *cobra.Command, args []string) error {
+	service, name := strings.TrimSpace(args[0]), args[1]
+
+	vaultService, err := openUnlockedVault()
+	if err != nil {
+		return err
+	}
+	defer vaultService.Lock()
+
+	if err := vaultService.RemoveAttachment(service, name); err != nil {
+		return fmt.Errorf("failed to remove attachment: %w", err)
+	}
+
+	fmt.Printf("✅ Removed %s from %s\n", name, service)
+	return nil
+}
+
+// openUnlockedVault locates, opens, and unlocks the vault. Callers must Lock it.
+func openUnlockedVault() (*vault.VaultService, error) {
+	vaultPath := GetVaultPath()
+
+	// Check if vault exists
+	if _, err := os.Stat(vaultPath); os.IsNotExist(err) {
+		return nil, fmt.Errorf("vault not found at %s\nRun 'pass-cli init' to create a vault first", vaultPath)
+	}
+
+	// Create vault service
+	vaultService, err := vault.New(vaultPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create vault service: %w", err)
+	}
+
+	// Unlock vault
+	if err := unlockVault(vaultService); err != nil {
+		return nil, err
+	}
+	return vaultService, nil
+}
+
+// writePrivateFile writes data readable only by the owner, tightening existing files too
+func writePrivateFile(path string, data []byte) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600) // #nosec G304 -- User-specified output path
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	if err := f.Chmod(0600); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to set permissions on %s: %w", path, err)
+	}
+	if _, err := f.Write(data); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return f.Close()
+}
+
+// formatSize renders a byte count as B, KB, or MB
+func formatSize(size int64) string {
+	switch {
+	case size < 1024:
+		return fmt.Sprintf("%d B", size)
+	case size < 1024*1024:
+		return fmt.Sprintf("%.1f KB", float64(size)/1024)
+	default:
+		return fmt.Sprintf("%.1f MB", float64(size)/(1024*1024))
+	}
+}
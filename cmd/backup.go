@@ -0,0 +1,356 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"pass-cli/internal/crypto"
+	"pass-cli/internal/storage"
+	"pass-cli/internal/vault"
+)
+
+var (
+	backupRestoreForce bool
+
+	backupKeep   int
+	backupMaxAge string
+)
+
+var backupCmd = &cobra.Command{
+	Use:   "backup",
+	Short: "Manage vault snapshots",
+	Long: `Every save that changes the vault's contents keeps a timestamped, encrypted
+snapshot of the vault next to it (in <vault>.snapshots/). Reading credentials
+and unlocking only record usage and take no snapshot. Snapshots are encrypted exactly like the vault, so
+they need the master password that was current when they were taken.
+
+By default the 10 most recent snapshots are kept. Use 'backup retention' to
+change the count or to also remove snapshots older than a maximum age.`,
+}
+
+var backupListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List vault snapshots",
+	Args:    cobra.NoArgs,
+	RunE:    runBackupList,
+}
+
+var backupRestoreCmd = &cobra.Command{
+	Use:   "restore <id>",
+	Short: "Restore the vault from a snapshot",
+	Long: `Restore replaces the vault with a snapshot. The current vault is snapshotted
+first, so a restore can itself be undone.
+
+A snapshot taken before a password change is encrypted with the old master
+password; you are asked for it, and it becomes the master password again.`,
+	Example: `  # See what a restore would change, then restore
+  pass-cli backup diff 20250114-093012
+  pass-cli backup restore 20250114-093012`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeSnapshotIDs,
+	RunE:              runBackupRestore,
+}
+
+var backupDiffCmd = &cobra.Command{
+	Use:   "diff <id>",
+	Short: "Show credentials changed since a snapshot",
+	Long: `Diff lists the credentials added, removed, or changed since a snapshot was
+taken. Changed credentials list the names of the changed fields; values
+(including passwords) are never shown.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeSnapshotIDs,
+	RunE:              runBackupDiff,
+}
+
+var backupRetentionCmd = &cobra.Command{
+	Use:   "retention",
+	Short: "Show or change snapshot retention",
+	Long: `Retention shows how many snapshots are kept and for how long. Use --keep to
+change the number of snapshots (0 disables snapshots and removes existing
+ones) and --max-age to also remove snapshots older than a duration
+(e.g. 30d, 12w; 0 means no age limit). The newest snapshot is always kept.`,
+	Example: `  # Keep 20 snapshots, none older than 90 days
+  pass-cli backup retention --keep 20 --max-age 90d
+
+  # Disable snapshots
+  pass-cli backup retention --keep 0`,
+	Args: cobra.NoArgs,
+	RunE: runBackupRetention,
+}
+
+func init() {
+	rootCmd.AddCommand(backupCmd)
+	backupCmd.AddCommand(backupListCmd)
+	backupCmd.AddCommand(backupRestoreCmd)
+	backupCmd.AddCommand(backupDiffCmd)
+	backupCmd.AddCommand(backupRetentionCmd)
+	backupRestoreCmd.Flags().BoolVarP(&backupRestoreForce, "force", "f", false, "skip confirmation prompt")
+	backupRetentionCmd.Flags().IntVar(&backupKeep, "keep", storage.DefaultSnapshotKeep, "number of snapshots to keep (0 disables snapshots)")
+	backupRetentionCmd.Flags().StringVar(&backupMaxAge, "max-age", "0", "remove snapshots older than this (e.g. 30d; 0 = no limit)")
+}
+
+func runBackupList(cmd *cobra.Command, args []string) error {
+	vaultService, err := openVaultService()
+	if err != nil {
+		return err
+	}
+
+	snapshots, err := vaultService.ListSnapshots()
+	if err != nil {
+		return fmt.Errorf("failed to list snapshots: %w", err)
+	}
+	retention, err := vaultService.GetSnapshotRetention()
+	if err != nil {
+		return fmt.Errorf("failed to read snapshot retention: %w", err)
+	}
+
+	if len(snapshots) == 0 {
+		fmt.Println("No snapshots yet.")
+		if retention.Keep == 0 {
+			fmt.Println("Snapshots are disabled. Enable them with: pass-cli backup retention --keep 10")
+		}
+		return nil
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	var data [][]string
+	for _, snapshot := range snapshots {
+		data = append(data, []string{
+			snapshot.ID,
+			snapshot.CreatedAt.Local().Format(time.DateTime),
+			formatRelativeTime(snapshot.CreatedAt),
+			formatSize(snapshot.Size),
+		})
+	}
+	table.Header([]string{"ID", "Created", "Age", "Size"})
+	_ = table.Bulk(data)
+	_ = table.Render()
+
+	fmt.Printf("\nTotal: %d snapshot(s) (%s)\n", len(snapshots), describeRetention(retention))
+	return nil
+}
+
+func runBackupRestore(cmd *cobra.Command, args []string) error {
+	id := strings.TrimSpace(args[0])
+
+	vaultService, err := openUnlockedVault()
+	if err != nil {
+		return err
+	}
+	defer vaultService.Lock()
+
+	// Show what will change before asking
+	changes, password, err := diffSnapshot(vaultService, id)
+	if err != nil {
+		return err
+	}
+	defer crypto.ClearBytes(password)
+
+	if len(changes) == 0 {
+		fmt.Printf("The vault already matches snapshot %s.\n", id)
+		return nil
+	}
+
+	if !backupRestoreForce {
+		printSnapshotChanges(changes, "Restoring will undo these changes:")
+		fmt.Printf("\nRestore snapshot %s? The current vault is snapshotted first. (y/N): ", id)
+		var response string
+		_, _ = fmt.Scanln(&response)
+		response = strings.ToLower(strings.TrimSpace(response))
+		if response != "y" && response != "yes" {
+			fmt.Println("Restore cancelled.")
+			return nil
+		}
+	}
+
+	if err := vaultService.RestoreSnapshot(id, password); err != nil {
+		return fmt.Errorf("failed to restore snapshot: %w", err)
+	}
+
+	fmt.Printf("✅ Restored vault from snapshot %s\n", id)
+	if password != nil {
+		fmt.Println("⚠️  The snapshot predates a password change: its master password is now the vault's master password")
+	}
+	return nil
+}
+
+func runBackupDiff(cmd *cobra.Command, args []string) error {
+	id := strings.TrimSpace(args[0])
+
+	vaultService, err := openUnlockedVault()
+	if err != nil {
+		return err
+	}
+	defer vaultService.Lock()
+
+	changes, password, err := diffSnapshot(vaultService, id)
+	if err != nil {
+		return err
+	}
+	crypto.ClearBytes(password)
+
+	if len(changes) == 0 {
+		fmt.Printf("No changes since snapshot %s.\n", id)
+		return nil
+	}
+	printSnapshotChanges(changes, fmt.Sprintf("Changes since snapshot %s:", id))
+	return nil
+}
+
+func runBackupRetention(cmd *cobra.Command, args []string) error {
+	keepSet := cmd.Flags().Changed("keep")
+	maxAgeSet := cmd.Flags().Changed("max-age")
+
+	if !keepSet && !maxAgeSet {
+		vaultService, err := openVaultService()
+		if err != nil {
+			return err
+		}
+		retention, err := vaultService.GetSnapshotRetention()
+		if err != nil {
+			return fmt.Errorf("failed to read snapshot retention: %w", err)
+		}
+		fmt.Printf("Snapshot retention: %s\n", describeRetention(retention))
+		return nil
+	}
+
+	if backupKeep < 0 {
+		return fmt.Errorf("--keep cannot be negative")
+	}
+	var maxAge time.Duration
+	if backupMaxAge != "0" {
+		d, err := parseDayDuration(backupMaxAge)
+		if err != nil {
+			return fmt.Errorf("invalid --max-age: %w", err)
+		}
+		maxAge = d
+	}
+
+	vaultService, err := openUnlockedVault()
+	if err != nil {
+		return err
+	}
+	defer vaultService.Lock()
+
+	// Unset flags keep their current value
+	retention, err := vaultService.GetSnapshotRetention()
+	if err != nil {
+		return fmt.Errorf("failed to read snapshot retention: %w", err)
+	}
+	if keepSet {
+		retention.Keep = backupKeep
+	}
+	if maxAgeSet {
+		retention.MaxAge = maxAge
+	}
+
+	if err := vaultService.SetSnapshotRetention(retention); err != nil {
+		return fmt.Errorf("failed to set snapshot retention: %w", err)
+	}
+	fmt.Printf("✅ Snapshot retention: %s\n", describeRetention(retention))
+	return nil
+}
+
+// openVaultService creates a vault service without unlocking it
+func openVaultService() (*vault.VaultService, error) {
+	vaultPath := GetVaultPath()
+
+	if _, err := os.Stat(vaultPath); os.IsNotExist(err) {
+		return nil, fmt.Errorf("vault not found at %s\nRun 'pass-cli init' to create a vault first", vaultPath)
+	}
+
+	vaultService, err := vault.New(vaultPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create vault service: %w", err)
+	}
+	return vaultService, nil
+}
+
+// diffSnapshot compares a snapshot with the vault, asking for the snapshot's own
+// master password if it predates a password change. The returned password is nil
+// when the current master password was used.
+func diffSnapshot(vaultService *vault.VaultService, id string) ([]vault.SnapshotChange, []byte, error) {
+	changes, err := vaultService.DiffSnapshot(id, nil)
+	if err == nil {
+		return changes, nil, nil
+	}
+	if !errors.Is(err, crypto.ErrDecryptionFailed) {
+		return nil, nil, fmt.Errorf("failed to read snapshot: %w", err)
+	}
+
+	fmt.Printf("Snapshot %s uses a previous master password.\nMaster password for snapshot: ", id)
+	password, err := readPassword()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read password: %w", err)
+	}
+	fmt.Println() // newline after password input
+
+	changes, err = vaultService.DiffSnapshot(id, password)
+	if err != nil {
+		crypto.ClearBytes(password)
+		return nil, nil, fmt.Errorf("failed to read snapshot: %w", err)
+	}
+	return changes, password, nil
+}
+
+// printSnapshotChanges prints credential changes without values
+func printSnapshotChanges(changes []vault.SnapshotChange, heading string) {
+	fmt.Println(heading)
+	for _, change := range changes {
+		switch change.Kind {
+		case vault.SnapshotAdded:
+			fmt.Printf("  + %s (added)\n", change.Service)
+		case vault.SnapshotRemoved:
+			fmt.Printf("  - %s (removed)\n", change.Service)
+		default:
+			fmt.Printf("  ~ %s (%s)\n", change.Service, strings.Join(change.Fields, ", "))
+		}
+	}
+	fmt.Printf("\n%d credential(s) differ\n", len(changes))
+}
+
+// describeRetention renders a retention policy
+func describeRetention(retention storage.SnapshotRetention) string {
+	if retention.Keep == 0 {
+		return "snapshots disabled"
+	}
+	description := fmt.Sprintf("keeping the newest %d", retention.Keep)
+	if retention.MaxAge > 0 {
+		days := int(retention.MaxAge / (24 * time.Hour))
+		if retention.MaxAge%(24*time.Hour) == 0 {
+			description += fmt.Sprintf(", up to %d days old", days)
+		} else {
+			description += fmt.Sprintf(", up to %s old", retention.MaxAge)
+		}
+	}
+	return description
+}
+
+// completeSnapshotIDs completes the snapshot ID argument
+func completeSnapshotIDs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	vaultService, err := vault.New(GetVaultPath())
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	snapshots, err := vaultService.ListSnapshots()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var ids []string
+	for _, snapshot := range snapshots {
+		ids = append(ids, snapshot.ID)
+	}
+	return ids, cobra.ShellCompDirectiveNoFileComp
+}
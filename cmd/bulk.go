@@ -0,0 +1,305 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"pass-cli/internal/query"
+	"pass-cli/internal/vault"
+)
+
+var (
+	bulkWhere      string
+	bulkDryRun     bool
+	bulkSet        []string
+	bulkAddTags    []string
+	bulkRemoveTags []string
+	bulkForce      bool
+)
+
+var bulkCmd = &cobra.Command{
+	Use:   "bulk",
+	Short: "Change many credentials at once",
+	Long: `Bulk commands change every credential matching a filter expression with a
+single unlock and a single save.
+
+Filter expressions (--where) compare fields with == (equals), != (differs),
+~ (contains), and !~ (does not contain), combined with &&, ||, ! and
+parentheses. Comparisons ignore case; values with spaces need quotes.
+
+Fields: service (name), username (user), category (cat), url, notes, type,
+tag (tags). For tags, == means "has the tag" and ~ means "a tag contains".
+The search syntax of 'pass-cli list --query' works too, including
+user:alice, url:*.internal, used>90d and uses<3.
+
+Use --dry-run to see the affected credentials without changing the vault.`,
+	Example: `  # Move AWS credentials into their own category
+  pass-cli bulk update --where 'category=="Cloud" && url~"aws"' --set category=AWS
+
+  # Tag every database credential
+  pass-cli bulk tag --where 'type==database' --add db
+
+  # Preview deleting old test credentials
+  pass-cli bulk delete --where 'tag==test || service~"-old"' --dry-run`,
+}
+
+var bulkUpdateCmd = &cobra.Command{
+	Use:   "update --where <expr> --set <field>=<value>",
+	Short: "Set fields on matching credentials",
+	Long: `Update sets fields on every credential matching --where. Settable fields are
+username, category, url, notes, and type; an empty value clears the field.
+Credentials that already have the new values are left untouched.`,
+	Example: `  pass-cli bulk update --where 'category=="Cloud" && url~"aws"' --set category=AWS
+  pass-cli bulk update --where 'user==old-admin' --set username=admin --set notes=`,
+	Args: cobra.NoArgs,
+	RunE: runBulkUpdate,
+}
+
+var bulkTagCmd = &cobra.Command{
+	Use:     "tag --where <expr> [--add tags] [--remove tags]",
+	Short:   "Add or remove tags on matching credentials",
+	Example: `  pass-cli bulk tag --where 'category=="Cloud"' --add cloud --remove legacy`,
+	Args:    cobra.NoArgs,
+	RunE:    runBulkTag,
+}
+
+var bulkDeleteCmd = &cobra.Command{
+	Use:   "delete --where <expr>",
+	Short: "Move matching credentials to the trash",
+	Long: `Delete moves every credential matching --where to the trash after a
+confirmation prompt (skipped with --force). Use 'pass-cli trash restore' to
+bring credentials back.`,
+	Example: `  pass-cli bulk delete --where 'tag==test' --dry-run
+  pass-cli bulk delete --where 'tag==test' --force`,
+	Args: cobra.NoArgs,
+	RunE: runBulkDelete,
+}
+
+func init() {
+	rootCmd.AddCommand(bulkCmd)
+	bulkCmd.AddCommand(bulkUpdateCmd)
+	bulkCmd.AddCommand(bulkTagCmd)
+	bulkCmd.AddCommand(bulkDeleteCmd)
+
+	for _, cmd := range []*cobra.Command{bulkUpdateCmd, bulkTagCmd, bulkDeleteCmd} {
+		cmd.Flags().StringVar(&bulkWhere, "where", "", "filter expression selecting the credentials (required)")
+		cmd.Flags().BoolVar(&bulkDryRun, "dry-run", false, "show the affected credentials without changing the vault")
+		_ = cmd.MarkFlagRequired("where")
+	}
+	bulkUpdateCmd.Flags().StringArrayVar(&bulkSet, "set", nil, "set field as field=value (repeatable)")
+	_ = bulkUpdateCmd.MarkFlagRequired("set")
+	bulkTagCmd.Flags().StringSliceVar(&bulkAddTags, "add", nil, "add tag (repeatable or comma-separated)")
+	bulkTagCmd.Flags().StringSliceVar(&bulkRemoveTags, "remove", nil, "remove tag (repeatable or comma-separated)")
+	bulkDeleteCmd.Flags().BoolVarP(&bulkForce, "force", "f", false, "skip confirmation prompt")
+}
+
+func runBulkUpdate(cmd *cobra.Command, args []string) error {
+	q, err := query.Parse(bulkWhere)
+	if err != nil {
+		return err
+	}
+	update, err := parseBulkSet(bulkSet)
+	if err != nil {
+		return err
+	}
+	return runBulkChange(q, update)
+}
+
+func runBulkTag(cmd *cobra.Command, args []string) error {
+	q, err := query.Parse(bulkWhere)
+	if err != nil {
+		return err
+	}
+	if len(bulkAddTags) == 0 && len(bulkRemoveTags) == 0 {
+		return fmt.Errorf("nothing to do: use --add and/or --remove")
+	}
+	return runBulkChange(q, vault.BulkUpdate{AddTags: bulkAddTags, RemoveTags: bulkRemoveTags})
+}
+
+// runBulkChange applies an update to the credentials matching q and prints the result
+func runBulkChange(q *query.Query, update vault.BulkUpdate) error {
+	vaultService, err := openUnlockedVault()
+	if err != nil {
+		return err
+	}
+	defer vaultService.Lock()
+
+	// Changes that break a type's requirements come back with the error, so
+	// they can be listed before reporting that nothing was saved
+	changes, err := vaultService.UpdateMatching(q.Match, update, bulkDryRun)
+	if err != nil && len(changes) == 0 {
+		return fmt.Errorf("failed to update credentials: %w", err)
+	}
+	if len(changes) == 0 {
+		fmt.Println("No matching credentials need changes")
+		return nil
+	}
+
+	if bulkDryRun {
+		fmt.Println("🔍 Dry run: the vault was not changed")
+		fmt.Println()
+	}
+	data := make([][]string, 0, len(changes))
+	invalid := 0
+	for _, change := range changes {
+		description := describeBulkChange(change)
+		if change.Err != nil {
+			description = fmt.Sprintf("❌ %s (%v)", description, change.Err)
+			invalid++
+		}
+		data = append(data, []string{change.Before.Service, description})
+	}
+	table := tablewriter.NewWriter(os.Stdout)
+	table.Header("Service", "Changes")
+	_ = table.Bulk(data)
+	_ = table.Render()
+	fmt.Println()
+
+	if err != nil {
+		return fmt.Errorf("no credentials were updated: %w", err)
+	}
+	if bulkDryRun {
+		fmt.Printf("%d credential(s) would be updated\n", len(changes)-invalid)
+		if invalid > 0 {
+			fmt.Printf("❌ %d credential(s) would not meet their type's requirements; the update would be refused\n", invalid)
+		}
+	} else {
+		fmt.Printf("✅ Updated %d credential(s)\n", len(changes))
+	}
+	return nil
+}
+
+func runBulkDelete(cmd *cobra.Command, args []string) error {
+	q, err := query.Parse(bulkWhere)
+	if err != nil {
+		return err
+	}
+
+	vaultService, err := openUnlockedVault()
+	if err != nil {
+		return err
+	}
+	defer vaultService.Lock()
+
+	matches, err := vaultService.DeleteMatching(q.Match, true)
+	if err != nil {
+		return fmt.Errorf("failed to delete credentials: %w", err)
+	}
+	if len(matches) == 0 {
+		fmt.Println("No credentials match")
+		return nil
+	}
+
+	if bulkDryRun {
+		fmt.Println("🔍 Dry run: the vault was not changed")
+		fmt.Println()
+	}
+	data := make([][]string, 0, len(matches))
+	for _, match := range matches {
+		meta := match.Before
+		data = append(data, []string{meta.Service, meta.Username, meta.Category, fmt.Sprintf("%d", meta.UsageCount)})
+	}
+	table := tablewriter.NewWriter(os.Stdout)
+	table.Header("Service", "Username", "Category", "Uses")
+	_ = table.Bulk(data)
+	_ = table.Render()
+	fmt.Println()
+
+	if bulkDryRun {
+		fmt.Printf("%d credential(s) would be moved to the trash\n", len(matches))
+		return nil
+	}
+
+	if !bulkForce {
+		fmt.Printf("Move %d credential(s) to the trash? (y/N): ", len(matches))
+		var confirm string
+		_, _ = fmt.Scanln(&confirm)
+		confirm = strings.ToLower(strings.TrimSpace(confirm))
+		if confirm != "y" && confirm != "yes" {
+			fmt.Println("Delete cancelled.")
+			return nil
+		}
+	}
+
+	deleted, err := vaultService.DeleteMatching(q.Match, false)
+	if err != nil {
+		return fmt.Errorf("failed to delete credentials: %w", err)
+	}
+	fmt.Printf("✅ Moved %d credential(s) to the trash\n", len(deleted))
+	fmt.Println("Restore with: pass-cli trash restore <service>")
+	return nil
+}
+
+// parseBulkSet converts --set field=value flags into a bulk update
+func parseBulkSet(values []string) (vault.BulkUpdate, error) {
+	var update vault.BulkUpdate
+	for _, value := range values {
+		key, fieldValue, ok := strings.Cut(value, "=")
+		if !ok {
+			return update, fmt.Errorf("invalid --set %q (expected field=value)", value)
+		}
+		fieldValue = strings.TrimSpace(fieldValue)
+
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "username", "user":
+			update.Username = &fieldValue
+		case "category", "cat":
+			update.Category = &fieldValue
+		case "url":
+			update.URL = &fieldValue
+		case "notes":
+			update.Notes = &fieldValue
+		case "type":
+			credType, err := vault.ParseCredentialType(fieldValue)
+			if err != nil {
+				return update, err
+			}
+			update.Type = &credType
+		default:
+			return update, fmt.Errorf("cannot set %q (settable fields: username, category, url, notes, type; use 'bulk tag' for tags)", key)
+		}
+	}
+	return update, nil
+}
+
+// describeBulkChange summarizes what an update changed ("category: Cloud → AWS, +tag cloud")
+func describeBulkChange(change vault.BulkChange) string {
+	before, after := change.Before, change.After
+	var parts []string
+	describe := func(field, old, new string) {
+		if old != new {
+			parts = append(parts, fmt.Sprintf("%s: %s → %s", field, displayValue(old), displayValue(new)))
+		}
+	}
+	describe("username", before.Username, after.Username)
+	describe("category", before.Category, after.Category)
+	describe("url", before.URL, after.URL)
+	if before.Notes != after.Notes {
+		parts = append(parts, "notes changed")
+	}
+	describe("type", string(before.Type), string(after.Type))
+
+	for _, tag := range after.Tags {
+		if !vault.HasTag(before.Tags, tag) {
+			parts = append(parts, "+tag "+tag)
+		}
+	}
+	for _, tag := range before.Tags {
+		if !vault.HasTag(after.Tags, tag) {
+			parts = append(parts, "-tag "+tag)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// displayValue shows empty values as "(none)"
+func displayValue(value string) string {
+	if value == "" {
+		return "(none)"
+	}
+	return value
+}
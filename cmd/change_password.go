@@ -1,7 +1,10 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
 
@@ -15,7 +18,10 @@ var changePasswordCmd = &cobra.Command{
 	Short: "Change the master password for your vault",
 	Long: `Change the master password used to encrypt and decrypt your vault.
 
-You must enter your current master password to authorize the change.
+You must enter your current master password (or a recovery code) to authorize
+the change, or pass --key-file to authorize it with a key file slot. Vaults that
+require a key file with the password need --key-file (or the vaults.key_file
+config default) as well.
 The new password must meet the security requirements:
 - At least 12 characters long
 - Contains at least one uppercase letter
@@ -23,18 +29,60 @@ The new password must meet the security requirements:
 - Contains at least one digit
 - Contains at least one special character or symbol
 
-This operation will re-encrypt your vault with the new password.`,
+Only the password's key slot is rewritten; other key slots (see 'pass-cli
+keyslot list') keep working. Snapshots taken earlier still open with the old
+password unless you also replace the data key: you are asked whether to, or
+pass --rekey. A rekey re-encrypts the vault and its snapshots with a new data
+key and deletes the backup file; other key slots are kept if you enter their
+secret when asked.
+Vaults that still use PBKDF2 key derivation are upgraded to Argon2id at the
+same time.
+Use --add-key-file to require a key file in addition to the new password; the
+file is created with random contents if it does not exist, and the data key is
+always replaced so snapshots stop opening with the password alone. Use
+--remove-key-file to go back to the password alone. Without either flag, a
+vault that requires a key file keeps requiring the same one.
+Use --kdf-benchmark to recalibrate Argon2id so unlocking takes about the
+given time on this machine.`,
 	Example: `  # Change master password
-  pass-cli change-password`,
+  pass-cli change-password
+
+  # Change a compromised password so older snapshots stop opening with it
+  pass-cli change-password --rekey
+
+  # Change password and calibrate key derivation for a 1 second unlock
+  pass-cli change-password --kdf-benchmark 1s
+
+  # Require a key file on a USB drive from now on
+  pass-cli change-password --add-key-file /media/usb/vault.key
+
+  # Stop requiring the key file
+  pass-cli --key-file /media/usb/vault.key change-password --remove-key-file`,
 	RunE: runChangePassword,
 }
 
+var (
+	changePasswordKDFBenchmark string
+	changePasswordAddKeyFile   string
+	changePasswordRemoveKey    bool
+	changePasswordRekey        bool
+)
+
 func init() {
 	rootCmd.AddCommand(changePasswordCmd)
+	addKDFBenchmarkFlag(changePasswordCmd, &changePasswordKDFBenchmark)
+	changePasswordCmd.Flags().StringVar(&changePasswordAddKeyFile, "add-key-file", "", "require this key file with the new password, creating it if it does not exist")
+	changePasswordCmd.Flags().BoolVar(&changePasswordRemoveKey, "remove-key-file", false, "stop requiring a key file with the password")
+	changePasswordCmd.Flags().BoolVar(&changePasswordRekey, "rekey", false, "replace the data key without asking, re-encrypting the vault and its snapshots")
+	changePasswordCmd.MarkFlagsMutuallyExclusive("add-key-file", "remove-key-file")
 }
 
 func runChangePassword(cmd *cobra.Command, args []string) error {
 	vaultPath := GetVaultPath()
+	benchmark, err := parseKDFBenchmark(changePasswordKDFBenchmark)
+	if err != nil {
+		return err
+	}
 
 	fmt.Println("🔐 Change Master Password")
 	fmt.Printf("📁 Vault location: %s\n\n", vaultPath)
@@ -45,18 +93,25 @@ func runChangePassword(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to create vault service: %w", err)
 	}
 
-	// Prompt for current password
-	fmt.Print("Enter current master password: ")
-	currentPassword, err := readPassword()
+	// A key file slot authorizes the change, e.g. after removing the password slot
+	unlocked, err := unlockWithKeyFile(vaultService)
 	if err != nil {
-		return fmt.Errorf("failed to read password: %w", err)
+		return err
 	}
-	defer crypto.ClearBytes(currentPassword)
-	fmt.Println() // newline after password input
-
-	// Unlock vault with current password
-	if err := vaultService.Unlock(currentPassword); err != nil {
-		return fmt.Errorf("failed to unlock vault: %w", err)
+	if !unlocked {
+		// Prompt for current password
+		fmt.Print("Enter current master password: ")
+		currentPassword, err := readPassword()
+		if err != nil {
+			return fmt.Errorf("failed to read password: %w", err)
+		}
+		defer crypto.ClearBytes(currentPassword)
+		fmt.Println() // newline after password input
+
+		// Unlock vault with current password
+		if err := vaultService.Unlock(currentPassword); err != nil {
+			return fmt.Errorf("failed to unlock vault: %w", err)
+		}
 	}
 	defer vaultService.Lock()
 
@@ -101,14 +156,79 @@ func runChangePassword(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("passwords do not match")
 	}
 
+	// Calibrate key derivation if requested
+	kdfParams, err := benchmarkKDF(benchmark)
+	if err != nil {
+		crypto.ClearBytes(newPassword)
+		return err
+	}
+
+	if changePasswordAddKeyFile != "" {
+		if _, err := os.Stat(changePasswordAddKeyFile); os.IsNotExist(err) {
+			if err := vault.CreateKeyFile(changePasswordAddKeyFile); err != nil {
+				crypto.ClearBytes(newPassword)
+				return err
+			}
+			fmt.Printf("🔑 Created key file: %s\n", changePasswordAddKeyFile)
+		}
+	}
+
+	// Offer to replace the data key, which the old password still opens in
+	// snapshots; a new key file requirement always replaces it
+	rekey := changePasswordRekey || changePasswordAddKeyFile != ""
+	if !rekey {
+		fmt.Print("🔑 Also replace the data key, so snapshots stop opening with the old password? (y/N): ")
+		var confirm string
+		_, _ = fmt.Scanln(&confirm)
+		confirm = strings.ToLower(strings.TrimSpace(confirm))
+		rekey = confirm == "y" || confirm == "yes"
+	}
+	var secrets vault.SlotSecrets
+	if rekey {
+		passwordSlot, err := vaultService.PasswordSlotID()
+		if err != nil {
+			crypto.ClearBytes(newPassword)
+			return fmt.Errorf("failed to list key slots: %w", err)
+		}
+		var ok bool
+		if secrets, ok, err = readKeptSlotSecrets(vaultService, false, passwordSlot); err != nil || !ok {
+			crypto.ClearBytes(newPassword)
+			return err
+		}
+		defer secrets.Clear()
+	}
+
 	// Change password
-	if err := vaultService.ChangePassword(newPassword); err != nil {
+	opts := vault.ChangePasswordOpts{
+		KDF:           kdfParams,
+		KeyFile:       changePasswordAddKeyFile,
+		RemoveKeyFile: changePasswordRemoveKey,
+		Rekey:         rekey,
+		Secrets:       secrets,
+	}
+	if err := vaultService.ChangePasswordWithOpts(newPassword, opts); err != nil {
 		crypto.ClearBytes(newPassword)
+		if errors.Is(err, vault.ErrKeyFileRequired) {
+			return fmt.Errorf("failed to change password: %w (pass --key-file, or --remove-key-file)", err)
+		}
 		return fmt.Errorf("failed to change password: %w", err)
 	}
 
 	// Success message
 	fmt.Println("✅ Master password changed successfully!")
+	switch {
+	case changePasswordAddKeyFile != "":
+		fmt.Printf("🔑 Unlocking now also requires the key file: %s\n", changePasswordAddKeyFile)
+		fmt.Println("💡 Pass --key-file or set vaults.key_file in config.yml")
+	case changePasswordRemoveKey:
+		fmt.Println("🔑 The key file is no longer required")
+	}
+	if rekey {
+		fmt.Println("🔑 The vault and its snapshots were re-encrypted with a new data key")
+	}
+	if params, err := vaultService.KDFParams(); err == nil {
+		fmt.Printf("🔑 Key derivation: %s\n", params)
+	}
 	fmt.Println("⚠️  Remember your new password - it cannot be recovered if lost!")
 
 	return nil
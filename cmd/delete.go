@@ -18,7 +18,9 @@ var deleteCmd = &cobra.Command{
 	Use:     "delete <service> [service...]",
 	Aliases: []string{"rm", "remove"},
 	Short:   "Delete credentials from the vault",
-	Long: `Delete removes one or more credentials from your vault.
+	Long: `Delete moves one or more credentials from your vault to the trash.
+Use 'pass-cli trash restore' to bring them back, or 'pass-cli trash purge'
+to remove them permanently.
 
 By default, you'll see a usage warning if the credential has been accessed before,
 showing where and when it was last used. This helps prevent accidental deletion
@@ -126,7 +128,7 @@ func runDelete(cmd *cobra.Command, args []string) error {
 			continue
 		}
 
-		fmt.Printf("✅ Deleted: %s\n", service)
+		fmt.Printf("✅ Moved to trash: %s\n", service)
 		deleted++
 	}
 
@@ -134,6 +136,7 @@ func runDelete(cmd *cobra.Command, args []string) error {
 	fmt.Println()
 	if deleted > 0 {
 		fmt.Printf("Successfully deleted %d credential(s)\n", deleted)
+		fmt.Println("Restore with: pass-cli trash restore <service>")
 	}
 	if skipped > 0 {
 		fmt.Printf("Skipped %d credential(s)\n", skipped)
@@ -0,0 +1,204 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"pass-cli/internal/vault"
+)
+
+var (
+	expiringWithin string
+	expiringFormat string
+)
+
+var expiringCmd = &cobra.Command{
+	Use:   "expiring",
+	Short: "List credentials that are expired or expire soon",
+	Long: `Expiring lists credentials whose expiry date or rotation due date falls
+within the given window, soonest first. Expired credentials are always included.
+
+A credential expires at the earlier of its explicit expiry date (--expires)
+and its rotation due date (last password change plus --rotate-every).
+
+Output formats:
+  table    Display as formatted table (default)
+  json     Output as JSON array
+
+Exit codes:
+  0 - No expired credentials
+  1 - Error
+  2 - At least one credential has expired`,
+	Example: `  # Show credentials expiring in the next 14 days
+  pass-cli expiring
+
+  # Show credentials expiring in the next 30 days
+  pass-cli expiring --within 30d
+
+  # Fail a CI job when anything has expired
+  pass-cli expiring --format json > expiring.json`,
+	Args: cobra.NoArgs,
+	RunE: runExpiring,
+}
+
+// expiringEntry is one row of expiring output
+type expiringEntry struct {
+	Service      string    `json:"service"`
+	Username     string    `json:"username,omitempty"`
+	ExpiresAt    time.Time `json:"expires_at"`
+	Status       string    `json:"status"`
+	Reason       string    `json:"reason"`
+	RotationDays int       `json:"rotation_days,omitempty"`
+}
+
+func init() {
+	rootCmd.AddCommand(expiringCmd)
+	expiringCmd.Flags().StringVar(&expiringWithin, "within", "14d", "warning window (e.g., 14d, 2w, 72h)")
+	expiringCmd.Flags().StringVarP(&expiringFormat, "format", "f", "table", "output format: table, json")
+}
+
+func runExpiring(cmd *cobra.Command, args []string) error {
+	// Validate flags before unlocking so typos fail fast
+	within, err := parseDayDuration(expiringWithin)
+	if err != nil {
+		return fmt.Errorf("invalid --within value: %w", err)
+	}
+	format := strings.ToLower(expiringFormat)
+	if format != "table" && format != "json" {
+		return fmt.Errorf("invalid format: %s (valid: table, json)", expiringFormat)
+	}
+
+	expired, err := listExpiring(within, format)
+	if err != nil {
+		return err
+	}
+
+	// Exit after the vault has been locked so CI jobs can fail on expired credentials
+	if expired > 0 {
+		os.Exit(2)
+	}
+	return nil
+}
+
+// listExpiring prints credentials expiring within the window and returns how many have expired
+func listExpiring(within time.Duration, format string) (int, error) {
+	vaultPath := GetVaultPath()
+
+	// Check if vault exists
+	if _, err := os.Stat(vaultPath); os.IsNotExist(err) {
+		return 0, fmt.Errorf("vault not found at %s\nRun 'pass-cli init' to create a vault first", vaultPath)
+	}
+
+	// Create vault service
+	vaultService, err := vault.New(vaultPath)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create vault service: %w", err)
+	}
+
+	// Unlock vault
+	if err := unlockVault(vaultService); err != nil {
+		return 0, err
+	}
+	defer vaultService.Lock()
+
+	metadata, err := vaultService.ListCredentialsWithMetadata()
+	if err != nil {
+		return 0, fmt.Errorf("failed to list credentials: %w", err)
+	}
+
+	now := time.Now()
+	entries := make([]expiringEntry, 0)
+	expired := 0
+	for _, meta := range metadata {
+		status := vault.GetExpiryStatus(meta.ExpiresAt, now, within)
+		if status != vault.ExpirySoon && status != vault.ExpiryExpired {
+			continue
+		}
+		if status == vault.ExpiryExpired {
+			expired++
+		}
+		entries = append(entries, expiringEntry{
+			Service:      meta.Service,
+			Username:     meta.Username,
+			ExpiresAt:    meta.ExpiresAt,
+			Status:       status.String(),
+			Reason:       expiryReason(meta),
+			RotationDays: meta.RotationDays,
+		})
+	}
+
+	// Soonest expiry first
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].ExpiresAt.Equal(entries[j].ExpiresAt) {
+			return entries[i].Service < entries[j].Service
+		}
+		return entries[i].ExpiresAt.Before(entries[j].ExpiresAt)
+	})
+
+	if format == "json" {
+		data, err := json.MarshalIndent(entries, "", "  ")
+		if err != nil {
+			return 0, fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return expired, nil
+	}
+
+	if len(entries) == 0 {
+		fmt.Printf("✅ No credentials expire within %s\n", expiringWithin)
+		return 0, nil
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	var data [][]string
+	for _, entry := range entries {
+		data = append(data, []string{
+			entry.Service,
+			formatExpiry(entry.ExpiresAt, now),
+			entry.Status,
+			entry.Reason,
+		})
+	}
+	table.Header([]string{"Service", "Expires", "Status", "Reason"})
+	_ = table.Bulk(data)
+	_ = table.Render()
+
+	fmt.Printf("\nExpired: %d, expiring within %s: %d\n", expired, expiringWithin, len(entries)-expired)
+
+	return expired, nil
+}
+
+// expiryReason explains whether the effective expiry comes from rotation or an explicit date
+func expiryReason(meta vault.CredentialMetadata) string {
+	if meta.RotationDue {
+		return fmt.Sprintf("rotation every %d days", meta.RotationDays)
+	}
+	return "expiry date"
+}
+
+// formatExpiry formats an expiry date with a relative hint ("in 3 days", "2 days ago")
+func formatExpiry(expiresAt, now time.Time) string {
+	date := expiresAt.Format("2006-01-02")
+	days := int(math.Ceil(expiresAt.Sub(now).Hours() / 24))
+
+	switch {
+	case !expiresAt.After(now) && days == 0:
+		return date + " (expired today)"
+	case !expiresAt.After(now):
+		return fmt.Sprintf("%s (expired %d days ago)", date, -days)
+	case days == 0:
+		return date + " (today)"
+	case days == 1:
+		return date + " (tomorrow)"
+	default:
+		return fmt.Sprintf("%s (in %d days)", date, days)
+	}
+}
@@ -0,0 +1,191 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"pass-cli/internal/crypto"
+	"pass-cli/internal/exporter"
+	"pass-cli/internal/kdbx"
+	"pass-cli/internal/security"
+	"pass-cli/internal/vault"
+)
+
+var (
+	exportFormat          string
+	exportOutput          string
+	exportUnsafePlaintext bool
+	exportForce           bool
+	exportKDBXCipher      string
+	exportKDBXKDF         string
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export --format <format> --output <file>",
+	Short: "Export credentials to a file",
+	Long: `Export writes every credential in the vault to a file.
+
+Formats (--format):
+
+  bundle   encrypted with a separate passphrase you choose; includes password
+           history, usage, and attachments. Read it back with
+           "pass-cli import --from bundle".
+  kdbx     KeePass KDBX 4 database encrypted with a separate password you
+           choose; categories become groups and the password history
+           becomes entry history. Open it in KeePassXC or KeePass 2.
+           --kdbx-cipher (chacha20, aes256) and --kdbx-kdf (argon2id,
+           argon2d, aes-kdf) select the encryption.
+  csv      plaintext, one row per credential, custom fields as columns
+  json     plaintext, one object per credential
+
+Plaintext formats contain every password unencrypted. They require
+--unsafe-plaintext and a confirmation prompt (skipped with --force).
+
+The output file is created with 0600 permissions, and every export is
+recorded in the audit log.`,
+	Example: `  # Encrypted backup to move to another machine
+  pass-cli export --format bundle --output vault.bundle
+
+  # KeePass database for KeePassXC
+  pass-cli export --format kdbx --output Passwords.kdbx
+
+  # Plaintext CSV for a spreadsheet or another password manager
+  pass-cli export --format csv --output passwords.csv --unsafe-plaintext`,
+	Args: cobra.NoArgs,
+	RunE: runExport,
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+	exportCmd.Flags().StringVar(&exportFormat, "format", string(exporter.FormatBundle), "export format: bundle, kdbx, csv, json")
+	exportCmd.Flags().StringVarP(&exportOutput, "output", "o", "", "file to write (required)")
+	exportCmd.Flags().BoolVar(&exportUnsafePlaintext, "unsafe-plaintext", false, "allow formats that write passwords unencrypted")
+	exportCmd.Flags().BoolVarP(&exportForce, "force", "f", false, "skip the confirmation prompt and overwrite an existing file")
+	exportCmd.Flags().StringVar(&exportKDBXCipher, "kdbx-cipher", string(kdbx.CipherChaCha20), "kdbx cipher: chacha20, aes256")
+	exportCmd.Flags().StringVar(&exportKDBXKDF, "kdbx-kdf", string(kdbx.KDFArgon2id), "kdbx key derivation: argon2id, argon2d, aes-kdf")
+	_ = exportCmd.MarkFlagRequired("output")
+
+	_ = exportCmd.RegisterFlagCompletionFunc("format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		var formats []string
+		for _, f := range exporter.Formats {
+			formats = append(formats, string(f))
+		}
+		return formats, cobra.ShellCompDirectiveNoFileComp
+	})
+	_ = exportCmd.RegisterFlagCompletionFunc("kdbx-cipher", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{string(kdbx.CipherChaCha20), string(kdbx.CipherAES256)}, cobra.ShellCompDirectiveNoFileComp
+	})
+	_ = exportCmd.RegisterFlagCompletionFunc("kdbx-kdf", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{string(kdbx.KDFArgon2id), string(kdbx.KDFArgon2d), string(kdbx.KDFAES)}, cobra.ShellCompDirectiveNoFileComp
+	})
+}
+
+func runExport(cmd *cobra.Command, args []string) error {
+	format, err := exporter.ParseFormat(exportFormat)
+	if err != nil {
+		return err
+	}
+	if format.Plaintext() && !exportUnsafePlaintext {
+		return fmt.Errorf("%s exports contain every password in plaintext: pass --unsafe-plaintext to continue, or use --format bundle", format)
+	}
+	if _, err := os.Stat(exportOutput); err == nil && !exportForce {
+		return fmt.Errorf("%s already exists (use --force to overwrite)", exportOutput)
+	}
+
+	opts := exporter.Options{KDBX: kdbx.WriteOptions{
+		Cipher: kdbx.Cipher(strings.ToLower(exportKDBXCipher)),
+		KDF:    kdbx.KDF(strings.ToLower(exportKDBXKDF)),
+	}}
+	if err := opts.KDBX.Validate(); err != nil {
+		return err
+	}
+
+	vaultService, err := openUnlockedVault()
+	if err != nil {
+		return err
+	}
+	defer vaultService.Lock()
+
+	switch {
+	case format == exporter.FormatBundle:
+		passphrase, err := readExportPassphrase("bundle passphrase")
+		if err != nil {
+			return err
+		}
+		defer crypto.ClearBytes(passphrase)
+		opts.Passphrase = passphrase
+	case format == exporter.FormatKDBX:
+		password, err := readExportPassphrase("KeePass database password")
+		if err != nil {
+			return err
+		}
+		defer crypto.ClearBytes(password)
+		opts.Passphrase = password
+	case !exportForce:
+		fmt.Printf("⚠️  %s will contain every password in plaintext.\n", exportOutput)
+		fmt.Print("Write the plaintext export? (y/N): ")
+		var response string
+		_, _ = fmt.Scanln(&response)
+		response = strings.ToLower(strings.TrimSpace(response))
+		if response != "y" && response != "yes" {
+			fmt.Println("Export cancelled.")
+			return nil
+		}
+	}
+
+	count := 0
+	err = vaultService.ExportCredentials(string(format), func(credentials []vault.Credential) error {
+		var buf bytes.Buffer
+		defer func() { crypto.ClearBytes(buf.Bytes()) }()
+
+		if err := exporter.Write(format, &buf, credentials, opts); err != nil {
+			return err
+		}
+		count = len(credentials)
+		return writePrivateFile(exportOutput, buf.Bytes())
+	})
+	if err != nil {
+		return fmt.Errorf("failed to export: %w", err)
+	}
+
+	fmt.Printf("✅ Exported %d credential(s) to %s\n", count, exportOutput)
+	if format.Plaintext() {
+		fmt.Println("⚠️  Delete the file as soon as you no longer need it")
+	}
+	return nil
+}
+
+// readExportPassphrase asks for a new export passphrase twice. The passphrase
+// protects the whole vault, so it must meet the master password policy.
+func readExportPassphrase(label string) ([]byte, error) {
+	fmt.Printf("%s%s (min 12 characters with uppercase, lowercase, digit, symbol): ", strings.ToUpper(label[:1]), label[1:])
+	passphrase, err := readPassword()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read passphrase: %w", err)
+	}
+	fmt.Println() // newline after password input
+
+	if err := security.DefaultPasswordPolicy.Validate(passphrase); err != nil {
+		crypto.ClearBytes(passphrase)
+		return nil, fmt.Errorf("%s does not meet requirements: %w", label, err)
+	}
+
+	fmt.Printf("Confirm %s: ", label)
+	confirm, err := readPassword()
+	if err != nil {
+		crypto.ClearBytes(passphrase)
+		return nil, fmt.Errorf("failed to read passphrase: %w", err)
+	}
+	defer crypto.ClearBytes(confirm)
+	fmt.Println() // newline after password input
+
+	if !bytes.Equal(passphrase, confirm) {
+		crypto.ClearBytes(passphrase)
+		return nil, fmt.Errorf("passphrases do not match")
+	}
+	return passphrase, nil
+}
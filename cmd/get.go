@@ -1,17 +1,25 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/atotto/clipboard"
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
 
+	"pass-cli/internal/crypto"
+	"pass-cli/internal/fuzzy"
 	"pass-cli/internal/vault"
 )
 
+// maxFuzzyChoices caps the matches offered when a service name is not found
+const maxFuzzyChoices = 9
+
 var (
 	getQuiet       bool
 	getField       string
@@ -28,10 +36,17 @@ By default, the password is copied to the clipboard and credential details
 are displayed. Use flags to customize the output:
 
   --quiet      Output only the requested value (for scripts)
-  --field      Extract a specific field (username, password, category, url, notes, service)
+  --field      Extract a specific field (username, password, category, url, notes, service,
+               totp for the current one-time code, or the key of a custom field)
   --no-clipboard  Skip copying to clipboard
   --masked     Display password as asterisks (default shows full password)
 
+If no credential has exactly the given name, get looks for fuzzy matches
+(fzf-style) on service, username and URL, so "gthub" finds github and
+"aws prod" finds aws-prod. In a terminal a single match is used directly and
+several matches are offered as a numbered list; in scripts and with --quiet
+the command fails with "did you mean" suggestions instead of guessing.
+
 Automatic usage tracking records where credentials are accessed based on
 your current working directory.`,
 	Example: `  # Get credential with clipboard copy
@@ -43,19 +58,26 @@ your current working directory.`,
   # Get specific field for scripts
   pass-cli get github --field username --quiet
 
+  # Get a custom field for scripts
+  pass-cli get aws-prod --field client_id --quiet
+
   # Get without clipboard
   pass-cli get github --no-clipboard
 
   # Get with masked password display
-  pass-cli get github --masked`,
-	Args: cobra.ExactArgs(1),
-	RunE: runGet,
+  pass-cli get github --masked
+
+  # Find a credential by fuzzy name
+  pass-cli get "aws prod"`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeServicePaths,
+	RunE:              runGet,
 }
 
 func init() {
 	rootCmd.AddCommand(getCmd)
 	getCmd.Flags().BoolVarP(&getQuiet, "quiet", "q", false, "output only the requested value (script-friendly)")
-	getCmd.Flags().StringVarP(&getField, "field", "f", "password", "field to extract (username, password, category, url, notes, service)")
+	getCmd.Flags().StringVarP(&getField, "field", "f", "password", "field to extract (username, password, category, url, notes, service, totp, or a custom field key)")
 	getCmd.Flags().BoolVar(&getNoClipboard, "no-clipboard", false, "do not copy to clipboard")
 	getCmd.Flags().BoolVar(&getMasked, "masked", false, "display password as asterisks")
 }
@@ -85,8 +107,21 @@ func runGet(cmd *cobra.Command, args []string) error {
 	}
 	defer vaultService.Lock()
 
-	// Get credential (no automatic tracking)
+	// Get credential (no automatic tracking), falling back to fuzzy matches
 	cred, err := vaultService.GetCredential(service, false)
+	if errors.Is(err, vault.ErrCredentialNotFound) {
+		interactive := !getQuiet && term.IsTerminal(int(os.Stdin.Fd())) && term.IsTerminal(int(os.Stdout.Fd()))
+		resolved, resolveErr := resolveFuzzyService(vaultService, service, interactive)
+		if resolveErr != nil {
+			return resolveErr
+		}
+		if resolved == "" {
+			fmt.Println("Cancelled.")
+			return nil
+		}
+		service = resolved
+		cred, err = vaultService.GetCredential(service, false)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to get credential: %w", err)
 	}
@@ -124,8 +159,24 @@ func outputQuietMode(cred *vault.Credential, vaultService *vault.VaultService, s
 	case "service", "s":
 		value = cred.Service
 		fieldName = "service"
+	case "totp", "otp":
+		if cred.TOTP == nil {
+			return fmt.Errorf("credential %s has no TOTP seed configured", service)
+		}
+		code, err := cred.TOTP.GenerateCode(time.Now())
+		if err != nil {
+			return fmt.Errorf("failed to generate TOTP code: %w", err)
+		}
+		value = code
+		fieldName = "totp"
 	default:
-		return fmt.Errorf("invalid field: %s (valid: username, password, category, url, notes, service)", getField)
+		// Fall back to custom fields before rejecting the name
+		customField, ok := cred.GetCustomField(getField)
+		if !ok {
+			return fmt.Errorf("invalid field: %s (valid: username, password, category, url, notes, service, totp, or a custom field key)", getField)
+		}
+		value = customField.Value
+		fieldName = customField.Key
 	}
 
 	// Track field access
@@ -142,22 +193,41 @@ func outputNormalMode(cred *vault.Credential, vaultService *vault.VaultService,
 	// Display credential details
 	fmt.Printf("📝 Service: %s\n", cred.Service)
 
+	// Labels follow the credential type (e.g. "Key ID"/"Secret" for API keys)
+	template, _ := vault.GetTemplate(cred.EffectiveType())
+	if cred.EffectiveType() != vault.TypeLogin {
+		fmt.Printf("🗂️  Type: %s\n", template.Label)
+	}
+	usernameLabel, passwordLabel := template.UsernameLabel, template.PasswordLabel
+	if usernameLabel == "" {
+		usernameLabel = "Username"
+	}
+	if passwordLabel == "" {
+		passwordLabel = "Password"
+	}
+
 	if cred.Username != "" {
-		fmt.Printf("👤 Username: %s\n", cred.Username)
+		fmt.Printf("👤 %s: %s\n", usernameLabel, cred.Username)
 	}
 
-	// Display password (masked or full)
-	if getMasked {
-		fmt.Printf("🔑 Password: %s\n", strings.Repeat("*", len(cred.Password)))
-	} else {
-		// T020d: Convert []byte to string for display
-		fmt.Printf("🔑 Password: %s\n", string(cred.Password))
+	// Display password (masked or full); secure notes and keys without a passphrase have none
+	if len(cred.Password) > 0 {
+		if getMasked {
+			fmt.Printf("🔑 %s: %s\n", passwordLabel, strings.Repeat("*", len(cred.Password)))
+		} else {
+			// T020d: Convert []byte to string for display
+			fmt.Printf("🔑 %s: %s\n", passwordLabel, string(cred.Password))
+		}
 	}
 
 	if cred.Category != "" {
 		fmt.Printf("🏷️  Category: %s\n", cred.Category)
 	}
 
+	if len(cred.Tags) > 0 {
+		fmt.Printf("🔖 Tags: %s\n", strings.Join(cred.Tags, ", "))
+	}
+
 	if cred.URL != "" {
 		fmt.Printf("🔗 URL: %s\n", cred.URL)
 	}
@@ -166,14 +236,45 @@ func outputNormalMode(cred *vault.Credential, vaultService *vault.VaultService,
 		fmt.Printf("📋 Notes: %s\n", cred.Notes)
 	}
 
+	// Display custom fields in stored order (secret values follow --masked)
+	for _, field := range cred.CustomFields {
+		if field.Secret {
+			value := field.Value
+			if getMasked {
+				value = strings.Repeat("*", len(field.Value))
+			}
+			fmt.Printf("🔒 %s: %s\n", field.Key, value)
+		} else {
+			fmt.Printf("🧩 %s: %s\n", field.Key, field.Value)
+		}
+	}
+
+	for _, attachment := range cred.Attachments {
+		fmt.Printf("📎 Attachment: %s (%s)\n", attachment.Name, formatSize(attachment.Size()))
+		crypto.ClearBytes(attachment.Data)
+	}
+
+	if cred.TOTP != nil {
+		fmt.Printf("⏱️  TOTP: configured (run 'pass-cli otp %s' for the current code)\n", cred.Service)
+	}
+
 	// Display timestamps
 	fmt.Printf("📅 Created: %s\n", cred.CreatedAt.Format("2006-01-02 15:04:05"))
 	if !cred.UpdatedAt.Equal(cred.CreatedAt) {
 		fmt.Printf("📅 Updated: %s\n", cred.UpdatedAt.Format("2006-01-02 15:04:05"))
 	}
 
-	// Copy to clipboard unless disabled
-	if !getNoClipboard {
+	// Display expiry and rotation
+	if expiry := cred.EffectiveExpiry(); !expiry.IsZero() {
+		fmt.Printf("⌛ Expires: %s\n", formatExpiry(expiry, time.Now()))
+	}
+	if cred.RotationDays > 0 {
+		fmt.Printf("🔄 Rotate every: %d days (password last changed %s)\n",
+			cred.RotationDays, formatRelativeTime(cred.LastPasswordChange()))
+	}
+
+	// Copy to clipboard unless disabled (nothing to copy without a password)
+	if !getNoClipboard && len(cred.Password) > 0 {
 		// T020g: Convert []byte to string for clipboard, then immediately zero the byte slice
 		passwordStr := string(cred.Password)
 
@@ -211,3 +312,62 @@ func outputNormalMode(cred *vault.Credential, vaultService *vault.VaultService,
 
 	return nil
 }
+
+// resolveFuzzyService finds the credential a mistyped service name most likely means.
+// Interactive sessions use a single match directly or pick from a numbered list;
+// otherwise the error suggests the closest names. Returns "" if the user cancels.
+func resolveFuzzyService(vaultService *vault.VaultService, service string, interactive bool) (string, error) {
+	notFound := fmt.Errorf("failed to get credential: %w: %s", vault.ErrCredentialNotFound, service)
+
+	metadata, err := vaultService.ListCredentialsWithMetadata()
+	if err != nil {
+		return "", fmt.Errorf("failed to list credentials: %w", err)
+	}
+	matches := fuzzy.Rank(service, metadata)
+	if len(matches) == 0 {
+		return "", notFound
+	}
+	if len(matches) > maxFuzzyChoices {
+		matches = matches[:maxFuzzyChoices]
+	}
+
+	if !interactive {
+		suggestions := make([]string, 0, 3)
+		for _, match := range matches[:min(3, len(matches))] {
+			suggestions = append(suggestions, match.Credential.Service)
+		}
+		return "", fmt.Errorf("%w\nDid you mean: %s?", notFound, strings.Join(suggestions, ", "))
+	}
+
+	if len(matches) == 1 {
+		fmt.Printf("🔍 No credential named '%s'; showing '%s'\n\n", service, matches[0].Credential.Service)
+		return matches[0].Credential.Service, nil
+	}
+
+	fmt.Printf("🔍 No credential named '%s'. Closest matches:\n", service)
+	for i, match := range matches {
+		if match.Credential.Username != "" {
+			fmt.Printf("  %d. %s (%s)\n", i+1, match.Credential.Service, match.Credential.Username)
+		} else {
+			fmt.Printf("  %d. %s\n", i+1, match.Credential.Service)
+		}
+	}
+	fmt.Printf("Select [1-%d] (Enter for 1, q to cancel): ", len(matches))
+	choice, err := readLine()
+	if err != nil {
+		return "", fmt.Errorf("failed to read selection: %w", err)
+	}
+	fmt.Println()
+
+	switch strings.ToLower(choice) {
+	case "":
+		return matches[0].Credential.Service, nil
+	case "q", "quit":
+		return "", nil
+	}
+	n, err := strconv.Atoi(choice)
+	if err != nil || n < 1 || n > len(matches) {
+		return "", fmt.Errorf("invalid selection: %s (expected 1-%d)", choice, len(matches))
+	}
+	return matches[n-1].Credential.Service, nil
+}
@@ -0,0 +1,202 @@
+package cmd
+
+import (
+	"crypto/sha1" // #nosec G505 -- SHA-1 is the hash format of breach lists, not used for security
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"pass-cli/internal/security"
+	"pass-cli/internal/vault"
+)
+
+var (
+	healthFormat   string
+	healthMaxAge   string
+	healthBreached string
+	healthFailOn   string
+)
+
+var healthCmd = &cobra.Command{
+	Use:   "health",
+	Short: "Report reused, weak, old and breached passwords",
+	Long: `Health scans the passwords in your vault and reports problems, most severe
+first:
+
+  critical  breached  Password appears in the breach list (--breached)
+  high      reused    Same password as another credential (compared by hash)
+  medium    weak      Rated weak by the password policy
+  low       old       Unchanged for longer than --max-age
+
+The breach list is a local Have I Been Pwned style file of SHA-1 hashes: the
+full "HASH:COUNT" download, a range file named after a 5-character hash prefix
+with "SUFFIX:COUNT" lines, or a directory of range files. Passwords never leave
+your machine; only their hashes are looked up.
+
+Output formats:
+  table    Display as formatted table (default)
+  json     Output as JSON object
+
+Exit codes:
+  0 - No issues at or above --fail-on
+  1 - Error
+  2 - At least one issue at or above --fail-on`,
+	Example: `  # Check for reused, weak and old passwords
+  pass-cli health
+
+  # Also check against a downloaded breach list
+  pass-cli health --breached ~/pwned-passwords-sha1.txt
+
+  # Flag passwords older than 180 days
+  pass-cli health --max-age 180d
+
+  # Fail a CI job only on reused or breached passwords
+  pass-cli health --fail-on high --format json > health.json`,
+	Args: cobra.NoArgs,
+	RunE: runHealth,
+}
+
+// healthEntry is one issue in health output
+type healthEntry struct {
+	Service  string   `json:"service"`
+	Username string   `json:"username,omitempty"`
+	Issue    string   `json:"issue"`
+	Severity string   `json:"severity"`
+	Detail   string   `json:"detail"`
+	Related  []string `json:"related,omitempty"`
+}
+
+// healthOutput is the JSON form of a health report
+type healthOutput struct {
+	Checked int            `json:"checked"`
+	Counts  map[string]int `json:"counts"`
+	Issues  []healthEntry  `json:"issues"`
+}
+
+func init() {
+	rootCmd.AddCommand(healthCmd)
+	healthCmd.Flags().StringVarP(&healthFormat, "format", "f", "table", "output format: table, json")
+	healthCmd.Flags().StringVar(&healthMaxAge, "max-age", "365d", "report passwords unchanged for longer (e.g., 365d, 26w; 0 disables)")
+	healthCmd.Flags().StringVar(&healthBreached, "breached", "", "HIBP-style SHA-1 breach list file or directory of range files")
+	healthCmd.Flags().StringVar(&healthFailOn, "fail-on", "low", "lowest severity that sets exit code 2: low, medium, high, critical, none")
+
+	_ = healthCmd.RegisterFlagCompletionFunc("format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"table", "json"}, cobra.ShellCompDirectiveNoFileComp
+	})
+	_ = healthCmd.RegisterFlagCompletionFunc("fail-on", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"low", "medium", "high", "critical", "none"}, cobra.ShellCompDirectiveNoFileComp
+	})
+}
+
+func runHealth(cmd *cobra.Command, args []string) error {
+	// Validate flags before unlocking so typos fail fast
+	format := strings.ToLower(healthFormat)
+	if format != "table" && format != "json" {
+		return fmt.Errorf("invalid format: %s (valid: table, json)", healthFormat)
+	}
+	maxAge, err := parseDayDuration(healthMaxAge)
+	if err != nil {
+		return fmt.Errorf("invalid --max-age value: %w", err)
+	}
+	failOn := vault.SeverityCritical + 1 // "none": never fail
+	if !strings.EqualFold(healthFailOn, "none") {
+		if failOn, err = vault.ParseHealthSeverity(healthFailOn); err != nil {
+			return fmt.Errorf("invalid --fail-on value: %s (valid: low, medium, high, critical, none)", healthFailOn)
+		}
+	}
+	if healthBreached != "" {
+		if _, err := os.Stat(healthBreached); err != nil {
+			return fmt.Errorf("breach list not found: %w", err)
+		}
+	}
+
+	failing, err := reportHealth(format, maxAge, failOn)
+	if err != nil {
+		return err
+	}
+
+	// Exit after the vault has been locked so CI jobs can fail on unhealthy passwords
+	if failing > 0 {
+		os.Exit(2)
+	}
+	return nil
+}
+
+// reportHealth prints the health report and returns how many issues are at or above failOn
+func reportHealth(format string, maxAge time.Duration, failOn vault.HealthSeverity) (int, error) {
+	vaultService, err := openUnlockedVault()
+	if err != nil {
+		return 0, err
+	}
+	defer vaultService.Lock()
+
+	opts := vault.HealthOptions{MaxAge: maxAge}
+	if healthBreached != "" {
+		opts.Breached = func(hashes [][sha1.Size]byte) (map[[sha1.Size]byte]int, error) {
+			return security.LookupBreachedHashes(healthBreached, hashes)
+		}
+	}
+	report, err := vaultService.CheckHealth(opts)
+	if err != nil {
+		return 0, fmt.Errorf("failed to check password health: %w", err)
+	}
+
+	counts := map[string]int{}
+	for s := vault.SeverityLow; s <= vault.SeverityCritical; s++ {
+		counts[s.String()] = 0
+	}
+	failing := 0
+	entries := make([]healthEntry, 0, len(report.Issues))
+	for _, issue := range report.Issues {
+		counts[issue.Severity.String()]++
+		if issue.Severity >= failOn {
+			failing++
+		}
+		entries = append(entries, healthEntry{
+			Service:  issue.Service,
+			Username: issue.Username,
+			Issue:    string(issue.Kind),
+			Severity: issue.Severity.String(),
+			Detail:   issue.Detail,
+			Related:  issue.Related,
+		})
+	}
+
+	if format == "json" {
+		data, err := json.MarshalIndent(healthOutput{Checked: report.Checked, Counts: counts, Issues: entries}, "", "  ")
+		if err != nil {
+			return 0, fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return failing, nil
+	}
+
+	if len(entries) == 0 {
+		fmt.Printf("✅ No password issues found in %d credential(s)\n", report.Checked)
+		if healthBreached == "" {
+			fmt.Println("💡 Add --breached <file> to check against a breach list")
+		}
+		return 0, nil
+	}
+
+	data := make([][]string, 0, len(entries))
+	for _, entry := range entries {
+		data = append(data, []string{entry.Severity, entry.Service, entry.Issue, entry.Detail})
+	}
+	table := tablewriter.NewWriter(os.Stdout)
+	table.Header("Severity", "Service", "Issue", "Detail")
+	_ = table.Bulk(data)
+	_ = table.Render()
+
+	fmt.Printf("\nChecked %d credential(s): %d critical, %d high, %d medium, %d low\n",
+		report.Checked, counts["critical"], counts["high"], counts["medium"], counts["low"])
+	if healthBreached == "" {
+		fmt.Println("💡 Add --breached <file> to check against a breach list")
+	}
+	return failing, nil
+}
@@ -2,11 +2,18 @@ package cmd
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/howeyc/gopass"
+	"github.com/spf13/cobra"
 	"golang.org/x/term"
+
+	"pass-cli/internal/vault"
 )
 
 // readPassword reads a password from stdin with asterisk masking.
@@ -32,6 +39,29 @@ func readPassword() ([]byte, error) {
 	return passwordBytes, nil
 }
 
+// readLine reads one line from stdin with surrounding whitespace trimmed.
+// Reads a byte at a time so input meant for later prompts is not buffered away.
+func readLine() (string, error) {
+	var line []byte
+	buf := make([]byte, 1)
+	for {
+		n, err := os.Stdin.Read(buf)
+		if n > 0 {
+			if buf[0] == '\n' {
+				break
+			}
+			line = append(line, buf[0])
+		}
+		if err != nil {
+			if err == io.EOF && len(line) > 0 {
+				break
+			}
+			return "", err
+		}
+	}
+	return strings.TrimSpace(string(line)), nil
+}
+
 // T072: getAuditLogPath returns the audit log path from environment variable or default
 // Per FR-023: PASS_AUDIT_LOG environment variable for custom log location
 func getAuditLogPath(vaultPath string) string {
@@ -55,3 +85,135 @@ func getVaultID(vaultPath string) string {
 	}
 	return absPath
 }
+
+// parseFieldFlags converts repeated key=value flag values into custom fields.
+// The secret flag marks every parsed field as secret (masked in output).
+func parseFieldFlags(values []string, secret bool) ([]vault.CustomField, error) {
+	fields := make([]vault.CustomField, 0, len(values))
+	for _, value := range values {
+		key, fieldValue, ok := strings.Cut(value, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid field %q (expected key=value)", value)
+		}
+		fields = append(fields, vault.CustomField{Key: key, Value: fieldValue, Secret: secret})
+	}
+	return fields, nil
+}
+
+// parseTOTPFlag converts a --totp value (otpauth:// URI or base32 secret) into a TOTP config.
+// A value of "-" prompts for the secret with hidden input to keep it out of shell history.
+func parseTOTPFlag(value string) (*vault.TOTPConfig, error) {
+	if value == "-" {
+		fmt.Print("TOTP secret or otpauth:// URI: ")
+		input, err := readPassword()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read TOTP secret: %w", err)
+		}
+		fmt.Println() // newline after hidden input
+		value = string(input)
+	}
+
+	cfg, err := vault.ParseTOTP(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --totp value: %w", err)
+	}
+	return cfg, nil
+}
+
+// parseDayDuration parses durations like "14d", "2w", or any time.ParseDuration value ("36h").
+// A bare number is treated as days.
+func parseDayDuration(value string) (time.Duration, error) {
+	value = strings.TrimSpace(strings.ToLower(value))
+	if value == "" {
+		return 0, fmt.Errorf("duration cannot be empty")
+	}
+
+	unit := 24 * time.Hour
+	number := value
+	switch {
+	case strings.HasSuffix(value, "d"):
+		number = strings.TrimSuffix(value, "d")
+	case strings.HasSuffix(value, "w"):
+		number = strings.TrimSuffix(value, "w")
+		unit = 7 * 24 * time.Hour
+	}
+
+	if n, err := strconv.Atoi(number); err == nil {
+		if n < 0 {
+			return 0, fmt.Errorf("duration cannot be negative: %s", value)
+		}
+		return time.Duration(n) * unit, nil
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid duration %q (use e.g. 14d, 2w, 36h)", value)
+	}
+	return d, nil
+}
+
+// parseExpiryFlag parses an expiry as a date (YYYY-MM-DD), RFC 3339 timestamp,
+// or a duration from now ("90d").
+func parseExpiryFlag(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if d, err := parseDayDuration(value); err == nil {
+		return time.Now().Add(d), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid expiry %q (use YYYY-MM-DD or a duration like 90d)", value)
+}
+
+// parseRotationFlag converts a rotation interval ("90d", "12w") into whole days.
+func parseRotationFlag(value string) (int, error) {
+	d, err := parseDayDuration(value)
+	if err != nil {
+		return 0, err
+	}
+	days := int(d / (24 * time.Hour))
+	if days < 1 {
+		return 0, fmt.Errorf("rotation interval must be at least 1 day")
+	}
+	return days, nil
+}
+
+// completeServicePaths shell-completes service names one path segment at a time.
+// Completion cannot prompt for the master password, so it only works when the
+// vault can be unlocked from the keychain; otherwise no candidates are offered.
+func completeServicePaths(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	vaultService, err := vault.New(GetVaultPath())
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	vaultService.SetKeyFile(GetKeyFile())
+	if err := vaultService.UnlockWithKeychain(); err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	defer vaultService.Lock()
+
+	services, err := vaultService.ListCredentials()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	completions := vault.CompleteServicePath(services, toComplete)
+
+	// Don't add a space after a folder so the next segment can be completed
+	directive := cobra.ShellCompDirectiveNoFileComp
+	for _, completion := range completions {
+		if strings.HasSuffix(completion, vault.PathSeparator) {
+			directive |= cobra.ShellCompDirectiveNoSpace
+			break
+		}
+	}
+	return completions, directive
+}
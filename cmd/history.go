@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"pass-cli/internal/vault"
+)
+
+var (
+	historyReveal   bool
+	historySetLimit int
+)
+
+var historyCmd = &cobra.Command{
+	Use:   "history [service]",
+	Short: "Show previous passwords for a credential",
+	Long: `History lists the previous passwords of a credential, newest first.
+
+Every password change keeps the replaced value with the time it was replaced.
+Values are masked by default; use --reveal to display them. Roll back to an
+entry with 'pass-cli update <service> --restore-version N'.
+
+The number of entries kept per credential is a vault-wide retention limit
+(default 10). Change it with --set-limit; 0 disables history and discards
+stored entries.`,
+	Example: `  # List previous passwords (masked)
+  pass-cli history github
+
+  # Show previous password values
+  pass-cli history github --reveal
+
+  # Roll back to the most recent previous password
+  pass-cli update github --restore-version 1
+
+  # Keep at most 5 previous passwords per credential
+  pass-cli history --set-limit 5`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runHistory,
+}
+
+func init() {
+	rootCmd.AddCommand(historyCmd)
+	historyCmd.Flags().BoolVar(&historyReveal, "reveal", false, "display previous password values")
+	historyCmd.Flags().IntVar(&historySetLimit, "set-limit", 0, "set how many previous passwords to keep per credential (0 disables history)")
+}
+
+func runHistory(cmd *cobra.Command, args []string) error {
+	setLimit := cmd.Flags().Changed("set-limit")
+	if len(args) == 0 && !setLimit {
+		return fmt.Errorf("service name is required (or use --set-limit to change retention)")
+	}
+
+	var service string
+	if len(args) == 1 {
+		service = strings.TrimSpace(args[0])
+		if service == "" {
+			return fmt.Errorf("service name cannot be empty")
+		}
+	}
+
+	vaultPath := GetVaultPath()
+
+	// Check if vault exists
+	if _, err := os.Stat(vaultPath); os.IsNotExist(err) {
+		return fmt.Errorf("vault not found at %s\nRun 'pass-cli init' to create a vault first", vaultPath)
+	}
+
+	// Create vault service
+	vaultService, err := vault.New(vaultPath)
+	if err != nil {
+		return fmt.Errorf("failed to create vault service: %w", err)
+	}
+
+	// Unlock vault
+	if err := unlockVault(vaultService); err != nil {
+		return err
+	}
+	defer vaultService.Lock()
+
+	if setLimit {
+		if err := vaultService.SetPasswordHistoryLimit(historySetLimit); err != nil {
+			return fmt.Errorf("failed to set history limit: %w", err)
+		}
+		if historySetLimit == 0 {
+			fmt.Println("✅ Password history disabled")
+		} else {
+			fmt.Printf("✅ Password history limit set to %d\n", historySetLimit)
+		}
+		if service == "" {
+			return nil
+		}
+		fmt.Println()
+	}
+
+	history, err := vaultService.GetPasswordHistory(service)
+	if err != nil {
+		return fmt.Errorf("failed to get password history: %w", err)
+	}
+
+	if len(history) == 0 {
+		fmt.Printf("No password history for %s\n", service)
+		return nil
+	}
+
+	fmt.Printf("📜 Password history for %s\n\n", service)
+
+	table := tablewriter.NewWriter(os.Stdout)
+	var data [][]string
+	for i, entry := range history {
+		password := "********"
+		if historyReveal {
+			password = string(entry.Password)
+		}
+		data = append(data, []string{
+			fmt.Sprintf("%d", i+1),
+			entry.ChangedAt.Format("2006-01-02 15:04:05"),
+			formatRelativeTime(entry.ChangedAt),
+			password,
+		})
+	}
+	table.Header([]string{"Version", "Replaced", "Age", "Password"})
+	_ = table.Bulk(data)
+	_ = table.Render()
+
+	fmt.Printf("\nKept: %d of %d\n", len(history), vaultService.GetPasswordHistoryLimit())
+
+	if historyReveal {
+		// Track history access (revealing old values = usage)
+		if err := vaultService.RecordFieldAccess(service, "password_history"); err != nil {
+			// Log warning but don't fail the operation
+			fmt.Fprintf(os.Stderr, "Warning: failed to track history access: %v\n", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,220 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"pass-cli/internal/crypto"
+	"pass-cli/internal/importer"
+	"pass-cli/internal/vault"
+)
+
+var (
+	importFrom       string
+	importDuplicates string
+	importDryRun     bool
+	importGPG        string
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import --from <format> [path]",
+	Short: "Import credentials from another password manager",
+	Long: `Import reads an export from another password manager and adds its entries
+to the vault in a single save.
+
+Supported formats (--from):
+
+  bitwarden-json   unencrypted Bitwarden JSON export
+  1password-csv    1Password CSV export
+  keepass-csv      KeePassXC / KeePass 2 CSV export
+  kdbx             KeePass KDBX 4 database (.kdbx); you are asked for the
+                   database password (key files are not supported)
+  lastpass-csv     LastPass CSV export
+  password-store   a pass(1) store directory (default ~/.password-store);
+                   entries are decrypted with gpg, so gpg-agent asks for
+                   your passphrase if needed
+  bundle           encrypted bundle from "pass-cli export --format bundle";
+                   you are asked for the bundle passphrase
+
+Folders, groups and groupings become categories, the first URL becomes the
+credential URL, notes are kept, TOTP secrets are imported, and any extra
+fields become custom fields.
+
+When a service name already exists in the vault, --duplicates decides:
+
+  skip       keep the existing credential (default)
+  overwrite  replace its fields; the old password goes to the history
+  rename     import under a new name, e.g. github-2
+
+Entries repeated within the export itself are always renamed. Use --dry-run
+to preview the result without changing the vault.
+
+⚠️  Delete plaintext export files (everything except password-store, kdbx,
+and bundle) after importing: they contain your passwords unencrypted.`,
+	Example: `  # Preview a Bitwarden import
+  pass-cli import --from bitwarden-json bitwarden_export.json --dry-run
+
+  # Import a 1Password export, replacing existing credentials
+  pass-cli import --from 1password-csv export.csv --duplicates overwrite
+
+  # Import a KeePassXC database
+  pass-cli import --from kdbx Passwords.kdbx
+
+  # Import the default password store
+  pass-cli import --from password-store`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runImport,
+}
+
+func init() {
+	rootCmd.AddCommand(importCmd)
+	importCmd.Flags().StringVar(&importFrom, "from", "", "export format: bitwarden-json, 1password-csv, keepass-csv, kdbx, lastpass-csv, password-store, bundle")
+	importCmd.Flags().StringVar(&importDuplicates, "duplicates", string(vault.DuplicateSkip), "existing service names: skip, overwrite, rename")
+	importCmd.Flags().BoolVar(&importDryRun, "dry-run", false, "show what would be imported without changing the vault")
+	importCmd.Flags().StringVar(&importGPG, "gpg", "gpg", "gpg executable used for password-store imports")
+	_ = importCmd.MarkFlagRequired("from")
+
+	_ = importCmd.RegisterFlagCompletionFunc("from", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		var formats []string
+		for _, f := range importer.Formats {
+			formats = append(formats, string(f))
+		}
+		return formats, cobra.ShellCompDirectiveNoFileComp
+	})
+	_ = importCmd.RegisterFlagCompletionFunc("duplicates", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		var modes []string
+		for _, m := range vault.DuplicateModes {
+			modes = append(modes, string(m))
+		}
+		return modes, cobra.ShellCompDirectiveNoFileComp
+	})
+}
+
+func runImport(cmd *cobra.Command, args []string) error {
+	format, err := importer.ParseFormat(importFrom)
+	if err != nil {
+		return err
+	}
+	duplicates := vault.DuplicateMode(strings.ToLower(strings.TrimSpace(importDuplicates)))
+
+	var path string
+	switch {
+	case len(args) == 1:
+		path = args[0]
+	case format == importer.FormatPasswordStore:
+		if path, err = importer.DefaultPasswordStoreDir(); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("%s imports need the path of the export file", format)
+	}
+
+	vaultService, err := openUnlockedVault()
+	if err != nil {
+		return err
+	}
+	defer vaultService.Lock()
+
+	opts := importer.Options{GPGBinary: importGPG}
+	prompts := map[importer.Format]string{
+		importer.FormatBundle: "Bundle passphrase: ",
+		importer.FormatKDBX:   "KeePass database password: ",
+	}
+	if prompt, ok := prompts[format]; ok {
+		fmt.Print(prompt)
+		passphrase, err := readPassword()
+		if err != nil {
+			return fmt.Errorf("failed to read passphrase: %w", err)
+		}
+		defer crypto.ClearBytes(passphrase)
+		fmt.Println() // newline after password input
+		opts.Passphrase = passphrase
+	}
+
+	entries, err := importer.Read(format, path, opts)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	defer vault.ClearImportEntries(entries)
+
+	if len(entries) == 0 {
+		fmt.Printf("No credentials found in %s\n", path)
+		return nil
+	}
+
+	report, err := vaultService.ImportCredentials(entries, vault.ImportOptions{
+		Duplicates: duplicates,
+		DryRun:     importDryRun,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to import: %w", err)
+	}
+
+	printImportReport(report, importDryRun, format.Plaintext())
+	return nil
+}
+
+// printImportReport prints a preview table (dry run) or the changes worth a
+// closer look, followed by a summary of the import. plaintextSource adds a
+// reminder to delete the file that was imported.
+func printImportReport(report *vault.ImportReport, dryRun, plaintextSource bool) {
+	fmt.Println()
+	if dryRun {
+		fmt.Println("🔍 Dry run: the vault was not changed")
+		fmt.Println()
+
+		var data [][]string
+		for _, result := range report.Results {
+			detail := ""
+			switch {
+			case result.Err != nil:
+				detail = result.Err.Error()
+			case result.Service != result.Source:
+				detail = "from " + result.Source
+			}
+			data = append(data, []string{string(result.Action), result.Service, detail})
+		}
+
+		table := tablewriter.NewWriter(os.Stdout)
+		table.Header([]string{"Action", "Service", "Details"})
+		_ = table.Bulk(data)
+		_ = table.Render()
+	} else {
+		for _, result := range report.Results {
+			switch result.Action {
+			case vault.ImportOverwritten:
+				fmt.Printf("  ~ %s (overwritten)\n", result.Service)
+			case vault.ImportRenamed:
+				fmt.Printf("  + %s (renamed from %s)\n", result.Service, result.Source)
+			case vault.ImportFailed:
+				fmt.Printf("  ❌ %s: %v\n", displayImportSource(result.Source), result.Err)
+			}
+		}
+	}
+
+	fmt.Printf("\n%d added, %d renamed, %d overwritten, %d skipped, %d failed\n",
+		report.Count(vault.ImportAdded), report.Count(vault.ImportRenamed), report.Count(vault.ImportOverwritten),
+		report.Count(vault.ImportSkipped), report.Count(vault.ImportFailed))
+
+	if report.Count(vault.ImportSkipped) > 0 {
+		fmt.Println("💡 Skipped entries already exist; use --duplicates overwrite or rename to import them")
+	}
+	if !dryRun {
+		fmt.Println("✅ Import complete")
+		if plaintextSource {
+			fmt.Println("⚠️  Remember to delete the export file: it contains your passwords in plaintext")
+		}
+	}
+}
+
+// displayImportSource names an entry in error messages, even when it had no name
+func displayImportSource(source string) string {
+	if source == "" {
+		return "(unnamed entry)"
+	}
+	return source
+}
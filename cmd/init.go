@@ -6,13 +6,15 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"pass-cli/internal/crypto"
 	"pass-cli/internal/security"
 	"pass-cli/internal/vault"
 )
 
 var (
-	useKeychain  bool
-	enableAudit  bool   // T073: Flag to enable audit logging (FR-025)
+	useKeychain      bool
+	enableAudit      bool // T073: Flag to enable audit logging (FR-025)
+	initKDFBenchmark string
 )
 
 var initCmd = &cobra.Command{
@@ -27,7 +29,16 @@ By default, your vault will be stored at ~/.pass-cli/vault.enc
 
 Use the --use-keychain flag to store the master password in your system's
 keychain (Windows Credential Manager, macOS Keychain, or Linux Secret Service)
-so you don't have to enter it every time.`,
+so you don't have to enter it every time.
+
+With --key-file the vault is bound to a key file: unlocking needs both the
+master password and the file, so a leaked password alone is not enough. The
+file is created with random contents if it does not exist; keep it away from
+the vault, e.g. on a USB drive.
+
+The master password is stretched with Argon2id (64 MiB, 3 passes, 4 threads).
+Use --kdf-benchmark to calibrate the cost so unlocking takes about the given
+time on this machine instead.`,
 	Example: `  # Initialize a new vault
   pass-cli init
 
@@ -35,7 +46,13 @@ so you don't have to enter it every time.`,
   pass-cli init --use-keychain
 
   # Initialize with custom vault location
-  pass-cli init --vault /path/to/vault.enc`,
+  pass-cli init --vault /path/to/vault.enc
+
+  # Require a key file on a USB drive in addition to the password
+  pass-cli init --key-file /media/usb/vault.key
+
+  # Calibrate key derivation for a 2 second unlock
+  pass-cli init --kdf-benchmark 2s`,
 	RunE: runInit,
 }
 
@@ -44,6 +61,7 @@ func init() {
 	initCmd.Flags().BoolVar(&useKeychain, "use-keychain", false, "store master password in system keychain")
 	// T073: Add --enable-audit flag (FR-025: opt-in per FR-025)
 	initCmd.Flags().BoolVar(&enableAudit, "enable-audit", false, "enable tamper-evident audit logging for vault operations")
+	addKDFBenchmarkFlag(initCmd, &initKDFBenchmark)
 }
 
 func runInit(cmd *cobra.Command, args []string) error {
@@ -53,6 +71,10 @@ func runInit(cmd *cobra.Command, args []string) error {
 	if _, err := os.Stat(vaultPath); err == nil {
 		return fmt.Errorf("vault already exists at %s\nUse a different location with --vault flag", vaultPath)
 	}
+	benchmark, err := parseKDFBenchmark(initKDFBenchmark)
+	if err != nil {
+		return err
+	}
 
 	fmt.Println("🔐 Initializing new password vault")
 	fmt.Printf("📁 Vault location: %s\n\n", vaultPath)
@@ -102,6 +124,18 @@ func runInit(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to create vault service: %w", err)
 	}
 
+	// Bind the vault to a key file if requested. Only the flag counts here: the
+	// config default must not silently add a second factor to new vaults.
+	if keyFile != "" {
+		if _, err := os.Stat(keyFile); os.IsNotExist(err) {
+			if err := vault.CreateKeyFile(keyFile); err != nil {
+				return err
+			}
+			fmt.Printf("🔑 Created key file: %s\n", keyFile)
+		}
+		vaultService.SetKeyFile(keyFile)
+	}
+
 	// T073/DISC-013 fix: Prepare audit parameters if requested
 	var auditLogPath, vaultID string
 	if enableAudit {
@@ -109,8 +143,16 @@ func runInit(cmd *cobra.Command, args []string) error {
 		vaultID = getVaultID(vaultPath)
 	}
 
+	// Calibrate key derivation if requested, otherwise use the defaults
+	kdfParams := crypto.DefaultKDFParams()
+	if params, err := benchmarkKDF(benchmark); err != nil {
+		return err
+	} else if params != nil {
+		kdfParams = *params
+	}
+
 	// Initialize vault (with audit config if requested)
-	if err := vaultService.Initialize(password, useKeychain, auditLogPath, vaultID); err != nil {
+	if err := vaultService.InitializeWithKDF(password, useKeychain, auditLogPath, vaultID, kdfParams); err != nil {
 		return fmt.Errorf("failed to initialize vault: %w", err)
 	}
 
@@ -122,6 +164,11 @@ func runInit(cmd *cobra.Command, args []string) error {
 	// Success message
 	fmt.Println("✅ Vault initialized successfully!")
 	fmt.Printf("📍 Location: %s\n", vaultPath)
+	fmt.Printf("🔑 Key derivation: %s\n", kdfParams)
+	if keyFile != "" {
+		fmt.Printf("🔑 Key file required: %s\n", keyFile)
+		fmt.Println("⚠️  Without the key file the vault cannot be unlocked - keep a backup copy")
+	}
 
 	if useKeychain {
 		fmt.Println("🔑 Master password stored in system keychain")
@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"pass-cli/internal/crypto"
+)
+
+var upgradeKDFBenchmark string
+
+var vaultUpgradeKDFCmd = &cobra.Command{
+	Use:   "upgrade-kdf",
+	Short: "Re-encrypt the vault with Argon2id key derivation",
+	Long: `Upgrade-kdf re-derives the vault key with Argon2id, keeping your master
+password. Vaults created before Argon2id support use PBKDF2-SHA256; they keep
+unlocking, but Argon2id is memory-hard and far more costly to attack with GPUs.
+
+Without --kdf-benchmark the default parameters are used (64 MiB, 3 passes,
+4 threads). With --kdf-benchmark the parameters are calibrated so unlocking
+takes about the given time on this machine; use it to retune a vault that
+already uses Argon2id. Unlocking on slower machines takes proportionally longer.`,
+	Example: `  # Upgrade a PBKDF2 vault to Argon2id
+  pass-cli vault upgrade-kdf
+
+  # Calibrate for a 2 second unlock on this machine
+  pass-cli vault upgrade-kdf --kdf-benchmark 2s`,
+	Args: cobra.NoArgs,
+	RunE: runVaultUpgradeKDF,
+}
+
+func init() {
+	vaultCmd.AddCommand(vaultUpgradeKDFCmd)
+	addKDFBenchmarkFlag(vaultUpgradeKDFCmd, &upgradeKDFBenchmark)
+}
+
+// addKDFBenchmarkFlag registers --kdf-benchmark on a command that derives a new vault key
+func addKDFBenchmarkFlag(cmd *cobra.Command, target *string) {
+	cmd.Flags().StringVar(target, "kdf-benchmark", "", "calibrate Argon2id to unlock in about this long on this machine (e.g., 500ms, 1s, 2s)")
+}
+
+// parseKDFBenchmark validates a --kdf-benchmark value; 0 means not requested
+func parseKDFBenchmark(value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+	target, err := time.ParseDuration(value)
+	if err != nil || target <= 0 {
+		return 0, fmt.Errorf("invalid --kdf-benchmark value: %s (e.g., 500ms, 1s, 2s)", value)
+	}
+	return target, nil
+}
+
+// benchmarkKDF calibrates Argon2id parameters for the target unlock time, or
+// returns nil when no benchmark was requested
+func benchmarkKDF(target time.Duration) (*crypto.KDFParams, error) {
+	if target == 0 {
+		return nil, nil
+	}
+
+	fmt.Printf("⏱️  Calibrating key derivation for a %s unlock...\n", target)
+	params, err := crypto.NewCryptoService().BenchmarkKDF(target)
+	if err != nil {
+		return nil, fmt.Errorf("failed to benchmark key derivation: %w", err)
+	}
+	fmt.Printf("   Selected %s\n", params)
+	return &params, nil
+}
+
+func runVaultUpgradeKDF(cmd *cobra.Command, args []string) error {
+	target, err := parseKDFBenchmark(upgradeKDFBenchmark)
+	if err != nil {
+		return err
+	}
+
+	vaultService, err := openUnlockedVault()
+	if err != nil {
+		return err
+	}
+	defer vaultService.Lock()
+
+	current, err := vaultService.KDFParams()
+	if err != nil {
+		return fmt.Errorf("failed to read key derivation parameters: %w", err)
+	}
+
+	params, err := benchmarkKDF(target)
+	if err != nil {
+		return err
+	}
+	if params == nil {
+		if current.Algorithm == crypto.KDFArgon2id {
+			fmt.Printf("✅ Vault already uses %s\n", current)
+			fmt.Println("💡 Use --kdf-benchmark to recalibrate for this machine")
+			return nil
+		}
+		defaults := crypto.DefaultKDFParams()
+		params = &defaults
+	}
+
+	if err := vaultService.UpgradeKDF(*params); err != nil {
+		return fmt.Errorf("failed to upgrade key derivation: %w", err)
+	}
+
+	fmt.Println("✅ Key derivation upgraded")
+	fmt.Printf("   From: %s\n", current)
+	fmt.Printf("   To:   %s\n", params)
+	if current.Algorithm != crypto.KDFArgon2id {
+		fmt.Println("⚠️  Older pass-cli versions cannot unlock Argon2id vaults")
+	}
+	return nil
+}
@@ -0,0 +1,387 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"pass-cli/internal/crypto"
+	"pass-cli/internal/security"
+	"pass-cli/internal/shamir"
+	"pass-cli/internal/storage"
+	"pass-cli/internal/vault"
+)
+
+var (
+	keyslotAddKeyFile   string
+	keyslotAddRecovery  bool
+	keyslotAddLabel     string
+	keyslotAddBenchmark string
+	keyslotRemoveForce  bool
+)
+
+var keyslotCmd = &cobra.Command{
+	Use:   "keyslot",
+	Short: "Manage the ways to unlock the vault",
+	Long: `The vault is encrypted with a random data key. Each key slot wraps that key
+under one secret: a master password, a key file, or a recovery code. Any slot
+can unlock the vault. Adding a slot never re-encrypts your credentials.
+
+Removing a slot replaces the data key and re-encrypts the vault and its
+snapshots, so copies of the vault from before the removal do not open with the
+removed secret either. The other slots are kept if you enter their secrets when
+asked; slots left blank are removed too.
+
+change-password rewrites only the password slot you unlocked with; other
+slots keep working.`,
+}
+
+var keyslotListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List key slots",
+	Long: `List shows the vault's key slots. Slots are read from the vault header, so
+no password is needed.`,
+	Args: cobra.NoArgs,
+	RunE: runKeyslotList,
+}
+
+var keyslotAddCmd = &cobra.Command{
+	Use:   "add",
+	Short: "Add a way to unlock the vault",
+	Long: `Add creates a key slot. By default it asks for an additional password,
+which must meet the same requirements as the master password.
+
+With --key-file the contents of a file unlock the vault; the file is created
+with random contents if it does not exist. Unlock with it using the global
+--key-file flag. Keep the file away from the vault, e.g. on a USB drive.
+
+With --recovery a recovery code is generated and shown once. Type it at the
+master password prompt to unlock the vault, then run change-password.`,
+	Example: `  # Add a second password
+  pass-cli keyslot add --label laptop
+
+  # Add a key file on a USB drive
+  pass-cli keyslot add --key-file /media/usb/vault.key
+
+  # Generate a recovery code to print and store safely
+  pass-cli keyslot add --recovery --label "safe deposit box"`,
+	Args: cobra.NoArgs,
+	RunE: runKeyslotAdd,
+}
+
+var keyslotRemoveCmd = &cobra.Command{
+	Use:     "remove <id>",
+	Aliases: []string{"rm"},
+	Short:   "Remove a key slot",
+	Long: `Remove stops a key slot from unlocking the vault. The last key slot cannot be
+removed.
+
+The vault is then re-encrypted with a new data key, snapshots are re-encrypted
+or deleted, and the backup file is deleted, so no copy left on disk opens with
+the removed secret. Other slots are kept if you enter their secret (password,
+recovery code, key file path, or recovery shares) when asked; slots left blank
+are removed as well. The slot you unlocked with is kept without asking.`,
+	Example: `  # Remove slot 3 without confirmation
+  pass-cli keyslot remove 3 --force`,
+	Args: cobra.ExactArgs(1),
+	RunE: runKeyslotRemove,
+}
+
+func init() {
+	rootCmd.AddCommand(keyslotCmd)
+	keyslotCmd.AddCommand(keyslotListCmd)
+	keyslotCmd.AddCommand(keyslotAddCmd)
+	keyslotCmd.AddCommand(keyslotRemoveCmd)
+
+	keyslotAddCmd.Flags().StringVar(&keyslotAddKeyFile, "key-file", "", "add a key file slot, creating the file if it does not exist")
+	keyslotAddCmd.Flags().BoolVar(&keyslotAddRecovery, "recovery", false, "add a recovery code slot")
+	keyslotAddCmd.Flags().StringVar(&keyslotAddLabel, "label", "", "description shown by 'keyslot list'")
+	addKDFBenchmarkFlag(keyslotAddCmd, &keyslotAddBenchmark)
+	keyslotAddCmd.MarkFlagsMutuallyExclusive("key-file", "recovery")
+	keyslotRemoveCmd.Flags().BoolVarP(&keyslotRemoveForce, "force", "f", false, "skip confirmation prompt")
+}
+
+func runKeyslotList(cmd *cobra.Command, args []string) error {
+	vaultService, err := openVaultService()
+	if err != nil {
+		return err
+	}
+
+	slots, err := vaultService.ListKeySlots()
+	if err != nil {
+		return fmt.Errorf("failed to list key slots: %w", err)
+	}
+
+	if len(slots) == 0 {
+		fmt.Println("This vault has no key slots; it is unlocked with the master password only.")
+		fmt.Println("💡 Run 'pass-cli keyslot add' or 'pass-cli change-password' to convert it")
+		return nil
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	var data [][]string
+	for _, slot := range slots {
+		data = append(data, []string{
+			strconv.Itoa(slot.ID),
+			slot.Type,
+			slot.Label,
+			slot.KDFParams().String(),
+			formatRelativeTime(slot.CreatedAt),
+		})
+	}
+	table.Header([]string{"ID", "Type", "Label", "Key Derivation", "Created"})
+	_ = table.Bulk(data)
+	_ = table.Render()
+
+	fmt.Printf("\nTotal: %d key slot(s)\n", len(slots))
+	return nil
+}
+
+func runKeyslotAdd(cmd *cobra.Command, args []string) error {
+	benchmark, err := parseKDFBenchmark(keyslotAddBenchmark)
+	if err != nil {
+		return err
+	}
+
+	vaultService, err := openUnlockedVault()
+	if err != nil {
+		return err
+	}
+	defer vaultService.Lock()
+
+	params := crypto.DefaultKDFParams()
+	if benchmarked, err := benchmarkKDF(benchmark); err != nil {
+		return err
+	} else if benchmarked != nil {
+		params = *benchmarked
+	}
+
+	switch {
+	case keyslotAddKeyFile != "":
+		if _, err := os.Stat(keyslotAddKeyFile); os.IsNotExist(err) {
+			if err := vault.CreateKeyFile(keyslotAddKeyFile); err != nil {
+				return err
+			}
+			fmt.Printf("🔑 Created key file: %s\n", keyslotAddKeyFile)
+		}
+		id, err := vaultService.AddKeyFileSlot(keyslotAddKeyFile, keyslotAddLabel, params)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("✅ Added key file slot %d\n", id)
+		fmt.Println("💡 Unlock with: pass-cli --key-file " + keyslotAddKeyFile + " <command>")
+		fmt.Println("⚠️  Anyone with a copy of the key file can unlock the vault")
+
+	case keyslotAddRecovery:
+		code, id, err := vaultService.AddRecoverySlot(keyslotAddLabel, params)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("✅ Added recovery slot %d\n", id)
+		fmt.Printf("\n   %s\n\n", code)
+		fmt.Println("⚠️  Write this code down and store it safely - it is shown only once")
+		fmt.Println("💡 Type it at the master password prompt, then run 'pass-cli change-password'")
+
+	default:
+		password, err := readNewSlotPassword()
+		if err != nil {
+			return err
+		}
+		id, err := vaultService.AddPasswordSlot(password, keyslotAddLabel, params)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("✅ Added password slot %d\n", id)
+	}
+
+	fmt.Printf("🔑 Key derivation: %s\n", params)
+	return nil
+}
+
+// readNewSlotPassword prompts for an additional password and its confirmation
+func readNewSlotPassword() ([]byte, error) {
+	fmt.Print("Enter new password (min 12 characters with uppercase, lowercase, digit, symbol): ")
+	password, err := readPassword()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read password: %w", err)
+	}
+	fmt.Println() // newline after password input
+
+	policy := &security.PasswordPolicy{
+		MinLength:        12,
+		RequireUppercase: true,
+		RequireLowercase: true,
+		RequireDigit:     true,
+		RequireSymbol:    true,
+	}
+	if err := policy.Validate(password); err != nil {
+		crypto.ClearBytes(password)
+		return nil, fmt.Errorf("password does not meet requirements: %w", err)
+	}
+
+	fmt.Print("Confirm password: ")
+	confirm, err := readPassword()
+	if err != nil {
+		crypto.ClearBytes(password)
+		return nil, fmt.Errorf("failed to read confirmation password: %w", err)
+	}
+	defer crypto.ClearBytes(confirm)
+	fmt.Println() // newline after password input
+
+	if string(password) != string(confirm) {
+		crypto.ClearBytes(password)
+		return nil, fmt.Errorf("passwords do not match")
+	}
+	return password, nil
+}
+
+func runKeyslotRemove(cmd *cobra.Command, args []string) error {
+	id, err := strconv.Atoi(args[0])
+	if err != nil || id <= 0 {
+		return fmt.Errorf("invalid key slot ID: %s", args[0])
+	}
+
+	vaultService, err := openUnlockedVault()
+	if err != nil {
+		return err
+	}
+	defer vaultService.Lock()
+
+	if !keyslotRemoveForce {
+		fmt.Printf("⚠️  Remove key slot %d? It will no longer unlock the vault. (y/N): ", id)
+		var confirm string
+		_, _ = fmt.Scanln(&confirm)
+		confirm = strings.ToLower(strings.TrimSpace(confirm))
+		if confirm != "y" && confirm != "yes" {
+			fmt.Println("Removal cancelled.")
+			return nil
+		}
+	}
+
+	secrets, ok, err := readKeptSlotSecrets(vaultService, keyslotRemoveForce, id, vaultService.UnlockedSlot())
+	if err != nil || !ok {
+		return err
+	}
+	defer secrets.Clear()
+
+	if err := vaultService.RemoveKeySlot(id, secrets); err != nil {
+		if errors.Is(err, storage.ErrLastKeySlot) {
+			return fmt.Errorf("%w; add another slot first", err)
+		}
+		return fmt.Errorf("failed to remove key slot: %w", err)
+	}
+
+	fmt.Printf("✅ Removed key slot %d\n", id)
+	fmt.Println("🔑 The vault and its snapshots were re-encrypted with a new data key")
+	return nil
+}
+
+// readKeptSlotSecrets prepares a rekey, which replaces the data key and keeps
+// only the slots whose secret is known. It asks for the secret of each slot
+// except skip; slots left blank are removed, after confirmation unless force.
+// Reports false if the user cancelled.
+func readKeptSlotSecrets(vaultService *vault.VaultService, force bool, skip ...int) (vault.SlotSecrets, bool, error) {
+	slots, err := vaultService.ListKeySlots()
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to list key slots: %w", err)
+	}
+
+	secrets := vault.SlotSecrets{}
+	var removed []string
+	for _, slot := range slots {
+		if slices.Contains(skip, slot.ID) {
+			continue
+		}
+		if len(secrets) == 0 && len(removed) == 0 {
+			fmt.Println("🔑 The data key will be replaced. Enter the secret of each key slot to keep,")
+			fmt.Println("   or leave it blank to remove the slot.")
+		}
+		for {
+			input, err := readSlotInput(slot)
+			if err != nil {
+				secrets.Clear()
+				return nil, false, err
+			}
+			if len(input) == 0 {
+				removed = append(removed, strconv.Itoa(slot.ID))
+				break
+			}
+			secret, err := vaultService.KeySlotSecret(slot.ID, input)
+			crypto.ClearBytes(input)
+			if err != nil {
+				fmt.Printf("❌ %v - try again\n", err)
+				continue
+			}
+			secrets[slot.ID] = secret
+			break
+		}
+	}
+
+	if len(removed) > 0 && !force {
+		fmt.Printf("⚠️  Key slot(s) %s will be removed as well. Continue? (y/N): ", strings.Join(removed, ", "))
+		var confirm string
+		_, _ = fmt.Scanln(&confirm)
+		confirm = strings.ToLower(strings.TrimSpace(confirm))
+		if confirm != "y" && confirm != "yes" {
+			secrets.Clear()
+			fmt.Println("Cancelled.")
+			return nil, false, nil
+		}
+	}
+	return secrets, true, nil
+}
+
+// readSlotInput prompts for what unlocks a key slot: a password, a recovery
+// code, a key file path, or recovery shares. Empty input means "remove".
+func readSlotInput(slot storage.KeySlot) ([]byte, error) {
+	name := slot.Type
+	if slot.Label != "" {
+		name += ", " + slot.Label
+	}
+
+	switch slot.Type {
+	case storage.KeySlotKeyFile, storage.KeySlotRecovery:
+		what := "key file path"
+		if slot.Type == storage.KeySlotRecovery {
+			what = "recovery code"
+		}
+		fmt.Printf("Slot %d (%s) %s: ", slot.ID, name, what)
+		line, err := readLine()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", what, err)
+		}
+		return []byte(line), nil
+
+	case storage.KeySlotShares:
+		fmt.Printf("Slot %d (%s): keep it by entering its shares? (y/N): ", slot.ID, name)
+		line, err := readLine()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read answer: %w", err)
+		}
+		if answer := strings.ToLower(line); answer != "y" && answer != "yes" {
+			return nil, nil
+		}
+		shares, err := readRecoveryShares()
+		if err != nil {
+			return nil, err
+		}
+		return shamir.Combine(shares)
+
+	default:
+		fmt.Printf("Slot %d (%s) password: ", slot.ID, name)
+		password, err := readPassword()
+		fmt.Println() // newline after password input
+		if (err != nil && len(password) > 0) || errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("failed to read password: %w", err)
+		}
+		return password, nil
+	}
+}
@@ -11,20 +11,29 @@ import (
 	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
 
+	"pass-cli/internal/query"
 	"pass-cli/internal/vault"
 )
 
 var (
-	listFormat string
-	listUnused bool
-	listDays   int
+	listFormat  string
+	listUnused  bool
+	listDays    int
+	listTags    []string
+	listTagMode string
+	listType    string
+	listTree    bool
+	listQuery   string
 )
 
 var listCmd = &cobra.Command{
-	Use:   "list",
+	Use:   "list [path]",
 	Short: "List all credentials in the vault",
 	Long: `List displays all stored credentials with metadata.
 
+Service names can be hierarchical paths such as work/aws/prod/deploy-bot.
+Pass a path to list only that subtree, and use --tree to show the hierarchy.
+
 Output formats:
   table    Display as formatted table (default)
   json     Output as JSON array
@@ -32,7 +41,20 @@ Output formats:
 
 The --unused flag filters credentials that haven't been accessed recently
 or have never been accessed. Use --days to configure the threshold
-(default: 30 days).`,
+(default: 30 days).
+
+The --tag flag filters by tags. With several tags, --tag-mode and (default)
+requires every tag, while --tag-mode or accepts credentials with any of them.
+
+The --type flag shows only credentials of one type (login, api-key, ssh-key,
+database, secure-note).
+
+The --query flag filters with a search expression: bare words match the
+service, username, URL or category; field selectors such as user:alice,
+cat:db or url:*.internal narrow by one field; created, updated and used take
+dates or ages (created>2024-01-01, used>90d); uses compares the usage count
+(uses<3). Combine terms with and, or, not and parentheses; terms next to each
+other must all match.`,
 	Example: `  # List all credentials as table
   pass-cli list
 
@@ -46,8 +68,31 @@ or have never been accessed. Use --days to configure the threshold
   pass-cli list --unused
 
   # Show credentials unused for >90 days
-  pass-cli list --unused --days 90`,
-	RunE: runList,
+  pass-cli list --unused --days 90
+
+  # Show credentials tagged both prod and aws
+  pass-cli list --tag prod,aws
+
+  # Show credentials tagged prod or staging
+  pass-cli list --tag prod,staging --tag-mode or
+
+  # Show database credentials
+  pass-cli list --type database
+
+  # Show database credentials not used in 90 days
+  pass-cli list --query 'cat:db used>90d'
+
+  # Show internal admin logins used at most twice
+  pass-cli list --query 'url:*.internal and (user:admin or user:root) and uses<=2'
+
+  # Show everything under work/aws
+  pass-cli list work/aws
+
+  # Show the folder hierarchy
+  pass-cli list --tree`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeServicePaths,
+	RunE:              runList,
 }
 
 func init() {
@@ -55,9 +100,50 @@ func init() {
 	listCmd.Flags().StringVarP(&listFormat, "format", "f", "table", "output format: table, json, simple")
 	listCmd.Flags().BoolVar(&listUnused, "unused", false, "show only unused or rarely used credentials")
 	listCmd.Flags().IntVar(&listDays, "days", 30, "days threshold for --unused flag")
+	listCmd.Flags().StringSliceVarP(&listTags, "tag", "t", nil, "filter by tags (comma-separated or repeatable)")
+	listCmd.Flags().StringVar(&listTagMode, "tag-mode", "and", "how multiple tags combine: and, or")
+	listCmd.Flags().StringVar(&listType, "type", "", "filter by credential type: login, api-key, ssh-key, database, secure-note")
+	listCmd.Flags().BoolVar(&listTree, "tree", false, "show service paths as a folder tree")
+	listCmd.Flags().StringVar(&listQuery, "query", "", "filter with a search expression (e.g. 'cat:db used>90d')")
 }
 
 func runList(cmd *cobra.Command, args []string) error {
+	// Validate tag mode before unlocking so typos fail fast
+	matchAllTags := true
+	switch strings.ToLower(listTagMode) {
+	case "and", "all":
+		matchAllTags = true
+	case "or", "any":
+		matchAllTags = false
+	default:
+		return fmt.Errorf("invalid tag mode: %s (valid: and, or)", listTagMode)
+	}
+
+	var credType vault.CredentialType
+	if listType != "" {
+		var err error
+		if credType, err = vault.ParseCredentialType(listType); err != nil {
+			return err
+		}
+	}
+
+	var q *query.Query
+	if listQuery != "" {
+		var err error
+		if q, err = query.Parse(listQuery); err != nil {
+			return err
+		}
+	}
+
+	if listTree && strings.ToLower(listFormat) != "table" {
+		return fmt.Errorf("--tree cannot be combined with --format %s", listFormat)
+	}
+
+	path := ""
+	if len(args) > 0 {
+		path = vault.NormalizeServicePath(args[0])
+	}
+
 	vaultPath := GetVaultPath()
 
 	// Check if vault exists
@@ -83,11 +169,31 @@ func runList(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to list credentials: %w", err)
 	}
 
+	// Filter by path if requested
+	if path != "" {
+		metadata = filterByPath(metadata, path)
+	}
+
 	// Filter for unused if requested
 	if listUnused {
 		metadata = filterUnused(metadata, listDays)
 	}
 
+	// Filter by tags if requested
+	if len(listTags) > 0 {
+		metadata = filterByTags(metadata, listTags, matchAllTags)
+	}
+
+	// Filter by type if requested
+	if credType != "" {
+		metadata = filterByType(metadata, credType)
+	}
+
+	// Filter by query if requested
+	if q != nil {
+		metadata = filterByQuery(metadata, q)
+	}
+
 	// Sort by service name
 	sort.Slice(metadata, func(i, j int) bool {
 		return metadata[i].Service < metadata[j].Service
@@ -100,6 +206,9 @@ func runList(cmd *cobra.Command, args []string) error {
 	case "simple":
 		return outputSimple(metadata)
 	case "table":
+		if listTree {
+			return outputTree(metadata)
+		}
 		return outputTable(metadata)
 	default:
 		return fmt.Errorf("invalid format: %s (valid: table, json, simple)", listFormat)
@@ -120,6 +229,54 @@ func filterUnused(metadata []vault.CredentialMetadata, days int) []vault.Credent
 	return filtered
 }
 
+func filterByQuery(metadata []vault.CredentialMetadata, q *query.Query) []vault.CredentialMetadata {
+	filtered := make([]vault.CredentialMetadata, 0)
+
+	for i := range metadata {
+		if q.Match(&metadata[i]) {
+			filtered = append(filtered, metadata[i])
+		}
+	}
+
+	return filtered
+}
+
+func filterByPath(metadata []vault.CredentialMetadata, path string) []vault.CredentialMetadata {
+	filtered := make([]vault.CredentialMetadata, 0)
+
+	for _, meta := range metadata {
+		if vault.ServiceInPath(meta.Service, path) {
+			filtered = append(filtered, meta)
+		}
+	}
+
+	return filtered
+}
+
+func filterByTags(metadata []vault.CredentialMetadata, tags []string, matchAll bool) []vault.CredentialMetadata {
+	filtered := make([]vault.CredentialMetadata, 0)
+
+	for _, meta := range metadata {
+		if vault.MatchTags(meta.Tags, tags, matchAll) {
+			filtered = append(filtered, meta)
+		}
+	}
+
+	return filtered
+}
+
+func filterByType(metadata []vault.CredentialMetadata, credType vault.CredentialType) []vault.CredentialMetadata {
+	filtered := make([]vault.CredentialMetadata, 0)
+
+	for _, meta := range metadata {
+		if meta.EffectiveType() == credType {
+			filtered = append(filtered, meta)
+		}
+	}
+
+	return filtered
+}
+
 func outputSimple(metadata []vault.CredentialMetadata) error {
 	for _, meta := range metadata {
 		fmt.Println(meta.Service)
@@ -144,8 +301,25 @@ func outputTable(metadata []vault.CredentialMetadata) error {
 
 	table := tablewriter.NewWriter(os.Stdout)
 
+	// Only show the type and tags columns when they carry information
+	showTags, showType := false, false
+	for _, meta := range metadata {
+		if len(meta.Tags) > 0 {
+			showTags = true
+		}
+		if meta.EffectiveType() != vault.TypeLogin {
+			showType = true
+		}
+	}
+
 	// Prepare header
 	header := []string{"Service", "Username", "Usage", "Last Used", "Created"}
+	if showType {
+		header = append(header, "Type")
+	}
+	if showTags {
+		header = append(header, "Tags")
+	}
 
 	// Prepare data rows
 	var data [][]string
@@ -173,13 +347,20 @@ func outputTable(metadata []vault.CredentialMetadata) error {
 			username = username[:27] + "..."
 		}
 
-		data = append(data, []string{
+		row := []string{
 			meta.Service,
 			username,
 			usageStr,
 			lastUsedStr,
 			createdStr,
-		})
+		}
+		if showType {
+			row = append(row, string(meta.EffectiveType()))
+		}
+		if showTags {
+			row = append(row, strings.Join(meta.Tags, ", "))
+		}
+		data = append(data, row)
 	}
 
 	// Set table configuration
@@ -193,6 +374,76 @@ func outputTable(metadata []vault.CredentialMetadata) error {
 	return nil
 }
 
+// serviceTreeNode is a folder or credential in the --tree output
+type serviceTreeNode struct {
+	name       string
+	credential bool // A credential exists at this exact path
+	children   map[string]*serviceTreeNode
+}
+
+// buildServiceTree groups services by path segment
+func buildServiceTree(metadata []vault.CredentialMetadata) *serviceTreeNode {
+	root := &serviceTreeNode{children: make(map[string]*serviceTreeNode)}
+	for _, meta := range metadata {
+		node := root
+		for _, segment := range vault.SplitServicePath(meta.Service) {
+			child, ok := node.children[segment]
+			if !ok {
+				child = &serviceTreeNode{name: segment, children: make(map[string]*serviceTreeNode)}
+				node.children[segment] = child
+			}
+			node = child
+		}
+		node.credential = true
+	}
+	return root
+}
+
+func outputTree(metadata []vault.CredentialMetadata) error {
+	if len(metadata) == 0 {
+		fmt.Println("No credentials found.")
+		return nil
+	}
+
+	printServiceTree(buildServiceTree(metadata), "")
+
+	fmt.Printf("\nTotal: %d credential(s)\n", len(metadata))
+	return nil
+}
+
+// printServiceTree prints folders (with a trailing /) before credentials, each sorted by name
+// A path that is both a credential and a folder is printed once as each
+func printServiceTree(node *serviceTreeNode, indent string) {
+	type entry struct {
+		label string
+		node  *serviceTreeNode
+	}
+
+	var folders, leaves []entry
+	for name, child := range node.children {
+		if len(child.children) > 0 {
+			folders = append(folders, entry{name + vault.PathSeparator, child})
+		}
+		if child.credential {
+			leaves = append(leaves, entry{name, nil})
+		}
+	}
+	sort.Slice(folders, func(i, j int) bool { return folders[i].label < folders[j].label })
+	sort.Slice(leaves, func(i, j int) bool { return leaves[i].label < leaves[j].label })
+	entries := append(folders, leaves...)
+
+	for i, e := range entries {
+		branch, next := "├── ", "│   "
+		if i == len(entries)-1 {
+			branch, next = "└── ", "    "
+		}
+		fmt.Println(indent + branch + e.label)
+		if e.node != nil {
+			printServiceTree(e.node, indent+next)
+		}
+	}
+}
+
 func formatRelativeTime(t time.Time) string {
 	if t.IsZero() {
 		return "Never"
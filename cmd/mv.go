@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var mvCmd = &cobra.Command{
+	Use:     "mv <old-service> <new-service>",
+	Aliases: []string{"rename"},
+	Short:   "Rename a credential",
+	Long: `Mv renames a credential. Usage history, creation time, password history,
+and attachments are kept, so scripts using the new name continue where the
+old name left off.`,
+	Example: `  # Rename a credential
+  pass-cli mv github github-personal`,
+	Args: cobra.ExactArgs(2),
+	RunE: runMv,
+}
+
+var cpCmd = &cobra.Command{
+	Use:     "cp <source-service> <new-service>",
+	Aliases: []string{"copy"},
+	Short:   "Copy a credential under a new name",
+	Long: `Cp duplicates a credential, including its password, custom fields, tags,
+TOTP seed, and attachments. The copy starts with fresh timestamps and no
+usage history.`,
+	Example: `  # Start a staging credential from the production one
+  pass-cli cp aws-prod aws-staging`,
+	Args: cobra.ExactArgs(2),
+	RunE: runCp,
+}
+
+func init() {
+	rootCmd.AddCommand(mvCmd)
+	rootCmd.AddCommand(cpCmd)
+}
+
+func runMv(cmd *cobra.Command, args []string) error {
+	oldService, newService := strings.TrimSpace(args[0]), strings.TrimSpace(args[1])
+
+	vaultService, err := openUnlockedVault()
+	if err != nil {
+		return err
+	}
+	defer vaultService.Lock()
+
+	if err := vaultService.RenameCredential(oldService, newService); err != nil {
+		return fmt.Errorf("failed to rename credential: %w", err)
+	}
+
+	fmt.Printf("✅ Renamed %s to %s\n", oldService, newService)
+	return nil
+}
+
+func runCp(cmd *cobra.Command, args []string) error {
+	srcService, dstService := strings.TrimSpace(args[0]), strings.TrimSpace(args[1])
+
+	vaultService, err := openUnlockedVault()
+	if err != nil {
+		return err
+	}
+	defer vaultService.Lock()
+
+	if err := vaultService.CopyCredential(srcService, dstService); err != nil {
+		return fmt.Errorf("failed to copy credential: %w", err)
+	}
+
+	fmt.Printf("✅ Copied %s to %s\n", srcService, dstService)
+	return nil
+}
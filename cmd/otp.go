@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/atotto/clipboard"
+	"github.com/spf13/cobra"
+
+	"pass-cli/internal/vault"
+)
+
+var (
+	otpQuiet       bool
+	otpNoClipboard bool
+)
+
+var otpCmd = &cobra.Command{
+	Use:   "otp <service>",
+	Short: "Generate the current TOTP code for a credential",
+	Long: `OTP prints the current time-based one-time password (RFC 6238) for a credential
+that has a TOTP seed configured, and copies it to the clipboard.
+
+Configure a seed with 'pass-cli add --totp' or 'pass-cli update --totp' using
+an otpauth:// URI (as encoded in 2FA QR codes) or a raw base32 secret.
+
+  --quiet         Output only the code (for scripts)
+  --no-clipboard  Skip copying to clipboard`,
+	Example: `  # Show the code and copy it to clipboard
+  pass-cli otp github
+
+  # Output only the code (for scripts)
+  pass-cli otp github --quiet
+
+  # Show the code without clipboard
+  pass-cli otp github --no-clipboard`,
+	Args: cobra.ExactArgs(1),
+	RunE: runOTP,
+}
+
+func init() {
+	rootCmd.AddCommand(otpCmd)
+	otpCmd.Flags().BoolVarP(&otpQuiet, "quiet", "q", false, "output only the code (script-friendly)")
+	otpCmd.Flags().BoolVar(&otpNoClipboard, "no-clipboard", false, "do not copy to clipboard")
+}
+
+func runOTP(cmd *cobra.Command, args []string) error {
+	service := strings.TrimSpace(args[0])
+	if service == "" {
+		return fmt.Errorf("service name cannot be empty")
+	}
+
+	vaultPath := GetVaultPath()
+
+	// Check if vault exists
+	if _, err := os.Stat(vaultPath); os.IsNotExist(err) {
+		return fmt.Errorf("vault not found at %s\nRun 'pass-cli init' to create a vault first", vaultPath)
+	}
+
+	// Create vault service
+	vaultService, err := vault.New(vaultPath)
+	if err != nil {
+		return fmt.Errorf("failed to create vault service: %w", err)
+	}
+
+	// Unlock vault
+	if err := unlockVault(vaultService); err != nil {
+		return err
+	}
+	defer vaultService.Lock()
+
+	cred, err := vaultService.GetCredential(service, false)
+	if err != nil {
+		return fmt.Errorf("failed to get credential: %w", err)
+	}
+	if cred.TOTP == nil {
+		return fmt.Errorf("credential %s has no TOTP seed configured\nUse 'pass-cli update %s --totp -' to add one", service, service)
+	}
+
+	now := time.Now()
+	code, err := cred.TOTP.GenerateCode(now)
+	if err != nil {
+		return fmt.Errorf("failed to generate TOTP code: %w", err)
+	}
+
+	// Track TOTP access
+	if err := vaultService.RecordFieldAccess(service, "totp"); err != nil {
+		// Log warning but don't fail the operation
+		fmt.Fprintf(os.Stderr, "Warning: failed to track TOTP access: %v\n", err)
+	}
+
+	// Quiet mode - output only the code
+	if otpQuiet {
+		fmt.Println(code)
+		return nil
+	}
+
+	remaining := cred.TOTP.Remaining(now)
+	fmt.Printf("📝 Service: %s\n", cred.Service)
+	fmt.Printf("⏱️  Code: %s (valid for %ds)\n", code, int(remaining.Seconds()))
+
+	if otpNoClipboard {
+		return nil
+	}
+
+	if err := clipboard.WriteAll(code); err != nil {
+		fmt.Fprintf(os.Stderr, "\n⚠️  Warning: failed to copy to clipboard: %v\n", err)
+		return nil
+	}
+	fmt.Println("\n✅ Code copied to clipboard!")
+
+	// Schedule clipboard clear in background once the code expires
+	go func() {
+		time.Sleep(remaining)
+		// Only clear if the clipboard still contains our code
+		if current, err := clipboard.ReadAll(); err == nil && current == code {
+			_ = clipboard.WriteAll("")
+			if IsVerbose() {
+				fmt.Fprintln(os.Stderr, "🧹 Clipboard cleared")
+			}
+		}
+	}()
+
+	return nil
+}
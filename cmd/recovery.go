@@ -0,0 +1,241 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"pass-cli/internal/crypto"
+	"pass-cli/internal/shamir"
+	"pass-cli/internal/storage"
+	"pass-cli/internal/vault"
+)
+
+// wordsPerLine keeps printed shares readable
+const wordsPerLine = 6
+
+var (
+	recoverySetupShares     int
+	recoverySetupThreshold  int
+	recoverySetupBenchmark  string
+	recoverySetupForce      bool
+	recoveryUnlockRemoveKey bool
+)
+
+var recoveryCmd = &cobra.Command{
+	Use:   "recovery",
+	Short: "Recover a vault whose master password is lost",
+	Long: `Recovery splits a recovery key with Shamir's Secret Sharing into printable
+shares of 18 words each. Any threshold of the shares unlocks the vault so a new
+master password can be set; fewer shares reveal nothing about the key.
+
+Give each share to a different person or keep them in different places, so no
+single share holder can open the vault alone.`,
+}
+
+var recoverySetupCmd = &cobra.Command{
+	Use:   "setup",
+	Short: "Split a recovery key into shares",
+	Long: `Setup generates a recovery key, adds a key slot for it and prints the key as
+shares. The shares are shown only once. Running setup again replaces the
+previous shares, which stop working: the vault and its snapshots are
+re-encrypted with a new data key, so the old shares do not open older copies
+either. Other key slots are kept if you enter their secret when asked.`,
+	Example: `  # Five shares, any three of which recover the vault
+  pass-cli recovery setup --shares 5 --threshold 3`,
+	Args: cobra.NoArgs,
+	RunE: runRecoverySetup,
+}
+
+var recoveryUnlockCmd = &cobra.Command{
+	Use:   "unlock",
+	Short: "Unlock with recovery shares and set a new master password",
+	Long: `Unlock asks for recovery shares, one per prompt, until enough have been
+entered, then asks for a new master password. Words may be typed in any case
+and shortened to their first four letters.
+
+Vaults that require a key file with the password keep requiring it: pass
+--key-file, or --remove-key-file if the key file is lost too.`,
+	Example: `  # Recover a vault with three of its shares
+  pass-cli recovery unlock`,
+	Args: cobra.NoArgs,
+	RunE: runRecoveryUnlock,
+}
+
+func init() {
+	rootCmd.AddCommand(recoveryCmd)
+	recoveryCmd.AddCommand(recoverySetupCmd)
+	recoveryCmd.AddCommand(recoveryUnlockCmd)
+
+	recoverySetupCmd.Flags().IntVar(&recoverySetupShares, "shares", 5, "number of shares to create")
+	recoverySetupCmd.Flags().IntVar(&recoverySetupThreshold, "threshold", 3, "number of shares needed to recover the vault")
+	recoverySetupCmd.Flags().BoolVarP(&recoverySetupForce, "force", "f", false, "replace existing shares without confirmation")
+	addKDFBenchmarkFlag(recoverySetupCmd, &recoverySetupBenchmark)
+	recoveryUnlockCmd.Flags().BoolVar(&recoveryUnlockRemoveKey, "remove-key-file", false, "stop requiring a key file with the new password")
+}
+
+func runRecoverySetup(cmd *cobra.Command, args []string) error {
+	if recoverySetupThreshold < 2 || recoverySetupThreshold > recoverySetupShares {
+		return fmt.Errorf("--threshold must be between 2 and --shares (%d)", recoverySetupShares)
+	}
+	if recoverySetupShares > shamir.MaxShares {
+		return fmt.Errorf("--shares must be at most %d", shamir.MaxShares)
+	}
+	benchmark, err := parseKDFBenchmark(recoverySetupBenchmark)
+	if err != nil {
+		return err
+	}
+
+	vaultService, err := openUnlockedVault()
+	if err != nil {
+		return err
+	}
+	defer vaultService.Lock()
+
+	slots, err := vaultService.ListKeySlots()
+	if err != nil {
+		return fmt.Errorf("failed to list key slots: %w", err)
+	}
+	// Earlier shares are replaced, which rekeys the vault
+	replaced := []int{vaultService.UnlockedSlot()}
+	for _, slot := range slots {
+		if slot.Type != storage.KeySlotShares {
+			continue
+		}
+		if !recoverySetupForce && len(replaced) == 1 {
+			fmt.Printf("⚠️  Replace the existing recovery shares (%s)? They will stop working. (y/N): ", slot.Label)
+			var confirm string
+			_, _ = fmt.Scanln(&confirm)
+			confirm = strings.ToLower(strings.TrimSpace(confirm))
+			if confirm != "y" && confirm != "yes" {
+				fmt.Println("Setup cancelled.")
+				return nil
+			}
+		}
+		replaced = append(replaced, slot.ID)
+	}
+	var secrets vault.SlotSecrets
+	if len(replaced) > 1 {
+		var ok bool
+		if secrets, ok, err = readKeptSlotSecrets(vaultService, recoverySetupForce, replaced...); err != nil || !ok {
+			return err
+		}
+		defer secrets.Clear()
+	}
+
+	params := crypto.DefaultKDFParams()
+	if benchmarked, err := benchmarkKDF(benchmark); err != nil {
+		return err
+	} else if benchmarked != nil {
+		params = *benchmarked
+	}
+
+	shares, id, err := vaultService.SetupRecoveryShares(recoverySetupShares, recoverySetupThreshold, params, secrets)
+	if err != nil {
+		return fmt.Errorf("failed to set up recovery shares: %w", err)
+	}
+
+	fmt.Printf("✅ Added recovery slot %d: any %d of %d shares unlock the vault\n", id, recoverySetupThreshold, recoverySetupShares)
+	for _, share := range shares {
+		fmt.Printf("\n🔑 Share %d of %d\n", share.X, len(shares))
+		words := strings.Fields(share.Mnemonic())
+		for i := 0; i < len(words); i += wordsPerLine {
+			var line strings.Builder
+			for j := i; j < i+wordsPerLine && j < len(words); j++ {
+				fmt.Fprintf(&line, " %2d. %-9s", j+1, words[j])
+			}
+			fmt.Println("  " + strings.TrimRight(line.String(), " "))
+		}
+	}
+
+	fmt.Println()
+	fmt.Println("⚠️  Print or write down each share now - they are shown only once")
+	fmt.Printf("💡 Give the shares to different people; recover with 'pass-cli recovery unlock' and any %d of them\n", recoverySetupThreshold)
+	return nil
+}
+
+func runRecoveryUnlock(cmd *cobra.Command, args []string) error {
+	vaultService, err := openVaultService()
+	if err != nil {
+		return err
+	}
+	// A vault that requires a key file keeps requiring it after recovery
+	vaultService.SetKeyFile(GetKeyFile())
+
+	shares, err := readRecoveryShares()
+	if err != nil {
+		return err
+	}
+	if err := vaultService.UnlockWithRecoveryShares(shares); err != nil {
+		return err
+	}
+	defer vaultService.Lock()
+	fmt.Println("🔓 Vault unlocked with recovery shares")
+	fmt.Println()
+
+	password, err := readNewSlotPassword()
+	if err != nil {
+		return err
+	}
+	if recoveryUnlockRemoveKey {
+		err = vaultService.ChangePasswordWithOpts(password, vault.ChangePasswordOpts{RemoveKeyFile: true})
+	} else {
+		err = vaultService.ChangePassword(password)
+	}
+	if err != nil {
+		if errors.Is(err, vault.ErrKeyFileRequired) {
+			return fmt.Errorf("failed to set new master password: %w (pass --key-file, or --remove-key-file)", err)
+		}
+		return fmt.Errorf("failed to set new master password: %w", err)
+	}
+
+	fmt.Println("✅ New master password set")
+	fmt.Println("💡 The recovery shares still work; run 'pass-cli recovery setup' to replace them")
+	return nil
+}
+
+// readRecoveryShares prompts for shares until the threshold stored in them is reached
+func readRecoveryShares() ([]shamir.Share, error) {
+	var shares []shamir.Share
+	for len(shares) == 0 || len(shares) < shares[0].Threshold {
+		if len(shares) == 0 {
+			fmt.Print("Share 1: ")
+		} else {
+			fmt.Printf("Share %d of %d: ", len(shares)+1, shares[0].Threshold)
+		}
+		line, err := readLine()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read share: %w", err)
+		}
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		// Check each share as it is typed, so a mistake only costs one retry.
+		// Word numbers copied from the printout are ignored.
+		share, err := shamir.ParseMnemonic(strings.Map(func(r rune) rune {
+			if (r >= '0' && r <= '9') || r == '.' {
+				return ' '
+			}
+			return r
+		}, line))
+		if err == nil && len(shares) > 0 {
+			if share.SetID != shares[0].SetID || share.Threshold != shares[0].Threshold {
+				err = shamir.ErrMismatchedShares
+			}
+			for _, other := range shares {
+				if other.X == share.X {
+					err = shamir.ErrDuplicateShare
+				}
+			}
+		}
+		if err != nil {
+			fmt.Printf("❌ %v - try again\n", err)
+			continue
+		}
+		shares = append(shares, share)
+	}
+	return shares, nil
+}
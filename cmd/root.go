@@ -7,11 +7,15 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+
+	"pass-cli/internal/config"
 )
 
 var (
 	cfgFile   string
 	vaultPath string
+	profile   string
+	keyFile   string
 	verbose   bool
 
 	// Version information (set via ldflags during build)
@@ -48,6 +52,9 @@ Examples:
   pass-cli list
 
 For more information, visit: https://github.com/username/pass-cli`,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return checkProfile()
+		},
 	}
 )
 
@@ -65,24 +72,39 @@ func init() {
 	// Global flags
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.pass-cli/config.yaml)")
 	rootCmd.PersistentFlags().StringVar(&vaultPath, "vault", "", "vault file path (default is $HOME/.pass-cli/vault.enc)")
+	rootCmd.PersistentFlags().StringVar(&profile, "profile", "", "named vault from the vault registry (see 'pass-cli vault list')")
+	rootCmd.PersistentFlags().StringVar(&keyFile, "key-file", "", "key file that unlocks the vault, alone or with the master password (default is vaults.key_file in config.yml)")
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "verbose output")
 
 	// Bind flags to viper
 	_ = viper.BindPFlag("verbose", rootCmd.PersistentFlags().Lookup("verbose"))
 	_ = viper.BindPFlag("vault", rootCmd.PersistentFlags().Lookup("vault"))
+	_ = rootCmd.RegisterFlagCompletionFunc("profile", completeProfileNames)
 }
 
-// GetVaultPath returns the vault path from flag, config, or default
+// GetVaultPath returns the vault path from flag, profile, config, or default
 func GetVaultPath() string {
-	// Priority: flag > config > default
+	// Priority: --vault flag > --profile flag > vault config key > default profile > default
 	if vaultPath != "" {
 		return vaultPath
 	}
 
+	if profile != "" {
+		if vaults, err := loadVaults(); err == nil {
+			if path, err := vaults.Resolve(profile); err == nil {
+				return path
+			}
+		}
+	}
+
 	if viper.IsSet("vault") {
 		return viper.GetString("vault")
 	}
 
+	if vaults, err := loadVaults(); err == nil && vaults.DefaultPath() != "" {
+		return vaults.DefaultPath()
+	}
+
 	// Default vault path
 	home, err := os.UserHomeDir()
 	if err != nil {
@@ -92,6 +114,55 @@ func GetVaultPath() string {
 	return filepath.Join(home, ".pass-cli", "vault.enc")
 }
 
+// GetKeyFile returns the key file from the --key-file flag, else the
+// vaults.key_file config default, or "" if neither is set
+func GetKeyFile() string {
+	if keyFile != "" {
+		return keyFile
+	}
+
+	if vaults, err := loadVaults(); err == nil {
+		return vaults.KeyFile
+	}
+	return ""
+}
+
+// checkProfile fails early when --profile names a vault that is not registered,
+// so GetVaultPath never silently falls back to another vault
+func checkProfile() error {
+	if profile == "" {
+		return nil
+	}
+	if vaultPath != "" {
+		return fmt.Errorf("--vault and --profile cannot be used together")
+	}
+
+	vaults, err := loadVaults()
+	if err != nil {
+		return fmt.Errorf("failed to load vault registry: %w", err)
+	}
+	if _, err := vaults.Resolve(profile); err != nil {
+		return fmt.Errorf("%w (see 'pass-cli vault list')", err)
+	}
+	return nil
+}
+
+// vaultsConfigPath returns the config file holding the vault registry: the file
+// viper read the settings from (--config or ~/.pass-cli/config.yaml), otherwise
+// the default config path
+func vaultsConfigPath() (string, error) {
+	return config.VaultsConfigPath(viper.ConfigFileUsed())
+}
+
+// loadVaults reads the vault registry from vaultsConfigPath
+func loadVaults() (*config.VaultsConfig, error) {
+	configPath, err := vaultsConfigPath()
+	if err != nil {
+		return nil, err
+	}
+	return config.LoadVaultsFromPath(configPath)
+}
+
 // IsVerbose returns whether verbose mode is enabled
 func IsVerbose() bool {
 	return verbose || viper.GetBool("verbose")
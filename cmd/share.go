@@ -0,0 +1,253 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	"filippo.io/age"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"pass-cli/internal/share"
+	"pass-cli/internal/vault"
+)
+
+var (
+	shareExportTo       []string
+	shareExportServices []string
+	shareExportOutput   string
+	shareExportForce    bool
+	shareImportDups     string
+	shareImportDryRun   bool
+)
+
+var shareCmd = &cobra.Command{
+	Use:   "share",
+	Short: "Share credentials with other pass-cli users",
+	Long: `Share sends credentials to other people as bundles encrypted with age to
+their public keys, so only they can open them.
+
+Every vault has its own X25519 identity, created the first time it is needed.
+Give your public key ("pass-cli share identity") to the people who share with
+you, and ask for theirs to share with them.
+
+Bundles are authenticated: each one carries a tag per recipient that only the
+holder of the sender's identity can compute, and import rejects bundles whose
+sender cannot be verified. Check that the sender's public key is one you know.`,
+}
+
+var shareIdentityCmd = &cobra.Command{
+	Use:   "identity",
+	Short: "Show the public key others share credentials to",
+	Args:  cobra.NoArgs,
+	RunE:  runShareIdentity,
+}
+
+var shareExportCmd = &cobra.Command{
+	Use:   "export --to <public-key> --services <a,b> -o <file>",
+	Short: "Encrypt credentials to other users' public keys",
+	Long: `Export writes the chosen credentials to an ASCII-armored bundle that any of
+the recipients can import. Password history, usage data and attachments are not
+shared. The share is recorded in your vault ("pass-cli share log").`,
+	Example: `  # Share two credentials with a teammate
+  pass-cli share export --to age1ql3z7hjy54pw3hyww5ayyfg7zqgvc7w3j2elw8zmrj2kg5sfn9aqmcac8p \
+    --services github,aws/prod -o github-aws.age
+
+  # Share with several people at once
+  pass-cli share export --to age1... --to age1... --services vpn -o vpn.age`,
+	Args: cobra.NoArgs,
+	RunE: runShareExport,
+}
+
+var shareImportCmd = &cobra.Command{
+	Use:   "import <bundle>",
+	Short: "Merge a bundle shared with you into the vault",
+	Long: `Import decrypts a bundle with your identity and merges its credentials in a
+single save, recording who shared them and when.
+
+When a service name already exists in the vault, --duplicates decides:
+
+  skip        keep the existing credential (default)
+  overwrite   replace it with the shared one
+  rename      import the shared one under a new name, e.g. "<service>-2"`,
+	Example: `  # Preview what a bundle would change
+  pass-cli share import github-aws.age --dry-run
+
+  # Replace existing credentials with the shared ones
+  pass-cli share import github-aws.age --duplicates overwrite`,
+	Args: cobra.ExactArgs(1),
+	RunE: runShareImport,
+}
+
+var shareLogCmd = &cobra.Command{
+	Use:   "log",
+	Short: "List credentials shared with and by you",
+	Args:  cobra.NoArgs,
+	RunE:  runShareLog,
+}
+
+func init() {
+	rootCmd.AddCommand(shareCmd)
+	shareCmd.AddCommand(shareIdentityCmd)
+	shareCmd.AddCommand(shareExportCmd)
+	shareCmd.AddCommand(shareImportCmd)
+	shareCmd.AddCommand(shareLogCmd)
+
+	shareExportCmd.Flags().StringArrayVar(&shareExportTo, "to", nil, "recipient public key (repeatable)")
+	shareExportCmd.Flags().StringSliceVar(&shareExportServices, "services", nil, "comma-separated services to share")
+	shareExportCmd.Flags().StringVarP(&shareExportOutput, "output", "o", "", "file to write (required)")
+	shareExportCmd.Flags().BoolVarP(&shareExportForce, "force", "f", false, "overwrite an existing file")
+	_ = shareExportCmd.MarkFlagRequired("to")
+	_ = shareExportCmd.MarkFlagRequired("services")
+	_ = shareExportCmd.MarkFlagRequired("output")
+
+	shareImportCmd.Flags().StringVar(&shareImportDups, "duplicates", string(vault.DuplicateSkip), "existing service names: skip, overwrite, rename")
+	shareImportCmd.Flags().BoolVar(&shareImportDryRun, "dry-run", false, "show what would be imported without changing the vault")
+
+	_ = shareImportCmd.RegisterFlagCompletionFunc("duplicates", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{string(vault.DuplicateSkip), string(vault.DuplicateOverwrite), string(vault.DuplicateRename)}, cobra.ShellCompDirectiveNoFileComp
+	})
+	_ = shareExportCmd.RegisterFlagCompletionFunc("services", completeServicePaths)
+}
+
+func runShareIdentity(cmd *cobra.Command, args []string) error {
+	vaultService, err := openUnlockedVault()
+	if err != nil {
+		return err
+	}
+	defer vaultService.Lock()
+
+	identity, err := vaultService.ShareIdentity()
+	if err != nil {
+		return fmt.Errorf("failed to load share identity: %w", err)
+	}
+
+	fmt.Println(identity.Recipient().String())
+	return nil
+}
+
+func runShareExport(cmd *cobra.Command, args []string) error {
+	recipients, err := share.ParseRecipients(shareExportTo)
+	if err != nil {
+		return err
+	}
+	var services []string
+	for _, service := range shareExportServices {
+		if service = strings.TrimSpace(service); service != "" {
+			services = append(services, service)
+		}
+	}
+	if len(services) == 0 {
+		return fmt.Errorf("--services needs at least one service name")
+	}
+	if _, err := os.Stat(shareExportOutput); err == nil && !shareExportForce {
+		return fmt.Errorf("%s already exists (use --force to overwrite)", shareExportOutput)
+	}
+
+	vaultService, err := openUnlockedVault()
+	if err != nil {
+		return err
+	}
+	defer vaultService.Lock()
+
+	keys := make([]string, len(recipients))
+	for i, recipient := range recipients {
+		keys[i] = recipient.String()
+	}
+
+	count := 0
+	err = vaultService.ShareCredentials(services, keys, func(identity *age.X25519Identity, credentials []vault.Credential) error {
+		var buf bytes.Buffer
+		if err := share.Write(&buf, identity, recipients, credentials); err != nil {
+			return err
+		}
+		count = len(credentials)
+		return writePrivateFile(shareExportOutput, buf.Bytes())
+	})
+	if err != nil {
+		return fmt.Errorf("failed to share: %w", err)
+	}
+
+	fmt.Printf("✅ Shared %d credential(s) with %d recipient(s) in %s\n", count, len(recipients), shareExportOutput)
+	fmt.Println("💡 Recipients import it with 'pass-cli share import <bundle>'")
+	return nil
+}
+
+func runShareImport(cmd *cobra.Command, args []string) error {
+	duplicates := vault.DuplicateMode(strings.ToLower(strings.TrimSpace(shareImportDups)))
+	path := args[0]
+
+	f, err := os.Open(path) // #nosec G304 -- User-specified bundle path
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer func() { _ = f.Close() }()
+
+	vaultService, err := openUnlockedVault()
+	if err != nil {
+		return err
+	}
+	defer vaultService.Lock()
+
+	identity, err := vaultService.ShareIdentity()
+	if err != nil {
+		return fmt.Errorf("failed to load share identity: %w", err)
+	}
+	bundle, err := share.Read(f, identity)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	entries := bundle.Entries()
+	defer vault.ClearImportEntries(entries)
+
+	fmt.Printf("📦 %d credential(s) shared by %s (verified) on %s\n", len(entries), bundle.From, bundle.CreatedAt.Local().Format("2006-01-02 15:04"))
+	if len(entries) == 0 {
+		return nil
+	}
+
+	report, err := vaultService.ReceiveShare(bundle.From, bundle.CreatedAt, entries, vault.ImportOptions{
+		Duplicates: duplicates,
+		DryRun:     shareImportDryRun,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to import: %w", err)
+	}
+
+	printImportReport(report, shareImportDryRun, false)
+	return nil
+}
+
+func runShareLog(cmd *cobra.Command, args []string) error {
+	vaultService, err := openUnlockedVault()
+	if err != nil {
+		return err
+	}
+	defer vaultService.Lock()
+
+	records, err := vaultService.ListShares()
+	if err != nil {
+		return fmt.Errorf("failed to list shares: %w", err)
+	}
+	if len(records) == 0 {
+		fmt.Println("No credentials shared yet.")
+		return nil
+	}
+
+	var data [][]string
+	for _, record := range records {
+		data = append(data, []string{
+			record.SharedAt.Local().Format("2006-01-02 15:04"),
+			string(record.Direction),
+			strings.Join(record.Peers, "\n"),
+			strings.Join(record.Services, ", "),
+		})
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.Header([]string{"Shared", "Direction", "Public Key", "Services"})
+	_ = table.Bulk(data)
+	_ = table.Render()
+	return nil
+}
@@ -0,0 +1,207 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"pass-cli/internal/crypto"
+	"pass-cli/internal/vault"
+)
+
+var (
+	syncStrategy string
+	syncDryRun   bool
+)
+
+var syncCmd = &cobra.Command{
+	Use:   "sync <other-vault.enc>",
+	Short: "Merge another copy of the vault into this one",
+	Long: `Sync merges another copy of the vault (for example one edited on another
+machine) into the current vault, credential by credential.
+
+Each sync records the version of every credential. On the next sync that
+record is the common ancestor: a credential changed on only one side takes
+that side's version, a credential deleted on one side and untouched on the
+other is moved to the trash, and new credentials are copied over.
+
+Credentials changed differently on both sides are conflicts. By default you
+are asked which version to keep; --strategy resolves them without asking:
+
+  ask      prompt for every conflict (default)
+  local    keep this vault's version
+  remote   take the other vault's version
+  newest   take the most recently changed version
+
+Only the current vault is written (atomically). The other file is left
+untouched; sync in the other direction to update it too. Without a previous
+sync nothing is treated as deleted, and differing copies of the same
+credential are reported as conflicts.`,
+	Example: `  # Merge a copy from a USB stick, asking about conflicts
+  pass-cli sync /media/usb/vault.enc
+
+  # Preview what would change
+  pass-cli sync ~/Dropbox/vault.enc --dry-run
+
+  # Resolve conflicts in favour of the newest edit
+  pass-cli sync ~/Dropbox/vault.enc --strategy newest`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSync,
+}
+
+func init() {
+	rootCmd.AddCommand(syncCmd)
+	syncCmd.Flags().StringVar(&syncStrategy, "strategy", string(vault.SyncStrategyAsk), "conflict strategy: ask, local, remote, newest")
+	syncCmd.Flags().BoolVar(&syncDryRun, "dry-run", false, "show what would change without writing the vault")
+
+	_ = syncCmd.RegisterFlagCompletionFunc("strategy", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		var strategies []string
+		for _, s := range vault.SyncStrategies {
+			strategies = append(strategies, string(s))
+		}
+		return strategies, cobra.ShellCompDirectiveNoFileComp
+	})
+}
+
+func runSync(cmd *cobra.Command, args []string) error {
+	otherPath, err := filepath.Abs(args[0])
+	if err != nil {
+		return fmt.Errorf("invalid vault path: %w", err)
+	}
+	if _, err := os.Stat(otherPath); err != nil {
+		return fmt.Errorf("failed to read %s: %w", args[0], err)
+	}
+	if localPath, err := filepath.Abs(GetVaultPath()); err == nil && localPath == otherPath {
+		return fmt.Errorf("cannot sync a vault with itself")
+	}
+
+	strategy := vault.SyncStrategy(strings.ToLower(strings.TrimSpace(syncStrategy)))
+
+	vaultService, err := openUnlockedVault()
+	if err != nil {
+		return err
+	}
+	defer vaultService.Lock()
+
+	peer, err := openSyncPeer(vaultService, otherPath)
+	if err != nil {
+		return err
+	}
+	defer peer.Lock()
+
+	report, err := vaultService.Sync(peer, vault.SyncOptions{
+		Strategy: strategy,
+		Resolve:  promptSyncConflict,
+		DryRun:   syncDryRun,
+	})
+	if errors.Is(err, vault.ErrSyncAborted) {
+		fmt.Println("Sync cancelled. The vault was not changed.")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to sync: %w", err)
+	}
+
+	printSyncReport(report)
+	return nil
+}
+
+// openSyncPeer unlocks the other vault, trying the current master password first
+func openSyncPeer(vaultService *vault.VaultService, path string) (*vault.VaultService, error) {
+	peer, err := vaultService.OpenPeer(path, nil)
+	if err == nil {
+		return peer, nil
+	}
+	if !errors.Is(err, crypto.ErrDecryptionFailed) {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+
+	fmt.Printf("Master password for %s: ", filepath.Base(path))
+	password, err := readPassword()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read password: %w", err)
+	}
+	fmt.Println() // newline after password input
+
+	peer, err = vaultService.OpenPeer(path, password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	return peer, nil
+}
+
+// promptSyncConflict asks which version of a conflicting credential to keep
+func promptSyncConflict(conflict vault.SyncConflict) (vault.SyncChoice, error) {
+	fmt.Printf("\n⚠️  Conflict: %s (%s)\n", conflict.Service, conflict.Kind)
+	fmt.Printf("   [l] local:  %s\n", describeSyncVersion(conflict.Local))
+	fmt.Printf("   [r] remote: %s\n", describeSyncVersion(conflict.Remote))
+
+	for {
+		fmt.Print("Keep which version? [l/r/q]: ")
+		answer, err := readLine()
+		if err != nil {
+			return "", fmt.Errorf("failed to read answer: %w", err)
+		}
+
+		switch strings.ToLower(answer) {
+		case "l", "local":
+			return vault.SyncKeepLocal, nil
+		case "r", "remote":
+			return vault.SyncTakeRemote, nil
+		case "q", "quit":
+			return "", vault.ErrSyncAborted
+		}
+	}
+}
+
+// describeSyncVersion summarizes one side of a conflict without showing secrets
+func describeSyncVersion(version vault.SyncVersion) string {
+	if !version.Exists {
+		if version.UpdatedAt.IsZero() {
+			return "deleted"
+		}
+		return fmt.Sprintf("deleted %s", formatRelativeTime(version.UpdatedAt))
+	}
+
+	username := version.Username
+	if username == "" {
+		username = "(no username)"
+	}
+	return fmt.Sprintf("%s, modified %s (%s, %d edits)",
+		username, formatRelativeTime(version.UpdatedAt), version.UpdatedAt.Format(time.DateTime), version.ModifiedCount)
+}
+
+// printSyncReport prints the outcome of a sync
+func printSyncReport(report *vault.SyncReport) {
+	fmt.Println()
+	if syncDryRun {
+		fmt.Println("🔍 Dry run: the vault was not changed")
+	}
+	if !report.HasBase {
+		fmt.Println("💡 First sync between these copies: nothing is treated as deleted")
+	}
+
+	for _, service := range report.Added {
+		fmt.Printf("  + %s (added)\n", service)
+	}
+	for _, service := range report.Updated {
+		fmt.Printf("  ~ %s (updated)\n", service)
+	}
+	for _, service := range report.Deleted {
+		fmt.Printf("  - %s (moved to trash)\n", service)
+	}
+	for _, res := range report.Resolved {
+		fmt.Printf("  ! %s (%s: kept %s)\n", res.Conflict.Service, res.Conflict.Kind, res.Choice)
+	}
+
+	fmt.Printf("\n%d added, %d updated, %d deleted, %d conflict(s), %d unchanged\n",
+		len(report.Added), len(report.Updated), len(report.Deleted), len(report.Resolved), report.Unchanged)
+	if !syncDryRun {
+		fmt.Println("✅ Sync complete")
+	}
+}
@@ -0,0 +1,178 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+var (
+	trashPurgeOlderThan string
+	trashPurgeForce     bool
+)
+
+var trashCmd = &cobra.Command{
+	Use:   "trash",
+	Short: "Manage deleted credentials",
+	Long: `Deleted credentials are moved to the trash inside the vault instead of being
+removed immediately. Use the trash subcommands to review, restore, or
+permanently purge them.`,
+}
+
+var trashListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List credentials in the trash",
+	Args:    cobra.NoArgs,
+	RunE:    runTrashList,
+}
+
+var trashRestoreCmd = &cobra.Command{
+	Use:   "restore <service> [service...]",
+	Short: "Restore credentials from the trash",
+	Long: `Restore moves credentials from the trash back into the vault. If the same
+service was deleted more than once, the most recently deleted copy is restored.
+
+Restoring fails if a credential with the same service name already exists.`,
+	Example: `  # Restore a deleted credential
+  pass-cli trash restore github`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runTrashRestore,
+}
+
+var trashPurgeCmd = &cobra.Command{
+	Use:   "purge",
+	Short: "Permanently remove credentials from the trash",
+	Long: `Purge permanently removes credentials from the trash. Without --older-than
+the whole trash is emptied. Purged credentials cannot be recovered.`,
+	Example: `  # Empty the trash
+  pass-cli trash purge
+
+  # Remove credentials deleted more than 30 days ago
+  pass-cli trash purge --older-than 30d
+
+  # Skip the confirmation prompt
+  pass-cli trash purge --older-than 30d --force`,
+	Args: cobra.NoArgs,
+	RunE: runTrashPurge,
+}
+
+func init() {
+	rootCmd.AddCommand(trashCmd)
+	trashCmd.AddCommand(trashListCmd)
+	trashCmd.AddCommand(trashRestoreCmd)
+	trashCmd.AddCommand(trashPurgeCmd)
+	trashPurgeCmd.Flags().StringVar(&trashPurgeOlderThan, "older-than", "", "only purge credentials deleted before this age (e.g., 30d, 2w)")
+	trashPurgeCmd.Flags().BoolVarP(&trashPurgeForce, "force", "f", false, "skip confirmation prompt")
+}
+
+func runTrashList(cmd *cobra.Command, args []string) error {
+	vaultService, err := openUnlockedVault()
+	if err != nil {
+		return err
+	}
+	defer vaultService.Lock()
+
+	entries, err := vaultService.ListTrash()
+	if err != nil {
+		return fmt.Errorf("failed to list trash: %w", err)
+	}
+
+	if len(entries) == 0 {
+		fmt.Println("Trash is empty.")
+		return nil
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	var data [][]string
+	for _, entry := range entries {
+		data = append(data, []string{
+			entry.Service,
+			entry.Username,
+			entry.Category,
+			formatRelativeTime(entry.DeletedAt),
+		})
+	}
+	table.Header([]string{"Service", "Username", "Category", "Deleted"})
+	_ = table.Bulk(data)
+	_ = table.Render()
+
+	fmt.Printf("\nTotal: %d credential(s) in trash\n", len(entries))
+	return nil
+}
+
+func runTrashRestore(cmd *cobra.Command, args []string) error {
+	vaultService, err := openUnlockedVault()
+	if err != nil {
+		return err
+	}
+	defer vaultService.Lock()
+
+	failed := 0
+	for _, service := range args {
+		service = strings.TrimSpace(service)
+		if service == "" {
+			continue
+		}
+		if err := vaultService.RestoreCredential(service); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Error restoring %s: %v\n", service, err)
+			failed++
+			continue
+		}
+		fmt.Printf("✅ Restored: %s\n", service)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to restore %d credential(s)", failed)
+	}
+	return nil
+}
+
+func runTrashPurge(cmd *cobra.Command, args []string) error {
+	// Validate flags before unlocking so typos fail fast
+	var olderThan time.Duration
+	if trashPurgeOlderThan != "" {
+		duration, err := parseDayDuration(trashPurgeOlderThan)
+		if err != nil {
+			return fmt.Errorf("invalid --older-than value: %w", err)
+		}
+		olderThan = duration
+	}
+
+	vaultService, err := openUnlockedVault()
+	if err != nil {
+		return err
+	}
+	defer vaultService.Lock()
+
+	if !trashPurgeForce {
+		scope := "all credentials in the trash"
+		if trashPurgeOlderThan != "" {
+			scope = fmt.Sprintf("credentials deleted more than %s ago", trashPurgeOlderThan)
+		}
+		fmt.Printf("⚠️  Permanently remove %s? This cannot be undone. (y/N): ", scope)
+		var confirm string
+		_, _ = fmt.Scanln(&confirm)
+		confirm = strings.ToLower(strings.TrimSpace(confirm))
+		if confirm != "y" && confirm != "yes" {
+			fmt.Println("Purge cancelled.")
+			return nil
+		}
+	}
+
+	purged, err := vaultService.PurgeTrash(olderThan)
+	if err != nil {
+		return fmt.Errorf("failed to purge trash: %w", err)
+	}
+
+	if purged == 0 {
+		fmt.Println("Nothing to purge.")
+		return nil
+	}
+	fmt.Printf("✅ Purged %d credential(s) from the trash\n", purged)
+	return nil
+}
@@ -43,7 +43,9 @@ Keyboard shortcuts:
   q - Quit
 
 The TUI will automatically unlock the vault using the system keychain if available,
-otherwise it will prompt for the master password.`,
+otherwise it will prompt for the master password. With --key-file (or the
+vaults.key_file config default) the vault is unlocked with that key file, or
+with the key file and the master password if the vault requires both.`,
 	Run: runTUI,
 }
 
@@ -62,20 +64,26 @@ func runTUI(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
-	// Try keychain unlock first
-	err = vaultService.UnlockWithKeychain()
+	// Try key file, then keychain unlock
+	unlocked, err := unlockWithKeyFile(vaultService)
 	if err != nil {
-		// Keychain failed, prompt for password
-		password, err := promptForMasterPassword()
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error: Failed to read password: %v\n", err)
-			os.Exit(1)
-		}
-
-		err = vaultService.Unlock(password)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error: Failed to unlock vault: %v\n", err)
-			os.Exit(1)
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if !unlocked {
+		if err := vaultService.UnlockWithKeychain(); err != nil {
+			// Keychain failed, prompt for password
+			password, err := promptForMasterPassword()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: Failed to read password: %v\n", err)
+				os.Exit(1)
+			}
+
+			err = vaultService.Unlock(password)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: Failed to unlock vault: %v\n", err)
+				os.Exit(1)
+			}
 		}
 	}
 
@@ -102,6 +110,7 @@ func launchTUI(vaultService *vault.VaultService) error {
 
 	// Initialize AppState with vault service
 	appState := models.NewAppState(vaultService)
+	appState.SetVaultPath(vaultService.Path())
 
 	// Load credentials
 	if err := appState.LoadCredentials(); err != nil {
@@ -161,6 +170,10 @@ func launchTUI(vaultService *vault.VaultService) error {
 	pageManager.ShowPage("main", mainLayout)
 	app.SetRoot(pageManager.Pages, true)
 
+	// Start live TOTP countdown in the detail view (stopped when the app exits)
+	detailView.StartOTPRefresh(app)
+	defer detailView.StopOTPRefresh()
+
 	// Run application (blocking)
 	return app.Run()
 }
@@ -2,9 +2,11 @@ package components
 
 import (
 	"fmt"
+	"math"
 	"os"
 	"sort"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"pass-cli/cmd/tui/models"
@@ -24,6 +26,12 @@ const (
 
 	// Hybrid timestamp threshold: switch from relative to absolute format
 	timestampHybridThreshold = 7 * 24 * time.Hour
+
+	// Marker replaced with the live TOTP line on every render
+	otpPlaceholder = "\x00otp\x00"
+
+	// Countdown threshold below which the TOTP timer is highlighted
+	otpExpiryWarning = 5 * time.Second
 )
 
 // DetailView displays full credential information with password masking and copy support.
@@ -34,6 +42,12 @@ type DetailView struct {
 	appState                *models.AppState
 	passwordVisible         bool   // Toggle for password visibility (false = masked)
 	cachedCredentialService string // Cache last refreshed credential service to avoid unnecessary vault calls
+
+	// Live TOTP display: content keeps a placeholder that is re-rendered every tick
+	content   string
+	otpConfig *vault.TOTPConfig
+	otpActive atomic.Bool   // Read by the ticker goroutine to skip redraws when no code is shown
+	otpStop   chan struct{} // Closed to stop the ticker goroutine
 }
 
 // NewDetailView creates and configures a new DetailView component.
@@ -67,6 +81,7 @@ func (dv *DetailView) Refresh() {
 
 	if cred == nil {
 		dv.cachedCredentialService = "" // Clear cache
+		dv.setOTPConfig(nil)
 		dv.showEmptyState()
 		return
 	}
@@ -80,11 +95,77 @@ func (dv *DetailView) Refresh() {
 	// Update cache
 	dv.cachedCredentialService = cred.Service
 
-	content := dv.formatCredential(cred)
-	dv.SetText(content)
+	dv.content = dv.formatCredential(cred)
+	dv.render()
 	dv.ScrollToBeginning()
 }
 
+// render writes the cached content, substituting the current TOTP code.
+func (dv *DetailView) render() {
+	content := dv.content
+	if dv.otpConfig != nil {
+		content = strings.Replace(content, otpPlaceholder, dv.formatOTPLine(time.Now()), 1)
+	}
+	dv.SetText(content)
+}
+
+// RefreshOTP re-renders the live TOTP code without refetching the credential.
+// Keeps the scroll position so the countdown doesn't disturb reading.
+// Returns false when the displayed credential has no TOTP seed.
+func (dv *DetailView) RefreshOTP() bool {
+	if dv.otpConfig == nil {
+		return false
+	}
+
+	row, col := dv.GetScrollOffset()
+	dv.render()
+	dv.ScrollTo(row, col)
+	return true
+}
+
+// StartOTPRefresh starts a one-second ticker that refreshes the TOTP countdown.
+// Updates are queued through the tview app loop so drawing stays on the UI goroutine.
+func (dv *DetailView) StartOTPRefresh(app *tview.Application) {
+	if dv.otpStop != nil {
+		return // Already running
+	}
+
+	stop := make(chan struct{})
+	dv.otpStop = stop
+
+	go func() {
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				if dv.otpActive.Load() {
+					app.QueueUpdateDraw(func() {
+						dv.RefreshOTP()
+					})
+				}
+			}
+		}
+	}()
+}
+
+// StopOTPRefresh stops the TOTP ticker started by StartOTPRefresh.
+func (dv *DetailView) StopOTPRefresh() {
+	if dv.otpStop != nil {
+		close(dv.otpStop)
+		dv.otpStop = nil
+	}
+}
+
+// setOTPConfig records the TOTP seed of the displayed credential (nil = none).
+func (dv *DetailView) setOTPConfig(cfg *vault.TOTPConfig) {
+	dv.otpConfig = cfg
+	dv.otpActive.Store(cfg != nil)
+}
+
 // formatCredential creates formatted text display for a credential.
 // Uses tview color tags for styling and box drawing characters for sections.
 func (dv *DetailView) formatCredential(cred *vault.CredentialMetadata) string {
@@ -99,11 +180,23 @@ func (dv *DetailView) formatCredential(cred *vault.CredentialMetadata) string {
 	// Main credential fields
 	b.WriteString(fmt.Sprintf("[gray]Username:[-]   [white]%s[-]\n", cred.Username))
 
+	// Type (plain logins are the default and not shown)
+	if cred.Type != "" && cred.Type != vault.TypeLogin {
+		if template, ok := vault.GetTemplate(cred.Type); ok {
+			b.WriteString(fmt.Sprintf("[gray]Type:[-]       [white]%s[-]\n", template.Label))
+		}
+	}
+
 	// Category (if present)
 	if cred.Category != "" {
 		b.WriteString(fmt.Sprintf("[gray]Category:[-]   [white]%s[-]\n", cred.Category))
 	}
 
+	// Tags (if present)
+	if len(cred.Tags) > 0 {
+		b.WriteString(fmt.Sprintf("[gray]Tags:[-]       [white]%s[-]\n", tview.Escape(strings.Join(cred.Tags, ", "))))
+	}
+
 	// URL (if present)
 	if cred.URL != "" {
 		b.WriteString(fmt.Sprintf("[gray]URL:[-]        [white]%s[-]\n", cred.URL))
@@ -112,6 +205,30 @@ func (dv *DetailView) formatCredential(cred *vault.CredentialMetadata) string {
 	// Password field with masking
 	dv.formatPasswordField(&b, cred)
 
+	// Fetch full credential once for custom fields, TOTP, and usage locations
+	fullCred, err := dv.appState.GetFullCredential(cred.Service)
+	if err != nil {
+		fullCred = nil
+	}
+
+	// Live TOTP code (rendered from the placeholder on every tick)
+	if fullCred != nil && fullCred.TOTP != nil {
+		dv.setOTPConfig(fullCred.TOTP)
+		b.WriteString(otpPlaceholder)
+	} else {
+		dv.setOTPConfig(nil)
+	}
+
+	// Custom fields (if any) - secret values follow the password visibility toggle
+	if fullCred != nil && len(fullCred.CustomFields) > 0 {
+		dv.formatCustomFields(&b, fullCred.CustomFields)
+	}
+
+	// Attachments (if any) - names and sizes only, contents stay in the vault
+	if fullCred != nil && len(fullCred.Attachments) > 0 {
+		formatAttachments(&b, fullCred.Attachments)
+	}
+
 	// Notes (if present)
 	if cred.Notes != "" {
 		b.WriteString("\n[gray]Notes:[-]\n")
@@ -120,6 +237,11 @@ func (dv *DetailView) formatCredential(cred *vault.CredentialMetadata) string {
 		b.WriteString(fmt.Sprintf("[white]  %s[-]\n", indentedNotes))
 	}
 
+	// Password history (if any) - values follow the password visibility toggle
+	if fullCred != nil && len(fullCred.PasswordHistory) > 0 {
+		dv.formatPasswordHistory(&b, fullCred.PasswordHistory)
+	}
+
 	// Metadata section
 	b.WriteString("\n")
 	b.WriteString(detailSeparator)
@@ -139,6 +261,14 @@ func (dv *DetailView) formatCredential(cred *vault.CredentialMetadata) string {
 		b.WriteString(fmt.Sprintf("[gray]# Modified:[-]  [white]%d %s[-]\n", cred.ModifiedCount, timesText))
 	}
 
+	// Expiry and rotation (highlighted when expired or expiring soon)
+	if !cred.ExpiresAt.IsZero() {
+		b.WriteString(formatExpiryLine(cred.ExpiresAt, time.Now()))
+	}
+	if cred.RotationDays > 0 {
+		b.WriteString(fmt.Sprintf("[gray]Rotation:[-]    [white]every %d days[-]\n", cred.RotationDays))
+	}
+
 	if !cred.LastAccessed.IsZero() {
 		relativeTime := formatRelativeTime(cred.LastAccessed)
 		b.WriteString(fmt.Sprintf("[gray]Last Used:[-]   [white]%s[-]\n", relativeTime))
@@ -154,9 +284,7 @@ func (dv *DetailView) formatCredential(cred *vault.CredentialMetadata) string {
 	}
 
 	// T047: Integrate usage locations display into detail panel
-	// Fetch full credential to get UsageRecord data
-	fullCred, err := dv.appState.GetFullCredential(cred.Service)
-	if err == nil && fullCred != nil {
+	if fullCred != nil {
 		// Append usage locations section
 		usageSection := FormatUsageLocations(fullCred)
 		b.WriteString(usageSection)
@@ -187,6 +315,93 @@ func (dv *DetailView) formatPasswordField(b *strings.Builder, cred *vault.Creden
 	fmt.Fprintf(b, "[gray]Password:[-]   [white]%s[-]%s\n", password, hint)
 }
 
+// formatCustomFields adds the credential's custom fields in stored order.
+// Secret values are masked unless password visibility is toggled on.
+func (dv *DetailView) formatCustomFields(b *strings.Builder, fields []vault.CustomField) {
+	b.WriteString("\n[gray]Custom Fields:[-]\n")
+	for _, field := range fields {
+		value := tview.Escape(field.Value)
+		if field.Secret && !dv.passwordVisible {
+			value = "********"
+		}
+		fmt.Fprintf(b, "  [gray]%s:[-] [white]%s[-]\n", tview.Escape(field.Key), value)
+	}
+}
+
+// formatAttachments lists attached files with their size and original mode.
+func formatAttachments(b *strings.Builder, attachments []vault.Attachment) {
+	b.WriteString("\n[gray]Attachments:[-]\n")
+	for _, attachment := range attachments {
+		fmt.Fprintf(b, "  [white]%s[-] [gray](%s, %04o)[-]\n",
+			tview.Escape(attachment.Name), formatByteSize(attachment.Size()), attachment.Mode)
+	}
+}
+
+// formatByteSize renders a byte count as B, KB, or MB.
+func formatByteSize(size int64) string {
+	switch {
+	case size < 1024:
+		return fmt.Sprintf("%d B", size)
+	case size < 1024*1024:
+		return fmt.Sprintf("%.1f KB", float64(size)/1024)
+	default:
+		return fmt.Sprintf("%.1f MB", float64(size)/(1024*1024))
+	}
+}
+
+// formatPasswordHistory adds previous passwords, newest first, numbered like 'pass-cli history'.
+// Values are masked unless password visibility is toggled on.
+func (dv *DetailView) formatPasswordHistory(b *strings.Builder, history []vault.PasswordHistoryEntry) {
+	b.WriteString("\n[gray]Password History:[-]\n")
+	for i, entry := range history {
+		value := "********"
+		if dv.passwordVisible {
+			value = tview.Escape(string(entry.Password))
+		}
+		fmt.Fprintf(b, "  [gray]%d.[-] [white]%s[-] [gray](replaced %s)[-]\n", i+1, value, FormatTimestamp(entry.ChangedAt))
+	}
+}
+
+// formatOTPLine renders the TOTP code and its countdown for the given time.
+func (dv *DetailView) formatOTPLine(now time.Time) string {
+	code, err := dv.otpConfig.GenerateCode(now)
+	if err != nil {
+		return "[gray]TOTP:[-]       [red]Invalid TOTP seed[-]\n"
+	}
+
+	remaining := dv.otpConfig.Remaining(now)
+	countColor := "gray"
+	if remaining <= otpExpiryWarning {
+		countColor = "red"
+	}
+
+	return fmt.Sprintf("[gray]TOTP:[-]       [white]%s[-]  [%s](%ds)[-]\n", formatOTPCode(code), countColor, int(remaining.Seconds()))
+}
+
+// formatExpiryLine renders the effective expiry date, coloured by expiry status.
+func formatExpiryLine(expiresAt, now time.Time) string {
+	date := expiresAt.Format("2006-01-02")
+	days := int(math.Ceil(expiresAt.Sub(now).Hours() / 24))
+
+	switch vault.GetExpiryStatus(expiresAt, now, vault.DefaultExpiryWarning) {
+	case vault.ExpiryExpired:
+		return fmt.Sprintf("[gray]Expires:[-]     [red]%s (expired)[-]\n", date)
+	case vault.ExpirySoon:
+		return fmt.Sprintf("[gray]Expires:[-]     [yellow]%s (in %d days)[-]\n", date, days)
+	default:
+		return fmt.Sprintf("[gray]Expires:[-]     [white]%s (in %d days)[-]\n", date, days)
+	}
+}
+
+// formatOTPCode groups a code into two halves for readability (e.g. "123 456").
+func formatOTPCode(code string) string {
+	if len(code)%2 != 0 {
+		return code
+	}
+	half := len(code) / 2
+	return code[:half] + " " + code[half:]
+}
+
 // showEmptyState displays a message when no credential is selected.
 func (dv *DetailView) showEmptyState() {
 	content := `
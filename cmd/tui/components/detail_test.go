@@ -1,7 +1,9 @@
 package components
 
 import (
+	"strings"
 	"testing"
+	"time"
 
 	"pass-cli/cmd/tui/models"
 	"pass-cli/internal/vault"
@@ -53,6 +55,10 @@ func (t *testVaultService) AddCredential(service, username string, password []by
 	return nil
 }
 
+func (t *testVaultService) AddCredentialWithOpts(service, username string, password []byte, category, url, notes string, opts vault.AddOpts) error {
+	return nil
+}
+
 func (t *testVaultService) UpdateCredential(service string, opts vault.UpdateOpts) error {
 	return nil
 }
@@ -68,3 +74,91 @@ func (t *testVaultService) GetCredential(service string, trackUsage bool) (*vaul
 func (t *testVaultService) RecordFieldAccess(service, field string) error {
 	return nil
 }
+
+func (t *testVaultService) RenameCredential(oldService, newService string) error {
+	return nil
+}
+
+func (t *testVaultService) ListTrash() ([]vault.TrashEntry, error) {
+	return nil, nil
+}
+
+func (t *testVaultService) RestoreCredential(service string) error {
+	return nil
+}
+
+func (t *testVaultService) PurgeTrash(olderThan time.Duration) (int, error) {
+	return 0, nil
+}
+
+func (t *testVaultService) Lock() {}
+
+// TestDetailView_FormatOTPLine verifies the live TOTP line shows the grouped code and countdown.
+func TestDetailView_FormatOTPLine(t *testing.T) {
+	cfg, err := vault.ParseTOTP("GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ")
+	if err != nil {
+		t.Fatalf("ParseTOTP() failed: %v", err)
+	}
+
+	detailView := NewDetailView(models.NewAppState(&testVaultService{}))
+	detailView.setOTPConfig(cfg)
+
+	// RFC 6238 test vector: T=59 yields 94287082, truncated to 6 digits
+	line := detailView.formatOTPLine(time.Unix(59, 0))
+	if !strings.Contains(line, "287 082") {
+		t.Errorf("expected grouped code in %q", line)
+	}
+	if !strings.Contains(line, "[red](1s)") {
+		t.Errorf("expected highlighted countdown in %q", line)
+	}
+
+	if !detailView.RefreshOTP() {
+		t.Error("RefreshOTP() should report an active TOTP display")
+	}
+	detailView.setOTPConfig(nil)
+	if detailView.RefreshOTP() {
+		t.Error("RefreshOTP() should be a no-op without a TOTP seed")
+	}
+}
+
+// TestFormatExpiryLine verifies expired and soon-to-expire credentials are highlighted.
+func TestFormatExpiryLine(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		expiresAt time.Time
+		want      string
+	}{
+		{"expired", now.AddDate(0, 0, -1), "[red]2025-05-31 (expired)"},
+		{"expiring soon", now.AddDate(0, 0, 3), "[yellow]2025-06-04 (in 3 days)"},
+		{"not due", now.AddDate(0, 0, 60), "[white]2025-07-31 (in 60 days)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if line := formatExpiryLine(tt.expiresAt, now); !strings.Contains(line, tt.want) {
+				t.Errorf("formatExpiryLine() = %q, want it to contain %q", line, tt.want)
+			}
+		})
+	}
+}
+
+// TestFormatAttachments verifies attachments are listed by name, size, and mode without contents.
+func TestFormatAttachments(t *testing.T) {
+	var b strings.Builder
+	formatAttachments(&b, []vault.Attachment{
+		{Name: "id_ed25519", Data: []byte("secret-key-material"), Mode: 0o600},
+		{Name: "kubeconfig", Data: make([]byte, 2048), Mode: 0o644},
+	})
+
+	out := b.String()
+	for _, want := range []string{"id_ed25519[-] [gray](19 B, 0600)", "kubeconfig[-] [gray](2.0 KB, 0644)"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("formatAttachments() = %q, want it to contain %q", out, want)
+		}
+	}
+	if strings.Contains(out, "secret-key-material") {
+		t.Error("formatAttachments() must not render attachment contents")
+	}
+}
@@ -1,5 +1,6 @@
 // Package components provides TUI form components for credential management.
-// All forms support the complete credential model: service, username, password, category, URL, and notes.
+// All forms support the complete credential model: service, username, password, category, URL, notes,
+// and custom fields.
 package components
 
 import (
@@ -23,6 +24,131 @@ func normalizeCategory(c string) string {
 	return c
 }
 
+// Custom field editing uses one "key=value" line per field.
+// Lines prefixed with secretFieldPrefix are secret; secret values are shown as maskedFieldValue.
+const (
+	customFieldsLabel = "Custom Fields"
+	secretFieldPrefix = "!"
+	maskedFieldValue  = "********"
+)
+
+// formatCustomFields renders custom fields as editable "key=value" lines.
+// Secret fields are prefixed with "!" and their values replaced by a mask placeholder.
+func formatCustomFields(fields []vault.CustomField) string {
+	lines := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if field.Secret {
+			lines = append(lines, secretFieldPrefix+field.Key+"="+maskedFieldValue)
+		} else {
+			lines = append(lines, field.Key+"="+field.Value)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// parseCustomFields parses "key=value" lines back into custom fields.
+// A secret line that still holds the mask placeholder keeps its value from original.
+func parseCustomFields(text string, original []vault.CustomField) ([]vault.CustomField, error) {
+	fields := make([]vault.CustomField, 0)
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		secret := strings.HasPrefix(line, secretFieldPrefix)
+		line = strings.TrimPrefix(line, secretFieldPrefix)
+
+		key, value, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid custom field %q (expected key=value)", line)
+		}
+
+		// Keep the stored secret when the user didn't touch the placeholder
+		if secret && value == maskedFieldValue {
+			for _, existing := range original {
+				if strings.EqualFold(existing.Key, key) {
+					value = existing.Value
+					break
+				}
+			}
+		}
+
+		fields = append(fields, vault.CustomField{Key: key, Value: value, Secret: secret})
+	}
+	return fields, nil
+}
+
+// templateFieldsText renders a template's fields as empty "key=" lines for the custom fields area.
+func templateFieldsText(template vault.CredentialTemplate) string {
+	lines := make([]string, 0, len(template.Fields))
+	for _, field := range template.Fields {
+		prefix := ""
+		if field.Secret {
+			prefix = secretFieldPrefix
+		}
+		lines = append(lines, prefix+field.Key+"=")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// applyTemplateFields swaps template placeholders in custom fields text when the type changes.
+// Empty lines for the previous template's keys are dropped; filled-in values and user fields are kept.
+// Keys of the new template that are not present yet are appended as empty "key=" lines.
+func applyTemplateFields(text string, previous, next vault.CredentialTemplate) string {
+	kept := make([]string, 0)
+	present := make(map[string]bool)
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			continue
+		}
+		key, value, _ := strings.Cut(strings.TrimPrefix(trimmed, secretFieldPrefix), "=")
+		key = strings.ToLower(strings.TrimSpace(key))
+		if strings.TrimSpace(value) == "" && isTemplateField(previous, key) {
+			continue
+		}
+		present[key] = true
+		kept = append(kept, trimmed)
+	}
+
+	for _, field := range next.Fields {
+		if present[strings.ToLower(field.Key)] {
+			continue
+		}
+		prefix := ""
+		if field.Secret {
+			prefix = secretFieldPrefix
+		}
+		kept = append(kept, prefix+field.Key+"=")
+	}
+	return strings.Join(kept, "\n")
+}
+
+// isTemplateField reports whether key is one of the template's fields (case-insensitive).
+func isTemplateField(template vault.CredentialTemplate, key string) bool {
+	for _, field := range template.Fields {
+		if strings.EqualFold(field.Key, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// dropEmptyTemplateFields removes template placeholders the user left empty.
+func dropEmptyTemplateFields(fields []vault.CustomField, template vault.CredentialTemplate) []vault.CustomField {
+	kept := make([]vault.CustomField, 0, len(fields))
+	for _, field := range fields {
+		if field.Value == "" && isTemplateField(template, field.Key) {
+			continue
+		}
+		kept = append(kept, field)
+	}
+	return kept
+}
+
 // AddForm provides a modal form for adding new credentials.
 // Embeds tview.Flex (which contains Form + hints footer) and manages validation and submission.
 type AddForm struct {
@@ -31,6 +157,19 @@ type AddForm struct {
 
 	appState *models.AppState
 
+	template           vault.CredentialTemplate // Selected credential type template
+	templateFieldsText string                   // Custom fields text pre-filled by the template
+
+	// Form items are kept rather than looked up by index or label: the type
+	// dropdown sits between them and the username/password labels change with it
+	serviceField     *tview.InputField
+	usernameField    *tview.InputField
+	passwordField    *tview.InputField
+	categoryField    *tview.InputField
+	urlField         *tview.InputField
+	notesArea        *tview.TextArea
+	customFieldsArea *tview.TextArea
+
 	passwordVisible bool // Track password visibility state for toggle
 
 	onSubmit        func()
@@ -47,7 +186,9 @@ type EditForm struct {
 	appState   *models.AppState
 	credential *vault.CredentialMetadata
 
-	originalPassword string // Track original password to detect changes
+	originalPassword     string              // Track original password to detect changes
+	originalCustomFields []vault.CustomField // Stored custom fields (secret values kept out of the form)
+	originalFieldsText   string              // Rendered custom fields text to detect changes
 	passwordFetched  bool   // Track if password has been fetched (lazy loading)
 	passwordVisible  bool   // Track password visibility state for toggle
 
@@ -57,7 +198,7 @@ type EditForm struct {
 }
 
 // NewAddForm creates a new form for adding credentials.
-// Creates input fields for Service, Username, Password, Category, URL, Notes.
+// Creates input fields for Service, Username, Password, Type, Category, URL, Notes, Custom Fields.
 func NewAddForm(appState *models.AppState) *AddForm {
 	form := tview.NewForm()
 
@@ -65,6 +206,7 @@ func NewAddForm(appState *models.AppState) *AddForm {
 		form:     form,
 		appState: appState,
 	}
+	af.template, _ = vault.GetTemplate(vault.TypeLogin)
 
 	af.buildFormFields()
 	af.applyStyles()
@@ -94,8 +236,10 @@ func (af *AddForm) buildFormFields() {
 
 	// Core credential fields
 	// Use 0 width to make fields fill available space (prevents black rectangles)
-	af.form.AddInputField("Service (UID)", "", 0, nil, nil)
-	af.form.AddInputField("Username", "", 0, nil, nil)
+	af.serviceField = tview.NewInputField().SetLabel("Service (UID)").SetFieldWidth(0)
+	af.usernameField = tview.NewInputField().SetLabel("Username").SetFieldWidth(0)
+	af.form.AddFormItem(af.serviceField)
+	af.form.AddFormItem(af.usernameField)
 
 	// T048, T049: Password field with real-time strength indicator
 	passwordField := tview.NewInputField().
@@ -108,8 +252,22 @@ func (af *AddForm) buildFormFields() {
 		af.updatePasswordLabel(passwordField, []byte(text))
 	})
 
+	af.passwordField = passwordField
 	af.form.AddFormItem(passwordField)
 
+	// Credential type switches the field labels and template fields
+	templates := vault.CredentialTemplates()
+	typeLabels := make([]string, len(templates))
+	for i, template := range templates {
+		typeLabels[i] = template.Label
+	}
+	af.form.AddDropDown("Type", typeLabels, 0, func(_ string, index int) {
+		// Ignore the initial selection fired while the form is still being built
+		if af.customFieldsArea != nil && index >= 0 && index < len(templates) {
+			af.applyTemplate(templates[index])
+		}
+	})
+
 	// Optional metadata fields - default to "Uncategorized"
 
 	// Original dropdown approach (commented out for autocomplete field)
@@ -134,9 +292,16 @@ func (af *AddForm) buildFormFields() {
       return matches
   })
 
+  af.categoryField = categoryField
   af.form.AddFormItem(categoryField)
-	af.form.AddInputField("URL", "", 0, nil, nil)
-	af.form.AddTextArea("Notes", "", 0, 5, 0, nil)
+	af.urlField = tview.NewInputField().SetLabel("URL").SetFieldWidth(0)
+	af.notesArea = tview.NewTextArea().SetLabel("Notes").SetSize(3, 0)
+	af.form.AddFormItem(af.urlField)
+	af.form.AddFormItem(af.notesArea)
+
+	// Custom fields: one "key=value" per line, "!key=value" for secret values
+	af.customFieldsArea = tview.NewTextArea().SetLabel(customFieldsLabel).SetSize(3, 0)
+	af.form.AddFormItem(af.customFieldsArea)
 
 	// Action buttons
 	af.form.AddButton("Add", af.onAddPressed)
@@ -153,20 +318,25 @@ func (af *AddForm) onAddPressed() {
 		return
 	}
 
-	// Extract field values (using form item index)
-	service := af.form.GetFormItem(0).(*tview.InputField).GetText()
-	username := af.form.GetFormItem(1).(*tview.InputField).GetText()
-	password := af.form.GetFormItem(2).(*tview.InputField).GetText()
+	// Extract field values
+	service := af.serviceField.GetText()
+	username := af.usernameField.GetText()
+	password := af.passwordField.GetText()
 
 	// Extract category from input field
-	category := af.form.GetFormItem(3).(*tview.InputField).GetText()
+	category := af.categoryField.GetText()
 	category = normalizeCategory(category) // Convert "Uncategorized" to empty string
 
-	url := af.form.GetFormItem(4).(*tview.InputField).GetText()
-	notes := af.form.GetFormItem(5).(*tview.TextArea).GetText()
+	url := af.urlField.GetText()
+	notes := af.notesArea.GetText()
+
+	// Already validated above, parse cannot fail here
+	customFields, _ := parseCustomFields(af.customFieldsArea.GetText(), nil)
+	customFields = dropEmptyTemplateFields(customFields, af.template)
 
-	// Call AppState to add credential with all 6 fields
-	err := af.appState.AddCredential(service, username, password, category, url, notes)
+	// Call AppState to add credential with all fields
+	opts := models.AddCredentialOpts{Type: af.template.Type, CustomFields: customFields}
+	err := af.appState.AddCredentialWithOpts(service, username, password, category, url, notes, opts)
 	if err != nil {
 		// Error already handled by AppState onError callback
 		// Form stays open for correction
@@ -206,39 +376,52 @@ func (af *AddForm) onCancelPressed() {
 
 // hasUnsavedData checks if any form fields contain data.
 func (af *AddForm) hasUnsavedData() bool {
-	service := af.form.GetFormItem(0).(*tview.InputField).GetText()
-	username := af.form.GetFormItem(1).(*tview.InputField).GetText()
-	password := af.form.GetFormItem(2).(*tview.InputField).GetText()
-	category := af.form.GetFormItem(3).(*tview.InputField).GetText()
-	url := af.form.GetFormItem(4).(*tview.InputField).GetText()
-	notes := af.form.GetFormItem(5).(*tview.TextArea).GetText()
+	service := af.serviceField.GetText()
+	username := af.usernameField.GetText()
+	password := af.passwordField.GetText()
+	category := af.categoryField.GetText()
+	url := af.urlField.GetText()
+	notes := af.notesArea.GetText()
+	customFields := af.customFieldsArea.GetText()
 
 	// Consider form "dirty" if any field has non-empty value
-	// Ignore "Uncategorized" since it's the default
+	// Ignore "Uncategorized" since it's the default, and untouched template field placeholders
 	return service != "" || username != "" || password != "" ||
 		(category != "" && category != "Uncategorized") ||
-		url != "" || notes != ""
+		url != "" || notes != "" ||
+		(strings.TrimSpace(customFields) != "" && customFields != af.templateFieldsText)
 }
 
 // validate checks that required fields are filled.
 // Returns error describing first validation failure, or nil if valid.
 func (af *AddForm) validate() error {
 	// Service is required (cannot be empty)
-	service := af.form.GetFormItem(0).(*tview.InputField).GetText()
+	service := af.serviceField.GetText()
 	if service == "" {
 		return fmt.Errorf("service is required")
 	}
 
-	// Username is required (minimum validation)
-	username := af.form.GetFormItem(1).(*tview.InputField).GetText()
-	if username == "" {
-		return fmt.Errorf("username is required")
+	// Username is required unless the credential type makes it optional
+	username := af.usernameField.GetText()
+	if username == "" && af.template.UsernameRequired {
+		return fmt.Errorf("%s is required", strings.ToLower(af.template.UsernameLabel))
 	}
 
 	// Password validation (basic check)
-	password := af.form.GetFormItem(2).(*tview.InputField).GetText()
-	if password == "" {
-		return fmt.Errorf("password is required")
+	password := af.passwordField.GetText()
+	if password == "" && af.template.PasswordRequired {
+		return fmt.Errorf("%s is required", strings.ToLower(af.template.PasswordLabel))
+	}
+
+	// Custom fields must parse as key=value lines
+	customFields, err := parseCustomFields(af.customFieldsArea.GetText(), nil)
+	if err != nil {
+		return err
+	}
+
+	// Template fields marked required need a value
+	if missing := af.template.MissingFields(customFields); len(missing) > 0 {
+		return fmt.Errorf("%s requires %s", af.template.Label, strings.Join(missing, ", "))
 	}
 
 	return nil
@@ -248,24 +431,61 @@ func (af *AddForm) validate() error {
 func (af *AddForm) updatePasswordLabel(field *tview.InputField, password []byte) {
 	policy := security.DefaultPasswordPolicy
 	strength := policy.Strength(password)
+	base := af.passwordLabel()
 
 	var label string
 	if len(password) == 0 {
-		label = "Password"
+		label = base
 	} else {
 		switch strength {
 		case security.PasswordStrengthWeak:
-			label = "Password [yellow](Weak)[-]"
+			label = base + " [yellow](Weak)[-]"
 		case security.PasswordStrengthMedium:
-			label = "Password [orange](Medium)[-]"
+			label = base + " [orange](Medium)[-]"
 		case security.PasswordStrengthStrong:
-			label = "Password [green](Strong)[-]"
+			label = base + " [green](Strong)[-]"
 		}
 	}
 
 	field.SetLabel(label)
 }
 
+// passwordLabel returns the password field label for the selected credential type.
+func (af *AddForm) passwordLabel() string {
+	if af.template.PasswordLabel == "" {
+		return "Password"
+	}
+	return af.template.PasswordLabel
+}
+
+// applyTemplate switches the form to a credential type's field set.
+// Relabels username/password, disables them when the type has none, and replaces
+// untouched template placeholders in the custom fields with the new template's keys.
+func (af *AddForm) applyTemplate(template vault.CredentialTemplate) {
+	previous := af.template
+	af.template = template
+
+	if template.UsernameLabel == "" {
+		af.usernameField.SetText("").SetLabel("Username").SetDisabled(true)
+	} else {
+		af.usernameField.SetLabel(template.UsernameLabel).SetDisabled(false)
+	}
+
+	if template.PasswordLabel == "" {
+		af.passwordField.SetText("").SetDisabled(true)
+	} else {
+		af.passwordField.SetDisabled(false)
+	}
+	af.updatePasswordLabel(af.passwordField, []byte(af.passwordField.GetText()))
+	if af.passwordVisible && template.PasswordLabel != "" {
+		af.passwordField.SetLabel(af.passwordLabel() + " [VISIBLE]")
+	}
+
+	text := applyTemplateFields(af.customFieldsArea.GetText(), previous, template)
+	af.customFieldsArea.SetText(text, false)
+	af.templateFieldsText = templateFieldsText(template)
+}
+
 // getCategories retrieves available categories from AppState.
 // Returns default "Uncategorized" if no categories exist.
 func (af *AddForm) getCategories() []string {
@@ -351,7 +571,7 @@ func (af *AddForm) applyStyles() {
 
 	// Style individual input fields
 	// Use BackgroundLight for input fields - lighter than form Background for contrast
-	for i := 0; i < 6; i++ {
+	for i := 0; i < af.form.GetFormItemCount(); i++ {
 		item := af.form.GetFormItem(i)
 		switch field := item.(type) {
 		case *tview.InputField:
@@ -376,19 +596,17 @@ func (af *AddForm) applyStyles() {
 func (af *AddForm) togglePasswordVisibility() {
 	af.passwordVisible = !af.passwordVisible
 
-	passwordField := af.form.GetFormItem(2).(*tview.InputField)
-
 	if af.passwordVisible {
-		passwordField.SetMaskCharacter(0) // 0 = plaintext (tview convention)
-		passwordField.SetLabel("Password [VISIBLE]")
+		af.passwordField.SetMaskCharacter(0) // 0 = plaintext (tview convention)
+		af.passwordField.SetLabel(af.passwordLabel() + " [VISIBLE]")
 	} else {
-		passwordField.SetMaskCharacter('*')
+		af.passwordField.SetMaskCharacter('*')
 		// Restore label with current strength if password exists
-		text := passwordField.GetText()
+		text := af.passwordField.GetText()
 		if text != "" {
-			af.updatePasswordLabel(passwordField, []byte(text))
+			af.updatePasswordLabel(af.passwordField, []byte(text))
 		} else {
-			passwordField.SetLabel("Password")
+			af.passwordField.SetLabel(af.passwordLabel())
 		}
 	}
 }
@@ -442,11 +660,9 @@ func (ef *EditForm) buildFormFieldsWithValues() {
 	categories := ef.getCategories()
 
 	// Pre-populate fields with existing credential data
-	// Service field is read-only (cannot be changed in edit mode)
+	// Changing the service name renames the credential on save
 	// Use 0 width to make fields fill available space (prevents black rectangles)
 	ef.form.AddInputField("Service (UID)", ef.credential.Service, 0, nil, nil)
-	serviceField := ef.form.GetFormItem(0).(*tview.InputField)
-	serviceField.SetDisabled(true) // Make read-only to prevent confusion
 
 	ef.form.AddInputField("Username", ef.credential.Username, 0, nil, nil)
 
@@ -504,6 +720,14 @@ func (ef *EditForm) buildFormFieldsWithValues() {
 	ef.form.AddInputField("URL", ef.credential.URL, 0, nil, nil)
 	ef.form.AddTextArea("Notes", ef.credential.Notes, 0, 5, 0, nil)
 
+	// Custom fields are not part of metadata, so fetch them without tracking usage
+	// Secret values stay out of the form; the mask placeholder keeps them unchanged
+	if cred, err := ef.appState.GetFullCredentialWithTracking(ef.credential.Service, false); err == nil && cred != nil {
+		ef.originalCustomFields = cred.CustomFields
+	}
+	ef.originalFieldsText = formatCustomFields(ef.originalCustomFields)
+	ef.form.AddTextArea(customFieldsLabel, ef.originalFieldsText, 0, 3, 0, nil)
+
 	// Action buttons
 	ef.form.AddButton("Save", ef.onSavePressed)
 	ef.form.AddButton("Cancel", ef.onCancelPressed)
@@ -566,11 +790,23 @@ func (ef *EditForm) performSave() {
 		return
 	}
 
-	// Extract field values
-	// Note: Use original credential.Service as identifier, not form field
-	// This prevents ErrCredentialNotFound if user tries to edit service name
-	// For service renaming, a dedicated rename flow should be implemented
+	// Rename first so the update below targets the new service name
+	// After a successful rename the form tracks the new name, so a retry after a
+	// failed update does not attempt the rename again
 	service := ef.credential.Service
+	newService := strings.TrimSpace(ef.form.GetFormItem(0).(*tview.InputField).GetText())
+	if newService != service {
+		if err := ef.appState.RenameCredential(service, newService); err != nil {
+			// Error already handled by AppState onError callback
+			return
+		}
+		renamed := *ef.credential
+		renamed.Service = newService
+		ef.credential = &renamed
+		service = newService
+	}
+
+	// Extract field values
 	username := ef.form.GetFormItem(1).(*tview.InputField).GetText()
 	password := ef.form.GetFormItem(2).(*tview.InputField).GetText()
 
@@ -605,6 +841,14 @@ func (ef *EditForm) performSave() {
 	// Always set notes (even if empty, to allow clearing)
 	opts.Notes = &notes
 
+	// Only replace custom fields when the text was edited
+	// Already validated above, parse cannot fail here
+	fieldsText := ef.form.GetFormItem(6).(*tview.TextArea).GetText()
+	if fieldsText != ef.originalFieldsText {
+		customFields, _ := parseCustomFields(fieldsText, ef.originalCustomFields)
+		opts.CustomFields = &customFields
+	}
+
 	// Call AppState to update credential with options struct
 	err := ef.appState.UpdateCredential(service, opts)
 	if err != nil {
@@ -646,40 +890,49 @@ func (ef *EditForm) onCancelPressed() {
 
 // hasUnsavedChanges checks if any form fields have been modified from original values.
 func (ef *EditForm) hasUnsavedChanges() bool {
+	service := ef.form.GetFormItem(0).(*tview.InputField).GetText()
 	username := ef.form.GetFormItem(1).(*tview.InputField).GetText()
 	password := ef.form.GetFormItem(2).(*tview.InputField).GetText()
 	category := ef.form.GetFormItem(3).(*tview.InputField).GetText()
 	url := ef.form.GetFormItem(4).(*tview.InputField).GetText()
 	notes := ef.form.GetFormItem(5).(*tview.TextArea).GetText()
+	customFields := ef.form.GetFormItem(6).(*tview.TextArea).GetText()
 
 	// Normalize current category for comparison
 	normalizedCategory := normalizeCategory(category)
 
 	// Compare with original values
-	return username != ef.credential.Username ||
+	return strings.TrimSpace(service) != ef.credential.Service ||
+		username != ef.credential.Username ||
 		password != ef.originalPassword ||
 		normalizedCategory != ef.credential.Category ||
 		url != ef.credential.URL ||
-		notes != ef.credential.Notes
+		notes != ef.credential.Notes ||
+		customFields != ef.originalFieldsText
 }
 
 // validate checks that required fields are filled.
 // Returns error describing first validation failure, or nil if valid.
 func (ef *EditForm) validate() error {
 	// Service is required (cannot be empty)
-	service := ef.form.GetFormItem(0).(*tview.InputField).GetText()
+	service := strings.TrimSpace(ef.form.GetFormItem(0).(*tview.InputField).GetText())
 	if service == "" {
 		return fmt.Errorf("service is required")
 	}
 
-	// Username is required (minimum validation)
+	// Username is required unless the credential type makes it optional
 	username := ef.form.GetFormItem(1).(*tview.InputField).GetText()
-	if username == "" {
+	if template, ok := vault.GetTemplate(ef.credential.Type); username == "" && (!ok || template.UsernameRequired) {
 		return fmt.Errorf("username is required")
 	}
 
 	// Password not required in edit form (can keep existing)
 
+	// Custom fields must parse as key=value lines
+	if _, err := parseCustomFields(ef.form.GetFormItem(6).(*tview.TextArea).GetText(), ef.originalCustomFields); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -789,7 +1042,7 @@ func (ef *EditForm) applyStyles() {
 
 	// Style individual input fields
 	// Use BackgroundLight for input fields - lighter than form Background for contrast
-	for i := 0; i < 6; i++ {
+	for i := 0; i < ef.form.GetFormItemCount(); i++ {
 		item := ef.form.GetFormItem(i)
 		switch field := item.(type) {
 		case *tview.InputField:
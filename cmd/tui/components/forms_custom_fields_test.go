@@ -0,0 +1,116 @@
+package components
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rivo/tview"
+
+	"pass-cli/cmd/tui/models"
+	"pass-cli/internal/vault"
+)
+
+// TestCustomFieldsRoundTrip verifies formatting and parsing keep order and secret values
+func TestCustomFieldsRoundTrip(t *testing.T) {
+	original := []vault.CustomField{
+		{Key: "client_id", Value: "abc"},
+		{Key: "token", Value: "s3cr3t", Secret: true},
+	}
+
+	text := formatCustomFields(original)
+	if text != "client_id=abc\n!token="+maskedFieldValue {
+		t.Fatalf("formatCustomFields() = %q", text)
+	}
+
+	parsed, err := parseCustomFields(text, original)
+	if err != nil {
+		t.Fatalf("parseCustomFields() failed: %v", err)
+	}
+	if len(parsed) != 2 || parsed[0] != original[0] || parsed[1] != original[1] {
+		t.Errorf("parseCustomFields() = %+v, want %+v", parsed, original)
+	}
+}
+
+// TestParseCustomFields verifies edits, blank lines, and invalid input
+func TestParseCustomFields(t *testing.T) {
+	parsed, err := parseCustomFields("region = eu-west-1\n\n!token=new-value\n", nil)
+	if err != nil {
+		t.Fatalf("parseCustomFields() failed: %v", err)
+	}
+	if len(parsed) != 2 {
+		t.Fatalf("expected 2 fields, got %d", len(parsed))
+	}
+	if parsed[0].Key != "region" || parsed[0].Value != "eu-west-1" || parsed[0].Secret {
+		t.Errorf("unexpected plain field: %+v", parsed[0])
+	}
+	if parsed[1].Key != "token" || parsed[1].Value != "new-value" || !parsed[1].Secret {
+		t.Errorf("unexpected secret field: %+v", parsed[1])
+	}
+
+	if _, err := parseCustomFields("missing-separator", nil); err == nil {
+		t.Error("expected error for line without '='")
+	}
+}
+
+// TestApplyTemplateFields verifies placeholders are swapped when the credential type changes
+func TestApplyTemplateFields(t *testing.T) {
+	login, _ := vault.GetTemplate(vault.TypeLogin)
+	database, _ := vault.GetTemplate(vault.TypeDatabase)
+	ssh, _ := vault.GetTemplate(vault.TypeSSHKey)
+
+	text := applyTemplateFields("region=eu", login, database)
+	if text != "region=eu\nhost=\nport=\ndatabase=" {
+		t.Fatalf("applyTemplateFields() login->database = %q", text)
+	}
+
+	// Filled-in values survive, empty placeholders of the old type are dropped
+	text = applyTemplateFields("region=eu\nhost=db.internal\nport=\ndatabase=", database, ssh)
+	if text != "region=eu\nhost=db.internal\nport=\nfingerprint=" {
+		t.Errorf("applyTemplateFields() database->ssh = %q", text)
+	}
+}
+
+// TestAddFormTypeSwitch verifies the form relabels and validates per credential type
+func TestAddFormTypeSwitch(t *testing.T) {
+	form := NewAddForm(models.NewAppState(newMockVaultServiceForForms()))
+
+	database, _ := vault.GetTemplate(vault.TypeDatabase)
+	form.applyTemplate(database)
+
+	form.form.GetFormItem(0).(*tview.InputField).SetText("orders-db")
+	form.form.GetFormItem(1).(*tview.InputField).SetText("app")
+	form.form.GetFormItem(2).(*tview.InputField).SetText("pass")
+
+	if passwordField := form.form.GetFormItem(2).(*tview.InputField); !strings.HasPrefix(passwordField.GetLabel(), "Password") {
+		t.Errorf("expected database password label 'Password', got %q", passwordField.GetLabel())
+	}
+	if !form.hasUnsavedData() {
+		t.Error("expected unsaved data after typing a service")
+	}
+	if err := form.validate(); err == nil || !strings.Contains(err.Error(), "Host") {
+		t.Errorf("expected missing Host error, got %v", err)
+	}
+
+	apiKey, _ := vault.GetTemplate(vault.TypeAPIKey)
+	form.applyTemplate(apiKey)
+	if label := form.form.GetFormItem(1).(*tview.InputField).GetLabel(); label != "Key ID" {
+		t.Errorf("expected username label 'Key ID', got %q", label)
+	}
+	if label := form.form.GetFormItem(2).(*tview.InputField).GetLabel(); !strings.HasPrefix(label, "Secret") {
+		t.Errorf("expected password label 'Secret', got %q", label)
+	}
+
+	note, _ := vault.GetTemplate(vault.TypeSecureNote)
+	form.applyTemplate(note)
+
+	passwordField := form.form.GetFormItem(2).(*tview.InputField)
+	if passwordField.GetText() != "" {
+		t.Error("expected password field to be cleared for secure notes")
+	}
+	if text := form.form.GetFormItem(7).(*tview.TextArea).GetText(); text != "" {
+		t.Errorf("expected template placeholders to be removed, got %q", text)
+	}
+	if err := form.validate(); err != nil {
+		t.Errorf("secure note should validate without username or password, got %v", err)
+	}
+}
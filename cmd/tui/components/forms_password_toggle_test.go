@@ -40,6 +40,10 @@ func (m *mockVaultServiceForForms) AddCredential(service, username string, passw
 	return nil
 }
 
+func (m *mockVaultServiceForForms) AddCredentialWithOpts(service, username string, password []byte, category, url, notes string, opts vault.AddOpts) error {
+	return m.AddCredential(service, username, password, category, url, notes)
+}
+
 func (m *mockVaultServiceForForms) UpdateCredential(service string, opts vault.UpdateOpts) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -91,6 +95,32 @@ func (m *mockVaultServiceForForms) RecordFieldAccess(service, field string) erro
 	return nil
 }
 
+func (m *mockVaultServiceForForms) RenameCredential(oldService, newService string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, cred := range m.credentials {
+		if cred.Service == oldService {
+			m.credentials[i].Service = newService
+			return nil
+		}
+	}
+	return errors.New("not found")
+}
+
+func (m *mockVaultServiceForForms) ListTrash() ([]vault.TrashEntry, error) {
+	return nil, nil
+}
+
+func (m *mockVaultServiceForForms) RestoreCredential(service string) error {
+	return nil
+}
+
+func (m *mockVaultServiceForForms) PurgeTrash(olderThan time.Duration) (int, error) {
+	return 0, nil
+}
+
+func (m *mockVaultServiceForForms) Lock() {}
+
 // TestAddFormPasswordVisibilityToggle verifies the toggle changes label
 // T004: Unit test for AddForm password visibility toggle functionality
 // NOTE: tview InputField doesn't expose GetMaskCharacter(), so we test via label changes
@@ -0,0 +1,52 @@
+package components
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rivo/tview"
+
+	"pass-cli/cmd/tui/models"
+	"pass-cli/internal/vault"
+)
+
+// TestEditFormRenameService verifies changing the service name renames before updating
+func TestEditFormRenameService(t *testing.T) {
+	mockVault := newMockVaultServiceForForms()
+	mockVault.credentials = []vault.CredentialMetadata{
+		{Service: "github", Username: "user", CreatedAt: time.Now()},
+	}
+	appState := models.NewAppState(mockVault)
+	_ = appState.LoadCredentials()
+
+	credential, _ := appState.FindCredentialByService("github")
+	appState.SetSelectedCredential(credential)
+	form := NewEditForm(appState, credential)
+
+	submitted := false
+	form.SetOnSubmit(func() { submitted = true })
+
+	form.GetFormItem(0).(*tview.InputField).SetText("github-work")
+	form.GetFormItem(1).(*tview.InputField).SetText("work-user")
+	if !form.hasUnsavedChanges() {
+		t.Error("Expected a service name change to count as unsaved")
+	}
+	form.performSave()
+
+	if !submitted {
+		t.Fatal("Expected save to succeed")
+	}
+	if _, found := appState.FindCredentialByService("github"); found {
+		t.Error("Expected old service name to be gone")
+	}
+	renamed, found := appState.FindCredentialByService("github-work")
+	if !found {
+		t.Fatal("Expected renamed credential")
+	}
+	if renamed.Username != "work-user" {
+		t.Errorf("Expected username 'work-user' on renamed credential, got '%s'", renamed.Username)
+	}
+	if selected := appState.GetSelectedCredential(); selected == nil || selected.Service != "github-work" {
+		t.Errorf("Expected selection to follow the rename, got %+v", selected)
+	}
+}
@@ -10,14 +10,18 @@ import (
 )
 
 // NodeReference identifies the type and value of a tree node.
-// Used to distinguish categories from credentials without relying on tree position.
+// Used to distinguish categories, types, tags, and credentials without relying on tree position.
 type NodeReference struct {
-	Kind  string // "category" or "credential"
-	Value string // Category name or service name
+	Kind     string // "category", "folder", "types", "type", "tag", or "credential"
+	Value    string // Category name, folder path, type name, tag name, or service name
+	Category string // Category a folder node belongs to (folder nodes only)
 }
 
-// Sidebar wraps tview.TreeView to display credential categories.
-// Provides category navigation with "All Credentials" root and category children.
+// tagNodePrefix marks tag nodes so they read differently from categories
+const tagNodePrefix = "#"
+
+// Sidebar wraps tview.TreeView to display credential categories, types, and tags.
+// Provides category navigation with "All Credentials" root, category children, a "Types" group, and tag children.
 type Sidebar struct {
 	*tview.TreeView
 
@@ -67,7 +71,10 @@ func NewSidebar(appState *models.AppState) *Sidebar {
 }
 
 // Refresh rebuilds the category tree from current AppState.
-// Clears existing children and builds category-grouped tree with credential nodes.
+// Clears existing children and builds category-grouped tree with credential nodes
+// (hierarchical service names such as "work/aws/prod" are nested in folder nodes),
+// followed by a "Types" group (when any credential is not a plain login) and tag nodes
+// (a credential appears under every tag it carries).
 func (s *Sidebar) Refresh() {
 	theme := styles.GetCurrentTheme()
 
@@ -109,25 +116,139 @@ func (s *Sidebar) Refresh() {
 			return credList[i].Service < credList[j].Service
 		})
 
-		// Add credential nodes from sorted list
+		// Add credential nodes from sorted list, nested in folders by service path
+		s.addPathNodes(categoryNode, category, credList)
+
+		// Add category node to root
+		s.rootNode.AddChild(categoryNode)
+	}
+
+	// Type nodes follow categories, grouped under a "Types" node in template order
+	typeGroups := make(map[vault.CredentialType][]vault.CredentialMetadata)
+	for _, cred := range credentials {
+		typeGroups[cred.EffectiveType()] = append(typeGroups[cred.EffectiveType()], cred)
+	}
+	if len(typeGroups) > 1 || len(typeGroups[vault.TypeLogin]) < len(credentials) {
+		typesNode := tview.NewTreeNode("Types").
+			SetSelectable(true).
+			SetColor(theme.TextPrimary).
+			SetReference(NodeReference{Kind: "types"}).
+			SetExpanded(false) // Collapsed by default
+
+		for _, template := range vault.CredentialTemplates() {
+			credList := typeGroups[template.Type]
+			if len(credList) == 0 {
+				continue
+			}
+
+			typeNode := tview.NewTreeNode(template.Label).
+				SetSelectable(true).
+				SetColor(theme.TextPrimary).
+				SetReference(NodeReference{Kind: "type", Value: string(template.Type)}).
+				SetExpanded(false) // Collapsed by default
+
+			sort.Slice(credList, func(i, j int) bool {
+				return credList[i].Service < credList[j].Service
+			})
+
+			for _, cred := range credList {
+				credNode := tview.NewTreeNode(cred.Service).
+					SetSelectable(true).
+					SetColor(theme.TextSecondary).
+					SetReference(NodeReference{Kind: "credential", Value: cred.Service})
+
+				typeNode.AddChild(credNode)
+			}
+
+			typesNode.AddChild(typeNode)
+		}
+
+		s.rootNode.AddChild(typesNode)
+	}
+
+	// Tag nodes follow types; credentials appear under each of their tags
+	tagGroups := make(map[string][]vault.CredentialMetadata)
+	for _, cred := range credentials {
+		for _, tag := range cred.Tags {
+			tagGroups[tag] = append(tagGroups[tag], cred)
+		}
+	}
+
+	tags := make([]string, 0, len(tagGroups))
+	for tag := range tagGroups {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+
+	for _, tag := range tags {
+		tagNode := tview.NewTreeNode(tagNodePrefix + tag).
+			SetSelectable(true).
+			SetColor(theme.TextAccent). // Accent color distinguishes tags from categories
+			SetReference(NodeReference{Kind: "tag", Value: tag}).
+			SetExpanded(false) // Collapsed by default
+
+		credList := tagGroups[tag]
+		sort.Slice(credList, func(i, j int) bool {
+			return credList[i].Service < credList[j].Service
+		})
+
 		for _, cred := range credList {
-			// Create credential node with NodeReference
 			credNode := tview.NewTreeNode(cred.Service).
 				SetSelectable(true).
-				SetColor(theme.TextSecondary). // Gray text to distinguish from category
+				SetColor(theme.TextSecondary).
 				SetReference(NodeReference{Kind: "credential", Value: cred.Service})
 
-			categoryNode.AddChild(credNode)
+			tagNode.AddChild(credNode)
 		}
 
-		// Add category node to root
-		s.rootNode.AddChild(categoryNode)
+		s.rootNode.AddChild(tagNode)
 	}
 
 	// Ensure root is expanded
 	s.rootNode.SetExpanded(true)
 }
 
+// addPathNodes adds credential nodes under parent, nesting hierarchical service names
+// in collapsible folder nodes. Credentials without a path separator are added directly.
+// credList must already be sorted by service name.
+func (s *Sidebar) addPathNodes(parent *tview.TreeNode, category string, credList []vault.CredentialMetadata) {
+	theme := styles.GetCurrentTheme()
+	folders := make(map[string]*tview.TreeNode)
+
+	var folderNode func(path string) *tview.TreeNode
+	folderNode = func(path string) *tview.TreeNode {
+		if path == "" {
+			return parent
+		}
+		if node, ok := folders[path]; ok {
+			return node
+		}
+		node := tview.NewTreeNode(vault.ServiceBaseName(path) + vault.PathSeparator).
+			SetSelectable(true).
+			SetColor(theme.TextPrimary).
+			SetReference(NodeReference{Kind: "folder", Value: path, Category: category}).
+			SetExpanded(false) // Collapsed by default
+		folderNode(vault.ServiceFolder(path)).AddChild(node)
+		folders[path] = node
+		return node
+	}
+
+	for _, cred := range credList {
+		label := cred.Service
+		folder := vault.ServiceFolder(cred.Service)
+		if base := vault.ServiceBaseName(cred.Service); folder != "" && base != "" {
+			label = base
+		}
+
+		credNode := tview.NewTreeNode(label).
+			SetSelectable(true).
+			SetColor(theme.TextSecondary). // Gray text to distinguish from category
+			SetReference(NodeReference{Kind: "credential", Value: cred.Service})
+
+		folderNode(folder).AddChild(credNode)
+	}
+}
+
 // onSelect handles node selection by updating AppState.
 // Root node shows all, category nodes filter by category, credential nodes select specific credential.
 func (s *Sidebar) onSelect(node *tview.TreeNode) {
@@ -155,6 +276,22 @@ func (s *Sidebar) onSelect(node *tview.TreeNode) {
 			// Use SetSelection for atomic update with single notification
 			s.appState.SetSelection(nodeRef.Value, nil)
 
+		case "folder":
+			// Folder node - filter to the credentials below this path within its category
+			s.appState.SetFolderSelection(nodeRef.Category, nodeRef.Value)
+
+		case "types":
+			// Types group node - shows all credentials like the root
+			s.appState.SetSelection("", nil)
+
+		case "type":
+			// Type node - filter by credential type (replaces category/tag filter)
+			s.appState.SetTypeSelection(vault.CredentialType(nodeRef.Value))
+
+		case "tag":
+			// Tag node - filter by tag (replaces category filter) and clear credential selection
+			s.appState.SetTagSelection(nodeRef.Value)
+
 		case "credential":
 			// Credential node - lookup credential by service and select it
 			if credMeta, found := s.appState.FindCredentialByService(nodeRef.Value); found {
@@ -14,6 +14,7 @@ import (
 type MockVaultService struct {
 	mu          sync.Mutex
 	credentials []vault.CredentialMetadata
+	trash       []vault.TrashEntry
 }
 
 func NewMockVaultService() *MockVaultService {
@@ -37,6 +38,10 @@ func (m *MockVaultService) AddCredential(service, username string, password []by
 	return nil
 }
 
+func (m *MockVaultService) AddCredentialWithOpts(service, username string, password []byte, category, url, notes string, opts vault.AddOpts) error {
+	return m.AddCredential(service, username, password, category, url, notes)
+}
+
 func (m *MockVaultService) UpdateCredential(service string, opts vault.UpdateOpts) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -66,6 +71,7 @@ func (m *MockVaultService) DeleteCredential(service string) error {
 	for i, cred := range m.credentials {
 		if cred.Service == service {
 			m.credentials = append(m.credentials[:i], m.credentials[i+1:]...)
+			m.trash = append(m.trash, vault.TrashEntry{Service: cred.Service, Username: cred.Username, DeletedAt: time.Now()})
 			return nil
 		}
 	}
@@ -88,6 +94,47 @@ func (m *MockVaultService) RecordFieldAccess(service, field string) error {
 	return nil
 }
 
+func (m *MockVaultService) RenameCredential(oldService, newService string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, cred := range m.credentials {
+		if cred.Service == oldService {
+			m.credentials[i].Service = newService
+			return nil
+		}
+	}
+	return errors.New("not found")
+}
+
+func (m *MockVaultService) ListTrash() ([]vault.TrashEntry, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.trash, nil
+}
+
+func (m *MockVaultService) RestoreCredential(service string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, entry := range m.trash {
+		if entry.Service == service {
+			m.trash = append(m.trash[:i], m.trash[i+1:]...)
+			m.credentials = append(m.credentials, vault.CredentialMetadata{Service: entry.Service, Username: entry.Username})
+			return nil
+		}
+	}
+	return errors.New("not found")
+}
+
+func (m *MockVaultService) PurgeTrash(olderThan time.Duration) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	purged := len(m.trash)
+	m.trash = nil
+	return purged, nil
+}
+
+func (m *MockVaultService) Lock() {}
+
 func (m *MockVaultService) SetCredentials(creds []vault.CredentialMetadata) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -578,3 +625,168 @@ func TestSidebarRefresh_MixedCategoriesAndUncategorized(t *testing.T) {
 		}
 	}
 }
+
+// TestSidebarSelection_TagNode verifies tag nodes follow categories and filter the table.
+func TestSidebarSelection_TagNode(t *testing.T) {
+	mockVault := NewMockVaultService()
+	state := models.NewAppState(mockVault)
+
+	mockCreds := []vault.CredentialMetadata{
+		{Service: "aws-prod", Category: "Cloud", Tags: []string{"prod", "aws"}, CreatedAt: time.Now()},
+		{Service: "db-prod", Category: "Databases", Tags: []string{"prod"}, CreatedAt: time.Now()},
+		{Service: "github", Category: "Cloud", CreatedAt: time.Now()},
+	}
+	mockVault.SetCredentials(mockCreds)
+	_ = state.LoadCredentials()
+
+	sidebar := NewSidebar(state)
+	table := NewCredentialTable(state)
+
+	// Expect 2 category nodes followed by 2 tag nodes (#aws, #prod)
+	children := sidebar.rootNode.GetChildren()
+	if len(children) != 4 {
+		t.Fatalf("Expected 4 nodes (2 categories + 2 tags), got %d", len(children))
+	}
+
+	prodNode := children[3]
+	if prodNode.GetText() != "#prod" {
+		t.Fatalf("Expected '#prod' tag node, got '%s'", prodNode.GetText())
+	}
+	if len(prodNode.GetChildren()) != 2 {
+		t.Errorf("Expected 2 credentials under #prod, got %d", len(prodNode.GetChildren()))
+	}
+
+	// Selecting the tag filters the table and clears the category filter
+	state.SetSelection("Cloud", nil)
+	sidebar.onSelect(prodNode)
+	if state.GetSelectedTag() != "prod" || state.GetSelectedCategory() != "" {
+		t.Errorf("Expected tag 'prod' with no category, got tag '%s' category '%s'",
+			state.GetSelectedTag(), state.GetSelectedCategory())
+	}
+
+	table.Refresh()
+	if got := table.GetRowCount() - 1; got != 2 {
+		t.Errorf("Expected 2 rows for #prod, got %d", got)
+	}
+
+	// Selecting a category replaces the tag filter
+	sidebar.onSelect(children[0])
+	if state.GetSelectedTag() != "" {
+		t.Errorf("Expected tag filter cleared, got '%s'", state.GetSelectedTag())
+	}
+}
+
+// TestSidebarSelection_FolderNode verifies hierarchical service names nest in folder nodes
+// and selecting a folder filters the table to its subtree.
+func TestSidebarSelection_FolderNode(t *testing.T) {
+	mockVault := NewMockVaultService()
+	state := models.NewAppState(mockVault)
+
+	mockCreds := []vault.CredentialMetadata{
+		{Service: "github", Category: "Work", CreatedAt: time.Now()},
+		{Service: "work/aws/prod/deploy-bot", Category: "Work", CreatedAt: time.Now()},
+		{Service: "work/aws/staging", Category: "Work", CreatedAt: time.Now()},
+		{Service: "work/gitlab", Category: "Work", CreatedAt: time.Now()},
+	}
+	mockVault.SetCredentials(mockCreds)
+	_ = state.LoadCredentials()
+
+	sidebar := NewSidebar(state)
+	table := NewCredentialTable(state)
+
+	children := sidebar.rootNode.GetChildren()
+	if len(children) != 1 {
+		t.Fatalf("Expected 1 category node, got %d", len(children))
+	}
+
+	// Category holds the flat credential plus the "work/" folder
+	categoryChildren := children[0].GetChildren()
+	if len(categoryChildren) != 2 {
+		t.Fatalf("Expected 2 nodes under category, got %d", len(categoryChildren))
+	}
+	workNode := categoryChildren[1]
+	if workNode.GetText() != "work/" {
+		t.Fatalf("Expected 'work/' folder node, got '%s'", workNode.GetText())
+	}
+
+	// work/ holds the aws/ folder and the gitlab leaf
+	workChildren := workNode.GetChildren()
+	if len(workChildren) != 2 || workChildren[0].GetText() != "aws/" || workChildren[1].GetText() != "gitlab" {
+		t.Fatalf("Unexpected children under work/: %d", len(workChildren))
+	}
+	leafRef, _ := workChildren[1].GetReference().(NodeReference)
+	if leafRef.Kind != "credential" || leafRef.Value != "work/gitlab" {
+		t.Errorf("Expected leaf reference to full service name, got %+v", leafRef)
+	}
+
+	// Selecting the aws/ folder narrows the table to its subtree
+	sidebar.onSelect(workChildren[0])
+	if state.GetSelectedCategory() != "Work" || state.GetSelectedPath() != "work/aws" {
+		t.Errorf("Expected category 'Work' path 'work/aws', got '%s' '%s'",
+			state.GetSelectedCategory(), state.GetSelectedPath())
+	}
+
+	table.Refresh()
+	if got := table.GetRowCount() - 1; got != 2 {
+		t.Errorf("Expected 2 rows under work/aws, got %d", got)
+	}
+
+	// Selecting the category clears the folder filter
+	sidebar.onSelect(children[0])
+	if state.GetSelectedPath() != "" {
+		t.Errorf("Expected folder filter cleared, got '%s'", state.GetSelectedPath())
+	}
+}
+
+// TestSidebarSelection_TypeNode verifies the Types group and filtering by credential type.
+func TestSidebarSelection_TypeNode(t *testing.T) {
+	mockVault := NewMockVaultService()
+	state := models.NewAppState(mockVault)
+
+	mockCreds := []vault.CredentialMetadata{
+		{Service: "orders-db", Category: "Databases", Type: vault.TypeDatabase, CreatedAt: time.Now()},
+		{Service: "users-db", Category: "Databases", Type: vault.TypeDatabase, CreatedAt: time.Now()},
+		{Service: "github", Category: "Cloud", CreatedAt: time.Now()},
+	}
+	mockVault.SetCredentials(mockCreds)
+	_ = state.LoadCredentials()
+
+	sidebar := NewSidebar(state)
+	table := NewCredentialTable(state)
+
+	// Expect 2 category nodes followed by the Types group
+	children := sidebar.rootNode.GetChildren()
+	if len(children) != 3 {
+		t.Fatalf("Expected 3 nodes (2 categories + Types), got %d", len(children))
+	}
+
+	typesNode := children[2]
+	if typesNode.GetText() != "Types" {
+		t.Fatalf("Expected 'Types' node, got '%s'", typesNode.GetText())
+	}
+
+	// Untyped credentials are grouped as logins, in template order
+	typeNodes := typesNode.GetChildren()
+	if len(typeNodes) != 2 || typeNodes[0].GetText() != "Login" || typeNodes[1].GetText() != "Database" {
+		t.Fatalf("Expected Login and Database type nodes, got %d nodes", len(typeNodes))
+	}
+
+	// Selecting a type filters the table and clears the category filter
+	state.SetSelection("Cloud", nil)
+	sidebar.onSelect(typeNodes[1])
+	if state.GetSelectedType() != vault.TypeDatabase || state.GetSelectedCategory() != "" {
+		t.Errorf("Expected type 'database' with no category, got type '%s' category '%s'",
+			state.GetSelectedType(), state.GetSelectedCategory())
+	}
+
+	table.Refresh()
+	if got := table.GetRowCount() - 1; got != 2 {
+		t.Errorf("Expected 2 rows for Database, got %d", got)
+	}
+
+	// Selecting a category replaces the type filter
+	sidebar.onSelect(children[0])
+	if state.GetSelectedType() != "" {
+		t.Errorf("Expected type filter cleared, got '%s'", state.GetSelectedType())
+	}
+}
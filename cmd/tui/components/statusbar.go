@@ -131,6 +131,11 @@ func (sb *StatusBar) getShortcutsForContext(focus FocusContext) string {
 	}
 
 	if isSearchActive {
+		// Query parse errors replace the hints until the query is fixed
+		if err := searchState.QueryError(); err != nil {
+			return fmt.Sprintf("[red]%s[-]  [yellow]Esc[-]:Exit", tview.Escape(err.Error()))
+		}
+
 		// Search mode shortcuts - First Esc exits search input (keeps filter), second Esc clears filter
 		helpKey := formatKey("help")
 		return fmt.Sprintf("[white]Type to filter  [yellow]↑↓[-]:Navigate  [yellow]Esc[-]:Exit (Esc again clears)  [yellow]p[-]:Show  [yellow]c[-]:Copy  %s:Help", helpKey)
@@ -2,8 +2,10 @@ package components
 
 import (
 	"fmt"
+	"sort"
 	"time"
 
+	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
 	"pass-cli/cmd/tui/models"
 	"pass-cli/cmd/tui/styles"
@@ -11,7 +13,7 @@ import (
 )
 
 // CredentialTable wraps tview.Table to display credentials in tabular format.
-// Supports filtering by category and selection handling.
+// Supports filtering by category or tag and selection handling.
 type CredentialTable struct {
 	*tview.Table
 
@@ -71,13 +73,16 @@ func (ct *CredentialTable) buildHeader() {
 }
 
 // Refresh rebuilds the table from filtered credentials.
-// Gets credentials from AppState, filters by selected category and search query, and updates rows.
+// Gets credentials from AppState, filters by selected category or tag and search query, and updates rows.
 // Uses incremental updates: reuses existing rows instead of full rebuild for better performance.
 func (ct *CredentialTable) Refresh() {
 	// Get credentials and filter by category (thread-safe read)
 	allCreds := ct.appState.GetCredentials()
 	category := ct.appState.GetSelectedCategory()
 	categoryFiltered := ct.filterByCategory(allCreds, category)
+	categoryFiltered = ct.filterByPath(categoryFiltered, ct.appState.GetSelectedPath())
+	categoryFiltered = ct.filterByTag(categoryFiltered, ct.appState.GetSelectedTag())
+	categoryFiltered = ct.filterByType(categoryFiltered, ct.appState.GetSelectedType())
 
 	// Apply search filter on top of category filter
 	searchState := ct.appState.GetSearchState()
@@ -88,6 +93,7 @@ func (ct *CredentialTable) Refresh() {
 	newRowCount := len(ct.filteredCreds)
 
 	theme := styles.GetCurrentTheme()
+	now := time.Now()
 
 	// Update existing rows and add new ones if needed
 	for i, cred := range ct.filteredCreds {
@@ -95,7 +101,8 @@ func (ct *CredentialTable) Refresh() {
 
 		if i < currentRowCount {
 			// Reuse existing row - update cell contents
-			ct.GetCell(row, 0).SetText(cred.Service).SetReference(cred)
+			ct.GetCell(row, 0).SetText(cred.Service).SetReference(cred).
+				SetTextColor(serviceColor(cred, now))
 			ct.GetCell(row, 1).SetText(cred.Username)
 
 			lastUsed := "Never"
@@ -106,7 +113,7 @@ func (ct *CredentialTable) Refresh() {
 		} else {
 			// Add new row (same as populateRows logic)
 			serviceCell := tview.NewTableCell(cred.Service).
-				SetTextColor(theme.TextPrimary).
+				SetTextColor(serviceColor(cred, now)).
 				SetAlign(tview.AlignLeft).
 				SetReference(cred)
 
@@ -148,6 +155,19 @@ func (ct *CredentialTable) Refresh() {
 	}
 }
 
+// serviceColor highlights expired (red) and soon-to-expire (yellow) credentials
+func serviceColor(cred vault.CredentialMetadata, now time.Time) tcell.Color {
+	theme := styles.GetCurrentTheme()
+	switch vault.GetExpiryStatus(cred.ExpiresAt, now, vault.DefaultExpiryWarning) {
+	case vault.ExpiryExpired:
+		return theme.Error
+	case vault.ExpirySoon:
+		return theme.Warning
+	default:
+		return theme.TextPrimary
+	}
+}
+
 // applySelection applies selection for a given row by updating AppState.
 // Used by both arrow key navigation and Enter key activation handlers.
 // Sources credentials via FindCredentialByService to ensure consistency and avoid stale pointers.
@@ -187,7 +207,55 @@ func (ct *CredentialTable) filterByCategory(creds []vault.CredentialMetadata, ca
 	return filtered
 }
 
-// filterBySearch filters credentials by search query.
+// filterByTag filters credentials by selected tag.
+// Empty tag returns all credentials.
+func (ct *CredentialTable) filterByTag(creds []vault.CredentialMetadata, tag string) []vault.CredentialMetadata {
+	if tag == "" {
+		return creds // Show all
+	}
+
+	filtered := make([]vault.CredentialMetadata, 0)
+	for _, cred := range creds {
+		if vault.HasTag(cred.Tags, tag) {
+			filtered = append(filtered, cred)
+		}
+	}
+	return filtered
+}
+
+// filterByType filters credentials by selected credential type.
+// Empty type returns all credentials.
+func (ct *CredentialTable) filterByType(creds []vault.CredentialMetadata, credType vault.CredentialType) []vault.CredentialMetadata {
+	if credType == "" {
+		return creds // Show all
+	}
+
+	filtered := make([]vault.CredentialMetadata, 0)
+	for _, cred := range creds {
+		if cred.EffectiveType() == credType {
+			filtered = append(filtered, cred)
+		}
+	}
+	return filtered
+}
+
+// filterByPath filters credentials to those at or below a service folder path.
+// Returns all credentials if path is empty.
+func (ct *CredentialTable) filterByPath(creds []vault.CredentialMetadata, path string) []vault.CredentialMetadata {
+	if path == "" {
+		return creds // Show all
+	}
+
+	filtered := make([]vault.CredentialMetadata, 0)
+	for _, cred := range creds {
+		if vault.ServiceInPath(cred.Service, path) {
+			filtered = append(filtered, cred)
+		}
+	}
+	return filtered
+}
+
+// filterBySearch filters credentials by search query and orders them by match score, best first.
 // Returns all credentials if search is inactive or query is empty.
 func (ct *CredentialTable) filterBySearch(creds []vault.CredentialMetadata, searchState *models.SearchState) []vault.CredentialMetadata {
 	if searchState == nil || !searchState.Active || searchState.Query == "" {
@@ -195,11 +263,18 @@ func (ct *CredentialTable) filterBySearch(creds []vault.CredentialMetadata, sear
 	}
 
 	filtered := make([]vault.CredentialMetadata, 0)
+	scores := make(map[string]int)
 	for _, cred := range creds {
-		if searchState.MatchesCredential(&cred) {
+		if score, ok := searchState.Score(&cred); ok {
 			filtered = append(filtered, cred)
+			scores[cred.Service] = score
 		}
 	}
+
+	// Equal scores keep their existing (alphabetical) order
+	sort.SliceStable(filtered, func(i, j int) bool {
+		return scores[filtered[i].Service] > scores[filtered[j].Service]
+	})
 	return filtered
 }
 
@@ -83,6 +83,45 @@ func TestCredentialTableRefresh(t *testing.T) {
 	}
 }
 
+// TestCredentialTableRefresh_SearchRanking verifies search results are ordered by fuzzy score.
+func TestCredentialTableRefresh_SearchRanking(t *testing.T) {
+	mockVault := NewMockVaultService()
+	state := models.NewAppState(mockVault)
+
+	mockCreds := []vault.CredentialMetadata{
+		{Service: "aws-prod", Username: "admin"},
+		{Service: "github", Username: "octocat"},
+		{Service: "grafana-hub", Username: "viewer"},
+	}
+	mockVault.SetCredentials(mockCreds)
+	_ = state.LoadCredentials()
+
+	searchState := state.GetSearchState()
+	searchState.Active = true
+	searchState.Query = "hub"
+
+	table := NewCredentialTable(state)
+	table.Refresh()
+
+	// "hub" starting a word ranks above "hub" inside a word
+	if table.GetRowCount() != 3 {
+		t.Fatalf("Expected 3 rows (1 header + 2 matches), got %d", table.GetRowCount())
+	}
+	if got := table.GetCell(1, 0).Text; got != "grafana-hub" {
+		t.Errorf("Expected best match 'grafana-hub' in row 1, got '%s'", got)
+	}
+	if got := table.GetCell(2, 0).Text; got != "github" {
+		t.Errorf("Expected 'github' in row 2, got '%s'", got)
+	}
+
+	// Field selectors filter without reordering
+	searchState.Query = "user:i"
+	table.Refresh()
+	if got := table.GetCell(1, 0).Text; got != "aws-prod" {
+		t.Errorf("Expected 'aws-prod' in row 1 for selector query, got '%s'", got)
+	}
+}
+
 // TestCredentialTableRefresh_CategoryFilter verifies filtering by category.
 func TestCredentialTableRefresh_CategoryFilter(t *testing.T) {
 	mockVault := NewMockVaultService()
@@ -0,0 +1,217 @@
+package components
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"pass-cli/cmd/tui/models"
+	"pass-cli/cmd/tui/styles"
+	"pass-cli/internal/vault"
+)
+
+// TrashView provides a modal listing deleted credentials.
+// Enter or r restores the selected credential, P purges the whole trash (with confirmation).
+type TrashView struct {
+	*tview.Flex
+	table *tview.Table
+
+	appState *models.AppState
+	entries  []vault.TrashEntry
+
+	onRestore      func(service string)
+	onPurge        func(purged int)
+	onClose        func()
+	onPurgeConfirm func(message string, onYes func(), onNo func()) // Callback to show confirmation dialog
+}
+
+// NewTrashView creates the trash modal and loads the current trash contents.
+func NewTrashView(appState *models.AppState) *TrashView {
+	tv := &TrashView{
+		table:    tview.NewTable(),
+		appState: appState,
+	}
+
+	tv.table.SetSelectable(true, false) // Select rows, not columns
+	tv.table.SetFixed(1, 0)             // Fix header row
+	tv.applyStyles()
+	tv.setupKeyboardShortcuts()
+	tv.wrapInFrame()
+	tv.Refresh()
+
+	return tv
+}
+
+// Refresh reloads the trash entries and rebuilds the table.
+func (tv *TrashView) Refresh() {
+	entries, err := tv.appState.ListTrash()
+	if err != nil {
+		entries = nil
+	}
+	tv.entries = entries
+
+	theme := styles.GetCurrentTheme()
+	tv.table.Clear()
+
+	headers := []string{"Service", "Username", "Category", "Deleted"}
+	for col, header := range headers {
+		tv.table.SetCell(0, col, tview.NewTableCell(header).
+			SetTextColor(theme.TableHeader).
+			SetAttributes(tcell.AttrBold).
+			SetSelectable(false).
+			SetExpansion(1))
+	}
+
+	if len(tv.entries) == 0 {
+		tv.table.SetCell(1, 0, tview.NewTableCell("Trash is empty").
+			SetTextColor(theme.TextSecondary).
+			SetSelectable(false))
+		return
+	}
+
+	for i, entry := range tv.entries {
+		row := i + 1
+		tv.table.SetCell(row, 0, tview.NewTableCell(entry.Service).SetTextColor(theme.TextPrimary).SetExpansion(1))
+		tv.table.SetCell(row, 1, tview.NewTableCell(entry.Username).SetTextColor(theme.TextPrimary).SetExpansion(1))
+		tv.table.SetCell(row, 2, tview.NewTableCell(entry.Category).SetTextColor(theme.TextSecondary).SetExpansion(1))
+		tv.table.SetCell(row, 3, tview.NewTableCell(formatRelativeTime(entry.DeletedAt)).SetTextColor(theme.TextSecondary).SetExpansion(1))
+	}
+
+	// Keep the selection within bounds after restoring the last row
+	row, _ := tv.table.GetSelection()
+	if row < 1 || row > len(tv.entries) {
+		tv.table.Select(1, 0)
+	}
+}
+
+// GetSelectedEntry returns the selected trash entry, or nil if the trash is empty.
+func (tv *TrashView) GetSelectedEntry() *vault.TrashEntry {
+	row, _ := tv.table.GetSelection()
+	index := row - 1
+	if index < 0 || index >= len(tv.entries) {
+		return nil
+	}
+	entry := tv.entries[index]
+	return &entry
+}
+
+// restoreSelected moves the selected credential back into the vault.
+func (tv *TrashView) restoreSelected() {
+	entry := tv.GetSelectedEntry()
+	if entry == nil {
+		return
+	}
+
+	// AppState reports errors through its error callback (status bar)
+	if err := tv.appState.RestoreCredential(entry.Service); err != nil {
+		return
+	}
+
+	tv.Refresh()
+	if tv.onRestore != nil {
+		tv.onRestore(entry.Service)
+	}
+}
+
+// purgeAll asks for confirmation and permanently empties the trash.
+func (tv *TrashView) purgeAll() {
+	if len(tv.entries) == 0 {
+		return
+	}
+
+	purge := func() {
+		purged, err := tv.appState.PurgeTrash(0)
+		if err != nil {
+			return
+		}
+		tv.Refresh()
+		if tv.onPurge != nil {
+			tv.onPurge(purged)
+		}
+	}
+
+	if tv.onPurgeConfirm == nil {
+		purge()
+		return
+	}
+	message := fmt.Sprintf("Permanently delete %d credential(s) in the trash?\nThis action cannot be undone.", len(tv.entries))
+	tv.onPurgeConfirm(message, purge, func() {})
+}
+
+// setupKeyboardShortcuts configures trash view keyboard shortcuts.
+func (tv *TrashView) setupKeyboardShortcuts() {
+	tv.table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEnter:
+			tv.restoreSelected()
+			return nil
+
+		case tcell.KeyEscape:
+			if tv.onClose != nil {
+				tv.onClose()
+			}
+			return nil
+
+		case tcell.KeyRune:
+			switch event.Rune() {
+			case 'r':
+				tv.restoreSelected()
+				return nil
+			case 'P':
+				tv.purgeAll()
+				return nil
+			}
+		}
+		return event
+	})
+}
+
+// applyStyles applies theme colors to the table.
+func (tv *TrashView) applyStyles() {
+	styles.ApplyTableStyle(tv.table)
+}
+
+// wrapInFrame wraps the table with a border, title, and keyboard hints.
+func (tv *TrashView) wrapInFrame() {
+	theme := styles.GetCurrentTheme()
+
+	hintsText := "[yellow]↑↓[-]:Navigate  [yellow]Enter[-]/[yellow]r[-]:Restore  [yellow]P[-]:Purge all  [yellow]Esc[-]:Close"
+	hints := tview.NewTextView().
+		SetText(hintsText).
+		SetTextAlign(tview.AlignCenter).
+		SetDynamicColors(true)
+	hints.SetBackgroundColor(theme.Background)
+
+	flex := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(tv.table, 0, 1, true). // Table takes all available space
+		AddItem(hints, 1, 0, false)    // Hints fixed at 1 row
+
+	flex.SetBorder(true).
+		SetTitle(" Trash ").
+		SetTitleAlign(tview.AlignLeft).
+		SetBorderColor(theme.BorderColor)
+
+	tv.Flex = flex
+}
+
+// SetOnRestore registers a callback invoked after a credential is restored.
+func (tv *TrashView) SetOnRestore(callback func(service string)) {
+	tv.onRestore = callback
+}
+
+// SetOnPurge registers a callback invoked after the trash is purged.
+func (tv *TrashView) SetOnPurge(callback func(purged int)) {
+	tv.onPurge = callback
+}
+
+// SetOnClose registers a callback invoked when the view is closed.
+func (tv *TrashView) SetOnClose(callback func()) {
+	tv.onClose = callback
+}
+
+// SetOnPurgeConfirm registers a callback to show the purge confirmation dialog.
+func (tv *TrashView) SetOnPurgeConfirm(callback func(message string, onYes func(), onNo func())) {
+	tv.onPurgeConfirm = callback
+}
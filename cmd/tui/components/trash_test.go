@@ -0,0 +1,64 @@
+package components
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"pass-cli/cmd/tui/models"
+	"pass-cli/internal/vault"
+)
+
+// TestTrashView_RestoreAndPurge verifies restoring the selected entry and purging the rest.
+func TestTrashView_RestoreAndPurge(t *testing.T) {
+	mockVault := NewMockVaultService()
+	state := models.NewAppState(mockVault)
+
+	mockVault.SetCredentials([]vault.CredentialMetadata{
+		{Service: "aws", Username: "admin", CreatedAt: time.Now()},
+		{Service: "github", Username: "user", CreatedAt: time.Now()},
+	})
+	_ = state.LoadCredentials()
+	_ = state.DeleteCredential("aws")
+	_ = state.DeleteCredential("github")
+
+	view := NewTrashView(state)
+	if got := view.table.GetRowCount() - 1; got != 2 {
+		t.Fatalf("Expected 2 trash rows, got %d", got)
+	}
+
+	entry := view.GetSelectedEntry()
+	if entry == nil {
+		t.Fatal("Expected first trash entry to be selected")
+	}
+
+	restored := ""
+	view.SetOnRestore(func(service string) { restored = service })
+	view.table.InputHandler()(tcell.NewEventKey(tcell.KeyRune, 'r', tcell.ModNone), func(p tview.Primitive) {})
+
+	if restored != entry.Service {
+		t.Errorf("Expected '%s' restored, got '%s'", entry.Service, restored)
+	}
+	if _, found := state.FindCredentialByService(entry.Service); !found {
+		t.Errorf("Expected '%s' back in the credential list", entry.Service)
+	}
+
+	// Purge asks for confirmation first
+	confirmed := false
+	purged := -1
+	view.SetOnPurgeConfirm(func(message string, onYes func(), onNo func()) {
+		confirmed = true
+		onYes()
+	})
+	view.SetOnPurge(func(count int) { purged = count })
+	view.table.InputHandler()(tcell.NewEventKey(tcell.KeyRune, 'P', tcell.ModNone), func(p tview.Primitive) {})
+
+	if !confirmed || purged != 1 {
+		t.Errorf("Expected confirmed purge of 1 credential, got confirmed=%v purged=%d", confirmed, purged)
+	}
+	if view.GetSelectedEntry() != nil {
+		t.Error("Expected empty trash after purge")
+	}
+}
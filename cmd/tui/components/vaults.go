@@ -0,0 +1,275 @@
+package components
+
+import (
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"pass-cli/cmd/tui/styles"
+	"pass-cli/internal/config"
+)
+
+// VaultSwitcher provides a modal listing the named vaults from the vault registry.
+// Enter opens the selected vault; the currently open vault is marked.
+type VaultSwitcher struct {
+	*tview.Flex
+	table *tview.Table
+
+	profiles    []config.VaultProfile
+	currentPath string
+
+	onSelect func(profile config.VaultProfile)
+	onClose  func()
+}
+
+// NewVaultSwitcher creates the vault switcher for the given profiles.
+// currentPath is the file path of the open vault, used to mark and preselect it.
+func NewVaultSwitcher(profiles []config.VaultProfile, currentPath string) *VaultSwitcher {
+	vs := &VaultSwitcher{
+		table:       tview.NewTable(),
+		profiles:    profiles,
+		currentPath: currentPath,
+	}
+
+	vs.table.SetSelectable(true, false) // Select rows, not columns
+	vs.table.SetFixed(1, 0)             // Fix header row
+	styles.ApplyTableStyle(vs.table)
+	vs.setupKeyboardShortcuts()
+	vs.wrapInFrame()
+	vs.render()
+
+	return vs
+}
+
+// render builds the table rows and selects the open vault.
+func (vs *VaultSwitcher) render() {
+	theme := styles.GetCurrentTheme()
+	vs.table.Clear()
+
+	headers := []string{"", "Name", "Path"}
+	for col, header := range headers {
+		vs.table.SetCell(0, col, tview.NewTableCell(header).
+			SetTextColor(theme.TableHeader).
+			SetAttributes(tcell.AttrBold).
+			SetSelectable(false).
+			SetExpansion(col)) // Marker column stays narrow
+	}
+
+	selected := 1
+	for i, profile := range vs.profiles {
+		row := i + 1
+		marker := " "
+		if vs.isCurrent(profile) {
+			marker = "●"
+			selected = row
+		}
+		name := profile.Name
+		if profile.IsDefault {
+			name += " (default)"
+		}
+		vs.table.SetCell(row, 0, tview.NewTableCell(marker).SetTextColor(theme.Success))
+		vs.table.SetCell(row, 1, tview.NewTableCell(name).SetTextColor(theme.TextPrimary).SetExpansion(1))
+		vs.table.SetCell(row, 2, tview.NewTableCell(profile.Path).SetTextColor(theme.TextSecondary).SetExpansion(2))
+	}
+
+	vs.table.Select(selected, 0)
+}
+
+// isCurrent reports whether profile points at the open vault.
+func (vs *VaultSwitcher) isCurrent(profile config.VaultProfile) bool {
+	return vs.currentPath != "" && profile.Path == vs.currentPath
+}
+
+// GetSelectedProfile returns the selected profile, or nil if none is selected.
+func (vs *VaultSwitcher) GetSelectedProfile() *config.VaultProfile {
+	row, _ := vs.table.GetSelection()
+	index := row - 1
+	if index < 0 || index >= len(vs.profiles) {
+		return nil
+	}
+	profile := vs.profiles[index]
+	return &profile
+}
+
+// selectCurrent invokes the select callback, or closes when the open vault is chosen.
+func (vs *VaultSwitcher) selectCurrent() {
+	profile := vs.GetSelectedProfile()
+	if profile == nil {
+		return
+	}
+
+	if vs.isCurrent(*profile) {
+		if vs.onClose != nil {
+			vs.onClose()
+		}
+		return
+	}
+
+	if vs.onSelect != nil {
+		vs.onSelect(*profile)
+	}
+}
+
+// setupKeyboardShortcuts configures vault switcher keyboard shortcuts.
+func (vs *VaultSwitcher) setupKeyboardShortcuts() {
+	vs.table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEnter:
+			vs.selectCurrent()
+			return nil
+
+		case tcell.KeyEscape:
+			if vs.onClose != nil {
+				vs.onClose()
+			}
+			return nil
+		}
+		return event
+	})
+}
+
+// wrapInFrame wraps the table with a border, title, and keyboard hints.
+func (vs *VaultSwitcher) wrapInFrame() {
+	theme := styles.GetCurrentTheme()
+
+	hintsText := "[yellow]↑↓[-]:Navigate  [yellow]Enter[-]:Open  [yellow]Esc[-]:Close"
+	hints := tview.NewTextView().
+		SetText(hintsText).
+		SetTextAlign(tview.AlignCenter).
+		SetDynamicColors(true)
+	hints.SetBackgroundColor(theme.Background)
+
+	flex := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(vs.table, 0, 1, true). // Table takes all available space
+		AddItem(hints, 1, 0, false)    // Hints fixed at 1 row
+
+	flex.SetBorder(true).
+		SetTitle(" Switch Vault ").
+		SetTitleAlign(tview.AlignLeft).
+		SetBorderColor(theme.BorderColor)
+
+	vs.Flex = flex
+}
+
+// SetOnSelect registers a callback invoked when another vault is chosen.
+func (vs *VaultSwitcher) SetOnSelect(callback func(profile config.VaultProfile)) {
+	vs.onSelect = callback
+}
+
+// SetOnClose registers a callback invoked when the switcher is closed.
+func (vs *VaultSwitcher) SetOnClose(callback func()) {
+	vs.onClose = callback
+}
+
+// UnlockForm prompts for the master password of a vault being switched to.
+type UnlockForm struct {
+	*tview.Flex
+	form *tview.Form
+
+	onSubmit func(password []byte)
+	onCancel func()
+}
+
+// NewUnlockForm creates the master password prompt for the named vault.
+func NewUnlockForm(name string) *UnlockForm {
+	uf := &UnlockForm{
+		form: tview.NewForm(),
+	}
+
+	uf.form.AddPasswordField("Master password", "", 0, '*', nil)
+	uf.form.AddButton("Unlock", uf.submit)
+	uf.form.AddButton("Cancel", uf.cancel)
+
+	uf.applyStyles()
+	uf.setupKeyboardShortcuts()
+	uf.wrapInFrame(name)
+
+	return uf
+}
+
+// submit hands the entered password to the submit callback and clears the field.
+func (uf *UnlockForm) submit() {
+	field := uf.form.GetFormItem(0).(*tview.InputField)
+	password := []byte(field.GetText())
+	field.SetText("") // Don't keep the password in the widget after use
+
+	if len(password) == 0 {
+		return
+	}
+	if uf.onSubmit != nil {
+		uf.onSubmit(password)
+	}
+}
+
+// cancel invokes the cancel callback.
+func (uf *UnlockForm) cancel() {
+	if uf.onCancel != nil {
+		uf.onCancel()
+	}
+}
+
+// setupKeyboardShortcuts submits on Enter in the password field and cancels on Escape.
+func (uf *UnlockForm) setupKeyboardShortcuts() {
+	field := uf.form.GetFormItem(0).(*tview.InputField)
+
+	uf.form.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEnter:
+			// Submit from the password field instead of moving focus to the buttons
+			if field.HasFocus() {
+				uf.submit()
+				return nil
+			}
+
+		case tcell.KeyEscape:
+			uf.cancel()
+			return nil
+		}
+		return event
+	})
+}
+
+// applyStyles applies theme colors to the form.
+func (uf *UnlockForm) applyStyles() {
+	theme := styles.GetCurrentTheme()
+
+	styles.ApplyFormStyle(uf.form)
+	uf.form.GetFormItem(0).(*tview.InputField).
+		SetFieldBackgroundColor(theme.BackgroundLight).
+		SetFieldTextColor(theme.TextPrimary)
+	uf.form.SetButtonsAlign(tview.AlignRight)
+}
+
+// wrapInFrame wraps the form with a border, title, and keyboard hints.
+func (uf *UnlockForm) wrapInFrame(name string) {
+	theme := styles.GetCurrentTheme()
+
+	hintsText := "[yellow]Enter[-]:Unlock  [yellow]Esc[-]:Cancel"
+	hints := tview.NewTextView().
+		SetText(hintsText).
+		SetTextAlign(tview.AlignCenter).
+		SetDynamicColors(true)
+	hints.SetBackgroundColor(theme.Background)
+
+	flex := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(uf.form, 0, 1, true). // Form takes all available space
+		AddItem(hints, 1, 0, false)   // Hints fixed at 1 row
+
+	flex.SetBorder(true).
+		SetTitle(" Unlock " + name + " ").
+		SetTitleAlign(tview.AlignLeft).
+		SetBorderColor(theme.BorderColor)
+
+	uf.Flex = flex
+}
+
+// SetOnSubmit registers a callback invoked with the entered master password.
+func (uf *UnlockForm) SetOnSubmit(callback func(password []byte)) {
+	uf.onSubmit = callback
+}
+
+// SetOnCancel registers a callback invoked when the prompt is cancelled.
+func (uf *UnlockForm) SetOnCancel(callback func()) {
+	uf.onCancel = callback
+}
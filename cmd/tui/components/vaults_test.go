@@ -0,0 +1,62 @@
+package components
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"pass-cli/internal/config"
+)
+
+// TestVaultSwitcher_Select verifies the open vault is preselected and choosing another vault reports it.
+func TestVaultSwitcher_Select(t *testing.T) {
+	profiles := []config.VaultProfile{
+		{Name: "personal", Path: "/vaults/personal.enc", IsDefault: true},
+		{Name: "work", Path: "/vaults/work.enc"},
+	}
+	switcher := NewVaultSwitcher(profiles, "/vaults/work.enc")
+
+	selected := switcher.GetSelectedProfile()
+	if selected == nil || selected.Name != "work" {
+		t.Fatalf("Expected open vault 'work' preselected, got %+v", selected)
+	}
+
+	// Choosing the open vault just closes the switcher
+	closed := false
+	var chosen *config.VaultProfile
+	switcher.SetOnClose(func() { closed = true })
+	switcher.SetOnSelect(func(profile config.VaultProfile) { chosen = &profile })
+
+	enter := tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone)
+	switcher.table.InputHandler()(enter, func(p tview.Primitive) {})
+	if !closed || chosen != nil {
+		t.Errorf("Expected close without select for the open vault, got closed=%v chosen=%+v", closed, chosen)
+	}
+
+	switcher.table.Select(1, 0)
+	switcher.table.InputHandler()(enter, func(p tview.Primitive) {})
+	if chosen == nil || chosen.Name != "personal" {
+		t.Errorf("Expected 'personal' chosen, got %+v", chosen)
+	}
+}
+
+// TestUnlockForm_Submit verifies Enter submits the password and clears the field.
+func TestUnlockForm_Submit(t *testing.T) {
+	unlockForm := NewUnlockForm("work")
+
+	var submitted string
+	unlockForm.SetOnSubmit(func(password []byte) { submitted = string(password) })
+
+	field := unlockForm.form.GetFormItem(0).(*tview.InputField)
+	field.SetText("Secret123!")
+	field.Focus(func(p tview.Primitive) {})
+	unlockForm.form.InputHandler()(tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone), func(p tview.Primitive) {})
+
+	if submitted != "Secret123!" {
+		t.Errorf("Expected password submitted, got '%s'", submitted)
+	}
+	if field.GetText() != "" {
+		t.Error("Expected password field cleared after submit")
+	}
+}
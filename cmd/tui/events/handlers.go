@@ -2,6 +2,7 @@ package events
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
@@ -10,6 +11,7 @@ import (
 	"pass-cli/cmd/tui/layout"
 	"pass-cli/cmd/tui/models"
 	"pass-cli/internal/config"
+	"pass-cli/internal/vault"
 )
 
 // EventHandler manages global keyboard shortcuts with focus-aware input protection.
@@ -118,6 +120,14 @@ func (eh *EventHandler) handleGlobalKey(event *tcell.EventKey) *tcell.EventKey {
 		eh.handleDeleteCredential()
 		return nil
 	}
+	if eh.config.MatchesKeybinding(event, "show_trash") {
+		eh.handleShowTrash()
+		return nil
+	}
+	if eh.config.MatchesKeybinding(event, "switch_vault") {
+		eh.handleSwitchVault()
+		return nil
+	}
 	if eh.config.MatchesKeybinding(event, "toggle_detail") {
 		eh.handleToggleDetailPanel()
 		return nil
@@ -232,7 +242,7 @@ func (eh *EventHandler) handleEditCredential() {
 	eh.pageManager.ShowModal("edit-form", form, layout.FormModalWidth, layout.FormModalHeight)
 }
 
-// handleDeleteCredential shows a confirmation dialog before deleting the selected credential.
+// handleDeleteCredential shows a confirmation dialog before moving the selected credential to the trash.
 func (eh *EventHandler) handleDeleteCredential() {
 	cred := eh.appState.GetSelectedCredential()
 	if cred == nil {
@@ -240,7 +250,7 @@ func (eh *EventHandler) handleDeleteCredential() {
 		return
 	}
 
-	message := fmt.Sprintf("Delete credential '%s'?\nThis action cannot be undone.", cred.Service)
+	message := fmt.Sprintf("Delete credential '%s'?\nIt can be restored from the trash.", cred.Service)
 
 	eh.pageManager.ShowConfirmDialog(
 		"Delete Credential",
@@ -251,7 +261,7 @@ func (eh *EventHandler) handleDeleteCredential() {
 			if err != nil {
 				eh.statusBar.ShowError(err)
 			} else {
-				eh.statusBar.ShowSuccess("Credential deleted")
+				eh.statusBar.ShowSuccess("Credential moved to trash")
 			}
 		},
 		func() {
@@ -260,6 +270,106 @@ func (eh *EventHandler) handleDeleteCredential() {
 	)
 }
 
+// handleShowTrash shows the trash modal for restoring or purging deleted credentials.
+func (eh *EventHandler) handleShowTrash() {
+	view := components.NewTrashView(eh.appState)
+
+	view.SetOnRestore(func(service string) {
+		eh.statusBar.ShowSuccess(fmt.Sprintf("Restored '%s'", service))
+	})
+
+	view.SetOnPurge(func(purged int) {
+		eh.statusBar.ShowSuccess(fmt.Sprintf("Purged %d credential(s)", purged))
+	})
+
+	view.SetOnClose(func() {
+		eh.pageManager.CloseModal("trash")
+	})
+
+	view.SetOnPurgeConfirm(func(message string, onYes func(), onNo func()) {
+		eh.pageManager.ShowConfirmDialog("Purge Trash", message, onYes, onNo)
+	})
+
+	eh.pageManager.ShowModal("trash", view, layout.TrashModalWidth, layout.TrashModalHeight)
+}
+
+// handleSwitchVault shows the named vaults and opens the chosen one in place of the current vault.
+// The keychain is tried first; otherwise the master password is prompted for.
+func (eh *EventHandler) handleSwitchVault() {
+	vaults, err := config.LoadVaults()
+	if err != nil {
+		eh.statusBar.ShowError(fmt.Errorf("failed to load vault registry: %w", err))
+		return
+	}
+
+	profiles := vaults.List()
+	if len(profiles) == 0 {
+		eh.statusBar.ShowInfo("No named vaults registered (use 'pass-cli vault add')")
+		return
+	}
+
+	switcher := components.NewVaultSwitcher(profiles, eh.appState.GetVaultPath())
+
+	switcher.SetOnClose(func() {
+		eh.pageManager.CloseModal("vaults")
+	})
+
+	switcher.SetOnSelect(func(profile config.VaultProfile) {
+		if _, err := os.Stat(profile.Path); err != nil {
+			eh.statusBar.ShowError(fmt.Errorf("vault '%s' not found at %s", profile.Name, profile.Path))
+			return
+		}
+
+		eh.pageManager.CloseModal("vaults")
+		if err := eh.openVault(profile, nil); err == nil {
+			return
+		}
+
+		// Keychain unlock unavailable for this vault, ask for the master password
+		unlockForm := components.NewUnlockForm(profile.Name)
+		unlockForm.SetOnSubmit(func(password []byte) {
+			if err := eh.openVault(profile, password); err != nil {
+				eh.statusBar.ShowError(err)
+				return
+			}
+			eh.pageManager.CloseModal("unlock")
+		})
+		unlockForm.SetOnCancel(func() {
+			eh.pageManager.CloseModal("unlock")
+		})
+		eh.pageManager.ShowModal("unlock", unlockForm, layout.UnlockModalWidth, layout.UnlockModalHeight)
+	})
+
+	eh.pageManager.ShowModal("vaults", switcher, layout.VaultSwitcherModalWidth, layout.VaultSwitcherModalHeight)
+}
+
+// openVault unlocks the vault of profile (keychain when password is nil) and switches AppState to it.
+// The current vault is only locked once the new one is unlocked.
+func (eh *EventHandler) openVault(profile config.VaultProfile, password []byte) error {
+	vaultService, err := vault.New(profile.Path)
+	if err != nil {
+		return fmt.Errorf("failed to open vault '%s': %w", profile.Name, err)
+	}
+	if vaults, err := config.LoadVaults(); err == nil {
+		vaultService.SetKeyFile(vaults.KeyFile)
+	}
+
+	if password == nil {
+		err = vaultService.UnlockWithKeychain()
+	} else {
+		err = vaultService.Unlock(password)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to unlock vault '%s': %w", profile.Name, err)
+	}
+
+	if err := eh.appState.SwitchVault(vaultService, profile.Path); err != nil {
+		return err
+	}
+	eh.statusBar.ShowSuccess(fmt.Sprintf("Switched to vault '%s'", profile.Name))
+	return nil
+}
+
 // handleTogglePassword toggles password visibility in the detail view.
 func (eh *EventHandler) handleTogglePassword() {
 	if eh.detailView == nil {
@@ -389,6 +499,7 @@ func (eh *EventHandler) handleShowHelp() {
 	addShortcut(getKey("add_credential"), "New credential")
 	addShortcut(getKey("edit_credential"), "Edit credential")
 	addShortcut(getKey("delete_credential"), "Delete credential")
+	addShortcut(getKey("show_trash"), "Show trash (restore deleted)")
 	addShortcut("p", "Toggle password visibility")
 	addShortcut("c", "Copy password to clipboard")
 	row++ // Blank line (just skip row, don't add cells)
@@ -402,6 +513,7 @@ func (eh *EventHandler) handleShowHelp() {
 
 	// General section
 	addSection("General")
+	addShortcut(getKey("switch_vault"), "Switch vault")
 	addShortcut(getKey("help"), "Show this help")
 	addShortcut(getKey("quit"), "Quit application")
 	addShortcut("Esc", "Close modal / Cancel search")
@@ -512,6 +624,9 @@ func (eh *EventHandler) handleSearchActivate() {
 
 		// Trigger filter changed callback to refresh table only (not detail view)
 		eh.appState.TriggerFilterChanged()
+
+		// Show or clear query parse errors in the statusbar
+		eh.statusBar.UpdateForContext(components.FocusTable)
 	})
 
 	// Setup done function to handle Escape (redundant but safe)
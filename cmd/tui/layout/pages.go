@@ -10,13 +10,22 @@ import (
 // Modal dimension constants to ensure consistent sizing across all modals.
 const (
 	FormModalWidth  = 60 // Standard width for credential forms (add, edit)
-	FormModalHeight = 27 // Standard height for 6-field forms + buttons + keyboard hints
+	FormModalHeight = 28 // Standard height for 7-field forms + buttons + keyboard hints
 
 	ConfirmDialogWidth  = 60 // Width for confirmation dialogs
 	ConfirmDialogHeight = 10 // Height for yes/no confirmation dialogs
 
 	HelpModalWidth  = 60 // Width for help screen modal
 	HelpModalHeight = 25 // Height for help screen content
+
+	TrashModalWidth  = 70 // Width for the trash view
+	TrashModalHeight = 20 // Height for the trash view (table + hints)
+
+	VaultSwitcherModalWidth  = 70 // Width for the vault switcher
+	VaultSwitcherModalHeight = 14 // Height for the vault switcher (table + hints)
+
+	UnlockModalWidth  = 50 // Width for the master password prompt
+	UnlockModalHeight = 8  // Height for password field + buttons + hints
 )
 
 // PageManager manages modal dialogs and page switching using tview.Pages.
@@ -1,6 +1,7 @@
 package tui
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -13,14 +14,16 @@ import (
 	"pass-cli/cmd/tui/models"
 	"pass-cli/cmd/tui/styles"
 	"pass-cli/internal/config"
+	"pass-cli/internal/storage"
 	"pass-cli/internal/vault"
 )
 
 const maxPasswordAttempts = 3
 
 // Run starts the TUI application (exported for main.go to call)
-// If vaultPath is empty, uses the default vault location
-func Run(vaultPath string) error {
+// If vaultPath is empty, uses the default vault location. The key file (keyFile,
+// else the config default) unlocks the vault alone or with the master password.
+func Run(vaultPath, keyFile string) error {
 	// 1. Get vault path (use provided path or default)
 	if vaultPath == "" {
 		vaultPath = getDefaultVaultPath()
@@ -32,8 +35,24 @@ func Run(vaultPath string) error {
 		return fmt.Errorf("failed to initialize vault service: %w", err)
 	}
 
-	// 3. Try keychain unlock
-	err = vaultService.UnlockWithKeychain()
+	// 3. Try key file, then keychain unlock
+	explicitKeyFile := keyFile != ""
+	if !explicitKeyFile {
+		if vaults, err := config.LoadVaults(); err == nil {
+			keyFile = vaults.KeyFile
+		}
+	}
+	vaultService.SetKeyFile(keyFile)
+	if keyFile != "" {
+		// The key file may be a second factor, combined with the password below
+		err = vaultService.UnlockWithKeyFile(keyFile)
+		if err != nil && explicitKeyFile && !errors.Is(err, storage.ErrNoMatchingSlot) {
+			return err
+		}
+	}
+	if keyFile == "" || err != nil {
+		err = vaultService.UnlockWithKeychain()
+	}
 	if err != nil {
 		// Keychain unlock failed, fall back to password prompt
 		fmt.Println("Keychain unlock unavailable, prompting for password...")
@@ -74,8 +93,12 @@ func Run(vaultPath string) error {
 	return nil
 }
 
-// getDefaultVaultPath returns the default vault file path
+// getDefaultVaultPath returns the default named vault, or the default vault file path
 func getDefaultVaultPath() string {
+	if vaults, err := config.LoadVaults(); err == nil && vaults.DefaultPath() != "" {
+		return vaults.DefaultPath()
+	}
+
 	home, err := os.UserHomeDir()
 	if err != nil {
 		// Fallback to current directory if home not available
@@ -115,6 +138,7 @@ func LaunchTUI(vaultService *vault.VaultService) error {
 
 	// 2. Initialize AppState with vault service
 	appState := models.NewAppState(vaultService)
+	appState.SetVaultPath(vaultService.Path())
 
 	// 3. Load credentials
 	if err := appState.LoadCredentials(); err != nil {
@@ -220,7 +244,11 @@ func LaunchTUI(vaultService *vault.VaultService) error {
 	pageManager.ShowPage("main", mainLayout)
 	app.SetRoot(pageManager.Pages, true)
 
-	// 14. Run application (blocking)
+	// 14. Start live TOTP countdown in the detail view (stopped when the app exits)
+	detailView.StartOTPRefresh(app)
+	defer detailView.StopOTPRefresh()
+
+	// 15. Run application (blocking)
 	return app.Run()
 }
 
@@ -3,6 +3,8 @@ package models
 import (
 	"strings"
 
+	"pass-cli/internal/fuzzy"
+	"pass-cli/internal/query"
 	"pass-cli/internal/vault"
 
 	"github.com/rivo/tview"
@@ -13,24 +15,64 @@ type SearchState struct {
 	Active     bool
 	Query      string
 	InputField *tview.InputField
+
+	compiledFor string       // Query text that compiled/err were built from
+	compiled    *query.Query // nil when Query does not parse
+	err         error
 }
 
 // MatchesCredential determines if a credential matches the current search query
-// Returns true if: (1) search inactive, (2) query empty, or (3) query substring-matches any field
+// Returns true if: (1) search inactive, (2) query empty, or (3) the query matches (see Score).
 func (ss *SearchState) MatchesCredential(cred *vault.CredentialMetadata) bool {
+	_, ok := ss.Score(cred)
+	return ok
+}
+
+// Score reports whether a credential matches the current search query and how well.
+// Plain search words also match fuzzily (fzf-style) on service, username and URL, and the
+// fuzzy score ranks results; field selectors and operators filter without ranking (score 0).
+// While the query does not parse (e.g. half-typed "user:"), it falls back to a plain substring match.
+func (ss *SearchState) Score(cred *vault.CredentialMetadata) (int, bool) {
 	// If search is inactive or query is empty, all credentials match
 	if !ss.Active || ss.Query == "" {
-		return true
+		return 0, true
+	}
+
+	if q := ss.compile(); q != nil {
+		if words, ok := q.Words(); ok {
+			if score, ok := fuzzy.ScoreCredential(strings.Join(words, " "), cred); ok {
+				return score, true
+			}
+		}
+		return 0, q.Match(cred)
 	}
 
 	// Case-insensitive substring matching
-	query := strings.ToLower(ss.Query)
+	text := strings.ToLower(ss.Query)
 
 	// Search across Service, Username, URL, Category fields (Notes excluded per spec)
-	return strings.Contains(strings.ToLower(cred.Service), query) ||
-		strings.Contains(strings.ToLower(cred.Username), query) ||
-		strings.Contains(strings.ToLower(cred.URL), query) ||
-		strings.Contains(strings.ToLower(cred.Category), query)
+	return 0, strings.Contains(strings.ToLower(cred.Service), text) ||
+		strings.Contains(strings.ToLower(cred.Username), text) ||
+		strings.Contains(strings.ToLower(cred.URL), text) ||
+		strings.Contains(strings.ToLower(cred.Category), text)
+}
+
+// QueryError returns the parse error for the current query, or nil if it is valid or empty
+func (ss *SearchState) QueryError() error {
+	if !ss.Active || strings.TrimSpace(ss.Query) == "" {
+		return nil
+	}
+	ss.compile()
+	return ss.err
+}
+
+// compile parses Query if it changed since the last call and returns the result
+func (ss *SearchState) compile() *query.Query {
+	if ss.Query != ss.compiledFor || (ss.compiled == nil && ss.err == nil) {
+		ss.compiledFor = ss.Query
+		ss.compiled, ss.err = query.Parse(ss.Query)
+	}
+	return ss.compiled
 }
 
 // Activate creates InputField and sets Active=true
@@ -46,4 +88,5 @@ func (ss *SearchState) Deactivate() {
 	ss.Active = false
 	ss.Query = ""
 	ss.InputField = nil
+	ss.compiledFor, ss.compiled, ss.err = "", nil, nil
 }
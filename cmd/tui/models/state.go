@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"sort"
 	"sync"
+	"time"
 
 	"pass-cli/internal/vault"
 
@@ -19,10 +20,16 @@ import (
 type VaultService interface {
 	ListCredentialsWithMetadata() ([]vault.CredentialMetadata, error)
 	AddCredential(service, username string, password []byte, category, url, notes string) error // T020d: []byte password
+	AddCredentialWithOpts(service, username string, password []byte, category, url, notes string, opts vault.AddOpts) error
 	UpdateCredential(service string, opts vault.UpdateOpts) error
 	DeleteCredential(service string) error
 	GetCredential(service string, trackUsage bool) (*vault.Credential, error)
 	RecordFieldAccess(service, field string) error // Track field-specific access
+	RenameCredential(oldService, newService string) error
+	ListTrash() ([]vault.TrashEntry, error)
+	RestoreCredential(service string) error
+	PurgeTrash(olderThan time.Duration) (int, error)
+	Lock()
 }
 
 // UpdateCredentialOpts mirrors vault.UpdateOpts for AppState layer.
@@ -34,6 +41,17 @@ type UpdateCredentialOpts struct {
 	Category *string
 	URL      *string
 	Notes    *string
+
+	CustomFields *[]vault.CustomField // nil = don't change, non-nil = replace the whole list
+	Tags         *[]string            // nil = don't change, non-nil = replace the whole list
+}
+
+// AddCredentialOpts mirrors vault.AddOpts for AppState layer.
+// Carries the optional fields that the basic AddCredential signature doesn't cover.
+type AddCredentialOpts struct {
+	Type         vault.CredentialType
+	CustomFields []vault.CustomField
+	Tags         []string
 }
 
 // AppState holds all application state with thread-safe access.
@@ -42,15 +60,22 @@ type AppState struct {
 	// Concurrency control
 	mu sync.RWMutex // Protects all fields below
 
-	// Vault service (interface for testability)
-	vault VaultService
+	// Vault service (interface for testability) and the file it was opened from
+	vault     VaultService
+	vaultPath string
 
 	// Credential data
 	credentials []vault.CredentialMetadata
 	categories  []string
+	tags        []string
+	types       []vault.CredentialType
 
-	// Current selections
+	// Current selections (category, tag, and type filters are mutually exclusive;
+	// a folder path narrows the category filter)
 	selectedCategory   string
+	selectedPath       string
+	selectedTag        string
+	selectedType       vault.CredentialType
 	selectedCredential *vault.CredentialMetadata
 
 	// UI components (single instances, created once)
@@ -84,6 +109,8 @@ func NewAppState(vaultService VaultService) *AppState {
 		vault:       vaultService,
 		credentials: make([]vault.CredentialMetadata, 0),
 		categories:  make([]string, 0),
+		tags:        make([]string, 0),
+		types:       make([]vault.CredentialType, 0),
 		searchState: NewSearchState(),
 	}
 }
@@ -106,6 +133,28 @@ func (s *AppState) GetCategories() []string {
 	return categories
 }
 
+// GetTags returns a copy of the tags slice (thread-safe read).
+func (s *AppState) GetTags() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	// Return a copy to prevent external mutation
+	tags := make([]string, len(s.tags))
+	copy(tags, s.tags)
+	return tags
+}
+
+// GetTypes returns the credential types present in the vault, in template order (thread-safe read).
+func (s *AppState) GetTypes() []vault.CredentialType {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	// Return a copy to prevent external mutation
+	types := make([]vault.CredentialType, len(s.types))
+	copy(types, s.types)
+	return types
+}
+
 // GetSelectedCredential returns a copy of the selected credential (thread-safe read).
 func (s *AppState) GetSelectedCredential() *vault.CredentialMetadata {
 	s.mu.RLock()
@@ -120,6 +169,27 @@ func (s *AppState) GetSelectedCategory() string {
 	return s.selectedCategory
 }
 
+// GetSelectedPath returns the selected service folder path (thread-safe read).
+func (s *AppState) GetSelectedPath() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.selectedPath
+}
+
+// GetSelectedTag returns the selected tag filter (thread-safe read).
+func (s *AppState) GetSelectedTag() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.selectedTag
+}
+
+// GetSelectedType returns the selected credential type filter (thread-safe read).
+func (s *AppState) GetSelectedType() vault.CredentialType {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.selectedType
+}
+
 // FindCredentialByService searches for a credential by service name (thread-safe read).
 // Returns the credential metadata and true if found, nil and false otherwise.
 func (s *AppState) FindCredentialByService(service string) (*vault.CredentialMetadata, bool) {
@@ -183,6 +253,44 @@ func (s *AppState) LoadCredentials() error {
 	return nil
 }
 
+// GetVaultPath returns the file path of the open vault (thread-safe read).
+func (s *AppState) GetVaultPath() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.vaultPath
+}
+
+// SetVaultPath records the file path of the open vault.
+func (s *AppState) SetVaultPath(path string) {
+	s.mu.Lock()
+	s.vaultPath = path
+	s.mu.Unlock()
+}
+
+// SwitchVault locks the current vault and replaces it with an already unlocked one.
+// Filters and the credential selection are reset because they refer to the previous vault.
+// CRITICAL: Follows Lock→Mutate→Unlock→Notify pattern.
+func (s *AppState) SwitchVault(vaultService VaultService, path string) error {
+	s.mu.Lock()
+	previous := s.vault
+	s.vault = vaultService
+	s.vaultPath = path
+	s.selectedCategory = ""
+	s.selectedPath = ""
+	s.selectedTag = ""
+	s.selectedType = ""
+	s.selectedCredential = nil
+	s.mu.Unlock() // ✅ RELEASE LOCK
+
+	// Clear the previous vault's secrets from memory
+	if previous != nil {
+		previous.Lock()
+	}
+
+	s.notifySelectionChanged()
+	return s.LoadCredentials() // Notifies credentials changed
+}
+
 // AddCredential adds a new credential to the vault.
 // CRITICAL: Minimizes lock duration by releasing lock during vault I/O operations.
 // T020d: Converts string password to []byte for vault storage
@@ -218,6 +326,46 @@ func (s *AppState) AddCredential(service, username, password, category, url, not
 	return nil
 }
 
+// AddCredentialWithOpts adds a new credential including optional extended fields.
+// CRITICAL: Minimizes lock duration by releasing lock during vault I/O operations.
+func (s *AppState) AddCredentialWithOpts(service, username, password, category, url, notes string, opts AddCredentialOpts) error {
+	// T020d: Convert string password to []byte for vault
+	passwordBytes := []byte(password)
+
+	vaultOpts := vault.AddOpts{
+		Type:         opts.Type,
+		CustomFields: opts.CustomFields,
+		Tags:         opts.Tags,
+	}
+
+	// Perform vault I/O without holding lock (vault has its own synchronization)
+	err := s.vault.AddCredentialWithOpts(service, username, passwordBytes, category, url, notes, vaultOpts)
+	if err != nil {
+		wrappedErr := fmt.Errorf("failed to add credential: %w", err)
+		s.notifyError(wrappedErr)
+		return wrappedErr
+	}
+
+	// Reload credentials without holding lock
+	creds, err := s.vault.ListCredentialsWithMetadata()
+	if err != nil {
+		wrappedErr := fmt.Errorf("failed to reload credentials: %w", err)
+		s.notifyError(wrappedErr)
+		return wrappedErr
+	}
+
+	// Only lock to update state
+	s.mu.Lock()
+	s.credentials = creds
+	s.updateCategories() // Update categories while locked
+	s.mu.Unlock()
+
+	// Notify after releasing lock
+	s.notifyCredentialsChanged()
+
+	return nil
+}
+
 // UpdateCredential updates an existing credential in the vault.
 // CRITICAL: Minimizes lock duration by releasing lock during vault I/O operations.
 // Accepts UpdateCredentialOpts to allow clearing fields to empty strings (non-nil pointer to empty string).
@@ -229,6 +377,9 @@ func (s *AppState) UpdateCredential(service string, opts UpdateCredentialOpts) e
 		Category: opts.Category,
 		URL:      opts.URL,
 		Notes:    opts.Notes,
+
+		CustomFields: opts.CustomFields,
+		Tags:         opts.Tags,
 	}
 
 	// Perform vault I/O without holding lock (vault has its own synchronization)
@@ -259,6 +410,53 @@ func (s *AppState) UpdateCredential(service string, opts UpdateCredentialOpts) e
 	return nil
 }
 
+// RenameCredential changes a credential's service name.
+// The selection follows the renamed credential so the detail view stays on it.
+// CRITICAL: Minimizes lock duration by releasing lock during vault I/O operations.
+func (s *AppState) RenameCredential(oldService, newService string) error {
+	// Perform vault I/O without holding lock (vault has its own synchronization)
+	err := s.vault.RenameCredential(oldService, newService)
+	if err != nil {
+		wrappedErr := fmt.Errorf("failed to rename credential: %w", err)
+		s.notifyError(wrappedErr)
+		return wrappedErr
+	}
+
+	// Reload credentials without holding lock
+	creds, err := s.vault.ListCredentialsWithMetadata()
+	if err != nil {
+		wrappedErr := fmt.Errorf("failed to reload credentials: %w", err)
+		s.notifyError(wrappedErr)
+		return wrappedErr
+	}
+
+	// Only lock to update state
+	s.mu.Lock()
+	s.credentials = creds
+	s.updateCategories() // Update categories while locked
+	selectionChanged := false
+	if s.selectedCredential != nil && s.selectedCredential.Service == oldService {
+		s.selectedCredential = nil
+		for i := range s.credentials {
+			if s.credentials[i].Service == newService {
+				selected := s.credentials[i]
+				s.selectedCredential = &selected
+				break
+			}
+		}
+		selectionChanged = true
+	}
+	s.mu.Unlock()
+
+	// Notify after releasing lock
+	s.notifyCredentialsChanged()
+	if selectionChanged {
+		s.notifySelectionChanged()
+	}
+
+	return nil
+}
+
 // DeleteCredential deletes a credential from the vault.
 // CRITICAL: Minimizes lock duration by releasing lock during vault I/O operations.
 func (s *AppState) DeleteCredential(service string) error {
@@ -290,11 +488,64 @@ func (s *AppState) DeleteCredential(service string) error {
 	return nil
 }
 
+// ListTrash returns the credentials in the vault's trash, most recently deleted first.
+func (s *AppState) ListTrash() ([]vault.TrashEntry, error) {
+	entries, err := s.vault.ListTrash()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list trash: %w", err)
+	}
+	return entries, nil
+}
+
+// RestoreCredential moves a credential from the trash back into the vault.
+// CRITICAL: Minimizes lock duration by releasing lock during vault I/O operations.
+func (s *AppState) RestoreCredential(service string) error {
+	// Perform vault I/O without holding lock (vault has its own synchronization)
+	err := s.vault.RestoreCredential(service)
+	if err != nil {
+		wrappedErr := fmt.Errorf("failed to restore credential: %w", err)
+		s.notifyError(wrappedErr)
+		return wrappedErr
+	}
+
+	// Reload credentials without holding lock
+	creds, err := s.vault.ListCredentialsWithMetadata()
+	if err != nil {
+		wrappedErr := fmt.Errorf("failed to reload credentials: %w", err)
+		s.notifyError(wrappedErr)
+		return wrappedErr
+	}
+
+	// Only lock to update state
+	s.mu.Lock()
+	s.credentials = creds
+	s.updateCategories() // Update categories while locked
+	s.mu.Unlock()
+
+	// Notify after releasing lock
+	s.notifyCredentialsChanged()
+
+	return nil
+}
+
+// PurgeTrash permanently removes trashed credentials deleted more than olderThan ago (0 = all).
+// Live credentials are unaffected, so no reload or notification is needed.
+func (s *AppState) PurgeTrash(olderThan time.Duration) (int, error) {
+	purged, err := s.vault.PurgeTrash(olderThan)
+	if err != nil {
+		wrappedErr := fmt.Errorf("failed to purge trash: %w", err)
+		s.notifyError(wrappedErr)
+		return 0, wrappedErr
+	}
+	return purged, nil
+}
+
 // SetSelectedCategory updates the selected category.
 // CRITICAL: Follows Lock→Mutate→Unlock→Notify pattern.
 func (s *AppState) SetSelectedCategory(category string) {
 	s.mu.Lock()
 	s.selectedCategory = category
+	s.selectedPath = ""
 	s.mu.Unlock() // ✅ RELEASE LOCK
 
 	s.notifySelectionChanged() // ✅ THEN notify
@@ -333,12 +584,58 @@ func (s *AppState) SetSelectedCredential(credential *vault.CredentialMetadata) {
 func (s *AppState) SetSelection(category string, credential *vault.CredentialMetadata) {
 	s.mu.Lock()
 	s.selectedCategory = category
+	s.selectedPath = ""
+	s.selectedTag = "" // Category selection replaces any tag or type filter
+	s.selectedType = ""
 	s.selectedCredential = credential
 	s.mu.Unlock() // ✅ RELEASE LOCK
 
 	s.notifySelectionChanged() // ✅ THEN notify (single notification)
 }
 
+// SetFolderSelection filters by a service folder path within a category,
+// clearing the tag/type filters and credential selection.
+// CRITICAL: Follows Lock→Mutate→Unlock→Notify pattern.
+func (s *AppState) SetFolderSelection(category, path string) {
+	s.mu.Lock()
+	s.selectedCategory = category
+	s.selectedPath = path
+	s.selectedTag = ""
+	s.selectedType = ""
+	s.selectedCredential = nil
+	s.mu.Unlock() // ✅ RELEASE LOCK
+
+	s.notifySelectionChanged() // ✅ THEN notify (single notification)
+}
+
+// SetTagSelection filters by tag, clearing the category filter and credential selection.
+// CRITICAL: Follows Lock→Mutate→Unlock→Notify pattern.
+func (s *AppState) SetTagSelection(tag string) {
+	s.mu.Lock()
+	s.selectedTag = tag
+	s.selectedCategory = ""
+	s.selectedPath = ""
+	s.selectedType = ""
+	s.selectedCredential = nil
+	s.mu.Unlock() // ✅ RELEASE LOCK
+
+	s.notifySelectionChanged() // ✅ THEN notify (single notification)
+}
+
+// SetTypeSelection filters by credential type, clearing the category/tag filters and credential selection.
+// CRITICAL: Follows Lock→Mutate→Unlock→Notify pattern.
+func (s *AppState) SetTypeSelection(credType vault.CredentialType) {
+	s.mu.Lock()
+	s.selectedType = credType
+	s.selectedCategory = ""
+	s.selectedPath = ""
+	s.selectedTag = ""
+	s.selectedCredential = nil
+	s.mu.Unlock() // ✅ RELEASE LOCK
+
+	s.notifySelectionChanged() // ✅ THEN notify (single notification)
+}
+
 // SetSidebar stores the sidebar component reference.
 func (s *AppState) SetSidebar(sidebar *tview.TreeView) {
 	s.mu.Lock()
@@ -505,10 +802,12 @@ func (s *AppState) notifyError(err error) {
 	}
 }
 
-// updateCategories extracts unique categories from credentials.
+// updateCategories extracts unique categories, tags, and types from credentials.
 // CRITICAL: Must be called while holding a write lock.
 func (s *AppState) updateCategories() {
 	categoryMap := make(map[string]bool)
+	tagMap := make(map[string]bool)
+	typeMap := make(map[vault.CredentialType]bool)
 
 	for _, cred := range s.credentials {
 		// Extract category from credential's Category field
@@ -518,6 +817,12 @@ func (s *AppState) updateCategories() {
 			// Empty category becomes "Uncategorized"
 			categoryMap["Uncategorized"] = true
 		}
+
+		for _, tag := range cred.Tags {
+			tagMap[tag] = true
+		}
+
+		typeMap[cred.EffectiveType()] = true
 	}
 
 	// Convert map to sorted slice
@@ -527,5 +832,21 @@ func (s *AppState) updateCategories() {
 	}
 	sort.Strings(categories)
 
+	tags := make([]string, 0, len(tagMap))
+	for tag := range tagMap {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+
+	// Types keep template order rather than alphabetical order
+	types := make([]vault.CredentialType, 0, len(typeMap))
+	for _, template := range vault.CredentialTemplates() {
+		if typeMap[template.Type] {
+			types = append(types, template.Type)
+		}
+	}
+
 	s.categories = categories
+	s.tags = tags
+	s.types = types
 }
@@ -17,6 +17,8 @@ type MockVaultService struct {
 
 	// Mock data
 	credentials []vault.CredentialMetadata
+	trash       []vault.TrashEntry
+	locked      bool
 
 	// Mock behaviors
 	listError   error
@@ -78,6 +80,11 @@ func (m *MockVaultService) AddCredential(service, username string, password []by
 	return nil
 }
 
+// AddCredentialWithOpts adds a mock credential, ignoring extended fields.
+func (m *MockVaultService) AddCredentialWithOpts(service, username string, password []byte, category, url, notes string, opts vault.AddOpts) error {
+	return m.AddCredential(service, username, password, category, url, notes)
+}
+
 // UpdateCredential updates a mock credential.
 func (m *MockVaultService) UpdateCredential(service string, opts vault.UpdateOpts) error {
 	m.mu.Lock()
@@ -120,10 +127,11 @@ func (m *MockVaultService) DeleteCredential(service string) error {
 		return m.deleteError
 	}
 
-	// Find and remove credential
+	// Find credential and move it to the trash
 	for i, cred := range m.credentials {
 		if cred.Service == service {
 			m.credentials = append(m.credentials[:i], m.credentials[i+1:]...)
+			m.trash = append(m.trash, vault.TrashEntry{Service: cred.Service, Username: cred.Username, DeletedAt: time.Now()})
 			return nil
 		}
 	}
@@ -162,6 +170,58 @@ func (m *MockVaultService) RecordFieldAccess(service, field string) error {
 	return nil
 }
 
+// RenameCredential renames a mock credential.
+func (m *MockVaultService) RenameCredential(oldService, newService string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, cred := range m.credentials {
+		if cred.Service == oldService {
+			m.credentials[i].Service = newService
+			return nil
+		}
+	}
+	return errors.New("not found")
+}
+
+// ListTrash returns the mock trash.
+func (m *MockVaultService) ListTrash() ([]vault.TrashEntry, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.trash, nil
+}
+
+// RestoreCredential moves a mock credential out of the trash.
+func (m *MockVaultService) RestoreCredential(service string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, entry := range m.trash {
+		if entry.Service == service {
+			m.trash = append(m.trash[:i], m.trash[i+1:]...)
+			m.credentials = append(m.credentials, vault.CredentialMetadata{Service: entry.Service, Username: entry.Username})
+			return nil
+		}
+	}
+	return errors.New("credential not in trash")
+}
+
+// PurgeTrash empties the mock trash.
+func (m *MockVaultService) PurgeTrash(olderThan time.Duration) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	purged := len(m.trash)
+	m.trash = nil
+	return purged, nil
+}
+
+// Lock records that the mock vault was locked.
+func (m *MockVaultService) Lock() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.locked = true
+}
+
 // SetCredentials sets the mock credentials for testing.
 func (m *MockVaultService) SetCredentials(creds []vault.CredentialMetadata) {
 	m.mu.Lock()
@@ -460,6 +520,88 @@ func TestDeleteCredential(t *testing.T) {
 	}
 }
 
+// TestRestoreCredential verifies restoring a deleted credential reloads state.
+func TestRestoreCredential(t *testing.T) {
+	mockVault := NewMockVaultService()
+	state := NewAppState(mockVault)
+
+	mockVault.SetCredentials([]vault.CredentialMetadata{
+		{Service: "AWS", Username: "admin", CreatedAt: time.Now()},
+	})
+	_ = state.LoadCredentials()
+
+	if err := state.DeleteCredential("AWS"); err != nil {
+		t.Fatalf("DeleteCredential failed: %v", err)
+	}
+
+	trash, err := state.ListTrash()
+	if err != nil || len(trash) != 1 {
+		t.Fatalf("Expected 1 trashed credential, got %d (err %v)", len(trash), err)
+	}
+
+	callbackInvoked := false
+	state.SetOnCredentialsChanged(func() {
+		callbackInvoked = true
+	})
+
+	if err := state.RestoreCredential("AWS"); err != nil {
+		t.Fatalf("RestoreCredential failed: %v", err)
+	}
+	if !callbackInvoked {
+		t.Error("onCredentialsChanged callback was not invoked")
+	}
+	if creds := state.GetCredentials(); len(creds) != 1 || creds[0].Service != "AWS" {
+		t.Errorf("Expected restored credential 'AWS', got %+v", creds)
+	}
+
+	if err := state.RestoreCredential("AWS"); err == nil {
+		t.Error("Expected error restoring a credential that is not in the trash")
+	}
+}
+
+// TestSwitchVault verifies switching locks the previous vault, resets filters, and reloads credentials.
+func TestSwitchVault(t *testing.T) {
+	personal := NewMockVaultService()
+	personal.SetCredentials([]vault.CredentialMetadata{
+		{Service: "github", Category: "Dev", CreatedAt: time.Now()},
+	})
+	state := NewAppState(personal)
+	state.SetVaultPath("/vaults/personal.enc")
+	_ = state.LoadCredentials()
+	state.SetSelection("Dev", nil)
+
+	work := NewMockVaultService()
+	work.SetCredentials([]vault.CredentialMetadata{
+		{Service: "jira", CreatedAt: time.Now()},
+		{Service: "vpn", CreatedAt: time.Now()},
+	})
+
+	callbackInvoked := false
+	state.SetOnCredentialsChanged(func() {
+		callbackInvoked = true
+	})
+
+	if err := state.SwitchVault(work, "/vaults/work.enc"); err != nil {
+		t.Fatalf("SwitchVault failed: %v", err)
+	}
+
+	if !personal.locked {
+		t.Error("Expected previous vault to be locked")
+	}
+	if !callbackInvoked {
+		t.Error("onCredentialsChanged callback was not invoked")
+	}
+	if state.GetVaultPath() != "/vaults/work.enc" {
+		t.Errorf("Expected vault path '/vaults/work.enc', got '%s'", state.GetVaultPath())
+	}
+	if state.GetSelectedCategory() != "" {
+		t.Errorf("Expected category filter reset, got '%s'", state.GetSelectedCategory())
+	}
+	if creds := state.GetCredentials(); len(creds) != 2 {
+		t.Errorf("Expected 2 credentials from the new vault, got %d", len(creds))
+	}
+}
+
 // TestCallbackInvocation_AfterUnlock is the CRITICAL deadlock prevention test.
 // It verifies that callbacks are invoked AFTER releasing locks.
 func TestCallbackInvocation_AfterUnlock(t *testing.T) {
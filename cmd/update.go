@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -21,6 +22,20 @@ var (
 	clearCategory  bool
 	clearURL       bool
 	clearNotes     bool
+	updateFields   []string
+	updateSecrets  []string
+	removeFields   []string
+	updateTOTP     string
+	clearTOTP      bool
+	restoreVersion int
+	updateTags     []string
+	removeTags     []string
+	clearTags      bool
+	updateExpires  string
+	updateRotate   string
+	clearExpiry    bool
+	clearRotation  bool
+	updateType     string
 )
 
 var updateCmd = &cobra.Command{
@@ -34,6 +49,25 @@ affecting the others. Empty values mean "don't change".
 To explicitly clear optional fields (category, url, notes) to empty, use the --clear-* flags.
 These flags take precedence over corresponding value flags.
 
+Expiry is set with --expires (YYYY-MM-DD or a duration like 90d) and a rotation
+interval with --rotate-every; remove them with --clear-expiry and --clear-rotation.
+
+Tags are added with --tag and removed with --remove-tag; --clear-tags removes all
+existing tags before any --tag values are applied.
+
+Custom fields are set with --field key=value (or --secret-field for masked values).
+Existing keys keep their position; new keys are appended. Use --remove-field key
+to drop a custom field.
+
+Use --type to change the credential type; existing custom fields are kept as-is,
+and the credential must meet the new type's requirements (add them with --field).
+
+Use --restore-version N to roll the password back to entry N of
+'pass-cli history <service>' (1 = most recent previous password).
+
+A TOTP seed can be set with --totp (otpauth:// URI or base32 secret, "-" to prompt)
+and removed with --clear-totp.
+
 By default, you'll see a usage warning if the credential has been accessed before,
 showing where and when it was last used. Use --force to skip the confirmation.`,
 	Example: `  # Update password only (interactive prompt)
@@ -48,6 +82,12 @@ showing where and when it was last used. Use --force to skip the confirmation.`,
   # Update category only
   pass-cli update github --category "Work"
 
+  # Add and remove tags
+  pass-cli update aws-prod --tag billing --remove-tag staging
+
+  # Require rotation every 90 days
+  pass-cli update stripe-key --rotate-every 90d
+
   # Update URL only
   pass-cli update github --url "https://github.com"
 
@@ -66,6 +106,21 @@ showing where and when it was last used. Use --force to skip the confirmation.`,
   # Update multiple fields
   pass-cli update github -u user -p pass --notes "New info"
 
+  # Set or replace custom fields
+  pass-cli update aws-prod --field region=eu-west-1 --secret-field session_token=xyz
+
+  # Remove a custom field
+  pass-cli update aws-prod --remove-field session_token
+
+  # Roll back to the previous password
+  pass-cli update github --restore-version 1
+
+  # Set a TOTP seed (prompted to keep it out of shell history)
+  pass-cli update github --totp -
+
+  # Remove the TOTP seed
+  pass-cli update github --clear-totp
+
   # Skip confirmation
   pass-cli update github --force`,
 	Args: cobra.ExactArgs(1),
@@ -79,9 +134,23 @@ func init() {
 	updateCmd.Flags().StringVar(&updateNotes, "notes", "", "new notes")
 	updateCmd.Flags().StringVar(&updateCategory, "category", "", "new category")
 	updateCmd.Flags().StringVar(&updateURL, "url", "", "new URL")
+	updateCmd.Flags().StringSliceVarP(&updateTags, "tag", "t", nil, "add tag (repeatable or comma-separated)")
+	updateCmd.Flags().StringSliceVar(&removeTags, "remove-tag", nil, "remove tag (repeatable or comma-separated)")
+	updateCmd.Flags().BoolVar(&clearTags, "clear-tags", false, "remove all tags")
+	updateCmd.Flags().StringVar(&updateType, "type", "", "new credential type: login, api-key, ssh-key, database, secure-note")
+	updateCmd.Flags().StringVar(&updateExpires, "expires", "", "new expiry date (YYYY-MM-DD) or duration from now (e.g., 90d)")
+	updateCmd.Flags().StringVar(&updateRotate, "rotate-every", "", "new rotation interval (e.g., 90d, 12w)")
+	updateCmd.Flags().BoolVar(&clearExpiry, "clear-expiry", false, "remove the expiry date")
+	updateCmd.Flags().BoolVar(&clearRotation, "clear-rotation", false, "remove the rotation interval")
 	updateCmd.Flags().BoolVar(&clearCategory, "clear-category", false, "clear category field to empty")
 	updateCmd.Flags().BoolVar(&clearURL, "clear-url", false, "clear URL field to empty")
 	updateCmd.Flags().BoolVar(&clearNotes, "clear-notes", false, "clear notes field to empty")
+	updateCmd.Flags().StringArrayVar(&updateFields, "field", nil, "set custom field as key=value (repeatable)")
+	updateCmd.Flags().StringArrayVar(&updateSecrets, "secret-field", nil, "set secret custom field as key=value (repeatable)")
+	updateCmd.Flags().StringArrayVar(&removeFields, "remove-field", nil, "remove custom field by key (repeatable)")
+	updateCmd.Flags().StringVar(&updateTOTP, "totp", "", "set TOTP seed as otpauth:// URI or base32 secret (\"-\" to prompt)")
+	updateCmd.Flags().BoolVar(&clearTOTP, "clear-totp", false, "remove the TOTP seed")
+	updateCmd.Flags().IntVar(&restoreVersion, "restore-version", 0, "restore password version N from 'pass-cli history'")
 	updateCmd.Flags().BoolVar(&updateForce, "force", false, "skip confirmation prompt")
 }
 
@@ -91,6 +160,60 @@ func runUpdate(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("service name cannot be empty")
 	}
 
+	// Parse custom field flags before unlocking so typos fail fast
+	plainFields, err := parseFieldFlags(updateFields, false)
+	if err != nil {
+		return err
+	}
+	secretFields, err := parseFieldFlags(updateSecrets, true)
+	if err != nil {
+		return err
+	}
+	fieldUpdates := append(plainFields, secretFields...)
+	hasFieldChanges := len(fieldUpdates) > 0 || len(removeFields) > 0
+
+	// --clear-totp takes precedence, so the seed is not parsed (or prompted for)
+	var totp *vault.TOTPConfig
+	if updateTOTP != "" && !clearTOTP {
+		if totp, err = parseTOTPFlag(updateTOTP); err != nil {
+			return err
+		}
+	}
+	hasTOTPChanges := totp != nil || clearTOTP
+	hasTagChanges := len(updateTags) > 0 || len(removeTags) > 0 || clearTags
+
+	var expiresAt time.Time
+	if updateExpires != "" {
+		if expiresAt, err = parseExpiryFlag(updateExpires); err != nil {
+			return err
+		}
+	}
+	var rotationDays int
+	if updateRotate != "" {
+		if rotationDays, err = parseRotationFlag(updateRotate); err != nil {
+			return err
+		}
+	}
+	hasExpiryChanges := updateExpires != "" || updateRotate != "" || clearExpiry || clearRotation
+
+	var credType vault.CredentialType
+	if updateType != "" {
+		if credType, err = vault.ParseCredentialType(updateType); err != nil {
+			return err
+		}
+	}
+	hasTypeChange := credType != ""
+
+	// --restore-version sets the password, so it cannot be combined with --password
+	if cmd.Flags().Changed("restore-version") {
+		if restoreVersion < 1 {
+			return fmt.Errorf("--restore-version must be 1 or greater")
+		}
+		if updatePassword != "" {
+			return fmt.Errorf("--restore-version cannot be combined with --password")
+		}
+	}
+
 	vaultPath := GetVaultPath()
 
 	// Check if vault exists
@@ -116,9 +239,18 @@ func runUpdate(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to get credential: %w", err)
 	}
 
+	// The restored password is applied with the other changes, after confirmation
+	var restoredPassword []byte
+	if restoreVersion > 0 {
+		if restoredPassword, err = vaultService.PasswordVersion(service, restoreVersion); err != nil {
+			return fmt.Errorf("failed to restore password: %w", err)
+		}
+	}
+	hasRestore := restoredPassword != nil
+
 	// If no flags provided (including clear flags), prompt for what to update
-	if updateUsername == "" && updatePassword == "" && updateNotes == "" && updateCategory == "" && updateURL == "" &&
-		!clearCategory && !clearURL && !clearNotes {
+	if !hasRestore && updateUsername == "" && updatePassword == "" && updateNotes == "" && updateCategory == "" && updateURL == "" &&
+		!clearCategory && !clearURL && !clearNotes && !hasFieldChanges && !hasTOTPChanges && !hasTagChanges && !hasExpiryChanges && !hasTypeChange {
 		fmt.Println("What would you like to update? (leave empty to keep current value)")
 		fmt.Println()
 
@@ -167,8 +299,8 @@ func runUpdate(cmd *cobra.Command, args []string) error {
 	}
 
 	// Check if anything is being updated
-	if updateUsername == "" && updatePassword == "" && updateNotes == "" && updateCategory == "" && updateURL == "" &&
-		!clearCategory && !clearURL && !clearNotes {
+	if !hasRestore && updateUsername == "" && updatePassword == "" && updateNotes == "" && updateCategory == "" && updateURL == "" &&
+		!clearCategory && !clearURL && !clearNotes && !hasFieldChanges && !hasTOTPChanges && !hasTagChanges && !hasExpiryChanges && !hasTypeChange {
 		fmt.Println("No changes specified.")
 		return nil
 	}
@@ -212,6 +344,9 @@ func runUpdate(cmd *cobra.Command, args []string) error {
 		passwordBytes := []byte(updatePassword)
 		opts.Password = &passwordBytes
 	}
+	if hasRestore {
+		opts.Password = &restoredPassword
+	}
 
 	// Handle notes: clear flag takes precedence
 	if clearNotes {
@@ -237,6 +372,43 @@ func runUpdate(cmd *cobra.Command, args []string) error {
 		opts.URL = &updateURL
 	}
 
+	// Merge custom field changes into the existing ordered list
+	if hasFieldChanges {
+		merged := vault.MergeCustomFields(cred.CustomFields, fieldUpdates, removeFields)
+		opts.CustomFields = &merged
+	}
+
+	// Handle tags: clear flag drops existing tags before additions are applied
+	if hasTagChanges {
+		existing := cred.Tags
+		if clearTags {
+			existing = nil
+		}
+		merged := vault.MergeTags(existing, updateTags, removeTags)
+		opts.Tags = &merged
+	}
+
+	// Handle expiry and rotation: clear flags take precedence
+	if clearExpiry {
+		opts.ExpiresAt = &time.Time{}
+	} else if !expiresAt.IsZero() {
+		opts.ExpiresAt = &expiresAt
+	}
+	if clearRotation {
+		noRotation := 0
+		opts.RotationDays = &noRotation
+	} else if rotationDays > 0 {
+		opts.RotationDays = &rotationDays
+	}
+
+	if hasTypeChange {
+		opts.Type = &credType
+	}
+
+	// Handle TOTP: the vault lets ClearTOTP take precedence over a new seed
+	opts.ClearTOTP = clearTOTP
+	opts.TOTP = totp
+
 	if err := vaultService.UpdateCredential(service, opts); err != nil {
 		return fmt.Errorf("failed to update credential: %w", err)
 	}
@@ -251,6 +423,10 @@ func runUpdate(cmd *cobra.Command, args []string) error {
 	if updatePassword != "" {
 		fmt.Printf("🔑 Password updated\n")
 	}
+	if hasRestore {
+		fmt.Printf("🔑 Password restored to version %d\n", restoreVersion)
+		fmt.Println("📜 Replaced password saved as version 1 in history")
+	}
 	if clearCategory {
 		fmt.Printf("🏷️  Category cleared\n")
 	} else if updateCategory != "" {
@@ -266,6 +442,40 @@ func runUpdate(cmd *cobra.Command, args []string) error {
 	} else if updateNotes != "" {
 		fmt.Printf("📋 New notes: %s\n", updateNotes)
 	}
+	for _, field := range fieldUpdates {
+		fmt.Printf("🧩 Field set: %s\n", field.Key)
+	}
+	for _, key := range removeFields {
+		fmt.Printf("🧩 Field removed: %s\n", key)
+	}
+	if hasTypeChange {
+		template, _ := vault.GetTemplate(credType)
+		fmt.Printf("🗂️  New type: %s\n", template.Label)
+	}
+	if clearExpiry {
+		fmt.Printf("⌛ Expiry cleared\n")
+	} else if !expiresAt.IsZero() {
+		fmt.Printf("⌛ New expiry: %s\n", expiresAt.Format("2006-01-02"))
+	}
+	if clearRotation {
+		fmt.Printf("🔄 Rotation interval cleared\n")
+	} else if rotationDays > 0 {
+		fmt.Printf("🔄 Rotate every: %d days\n", rotationDays)
+	}
+	if clearTags {
+		fmt.Printf("🔖 Tags cleared\n")
+	}
+	if len(updateTags) > 0 {
+		fmt.Printf("🔖 Tags added: %s\n", strings.Join(updateTags, ", "))
+	}
+	if len(removeTags) > 0 {
+		fmt.Printf("🔖 Tags removed: %s\n", strings.Join(removeTags, ", "))
+	}
+	if clearTOTP {
+		fmt.Printf("⏱️  TOTP seed removed\n")
+	} else if totp != nil {
+		fmt.Printf("⏱️  TOTP seed updated\n")
+	}
 
 	return nil
 }
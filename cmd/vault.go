@@ -0,0 +1,230 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"pass-cli/internal/config"
+)
+
+var vaultAddDefault bool
+
+var vaultCmd = &cobra.Command{
+	Use:   "vault",
+	Short: "Manage named vaults",
+	Long: `Manage the registry of named vaults (profiles) stored in the config file.
+
+Registered vaults can be selected for any command with --profile <name>.
+The default vault is used when neither --vault nor --profile is given.`,
+}
+
+var vaultAddCmd = &cobra.Command{
+	Use:   "add <name> <path>",
+	Short: "Register a named vault",
+	Long: `Add registers a vault file under a short name. The path is stored as an
+absolute path; the vault file does not have to exist yet.
+
+Names may contain lowercase letters, digits, '-' and '_'.`,
+	Example: `  # Register a work vault
+  pass-cli vault add work ~/work/vault.enc
+
+  # Register and make it the default
+  pass-cli vault add work ~/work/vault.enc --default
+
+  # Create the vault file for a new profile
+  pass-cli --profile work init`,
+	Args: cobra.ExactArgs(2),
+	RunE: runVaultAdd,
+}
+
+var vaultListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List named vaults",
+	Args:    cobra.NoArgs,
+	RunE:    runVaultList,
+}
+
+var vaultUseCmd = &cobra.Command{
+	Use:   "use <name>",
+	Short: "Set the default vault",
+	Long: `Use makes a registered vault the default for commands run without
+--vault or --profile.`,
+	Example: `  # Switch the default vault to the work vault
+  pass-cli vault use work`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeProfileArg,
+	RunE:              runVaultUse,
+}
+
+var vaultRemoveCmd = &cobra.Command{
+	Use:     "remove <name>",
+	Aliases: []string{"rm"},
+	Short:   "Unregister a named vault",
+	Long: `Remove deletes a vault from the registry. The vault file itself is not
+touched. Removing the default vault clears the default.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeProfileArg,
+	RunE:              runVaultRemove,
+}
+
+func init() {
+	rootCmd.AddCommand(vaultCmd)
+	vaultCmd.AddCommand(vaultAddCmd)
+	vaultCmd.AddCommand(vaultListCmd)
+	vaultCmd.AddCommand(vaultUseCmd)
+	vaultCmd.AddCommand(vaultRemoveCmd)
+	vaultAddCmd.Flags().BoolVar(&vaultAddDefault, "default", false, "also make this the default vault")
+}
+
+func runVaultAdd(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	path, err := expandVaultPath(args[1])
+	if err != nil {
+		return err
+	}
+
+	configPath, err := vaultsConfigPath()
+	if err != nil {
+		return err
+	}
+
+	if err := config.AddVaultProfile(configPath, name, path); err != nil {
+		return fmt.Errorf("failed to add vault: %w", err)
+	}
+	fmt.Printf("✅ Added vault '%s': %s\n", name, path)
+
+	if vaultAddDefault {
+		if err := config.SetDefaultVault(configPath, name); err != nil {
+			return fmt.Errorf("failed to set default vault: %w", err)
+		}
+		fmt.Printf("✅ Default vault: %s\n", name)
+	}
+
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		fmt.Printf("\n💡 The vault file does not exist yet. Create it with:\n")
+		fmt.Printf("   pass-cli --profile %s init\n", name)
+	}
+	return nil
+}
+
+func runVaultList(cmd *cobra.Command, args []string) error {
+	vaults, err := loadVaults()
+	if err != nil {
+		return fmt.Errorf("failed to load vault registry: %w", err)
+	}
+
+	profiles := vaults.List()
+	if len(profiles) == 0 {
+		fmt.Println("No named vaults registered.")
+		fmt.Println("Add one with: pass-cli vault add <name> <path>")
+		return nil
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	var data [][]string
+	for _, p := range profiles {
+		isDefault := ""
+		if p.IsDefault {
+			isDefault = "✓"
+		}
+		status := "ok"
+		if _, err := os.Stat(p.Path); os.IsNotExist(err) {
+			status = "missing"
+		}
+		data = append(data, []string{p.Name, p.Path, isDefault, status})
+	}
+	table.Header([]string{"Name", "Path", "Default", "Status"})
+	_ = table.Bulk(data)
+	_ = table.Render()
+
+	fmt.Printf("\nTotal: %d vault(s)\n", len(profiles))
+	return nil
+}
+
+func runVaultUse(cmd *cobra.Command, args []string) error {
+	configPath, err := vaultsConfigPath()
+	if err != nil {
+		return err
+	}
+
+	if err := config.SetDefaultVault(configPath, args[0]); err != nil {
+		return fmt.Errorf("failed to set default vault: %w", err)
+	}
+	fmt.Printf("✅ Default vault: %s\n", args[0])
+
+	// The legacy vault key still wins over the registry default
+	if viper.IsSet("vault") {
+		fmt.Fprintf(os.Stderr, "⚠️  The 'vault' setting (%s) overrides the default vault; remove it to use '%s'\n",
+			viper.GetString("vault"), args[0])
+	}
+	return nil
+}
+
+func runVaultRemove(cmd *cobra.Command, args []string) error {
+	configPath, err := vaultsConfigPath()
+	if err != nil {
+		return err
+	}
+
+	vaults, err := config.LoadVaultsFromPath(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load vault registry: %w", err)
+	}
+	path, err := vaults.Resolve(args[0])
+	if err != nil {
+		return err
+	}
+
+	if err := config.RemoveVaultProfile(configPath, args[0]); err != nil {
+		return fmt.Errorf("failed to remove vault: %w", err)
+	}
+	fmt.Printf("✅ Removed vault '%s'\n", args[0])
+	fmt.Printf("🗂️  The vault file was kept at %s\n", path)
+	return nil
+}
+
+// expandVaultPath expands a leading "~" and makes a vault path absolute
+func expandVaultPath(path string) (string, error) {
+	if path == "~" || strings.HasPrefix(path, "~/") || strings.HasPrefix(path, "~"+string(filepath.Separator)) {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to get home directory: %w", err)
+		}
+		path = filepath.Join(home, path[1:])
+	}
+
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return "", fmt.Errorf("invalid vault path: %w", err)
+	}
+	return absPath, nil
+}
+
+// completeProfileArg completes the single vault name argument of vault use/remove
+func completeProfileArg(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return completeProfileNames(cmd, args, toComplete)
+}
+
+// completeProfileNames completes registered vault names (also used for --profile)
+func completeProfileNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	vaults, err := loadVaults()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var names []string
+	for _, p := range vaults.List() {
+		names = append(names, p.Name)
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
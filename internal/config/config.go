@@ -6,6 +6,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"strings"
 
 	"github.com/spf13/viper"
 )
@@ -14,6 +15,7 @@ import (
 type Config struct {
 	Terminal    TerminalConfig    `mapstructure:"terminal"`
 	Keybindings map[string]string `mapstructure:"keybindings"`
+	Vaults      VaultsConfig      `mapstructure:"vaults"`
 
 	// LoadErrors populated during config loading (not in YAML)
 	LoadErrors []string `mapstructure:"-"`
@@ -62,6 +64,8 @@ func GetDefaults() *Config {
 			"add_credential":    "a",
 			"edit_credential":   "e",
 			"delete_credential": "d",
+			"show_trash":        "t",
+			"switch_vault":      "v",
 			"toggle_detail":     "i",
 			"toggle_sidebar":    "s",
 			"help":              "?",
@@ -81,6 +85,22 @@ func GetDefaults() *Config {
 
 // GetConfigPath returns the OS-appropriate config file path using os.UserConfigDir()
 func GetConfigPath() (string, error) {
+	configPath, err := DefaultConfigPath()
+	if err != nil {
+		return "", err
+	}
+
+	// Ensure directory exists
+	if err := os.MkdirAll(filepath.Dir(configPath), 0755); err != nil {
+		return "", fmt.Errorf("cannot create config directory: %w", err)
+	}
+
+	return configPath, nil
+}
+
+// DefaultConfigPath returns the same path as GetConfigPath without creating
+// the directory, for callers that only read the file
+func DefaultConfigPath() (string, error) {
 	configDir, err := os.UserConfigDir()
 	if err != nil {
 		// Fallback to home directory if UserConfigDir fails
@@ -93,11 +113,6 @@ func GetConfigPath() (string, error) {
 		configDir = filepath.Join(configDir, "pass-cli")
 	}
 
-	// Ensure directory exists
-	if err := os.MkdirAll(configDir, 0755); err != nil {
-		return "", fmt.Errorf("cannot create config directory: %w", err)
-	}
-
 	return filepath.Join(configDir, "config.yml"), nil
 }
 
@@ -175,7 +190,9 @@ keybindings:
   # Credential management
   add_credential: "a"          # Open form to add new credential
   edit_credential: "e"         # Edit selected credential
-  delete_credential: "d"       # Delete selected credential (with confirmation)
+  delete_credential: "d"       # Move selected credential to the trash (with confirmation)
+  show_trash: "t"              # Show deleted credentials (restore or purge)
+  switch_vault: "v"            # Switch to another named vault
   
   # View controls
   toggle_detail: "i"           # Toggle detail panel visibility
@@ -202,6 +219,19 @@ keybindings:
 #   quit: "ctrl+q"
 #   add_credential: "n"
 #   help: "f1"
+
+# Named vaults (managed by 'pass-cli vault add/use/remove')
+# Select one per command with --profile <name>; the default is used otherwise.
+#
+# key_file is used by vaults that require a key file (or have a key file
+# slot) when --key-file is not given; other vaults ignore it.
+#
+# vaults:
+#   default: personal
+#   profiles:
+#     personal: /home/me/.pass-cli/vault.enc
+#     work: /home/me/work/vault.enc
+#   key_file: /media/usb/vault.key
 `
 }
 
@@ -225,16 +255,25 @@ func detectUnknownFields(v *viper.Viper) []ValidationWarning {
 		"keybindings.add_credential":  true,
 		"keybindings.edit_credential": true,
 		"keybindings.delete_credential": true,
+		"keybindings.show_trash":        true,
+		"keybindings.switch_vault":      true,
 		"keybindings.toggle_detail":   true,
 		"keybindings.toggle_sidebar":  true,
 		"keybindings.help":            true,
 		"keybindings.search":          true,
 		"keybindings.confirm":         true,
 		"keybindings.cancel":          true,
+		"vaults":                      true,
+		"vaults.default":              true,
+		"vaults.profiles":             true,
+		"vaults.key_file":             true,
 	}
 
 	// Check for unknown fields
 	for _, key := range allKeys {
+		if strings.HasPrefix(key, "vaults.profiles.") {
+			continue // Profile names are user-defined
+		}
 		if !knownFields[key] {
 			warnings = append(warnings, ValidationWarning{
 				Field:   key,
@@ -378,6 +417,9 @@ func (c *Config) Validate() *ValidationResult {
 	// T032: Validate keybindings
 	result = c.validateKeybindings(result)
 
+	// Validate named vault registry
+	result = c.validateVaults(result)
+
 	// Set Valid flag based on error count
 	if len(result.Errors) > 0 {
 		result.Valid = false
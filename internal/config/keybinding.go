@@ -25,6 +25,8 @@ var validActions = []string{
 	"add_credential",
 	"edit_credential",
 	"delete_credential",
+	"show_trash",
+	"switch_vault",
 	"toggle_detail",
 	"toggle_sidebar",
 	"help",
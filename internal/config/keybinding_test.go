@@ -502,6 +502,8 @@ func TestGetValidActions(t *testing.T) {
 		"add_credential",
 		"edit_credential",
 		"delete_credential",
+		"show_trash",
+		"switch_vault",
 		"toggle_detail",
 		"toggle_sidebar",
 		"help",
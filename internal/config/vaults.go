@@ -0,0 +1,280 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// VaultsConfig is the registry of named vaults (profiles) stored under "vaults" in config.yml
+type VaultsConfig struct {
+	Default  string            `mapstructure:"default" yaml:"default,omitempty"`
+	Profiles map[string]string `mapstructure:"profiles" yaml:"profiles,omitempty"` // Profile name -> vault file path
+	KeyFile  string            `mapstructure:"key_file" yaml:"key_file,omitempty"` // Key file used when --key-file is not given
+}
+
+// VaultProfile is a single named vault from the registry
+type VaultProfile struct {
+	Name      string
+	Path      string
+	IsDefault bool
+}
+
+var (
+	// ErrProfileNotFound is returned when a profile name is not in the registry
+	ErrProfileNotFound = errors.New("vault profile not found")
+
+	// ErrProfileExists is returned when adding a profile name that is already registered
+	ErrProfileExists = errors.New("vault profile already exists")
+
+	// ErrInvalidProfileName is returned for names that cannot be stored as config keys
+	ErrInvalidProfileName = errors.New("invalid vault profile name")
+)
+
+// profileNamePattern keeps names lowercase because config keys are case-insensitive
+var profileNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// ValidateProfileName checks that a profile name is usable as a config key
+func ValidateProfileName(name string) error {
+	if !profileNamePattern.MatchString(name) {
+		return fmt.Errorf("%w '%s': use lowercase letters, digits, '-' and '_'", ErrInvalidProfileName, name)
+	}
+	return nil
+}
+
+// List returns the registered profiles sorted by name
+func (vc VaultsConfig) List() []VaultProfile {
+	profiles := make([]VaultProfile, 0, len(vc.Profiles))
+	for name, path := range vc.Profiles {
+		profiles = append(profiles, VaultProfile{Name: name, Path: path, IsDefault: name == vc.Default})
+	}
+	sort.Slice(profiles, func(i, j int) bool {
+		return profiles[i].Name < profiles[j].Name
+	})
+	return profiles
+}
+
+// Resolve returns the vault path registered under name
+func (vc VaultsConfig) Resolve(name string) (string, error) {
+	path, ok := vc.Profiles[name]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrProfileNotFound, name)
+	}
+	return path, nil
+}
+
+// DefaultPath returns the path of the default profile, or "" when none is set
+func (vc VaultsConfig) DefaultPath() string {
+	if vc.Default == "" {
+		return ""
+	}
+	return vc.Profiles[vc.Default]
+}
+
+// validateVaults validates the vault registry
+func (c *Config) validateVaults(result *ValidationResult) *ValidationResult {
+	for name, path := range c.Vaults.Profiles {
+		if err := ValidateProfileName(name); err != nil {
+			result.Errors = append(result.Errors, ValidationError{
+				Field:   fmt.Sprintf("vaults.profiles.%s", name),
+				Message: err.Error(),
+			})
+		}
+		if path == "" {
+			result.Errors = append(result.Errors, ValidationError{
+				Field:   fmt.Sprintf("vaults.profiles.%s", name),
+				Message: "vault path must not be empty",
+			})
+		}
+	}
+
+	if c.Vaults.Default != "" {
+		if _, ok := c.Vaults.Profiles[c.Vaults.Default]; !ok {
+			result.Errors = append(result.Errors, ValidationError{
+				Field:   "vaults.default",
+				Message: fmt.Sprintf("unknown profile '%s'", c.Vaults.Default),
+			})
+		}
+	}
+
+	return result
+}
+
+// LoadVaults reads only the vault registry from VaultsConfigPath("").
+// Unlike Load, it does not log or validate the rest of the file, so it is cheap
+// enough to call when resolving the vault path for every command.
+func LoadVaults() (*VaultsConfig, error) {
+	configPath, err := VaultsConfigPath("")
+	if err != nil {
+		return nil, err
+	}
+	return LoadVaultsFromPath(configPath)
+}
+
+// VaultsConfigPath returns the config file that holds the vault registry and
+// vaults.key_file: explicit (the file given with --config) if set, else the
+// CLI's settings file ~/.pass-cli/config.yaml when it exists, else the default
+// config path. Nothing is created.
+func VaultsConfigPath(explicit string) (string, error) {
+	if explicit != "" {
+		return explicit, nil
+	}
+
+	if homeDir, err := os.UserHomeDir(); err == nil {
+		for _, name := range []string{"config.yaml", "config.yml"} {
+			path := filepath.Join(homeDir, ".pass-cli", name)
+			if _, err := os.Stat(path); err == nil {
+				return path, nil
+			}
+		}
+	}
+	return DefaultConfigPath()
+}
+
+// LoadVaultsFromPath reads the vault registry from a config file.
+// A missing file yields an empty registry.
+func LoadVaultsFromPath(configPath string) (*VaultsConfig, error) {
+	data, err := os.ReadFile(configPath) // #nosec G304 -- Config path is user-controlled by design
+	if os.IsNotExist(err) {
+		return &VaultsConfig{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var file struct {
+		Vaults VaultsConfig `yaml:"vaults"`
+	}
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse YAML: %w", err)
+	}
+	return &file.Vaults, nil
+}
+
+// AddVaultProfile registers a named vault in the config file
+func AddVaultProfile(configPath, name, vaultPath string) error {
+	if err := ValidateProfileName(name); err != nil {
+		return err
+	}
+	if vaultPath == "" {
+		return fmt.Errorf("vault path must not be empty")
+	}
+
+	return updateVaults(configPath, func(vc *VaultsConfig) error {
+		if _, ok := vc.Profiles[name]; ok {
+			return fmt.Errorf("%w: %s", ErrProfileExists, name)
+		}
+		if vc.Profiles == nil {
+			vc.Profiles = make(map[string]string)
+		}
+		vc.Profiles[name] = vaultPath
+		return nil
+	})
+}
+
+// RemoveVaultProfile unregisters a named vault, clearing the default if it pointed at it.
+// The vault file itself is left untouched.
+func RemoveVaultProfile(configPath, name string) error {
+	return updateVaults(configPath, func(vc *VaultsConfig) error {
+		if _, ok := vc.Profiles[name]; !ok {
+			return fmt.Errorf("%w: %s", ErrProfileNotFound, name)
+		}
+		delete(vc.Profiles, name)
+		if vc.Default == name {
+			vc.Default = ""
+		}
+		return nil
+	})
+}
+
+// SetDefaultVault makes a registered profile the default vault
+func SetDefaultVault(configPath, name string) error {
+	return updateVaults(configPath, func(vc *VaultsConfig) error {
+		if _, ok := vc.Profiles[name]; !ok {
+			return fmt.Errorf("%w: %s", ErrProfileNotFound, name)
+		}
+		vc.Default = name
+		return nil
+	})
+}
+
+// updateVaults applies mutate to the registry and writes it back to the config file.
+// The rest of the document (including comments) is preserved by editing the YAML node tree.
+func updateVaults(configPath string, mutate func(vc *VaultsConfig) error) error {
+	data, err := os.ReadFile(configPath) // #nosec G304 -- Config path is user-controlled by design
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var doc yaml.Node
+	if len(data) > 0 {
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return fmt.Errorf("failed to parse YAML: %w", err)
+		}
+	}
+	if doc.Kind == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return fmt.Errorf("config file root must be a mapping")
+	}
+
+	var vc VaultsConfig
+	index := mappingKeyIndex(root, "vaults")
+	if index >= 0 {
+		if err := root.Content[index+1].Decode(&vc); err != nil {
+			return fmt.Errorf("failed to parse vaults: %w", err)
+		}
+	}
+
+	if err := mutate(&vc); err != nil {
+		return err
+	}
+
+	var value yaml.Node
+	if err := value.Encode(vc); err != nil {
+		return fmt.Errorf("failed to encode vaults: %w", err)
+	}
+	if index >= 0 {
+		value.HeadComment = root.Content[index+1].HeadComment
+		root.Content[index+1] = &value
+	} else {
+		root.Content = append(root.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Value: "vaults", HeadComment: "Named vaults (managed by 'pass-cli vault')"},
+			&value)
+	}
+
+	var out bytes.Buffer
+	encoder := yaml.NewEncoder(&out)
+	encoder.SetIndent(2) // Match the indentation of the config template
+	if err := encoder.Encode(&doc); err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(configPath), 0755); err != nil {
+		return fmt.Errorf("cannot create config directory: %w", err)
+	}
+	if err := os.WriteFile(configPath, out.Bytes(), 0644); err != nil { // #nosec G306 -- Config file holds no secrets
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	return nil
+}
+
+// mappingKeyIndex returns the index of key in a mapping node's content, or -1
+func mappingKeyIndex(mapping *yaml.Node, key string) int {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return i
+		}
+	}
+	return -1
+}
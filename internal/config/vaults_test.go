@@ -0,0 +1,183 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestVaultProfileRegistry(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yml")
+
+	// Missing config file yields an empty registry
+	vaults, err := LoadVaultsFromPath(configPath)
+	if err != nil {
+		t.Fatalf("LoadVaultsFromPath() failed: %v", err)
+	}
+	if len(vaults.Profiles) != 0 || vaults.DefaultPath() != "" {
+		t.Fatalf("expected empty registry, got %+v", vaults)
+	}
+
+	if err := AddVaultProfile(configPath, "personal", "/vaults/personal.enc"); err != nil {
+		t.Fatalf("AddVaultProfile(personal) failed: %v", err)
+	}
+	if err := AddVaultProfile(configPath, "work", "/vaults/work.enc"); err != nil {
+		t.Fatalf("AddVaultProfile(work) failed: %v", err)
+	}
+	if err := AddVaultProfile(configPath, "work", "/other.enc"); !errors.Is(err, ErrProfileExists) {
+		t.Errorf("expected ErrProfileExists, got %v", err)
+	}
+	if err := AddVaultProfile(configPath, "Work Vault", "/other.enc"); !errors.Is(err, ErrInvalidProfileName) {
+		t.Errorf("expected ErrInvalidProfileName, got %v", err)
+	}
+
+	if err := SetDefaultVault(configPath, "work"); err != nil {
+		t.Fatalf("SetDefaultVault() failed: %v", err)
+	}
+	if err := SetDefaultVault(configPath, "client"); !errors.Is(err, ErrProfileNotFound) {
+		t.Errorf("expected ErrProfileNotFound, got %v", err)
+	}
+
+	vaults, err = LoadVaultsFromPath(configPath)
+	if err != nil {
+		t.Fatalf("LoadVaultsFromPath() failed: %v", err)
+	}
+	if vaults.DefaultPath() != "/vaults/work.enc" {
+		t.Errorf("expected default path /vaults/work.enc, got %q", vaults.DefaultPath())
+	}
+	if path, err := vaults.Resolve("personal"); err != nil || path != "/vaults/personal.enc" {
+		t.Errorf("Resolve(personal) = %q, %v", path, err)
+	}
+	profiles := vaults.List()
+	if len(profiles) != 2 || profiles[0].Name != "personal" || !profiles[1].IsDefault {
+		t.Errorf("unexpected profile list: %+v", profiles)
+	}
+
+	// Removing the default profile clears the default
+	if err := RemoveVaultProfile(configPath, "work"); err != nil {
+		t.Fatalf("RemoveVaultProfile() failed: %v", err)
+	}
+	vaults, _ = LoadVaultsFromPath(configPath)
+	if vaults.Default != "" || len(vaults.Profiles) != 1 {
+		t.Errorf("expected one profile and no default, got %+v", vaults)
+	}
+}
+
+func TestVaultProfileRegistryPreservesConfig(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yml")
+	if err := os.WriteFile(configPath, []byte(GetDefaultConfigTemplate()), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	if err := AddVaultProfile(configPath, "work", "/vaults/work.enc"); err != nil {
+		t.Fatalf("AddVaultProfile() failed: %v", err)
+	}
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatalf("failed to read config: %v", err)
+	}
+	if !strings.Contains(string(data), "# Terminal size warning configuration") {
+		t.Error("expected comments to be preserved")
+	}
+
+	// The full loader accepts the registry without unknown-field warnings
+	cfg, result := LoadFromPath(configPath)
+	if !result.Valid {
+		t.Fatalf("expected valid config, got errors: %+v", result.Errors)
+	}
+	if len(result.Warnings) != 0 {
+		t.Errorf("expected no warnings, got %+v", result.Warnings)
+	}
+	if cfg.Vaults.Profiles["work"] != "/vaults/work.enc" {
+		t.Errorf("expected work profile in loaded config, got %+v", cfg.Vaults)
+	}
+	if cfg.Keybindings["quit"] != "q" {
+		t.Errorf("expected keybindings to survive the rewrite, got quit=%q", cfg.Keybindings["quit"])
+	}
+}
+
+func TestVaultKeyFileDefault(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yml")
+	content := "vaults:\n  key_file: /media/usb/vault.key\n"
+	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	// Registry edits keep the key file
+	if err := AddVaultProfile(configPath, "work", "/vaults/work.enc"); err != nil {
+		t.Fatalf("AddVaultProfile() failed: %v", err)
+	}
+	vaults, err := LoadVaultsFromPath(configPath)
+	if err != nil {
+		t.Fatalf("LoadVaultsFromPath() failed: %v", err)
+	}
+	if vaults.KeyFile != "/media/usb/vault.key" {
+		t.Errorf("expected key file /media/usb/vault.key, got %q", vaults.KeyFile)
+	}
+
+	_, result := LoadFromPath(configPath)
+	if len(result.Warnings) != 0 {
+		t.Errorf("expected no warnings, got %+v", result.Warnings)
+	}
+}
+
+func TestValidateVaults(t *testing.T) {
+	cfg := GetDefaults()
+	cfg.Vaults = VaultsConfig{
+		Default:  "missing",
+		Profiles: map[string]string{"work": ""},
+	}
+
+	result := cfg.Validate()
+	if result.Valid {
+		t.Fatal("expected invalid config")
+	}
+	if len(result.Errors) != 2 {
+		t.Errorf("expected 2 errors (empty path, unknown default), got %+v", result.Errors)
+	}
+}
+
+func TestVaultsConfigPath(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+
+	if got, _ := VaultsConfigPath("/etc/pass-cli.yaml"); got != "/etc/pass-cli.yaml" {
+		t.Errorf("VaultsConfigPath(explicit) = %q, want the explicit path", got)
+	}
+
+	// Without a CLI settings file the default config path is used, and reading
+	// the registry creates nothing
+	defaultPath, err := DefaultConfigPath()
+	if err != nil {
+		t.Fatalf("DefaultConfigPath() failed: %v", err)
+	}
+	if got, _ := VaultsConfigPath(""); got != defaultPath {
+		t.Errorf("VaultsConfigPath() = %q, want %q", got, defaultPath)
+	}
+	if _, err := LoadVaults(); err != nil {
+		t.Fatalf("LoadVaults() failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Dir(defaultPath)); !os.IsNotExist(err) {
+		t.Errorf("LoadVaults() created %s", filepath.Dir(defaultPath))
+	}
+
+	// The CLI's ~/.pass-cli/config.yaml wins when it exists
+	cliPath := filepath.Join(home, ".pass-cli", "config.yaml")
+	if err := AddVaultProfile(cliPath, "work", "/vaults/work.enc"); err != nil {
+		t.Fatalf("AddVaultProfile() failed: %v", err)
+	}
+	if got, _ := VaultsConfigPath(""); got != cliPath {
+		t.Errorf("VaultsConfigPath() = %q, want %q", got, cliPath)
+	}
+	vaults, err := LoadVaults()
+	if err != nil {
+		t.Fatalf("LoadVaults() failed: %v", err)
+	}
+	if path, err := vaults.Resolve("work"); err != nil || path != "/vaults/work.enc" {
+		t.Errorf("Resolve(work) = %q, %v", path, err)
+	}
+}
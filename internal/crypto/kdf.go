@@ -0,0 +1,153 @@
+package crypto
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"runtime"
+	"time"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// Key derivation algorithms recorded in vault metadata
+const (
+	KDFPBKDF2   = "pbkdf2-sha256"
+	KDFArgon2id = "argon2id"
+)
+
+const (
+	DefaultArgon2Memory  = 64 * 1024   // Argon2id memory in KiB for new vaults (RFC 9106 second recommended option)
+	DefaultArgon2Time    = 3           // Argon2id passes for new vaults
+	DefaultArgon2Threads = 4           // Argon2id parallelism for new vaults
+	MinArgon2Memory      = 19 * 1024   // Minimum memory in KiB (OWASP 2023)
+	MinArgon2Time        = 2           // Minimum passes (OWASP 2023)
+	MaxArgon2Memory      = 1024 * 1024 // Largest memory accepted, so a crafted vault file cannot exhaust RAM
+	MaxArgon2Time        = 64
+)
+
+var ErrInvalidKDFParams = errors.New("invalid key derivation parameters")
+
+// KDFParams selects the key derivation function and its cost. Iterations is
+// used by PBKDF2; Memory, Time and Threads by Argon2id.
+type KDFParams struct {
+	Algorithm  string
+	Iterations int    // PBKDF2 iteration count
+	Memory     uint32 // Argon2id memory in KiB
+	Time       uint32 // Argon2id passes over memory
+	Threads    uint8  // Argon2id parallelism
+}
+
+// DefaultKDFParams returns the parameters used for new vaults
+func DefaultKDFParams() KDFParams {
+	return KDFParams{
+		Algorithm: KDFArgon2id,
+		Memory:    DefaultArgon2Memory,
+		Time:      DefaultArgon2Time,
+		Threads:   DefaultArgon2Threads,
+	}
+}
+
+// PBKDF2Params returns PBKDF2-SHA256 parameters with the given iteration count
+func PBKDF2Params(iterations int) KDFParams {
+	return KDFParams{Algorithm: KDFPBKDF2, Iterations: iterations}
+}
+
+// String describes the parameters for display, e.g. "argon2id (m=64 MiB, t=3, p=4)"
+func (p KDFParams) String() string {
+	if p.Algorithm == KDFArgon2id {
+		return fmt.Sprintf("argon2id (m=%d MiB, t=%d, p=%d)", p.Memory/1024, p.Time, p.Threads)
+	}
+	return fmt.Sprintf("pbkdf2-sha256 (%d iterations)", p.Iterations)
+}
+
+// Validate checks that the parameters are strong enough for new vaults
+func (p KDFParams) Validate() error {
+	switch p.Algorithm {
+	case KDFArgon2id:
+		if p.Memory < MinArgon2Memory || p.Memory > MaxArgon2Memory {
+			return fmt.Errorf("%w: argon2id memory must be between %d and %d KiB", ErrInvalidKDFParams, MinArgon2Memory, MaxArgon2Memory)
+		}
+		if p.Time < MinArgon2Time || p.Time > MaxArgon2Time {
+			return fmt.Errorf("%w: argon2id passes must be between %d and %d", ErrInvalidKDFParams, MinArgon2Time, MaxArgon2Time)
+		}
+		if p.Threads == 0 {
+			return fmt.Errorf("%w: argon2id threads must be at least 1", ErrInvalidKDFParams)
+		}
+	case KDFPBKDF2:
+		if p.Iterations < MinIterations {
+			return fmt.Errorf("%w: iterations must be >= %d", ErrInvalidKDFParams, MinIterations)
+		}
+	default:
+		return fmt.Errorf("%w: unknown algorithm %q", ErrInvalidKDFParams, p.Algorithm)
+	}
+	return nil
+}
+
+// DeriveKeyWithParams derives an encryption key with the algorithm and cost in
+// params. Unlike Validate, it accepts the weaker settings of existing vaults
+// and only rejects parameters that cannot work or would exhaust memory.
+func (c *CryptoService) DeriveKeyWithParams(password []byte, salt []byte, params KDFParams) ([]byte, error) {
+	if len(salt) != SaltLength {
+		return nil, ErrInvalidSaltLength
+	}
+
+	switch params.Algorithm {
+	case KDFArgon2id:
+		if params.Memory == 0 || params.Memory > MaxArgon2Memory || params.Time == 0 || params.Time > MaxArgon2Time || params.Threads == 0 {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidKDFParams, params)
+		}
+		return argon2.IDKey(password, salt, params.Time, params.Memory, params.Threads, KeyLength), nil
+	case KDFPBKDF2, "":
+		if params.Iterations <= 0 {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidKDFParams, params)
+		}
+		return pbkdf2.Key(password, salt, params.Iterations, KeyLength, sha256.New), nil
+	}
+	return nil, fmt.Errorf("%w: unknown algorithm %q", ErrInvalidKDFParams, params.Algorithm)
+}
+
+// BenchmarkKDF picks Argon2id parameters that take about target to derive a
+// key on this machine. Spare time goes to memory first, since memory is what
+// makes GPU and ASIC attacks expensive, then to extra passes. On slow machines
+// memory is reduced, but never below the minimums.
+func (c *CryptoService) BenchmarkKDF(target time.Duration) (KDFParams, error) {
+	if target <= 0 {
+		return KDFParams{}, errors.New("benchmark target must be positive")
+	}
+
+	salt, err := c.GenerateSalt()
+	if err != nil {
+		return KDFParams{}, err
+	}
+	params := KDFParams{
+		Algorithm: KDFArgon2id,
+		Memory:    DefaultArgon2Memory,
+		Time:      MinArgon2Time,
+		Threads:   uint8(min(runtime.NumCPU(), DefaultArgon2Threads)), // #nosec G115 -- capped at DefaultArgon2Threads
+	}
+	measure := func() time.Duration {
+		start := time.Now()
+		key := argon2.IDKey([]byte("benchmark"), salt, params.Time, params.Memory, params.Threads, KeyLength)
+		elapsed := time.Since(start)
+		ClearBytes(key)
+		return elapsed
+	}
+
+	elapsed := measure()
+	for elapsed > target && params.Memory > MinArgon2Memory {
+		params.Memory = max(params.Memory/2, MinArgon2Memory)
+		elapsed = measure()
+	}
+	for elapsed*2 <= target && params.Memory*2 <= MaxArgon2Memory {
+		params.Memory *= 2
+		elapsed = measure()
+	}
+	if elapsed > 0 && elapsed < target {
+		// Passes scale the time linearly
+		scaled := uint64(params.Time) * uint64(target) / uint64(elapsed)
+		params.Time = uint32(min(scaled, MaxArgon2Time)) // #nosec G115 -- capped at MaxArgon2Time
+	}
+	return params, nil
+}
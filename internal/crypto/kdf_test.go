@@ -0,0 +1,144 @@
+package crypto
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDeriveKeyWithParams(t *testing.T) {
+	cs := NewCryptoService()
+	salt, err := cs.GenerateSalt()
+	if err != nil {
+		t.Fatalf("GenerateSalt failed: %v", err)
+	}
+	password := []byte("test-password")
+	argon := KDFParams{Algorithm: KDFArgon2id, Memory: MinArgon2Memory, Time: MinArgon2Time, Threads: 1}
+
+	key1, err := cs.DeriveKeyWithParams(password, salt, argon)
+	if err != nil {
+		t.Fatalf("DeriveKeyWithParams failed: %v", err)
+	}
+	if len(key1) != KeyLength {
+		t.Errorf("Expected key length %d, got %d", KeyLength, len(key1))
+	}
+	key2, err := cs.DeriveKeyWithParams(password, salt, argon)
+	if err != nil {
+		t.Fatalf("DeriveKeyWithParams failed: %v", err)
+	}
+	if !bytes.Equal(key1, key2) {
+		t.Error("Same password, salt and parameters should derive the same key")
+	}
+
+	// A different cost derives a different key
+	argon.Time++
+	key3, err := cs.DeriveKeyWithParams(password, salt, argon)
+	if err != nil {
+		t.Fatalf("DeriveKeyWithParams failed: %v", err)
+	}
+	if bytes.Equal(key1, key3) {
+		t.Error("Different Argon2id passes should derive a different key")
+	}
+
+	// PBKDF2 parameters match DeriveKey, and an empty algorithm means PBKDF2
+	legacy, err := cs.DeriveKey(password, salt, LegacyIterations)
+	if err != nil {
+		t.Fatalf("DeriveKey failed: %v", err)
+	}
+	for _, params := range []KDFParams{PBKDF2Params(LegacyIterations), {Iterations: LegacyIterations}} {
+		key, err := cs.DeriveKeyWithParams(password, salt, params)
+		if err != nil {
+			t.Fatalf("DeriveKeyWithParams(%+v) failed: %v", params, err)
+		}
+		if !bytes.Equal(key, legacy) {
+			t.Errorf("DeriveKeyWithParams(%+v) does not match DeriveKey", params)
+		}
+	}
+	if bytes.Equal(key1, legacy) {
+		t.Error("Argon2id and PBKDF2 should derive different keys")
+	}
+}
+
+func TestDeriveKeyWithParams_Invalid(t *testing.T) {
+	cs := NewCryptoService()
+	salt, err := cs.GenerateSalt()
+	if err != nil {
+		t.Fatalf("GenerateSalt failed: %v", err)
+	}
+
+	if _, err := cs.DeriveKeyWithParams([]byte("password"), salt[:16], DefaultKDFParams()); err != ErrInvalidSaltLength {
+		t.Errorf("Expected ErrInvalidSaltLength, got %v", err)
+	}
+
+	tests := []KDFParams{
+		{Algorithm: KDFArgon2id, Memory: MaxArgon2Memory + 1, Time: 1, Threads: 1},
+		{Algorithm: KDFArgon2id, Memory: MinArgon2Memory, Time: 0, Threads: 1},
+		{Algorithm: KDFArgon2id, Memory: MinArgon2Memory, Time: 1, Threads: 0},
+		{Algorithm: KDFPBKDF2, Iterations: 0},
+		{Algorithm: "scrypt"},
+	}
+	for _, params := range tests {
+		if _, err := cs.DeriveKeyWithParams([]byte("password"), salt, params); !errors.Is(err, ErrInvalidKDFParams) {
+			t.Errorf("DeriveKeyWithParams(%+v): expected ErrInvalidKDFParams, got %v", params, err)
+		}
+	}
+}
+
+func TestKDFParams_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		params KDFParams
+		valid  bool
+	}{
+		{"default", DefaultKDFParams(), true},
+		{"pbkdf2 minimum", PBKDF2Params(MinIterations), true},
+		{"pbkdf2 legacy", PBKDF2Params(LegacyIterations), false},
+		{"argon2id minimum", KDFParams{Algorithm: KDFArgon2id, Memory: MinArgon2Memory, Time: MinArgon2Time, Threads: 1}, true},
+		{"argon2id low memory", KDFParams{Algorithm: KDFArgon2id, Memory: MinArgon2Memory - 1, Time: 3, Threads: 1}, false},
+		{"argon2id huge memory", KDFParams{Algorithm: KDFArgon2id, Memory: MaxArgon2Memory * 2, Time: 3, Threads: 1}, false},
+		{"argon2id one pass", KDFParams{Algorithm: KDFArgon2id, Memory: DefaultArgon2Memory, Time: 1, Threads: 1}, false},
+		{"argon2id no threads", KDFParams{Algorithm: KDFArgon2id, Memory: DefaultArgon2Memory, Time: 3}, false},
+		{"unknown", KDFParams{Algorithm: "bcrypt"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.params.Validate()
+			if tt.valid && err != nil {
+				t.Errorf("Validate() = %v, want nil", err)
+			}
+			if !tt.valid && !errors.Is(err, ErrInvalidKDFParams) {
+				t.Errorf("Validate() = %v, want ErrInvalidKDFParams", err)
+			}
+		})
+	}
+}
+
+func TestKDFParams_String(t *testing.T) {
+	if got := DefaultKDFParams().String(); got != "argon2id (m=64 MiB, t=3, p=4)" {
+		t.Errorf("String() = %q", got)
+	}
+	if got := PBKDF2Params(600000).String(); got != "pbkdf2-sha256 (600000 iterations)" {
+		t.Errorf("String() = %q", got)
+	}
+}
+
+func TestBenchmarkKDF(t *testing.T) {
+	cs := NewCryptoService()
+
+	if _, err := cs.BenchmarkKDF(0); err == nil {
+		t.Error("Expected error for zero target")
+	}
+
+	params, err := cs.BenchmarkKDF(50 * time.Millisecond)
+	if err != nil {
+		t.Fatalf("BenchmarkKDF failed: %v", err)
+	}
+	if params.Algorithm != KDFArgon2id {
+		t.Errorf("Expected argon2id, got %s", params.Algorithm)
+	}
+	// Even a target faster than the minimums allow yields usable parameters
+	if err := params.Validate(); err != nil {
+		t.Errorf("Benchmarked parameters are invalid: %v", err)
+	}
+}
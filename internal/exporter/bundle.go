@@ -0,0 +1,108 @@
+package exporter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"pass-cli/internal/crypto"
+	"pass-cli/internal/vault"
+)
+
+const (
+	bundleFormatName = "pass-cli-bundle"
+	bundleVersion    = 1
+)
+
+// ErrNotBundle is returned when a file is not a pass-cli bundle
+var ErrNotBundle = errors.New("not a pass-cli bundle")
+
+// bundleFile is the on-disk envelope. Only the key derivation parameters and
+// the creation time are readable without the passphrase.
+type bundleFile struct {
+	Format     string    `json:"format"`
+	Version    int       `json:"version"`
+	CreatedAt  time.Time `json:"created_at"`
+	Salt       []byte    `json:"salt"`
+	Iterations int       `json:"iterations"`
+	Data       []byte    `json:"data"` // AES-GCM encrypted bundlePayload
+}
+
+type bundlePayload struct {
+	Credentials []vault.Credential `json:"credentials"`
+}
+
+// WriteBundle encrypts credentials, including password history, usage, and
+// attachments, with a key derived from passphrase
+func WriteBundle(w io.Writer, credentials []vault.Credential, passphrase []byte) error {
+	if len(passphrase) == 0 {
+		return fmt.Errorf("a passphrase is required to write a bundle")
+	}
+
+	plaintext, err := json.Marshal(bundlePayload{Credentials: credentials})
+	if err != nil {
+		return fmt.Errorf("failed to encode bundle: %w", err)
+	}
+	defer crypto.ClearBytes(plaintext)
+
+	cryptoService := crypto.NewCryptoService()
+	salt, err := cryptoService.GenerateSalt()
+	if err != nil {
+		return err
+	}
+	iterations := crypto.GetIterations()
+	key, err := cryptoService.DeriveKey(passphrase, salt, iterations)
+	if err != nil {
+		return err
+	}
+	defer cryptoService.ClearKey(key)
+
+	data, err := cryptoService.Encrypt(plaintext, key)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt bundle: %w", err)
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(bundleFile{
+		Format:     bundleFormatName,
+		Version:    bundleVersion,
+		CreatedAt:  time.Now().UTC(),
+		Salt:       salt,
+		Iterations: iterations,
+		Data:       data,
+	})
+}
+
+// ReadBundle decrypts a bundle written by WriteBundle. A wrong passphrase
+// returns crypto.ErrDecryptionFailed.
+func ReadBundle(r io.Reader, passphrase []byte) ([]vault.Credential, error) {
+	var file bundleFile
+	if err := json.NewDecoder(r).Decode(&file); err != nil || file.Format != bundleFormatName || file.Iterations <= 0 {
+		return nil, ErrNotBundle
+	}
+	if file.Version > bundleVersion {
+		return nil, fmt.Errorf("bundle version %d is newer than this pass-cli supports", file.Version)
+	}
+
+	cryptoService := crypto.NewCryptoService()
+	key, err := cryptoService.DeriveKey(passphrase, file.Salt, file.Iterations)
+	if err != nil {
+		return nil, err
+	}
+	defer cryptoService.ClearKey(key)
+
+	plaintext, err := cryptoService.Decrypt(file.Data, key)
+	if err != nil {
+		return nil, err
+	}
+	defer crypto.ClearBytes(plaintext)
+
+	var payload bundlePayload
+	if err := json.Unmarshal(plaintext, &payload); err != nil {
+		return nil, fmt.Errorf("failed to decode bundle: %w", err)
+	}
+	return payload.Credentials, nil
+}
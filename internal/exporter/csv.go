@@ -0,0 +1,72 @@
+package exporter
+
+import (
+	"encoding/csv"
+	"io"
+	"sort"
+	"strings"
+
+	"pass-cli/internal/vault"
+)
+
+// csvColumns are the fixed leading columns of a CSV export
+var csvColumns = []string{"service", "username", "password", "type", "category", "tags", "url", "notes", "totp"}
+
+// csvFieldPrefix marks custom field columns ("field:region")
+const csvFieldPrefix = "field:"
+
+// WriteCSV writes one row per credential. Tags are separated by semicolons,
+// TOTP is written as an otpauth:// URI, and every custom field key gets its own
+// "field:<key>" column. Password history and attachments are not included.
+func WriteCSV(w io.Writer, credentials []vault.Credential) error {
+	fieldKeys := customFieldKeys(credentials)
+
+	writer := csv.NewWriter(w)
+	header := append([]string(nil), csvColumns...)
+	for _, key := range fieldKeys {
+		header = append(header, csvFieldPrefix+key)
+	}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	for _, credential := range credentials {
+		row := []string{
+			credential.Service,
+			credential.Username,
+			string(credential.Password),
+			string(credential.Type),
+			credential.Category,
+			strings.Join(credential.Tags, ";"),
+			credential.URL,
+			credential.Notes,
+			totpURI(credential),
+		}
+		for _, key := range fieldKeys {
+			field, _ := vault.FindCustomField(credential.CustomFields, key)
+			row = append(row, field.Value)
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// customFieldKeys returns the sorted union of custom field keys (keys are case-insensitive)
+func customFieldKeys(credentials []vault.Credential) []string {
+	seen := make(map[string]bool)
+	var keys []string
+	for _, credential := range credentials {
+		for _, field := range credential.CustomFields {
+			if lower := strings.ToLower(field.Key); !seen[lower] {
+				seen[lower] = true
+				keys = append(keys, field.Key)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
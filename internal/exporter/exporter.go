@@ -0,0 +1,79 @@
+// Package exporter writes vault credentials to files other tools, or another
+// pass-cli vault, can read.
+package exporter
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"pass-cli/internal/kdbx"
+	"pass-cli/internal/vault"
+)
+
+// Format identifies an export format
+type Format string
+
+const (
+	FormatCSV    Format = "csv"    // Plaintext
+	FormatJSON   Format = "json"   // Plaintext
+	FormatBundle Format = "bundle" // Encrypted with a separate passphrase
+	FormatKDBX   Format = "kdbx"   // KeePass KDBX 4 database with its own password
+)
+
+// Formats lists the supported formats in display order
+var Formats = []Format{FormatCSV, FormatJSON, FormatBundle, FormatKDBX}
+
+// ErrUnsupportedFormat is returned for unknown format names
+var ErrUnsupportedFormat = errors.New("unsupported export format")
+
+// Options configures the formats that need more than the credentials
+type Options struct {
+	Passphrase []byte            // Encryption passphrase for bundles, database password for KDBX
+	KDBX       kdbx.WriteOptions // Cipher and key derivation for KDBX
+}
+
+// Plaintext reports whether the format writes passwords unencrypted
+func (f Format) Plaintext() bool {
+	return f != FormatBundle && f != FormatKDBX
+}
+
+// Write encodes credentials in the given format
+func Write(format Format, w io.Writer, credentials []vault.Credential, opts Options) error {
+	switch format {
+	case FormatCSV:
+		return WriteCSV(w, credentials)
+	case FormatJSON:
+		return WriteJSON(w, credentials)
+	case FormatBundle:
+		return WriteBundle(w, credentials, opts.Passphrase)
+	case FormatKDBX:
+		return WriteKDBX(w, credentials, opts.Passphrase, opts.KDBX)
+	}
+	return fmt.Errorf("%w: %s", ErrUnsupportedFormat, format)
+}
+
+// ParseFormat validates a format name
+func ParseFormat(value string) (Format, error) {
+	format := Format(strings.ToLower(strings.TrimSpace(value)))
+	for _, f := range Formats {
+		if f == format {
+			return f, nil
+		}
+	}
+
+	names := make([]string, len(Formats))
+	for i, f := range Formats {
+		names[i] = string(f)
+	}
+	return "", fmt.Errorf("%w %q (valid: %s)", ErrUnsupportedFormat, value, strings.Join(names, ", "))
+}
+
+// totpURI renders a credential's TOTP configuration, if any, as an otpauth:// URI
+func totpURI(credential vault.Credential) string {
+	if credential.TOTP == nil {
+		return ""
+	}
+	return credential.TOTP.URI()
+}
@@ -0,0 +1,182 @@
+package exporter
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"pass-cli/internal/crypto"
+	"pass-cli/internal/kdbx"
+	"pass-cli/internal/vault"
+)
+
+func testCredentials(t *testing.T) []vault.Credential {
+	t.Helper()
+	totp, err := vault.ParseTOTP("JBSWY3DPEHPK3PXP")
+	if err != nil {
+		t.Fatalf("ParseTOTP failed: %v", err)
+	}
+	now := time.Date(2025, 1, 14, 9, 30, 0, 0, time.UTC)
+	return []vault.Credential{
+		{
+			Service: "aws", Username: "admin", Password: []byte("s3cret,\"quoted\""),
+			Category: "Cloud", Tags: []string{"prod", "infra"}, URL: "https://aws.amazon.com",
+			Notes:           "line 1\nline 2",
+			CustomFields:    []vault.CustomField{{Key: "region", Value: "us-east-1"}, {Key: "key_id", Value: "AKIA", Secret: true}},
+			TOTP:            totp,
+			PasswordHistory: []vault.PasswordHistoryEntry{{Password: []byte("old"), ChangedAt: now}},
+			CreatedAt:       now, UpdatedAt: now, PasswordChangedAt: now,
+		},
+		{Service: "wifi", Password: []byte("pw"), Type: vault.TypeSecureNote, CustomFields: []vault.CustomField{{Key: "Region", Value: "eu"}}},
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, testCredentials(t)); err != nil {
+		t.Fatalf("WriteCSV failed: %v", err)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("output is not valid CSV: %v", err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("Expected header and 2 rows, got %d", len(records))
+	}
+
+	header := strings.Join(records[0], ",")
+	if header != "service,username,password,type,category,tags,url,notes,totp,field:key_id,field:region" {
+		t.Errorf("header = %s", header)
+	}
+	aws := records[1]
+	if aws[2] != "s3cret,\"quoted\"" || aws[5] != "prod;infra" || aws[7] != "line 1\nline 2" {
+		t.Errorf("aws row = %q", aws)
+	}
+	if !strings.HasPrefix(aws[8], "otpauth://totp/") || aws[9] != "AKIA" || aws[10] != "us-east-1" {
+		t.Errorf("aws totp/fields = %q", aws[8:])
+	}
+	// Field keys are case-insensitive, so "Region" shares the region column
+	if wifi := records[2]; wifi[3] != "secure-note" || wifi[10] != "eu" {
+		t.Errorf("wifi row = %q", wifi)
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteJSON(&buf, testCredentials(t)); err != nil {
+		t.Fatalf("WriteJSON failed: %v", err)
+	}
+
+	var export jsonExport
+	if err := json.Unmarshal(buf.Bytes(), &export); err != nil {
+		t.Fatalf("output is not valid JSON: %v", err)
+	}
+	if export.Version != jsonExportVersion || len(export.Credentials) != 2 {
+		t.Fatalf("export = %+v", export)
+	}
+	aws := export.Credentials[0]
+	if aws.Password != "s3cret,\"quoted\"" || aws.Category != "Cloud" || len(aws.CustomFields) != 2 {
+		t.Errorf("aws = %+v", aws)
+	}
+	if _, err := vault.ParseTOTP(aws.TOTP); err != nil {
+		t.Errorf("totp %q does not parse: %v", aws.TOTP, err)
+	}
+	if strings.Contains(buf.String(), "password_history") {
+		t.Error("JSON export must not include password history")
+	}
+}
+
+func TestBundleRoundTrip(t *testing.T) {
+	t.Setenv("PASS_CLI_ITERATIONS", "")
+	passphrase := []byte("Bundle-Passphrase-1")
+
+	var buf bytes.Buffer
+	if err := WriteBundle(&buf, testCredentials(t), passphrase); err != nil {
+		t.Fatalf("WriteBundle failed: %v", err)
+	}
+	if strings.Contains(buf.String(), "s3cret") || strings.Contains(buf.String(), "aws") {
+		t.Fatal("bundle contains plaintext")
+	}
+	encoded := buf.Bytes()
+
+	credentials, err := ReadBundle(bytes.NewReader(encoded), passphrase)
+	if err != nil {
+		t.Fatalf("ReadBundle failed: %v", err)
+	}
+	if len(credentials) != 2 || string(credentials[0].Password) != "s3cret,\"quoted\"" {
+		t.Fatalf("credentials = %+v", credentials)
+	}
+	if len(credentials[0].PasswordHistory) != 1 || credentials[0].TOTP == nil {
+		t.Errorf("bundle lost history or TOTP: %+v", credentials[0])
+	}
+
+	if _, err := ReadBundle(bytes.NewReader(encoded), []byte("wrong")); !errors.Is(err, crypto.ErrDecryptionFailed) {
+		t.Errorf("ReadBundle with wrong passphrase = %v, want ErrDecryptionFailed", err)
+	}
+	if _, err := ReadBundle(strings.NewReader(`{"credentials": []}`), passphrase); !errors.Is(err, ErrNotBundle) {
+		t.Errorf("ReadBundle on plain JSON = %v, want ErrNotBundle", err)
+	}
+	if err := WriteBundle(&buf, nil, nil); err == nil {
+		t.Error("Expected an error without a passphrase")
+	}
+}
+
+func TestWriteKDBX(t *testing.T) {
+	password := []byte("keepass-password")
+	credentials := testCredentials(t)
+	credentials[0].Category = "Cloud/AWS"
+	credentials[0].CustomFields = append(credentials[0].CustomFields, vault.CustomField{Key: "Title", Value: "clash"})
+
+	var buf bytes.Buffer
+	opts := kdbx.WriteOptions{KDF: kdbx.KDFArgon2id, Iterations: 1, Memory: 1024 * 1024, Parallelism: 1}
+	if err := WriteKDBX(&buf, credentials, password, opts); err != nil {
+		t.Fatalf("WriteKDBX failed: %v", err)
+	}
+
+	db, err := kdbx.Read(&buf, password)
+	if err != nil {
+		t.Fatalf("kdbx.Read failed: %v", err)
+	}
+	if len(db.Root.Entries) != 1 || db.Root.Entries[0].Get(kdbx.FieldTitle) != "wifi" {
+		t.Errorf("root entries = %+v", db.Root.Entries)
+	}
+	if len(db.Root.Groups) != 1 || db.Root.Groups[0].Name != "Cloud" || len(db.Root.Groups[0].Groups) != 1 {
+		t.Fatalf("groups = %+v", db.Root.Groups)
+	}
+
+	aws := db.Root.Groups[0].Groups[0].Entries[0]
+	if aws.Get(kdbx.FieldPassword) != "s3cret,\"quoted\"" || aws.Get(kdbx.FieldUserName) != "admin" {
+		t.Errorf("aws fields = %+v", aws.Fields)
+	}
+	if !strings.HasPrefix(aws.Get("otp"), "otpauth://totp/") || aws.Get("region") != "us-east-1" || aws.Get("Title-2") != "clash" {
+		t.Errorf("aws custom fields = %+v", aws.Fields)
+	}
+	for _, field := range aws.Fields {
+		if field.Key == "key_id" && !field.Protected {
+			t.Error("secret custom field must be protected")
+		}
+	}
+	if len(aws.History) != 1 || aws.History[0].Get(kdbx.FieldPassword) != "old" {
+		t.Errorf("history = %+v", aws.History)
+	}
+	if len(aws.Tags) != 2 {
+		t.Errorf("tags = %v", aws.Tags)
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	if format, err := ParseFormat("CSV"); err != nil || format != FormatCSV || !format.Plaintext() {
+		t.Errorf("ParseFormat = %q, %v", format, err)
+	}
+	if FormatBundle.Plaintext() || FormatKDBX.Plaintext() {
+		t.Error("bundle and kdbx must not be plaintext")
+	}
+	if _, err := ParseFormat("xml"); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Errorf("ParseFormat(xml) = %v", err)
+	}
+}
@@ -0,0 +1,70 @@
+package exporter
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+
+	"pass-cli/internal/vault"
+)
+
+// jsonExportVersion is the version of the plaintext JSON document
+const jsonExportVersion = 1
+
+type jsonExport struct {
+	Version     int              `json:"version"`
+	ExportedAt  time.Time        `json:"exported_at"`
+	Credentials []jsonCredential `json:"credentials"`
+}
+
+// jsonCredential is the readable form of a credential: the password is a
+// string and TOTP an otpauth:// URI. History and attachments are left out.
+type jsonCredential struct {
+	Service           string               `json:"service"`
+	Username          string               `json:"username"`
+	Password          string               `json:"password"`
+	Type              vault.CredentialType `json:"type,omitempty"`
+	Category          string               `json:"category,omitempty"`
+	Tags              []string             `json:"tags,omitempty"`
+	URL               string               `json:"url,omitempty"`
+	Notes             string               `json:"notes,omitempty"`
+	CustomFields      []vault.CustomField  `json:"custom_fields,omitempty"`
+	TOTP              string               `json:"totp,omitempty"`
+	ExpiresAt         *time.Time           `json:"expires_at,omitempty"`
+	RotationDays      int                  `json:"rotation_days,omitempty"`
+	PasswordChangedAt time.Time            `json:"password_changed_at"`
+	CreatedAt         time.Time            `json:"created_at"`
+	UpdatedAt         time.Time            `json:"updated_at"`
+}
+
+// WriteJSON writes credentials as an indented JSON document
+func WriteJSON(w io.Writer, credentials []vault.Credential) error {
+	export := jsonExport{
+		Version:     jsonExportVersion,
+		ExportedAt:  time.Now().UTC(),
+		Credentials: make([]jsonCredential, 0, len(credentials)),
+	}
+	for _, credential := range credentials {
+		export.Credentials = append(export.Credentials, jsonCredential{
+			Service:           credential.Service,
+			Username:          credential.Username,
+			Password:          string(credential.Password),
+			Type:              credential.Type,
+			Category:          credential.Category,
+			Tags:              credential.Tags,
+			URL:               credential.URL,
+			Notes:             credential.Notes,
+			CustomFields:      credential.CustomFields,
+			TOTP:              totpURI(credential),
+			ExpiresAt:         credential.ExpiresAt,
+			RotationDays:      credential.RotationDays,
+			PasswordChangedAt: credential.PasswordChangedAt,
+			CreatedAt:         credential.CreatedAt,
+			UpdatedAt:         credential.UpdatedAt,
+		})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(export)
+}
@@ -0,0 +1,107 @@
+package exporter
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"pass-cli/internal/kdbx"
+	"pass-cli/internal/vault"
+)
+
+const (
+	kdbxDatabaseName = "pass-cli"
+	kdbxOTPField     = "otp" // Field KeePassXC reads otpauth:// URIs from
+)
+
+// WriteKDBX writes credentials as a KeePass KDBX 4 database encrypted with
+// password. Categories become nested groups ("work/email"), the password
+// history becomes entry history, and TOTP is stored as an otpauth:// URI in the
+// "otp" field. Credential types, usage, and attachments are not included.
+func WriteKDBX(w io.Writer, credentials []vault.Credential, password []byte, opts kdbx.WriteOptions) error {
+	if len(password) == 0 {
+		return fmt.Errorf("a password is required to write a KeePass database")
+	}
+
+	db := &kdbx.Database{Name: kdbxDatabaseName, Root: kdbx.Group{Name: kdbxDatabaseName}}
+	for _, credential := range credentials {
+		group := kdbxGroup(&db.Root, credential.Category)
+		group.Entries = append(group.Entries, kdbxEntry(credential))
+	}
+	return kdbx.Write(w, db, password, opts)
+}
+
+// kdbxGroup returns the group for a category path, creating missing groups
+func kdbxGroup(root *kdbx.Group, category string) *kdbx.Group {
+	group := root
+	for _, name := range strings.Split(category, "/") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		index := -1
+		for i := range group.Groups {
+			if group.Groups[i].Name == name {
+				index = i
+				break
+			}
+		}
+		if index < 0 {
+			group.Groups = append(group.Groups, kdbx.Group{Name: name})
+			index = len(group.Groups) - 1
+		}
+		group = &group.Groups[index]
+	}
+	return group
+}
+
+// kdbxEntry maps a credential onto a KeePass entry
+func kdbxEntry(credential vault.Credential) kdbx.Entry {
+	entry := kdbx.Entry{
+		Fields:     kdbxStandardFields(credential, credential.Password),
+		Tags:       credential.Tags,
+		CreatedAt:  credential.CreatedAt,
+		ModifiedAt: credential.UpdatedAt,
+		ExpiresAt:  credential.ExpiresAt,
+	}
+
+	// KeePass reserves the standard field names (case-sensitively) and "otp"
+	used := map[string]bool{kdbxOTPField: true}
+	for _, field := range entry.Fields {
+		used[strings.ToLower(field.Key)] = true
+	}
+	if uri := totpURI(credential); uri != "" {
+		entry.Fields = append(entry.Fields, kdbx.Field{Key: kdbxOTPField, Value: uri, Protected: true})
+	}
+	for _, field := range credential.CustomFields {
+		key := field.Key
+		for n := 2; used[strings.ToLower(key)]; n++ {
+			key = fmt.Sprintf("%s-%d", field.Key, n)
+		}
+		used[strings.ToLower(key)] = true
+		entry.Fields = append(entry.Fields, kdbx.Field{Key: key, Value: field.Value, Protected: field.Secret})
+	}
+
+	// pass-cli keeps history newest first; KeePass lists it oldest first
+	for i := len(credential.PasswordHistory) - 1; i >= 0; i-- {
+		previous := credential.PasswordHistory[i]
+		entry.History = append(entry.History, kdbx.Entry{
+			Fields:     kdbxStandardFields(credential, previous.Password),
+			CreatedAt:  credential.CreatedAt,
+			ModifiedAt: previous.ChangedAt,
+		})
+	}
+	return entry
+}
+
+// kdbxStandardFields returns the built-in KeePass fields with the given password
+func kdbxStandardFields(credential vault.Credential, password []byte) []kdbx.Field {
+	return []kdbx.Field{
+		{Key: kdbx.FieldTitle, Value: credential.Service},
+		{Key: kdbx.FieldUserName, Value: credential.Username},
+		{Key: kdbx.FieldPassword, Value: string(password), Protected: true},
+		{Key: kdbx.FieldURL, Value: credential.URL},
+		{Key: kdbx.FieldNotes, Value: credential.Notes},
+	}
+}
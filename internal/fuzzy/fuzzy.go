@@ -0,0 +1,173 @@
+// Package fuzzy scores fzf-style fuzzy matches. A pattern matches when its
+// characters appear in the text in order, ignoring case; matches score higher
+// when the characters are consecutive, start words, or start the text, and
+// lower when they are spread out.
+package fuzzy
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	"pass-cli/internal/vault"
+)
+
+// Scoring follows fzf's defaults
+const (
+	scoreMatch        = 16
+	scoreGapStart     = -3
+	scoreGapExtension = -1
+	bonusBoundary     = 8 // First character of a word
+	bonusCamel        = 7 // Lower-to-upper case or letter-to-digit change
+	bonusConsecutive  = 4 // Character right after the previous match
+	bonusFirstChar    = 2 // Multiplier for the boundary bonus of the pattern's first character
+)
+
+// noMatch marks alignments that are impossible; far from overflowing when penalties are added
+const noMatch = math.MinInt32
+
+// Score reports whether pattern fuzzily matches text and how well. Higher
+// scores are better; an empty pattern matches everything with score 0.
+func Score(pattern, text string) (int, bool) {
+	p := lowerRunes(pattern)
+	if len(p) == 0 {
+		return 0, true
+	}
+	original := []rune(text)
+	t := lowerRunes(text)
+	if len(p) > len(t) {
+		return 0, false
+	}
+
+	bonus := make([]int, len(t))
+	for j := range t {
+		bonus[j] = positionBonus(original, j)
+	}
+
+	// prev[j] is the best score with the previous pattern character matched at text[j]
+	prev := make([]int, len(t))
+	cur := make([]int, len(t))
+	for j := range t {
+		prev[j] = noMatch
+		if t[j] == p[0] {
+			prev[j] = scoreMatch + bonus[j]*bonusFirstChar
+		}
+	}
+
+	for i := 1; i < len(p); i++ {
+		gap := noMatch // Best prev[k] for k < j-1, less the gap penalty up to j
+		for j := range t {
+			cur[j] = noMatch
+			if j >= 2 {
+				gap = max(gap+scoreGapExtension, prev[j-2]+scoreGapStart)
+			}
+			if j == 0 || t[j] != p[i] {
+				continue
+			}
+			if prev[j-1] > noMatch {
+				cur[j] = prev[j-1] + scoreMatch + max(bonus[j], bonusConsecutive)
+			}
+			if gap > noMatch {
+				cur[j] = max(cur[j], gap+scoreMatch+bonus[j])
+			}
+		}
+		prev, cur = cur, prev
+	}
+
+	best := noMatch
+	for _, score := range prev {
+		best = max(best, score)
+	}
+	if best == noMatch {
+		return 0, false
+	}
+	return best, true
+}
+
+// lowerRunes lowercases s rune by rune so indexes line up with []rune(s)
+func lowerRunes(s string) []rune {
+	runes := []rune(s)
+	for i, r := range runes {
+		runes[i] = unicode.ToLower(r)
+	}
+	return runes
+}
+
+// positionBonus rewards matches at word starts and case or digit changes
+func positionBonus(text []rune, j int) int {
+	if j == 0 {
+		return bonusBoundary
+	}
+	prev, cur := text[j-1], text[j]
+	switch {
+	case !isWordRune(prev) && isWordRune(cur):
+		return bonusBoundary
+	case unicode.IsLower(prev) && unicode.IsUpper(cur):
+		return bonusCamel
+	case !unicode.IsDigit(prev) && unicode.IsDigit(cur):
+		return bonusCamel
+	}
+	return 0
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// ScoreCredential scores a credential against a pattern of space-separated
+// terms. Every term must match the service, username or URL; each term
+// contributes its best score among them.
+func ScoreCredential(pattern string, cred *vault.CredentialMetadata) (int, bool) {
+	texts := []string{cred.Service, cred.Username, trimScheme(cred.URL)}
+	total := 0
+	for _, term := range strings.Fields(pattern) {
+		best, found := 0, false
+		for _, text := range texts {
+			if score, ok := Score(term, text); ok && (!found || score > best) {
+				best, found = score, true
+			}
+		}
+		if !found {
+			return 0, false
+		}
+		total += best
+	}
+	return total, true
+}
+
+// trimScheme drops "https://" and similar so every URL doesn't match "htp"
+func trimScheme(rawURL string) string {
+	if _, rest, ok := strings.Cut(rawURL, "://"); ok {
+		return rest
+	}
+	return rawURL
+}
+
+// Match is a credential and its fuzzy score
+type Match struct {
+	Credential vault.CredentialMetadata
+	Score      int
+}
+
+// Rank returns the credentials matching pattern, best first. Ties go to the
+// shorter service name, then alphabetical order.
+func Rank(pattern string, creds []vault.CredentialMetadata) []Match {
+	var matches []Match
+	for i := range creds {
+		if score, ok := ScoreCredential(pattern, &creds[i]); ok {
+			matches = append(matches, Match{Credential: creds[i], Score: score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if len(a.Credential.Service) != len(b.Credential.Service) {
+			return len(a.Credential.Service) < len(b.Credential.Service)
+		}
+		return a.Credential.Service < b.Credential.Service
+	})
+	return matches
+}
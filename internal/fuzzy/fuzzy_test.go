@@ -0,0 +1,108 @@
+package fuzzy
+
+import (
+	"testing"
+
+	"pass-cli/internal/vault"
+)
+
+func TestScore(t *testing.T) {
+	tests := []struct {
+		pattern, text string
+		want          bool
+	}{
+		{"", "github", true},
+		{"gthub", "github", true},
+		{"GTHUB", "GitHub", true},
+		{"ghb", "github", true},
+		{"hubgit", "github", false},
+		{"githubs", "github", false},
+		{"prd", "aws-prod", true},
+		{"grün", "Grünkohl", true},
+	}
+	for _, tt := range tests {
+		if _, got := Score(tt.pattern, tt.text); got != tt.want {
+			t.Errorf("Score(%q, %q) matched = %v, want %v", tt.pattern, tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestScore_Ordering(t *testing.T) {
+	tests := []struct {
+		pattern, better, worse string
+	}{
+		{"git", "github", "gaming-it"},         // Consecutive beats scattered
+		{"hub", "git-hub", "githbub"},          // Word start beats mid-word
+		{"db", "prod-db", "dashboard"},         // Word start beats a gap
+		{"pd", "ProdDb", "updated"},            // Camel case counts as a word start
+		{"api", "api-gateway", "legacy-rapid"}, // Start of text wins
+	}
+	for _, tt := range tests {
+		better, ok := Score(tt.pattern, tt.better)
+		if !ok {
+			t.Fatalf("Score(%q, %q) did not match", tt.pattern, tt.better)
+		}
+		worse, ok := Score(tt.pattern, tt.worse)
+		if !ok {
+			t.Fatalf("Score(%q, %q) did not match", tt.pattern, tt.worse)
+		}
+		if better <= worse {
+			t.Errorf("Score(%q): %q = %d, want more than %q = %d", tt.pattern, tt.better, better, tt.worse, worse)
+		}
+	}
+}
+
+func TestScoreCredential(t *testing.T) {
+	cred := &vault.CredentialMetadata{Service: "aws-prod", Username: "deploy-bot", URL: "https://console.aws.amazon.com"}
+
+	tests := []struct {
+		pattern string
+		want    bool
+	}{
+		{"aws prod", true},
+		{"prod aws", true},
+		{"awsprd", true},
+		{"deploy", true},
+		{"console", true},
+		{"aws staging", false},
+		{"https", false}, // The URL scheme is not searched
+		{"", true},
+	}
+	for _, tt := range tests {
+		if _, got := ScoreCredential(tt.pattern, cred); got != tt.want {
+			t.Errorf("ScoreCredential(%q) matched = %v, want %v", tt.pattern, got, tt.want)
+		}
+	}
+}
+
+func TestRank(t *testing.T) {
+	creds := []vault.CredentialMetadata{
+		{Service: "github-enterprise"},
+		{Service: "gitlab"},
+		{Service: "github"},
+		{Service: "aws-prod"},
+		{Service: "aws-dev"},
+	}
+
+	services := func(matches []Match) []string {
+		var names []string
+		for _, match := range matches {
+			names = append(names, match.Credential.Service)
+		}
+		return names
+	}
+
+	got := services(Rank("gthub", creds))
+	if len(got) != 2 || got[0] != "github" || got[1] != "github-enterprise" {
+		t.Errorf("Rank(gthub) = %v, want [github github-enterprise]", got)
+	}
+
+	got = services(Rank("aws prod", creds))
+	if len(got) != 1 || got[0] != "aws-prod" {
+		t.Errorf("Rank(aws prod) = %v, want [aws-prod]", got)
+	}
+
+	if got := Rank("docker", creds); len(got) != 0 {
+		t.Errorf("Rank(docker) = %v, want no matches", services(got))
+	}
+}
@@ -0,0 +1,151 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"pass-cli/internal/vault"
+)
+
+// Bitwarden item types
+const (
+	bitwardenLogin      = 1
+	bitwardenSecureNote = 2
+	bitwardenCard       = 3
+	bitwardenIdentity   = 4
+)
+
+// Bitwarden custom field types
+const (
+	bitwardenFieldHidden = 1
+	bitwardenFieldLinked = 3
+)
+
+type bitwardenExport struct {
+	Encrypted bool              `json:"encrypted"`
+	Folders   []bitwardenFolder `json:"folders"`
+	Items     []bitwardenItem   `json:"items"`
+}
+
+type bitwardenFolder struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type bitwardenItem struct {
+	Type     int                    `json:"type"`
+	Name     string                 `json:"name"`
+	Notes    string                 `json:"notes"`
+	FolderID string                 `json:"folderId"`
+	Fields   []bitwardenField       `json:"fields"`
+	Login    *bitwardenLoginData    `json:"login"`
+	Card     map[string]interface{} `json:"card"`
+	Identity map[string]interface{} `json:"identity"`
+}
+
+type bitwardenField struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+	Type  int    `json:"type"`
+}
+
+type bitwardenLoginData struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	TOTP     string `json:"totp"`
+	URIs     []struct {
+		URI string `json:"uri"`
+	} `json:"uris"`
+}
+
+// bitwardenCardSecrets are card fields stored as secret custom fields
+var bitwardenCardSecrets = map[string]bool{"number": true, "code": true}
+
+// ReadBitwardenJSON parses an unencrypted Bitwarden JSON export.
+// Folders become categories, the first URI the URL, and further URIs, custom
+// fields, and card or identity details become custom fields.
+func ReadBitwardenJSON(r io.Reader) ([]vault.ImportEntry, error) {
+	var export bitwardenExport
+	if err := json.NewDecoder(r).Decode(&export); err != nil {
+		return nil, fmt.Errorf("failed to parse Bitwarden export: %w", err)
+	}
+	if export.Encrypted {
+		return nil, fmt.Errorf("encrypted Bitwarden exports are not supported: export as unencrypted JSON")
+	}
+
+	folders := make(map[string]string, len(export.Folders))
+	for _, folder := range export.Folders {
+		folders[folder.ID] = folder.Name
+	}
+
+	entries := make([]vault.ImportEntry, 0, len(export.Items))
+	for _, item := range export.Items {
+		entry := vault.ImportEntry{
+			Service:  item.Name,
+			Category: folders[item.FolderID],
+			Notes:    item.Notes,
+		}
+
+		switch item.Type {
+		case bitwardenLogin:
+			if item.Login != nil {
+				entry.Username = item.Login.Username
+				entry.Password = []byte(item.Login.Password)
+				applyTOTP(&entry, item.Login.TOTP)
+				for i, uri := range item.Login.URIs {
+					if i == 0 {
+						entry.URL = uri.URI
+						continue
+					}
+					entry.CustomFields = append(entry.CustomFields, vault.CustomField{Key: fmt.Sprintf("uri%d", i+1), Value: uri.URI})
+				}
+			}
+		case bitwardenSecureNote:
+			entry.Type = vault.TypeSecureNote
+		case bitwardenCard:
+			entry.Type = vault.TypeSecureNote
+			entry.CustomFields = append(entry.CustomFields, bitwardenDetails("card", item.Card, bitwardenCardSecrets)...)
+		case bitwardenIdentity:
+			entry.Type = vault.TypeSecureNote
+			entry.CustomFields = append(entry.CustomFields, bitwardenDetails("identity", item.Identity, nil)...)
+		}
+
+		for _, field := range item.Fields {
+			if field.Type == bitwardenFieldLinked {
+				continue // Links to another field; no value of its own
+			}
+			entry.CustomFields = append(entry.CustomFields, vault.CustomField{
+				Key:    field.Name,
+				Value:  field.Value,
+				Secret: field.Type == bitwardenFieldHidden,
+			})
+		}
+
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// bitwardenDetails turns card or identity details into prefixed custom fields in a stable order
+func bitwardenDetails(prefix string, details map[string]interface{}, secrets map[string]bool) []vault.CustomField {
+	keys := make([]string, 0, len(details))
+	for key, value := range details {
+		if s, ok := value.(string); ok && strings.TrimSpace(s) != "" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	fields := make([]vault.CustomField, 0, len(keys))
+	for _, key := range keys {
+		fields = append(fields, vault.CustomField{
+			Key:    prefix + "_" + strings.ToLower(key),
+			Value:  details[key].(string),
+			Secret: secrets[strings.ToLower(key)],
+		})
+	}
+	return fields
+}
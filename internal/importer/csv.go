@@ -0,0 +1,137 @@
+package importer
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"pass-cli/internal/vault"
+)
+
+// lastPassNoteURL marks secure notes in LastPass exports
+const lastPassNoteURL = "http://sn"
+
+// ReadOnePasswordCSV parses a 1Password CSV export (1Password 7 and 8 column names).
+// Columns without a built-in equivalent become custom fields.
+func ReadOnePasswordCSV(r io.Reader) ([]vault.ImportEntry, error) {
+	rows, err := csvRows(r)
+	if err != nil {
+		return nil, err
+	}
+	if !hasColumn(rows, "title") {
+		return nil, fmt.Errorf("not a 1Password CSV export: missing Title column")
+	}
+
+	known := map[string]bool{
+		"title": true, "url": true, "website": true, "username": true, "password": true,
+		"otpauth": true, "one-time password": true, "notes": true, "notesplain": true,
+		"tags": true, "type": true, "favorite": true, "archived": true, "uuid": true,
+	}
+
+	entries := make([]vault.ImportEntry, 0, len(rows))
+	for _, row := range rows {
+		entry := vault.ImportEntry{
+			Service:  column(row, "title"),
+			Username: column(row, "username"),
+			Password: []byte(column(row, "password")),
+			URL:      column(row, "url", "website"),
+			Notes:    column(row, "notes", "notesplain"),
+			Tags:     splitTags(column(row, "tags")),
+		}
+		if strings.Contains(strings.ToLower(column(row, "type")), "note") {
+			entry.Type = vault.TypeSecureNote
+		}
+		applyTOTP(&entry, column(row, "otpauth", "one-time password"))
+		entry.CustomFields = append(entry.CustomFields, extraColumns(row, known)...)
+
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// ReadKeePassCSV parses a KeePassXC or KeePass 2 CSV export.
+// The group path becomes the category, without the "Root" group.
+func ReadKeePassCSV(r io.Reader) ([]vault.ImportEntry, error) {
+	rows, err := csvRows(r)
+	if err != nil {
+		return nil, err
+	}
+	if !hasColumn(rows, "title", "account") {
+		return nil, fmt.Errorf("not a KeePass CSV export: missing Title column")
+	}
+
+	entries := make([]vault.ImportEntry, 0, len(rows))
+	for _, row := range rows {
+		entry := vault.ImportEntry{
+			Service:  column(row, "title", "account"),
+			Username: column(row, "username", "login name", "user name"),
+			Password: []byte(column(row, "password")),
+			URL:      column(row, "url", "web site"),
+			Notes:    column(row, "notes", "comments"),
+			Category: keePassGroup(column(row, "group")),
+		}
+		applyTOTP(&entry, column(row, "totp"))
+
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// ReadLastPassCSV parses a LastPass CSV export.
+// Groupings become categories; entries with the "http://sn" URL are secure notes.
+func ReadLastPassCSV(r io.Reader) ([]vault.ImportEntry, error) {
+	rows, err := csvRows(r)
+	if err != nil {
+		return nil, err
+	}
+	if !hasColumn(rows, "name") || !hasColumn(rows, "grouping") {
+		return nil, fmt.Errorf("not a LastPass CSV export: missing name or grouping column")
+	}
+
+	entries := make([]vault.ImportEntry, 0, len(rows))
+	for _, row := range rows {
+		entry := vault.ImportEntry{
+			Service:  column(row, "name"),
+			Username: column(row, "username"),
+			Password: []byte(column(row, "password")),
+			URL:      column(row, "url"),
+			Notes:    row["extra"], // Keep note formatting
+			Category: strings.ReplaceAll(column(row, "grouping"), "\\", "/"),
+		}
+		if entry.URL == lastPassNoteURL {
+			entry.URL = ""
+			entry.Type = vault.TypeSecureNote
+		}
+		applyTOTP(&entry, column(row, "totp"))
+
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// keePassGroup strips the top-level "Root" group KeePass adds to every path
+func keePassGroup(group string) string {
+	group = strings.Trim(group, "/")
+	if group == "Root" {
+		return ""
+	}
+	return strings.TrimPrefix(group, "Root/")
+}
+
+// extraColumns keeps non-empty unknown columns as custom fields, sorted by column name
+func extraColumns(row map[string]string, known map[string]bool) []vault.CustomField {
+	var keys []string
+	for key := range row {
+		if !known[key] && strings.TrimSpace(row[key]) != "" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	fields := make([]vault.CustomField, 0, len(keys))
+	for _, key := range keys {
+		fields = append(fields, vault.CustomField{Key: key, Value: row[key]})
+	}
+	return fields
+}
@@ -0,0 +1,210 @@
+// Package importer reads credentials exported by other password managers
+// and maps them onto vault import entries.
+package importer
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"pass-cli/internal/crypto"
+	"pass-cli/internal/exporter"
+	"pass-cli/internal/vault"
+)
+
+// Format identifies an export format
+type Format string
+
+const (
+	FormatBitwardenJSON Format = "bitwarden-json"
+	FormatOnePassword   Format = "1password-csv"
+	FormatKeePassCSV    Format = "keepass-csv"
+	FormatKDBX          Format = "kdbx" // KeePass KDBX 4 database
+	FormatLastPassCSV   Format = "lastpass-csv"
+	FormatPasswordStore Format = "password-store"
+	FormatBundle        Format = "bundle" // Encrypted pass-cli export
+)
+
+// Formats lists the supported formats in display order
+var Formats = []Format{FormatBitwardenJSON, FormatOnePassword, FormatKeePassCSV, FormatKDBX, FormatLastPassCSV, FormatPasswordStore, FormatBundle}
+
+// ErrUnsupportedFormat is returned for unknown format names
+var ErrUnsupportedFormat = errors.New("unsupported import format")
+
+// Options configures the importers that need more than a path
+type Options struct {
+	GPGBinary  string // gpg executable for password-store (default "gpg")
+	Passphrase []byte // Passphrase or database password for encrypted formats
+}
+
+// Plaintext reports whether the format's files hold passwords unencrypted
+func (f Format) Plaintext() bool {
+	return f != FormatPasswordStore && f != FormatBundle && f != FormatKDBX
+}
+
+// Read parses the export at path in the given format
+func Read(format Format, path string, opts Options) ([]vault.ImportEntry, error) {
+	switch format {
+	case FormatBitwardenJSON:
+		return readFile(path, ReadBitwardenJSON)
+	case FormatOnePassword:
+		return readFile(path, ReadOnePasswordCSV)
+	case FormatKeePassCSV:
+		return readFile(path, ReadKeePassCSV)
+	case FormatKDBX:
+		return readFile(path, func(r io.Reader) ([]vault.ImportEntry, error) {
+			return ReadKDBX(r, opts.Passphrase)
+		})
+	case FormatLastPassCSV:
+		return readFile(path, ReadLastPassCSV)
+	case FormatPasswordStore:
+		return ReadPasswordStore(path, opts.GPGBinary)
+	case FormatBundle:
+		return readFile(path, func(r io.Reader) ([]vault.ImportEntry, error) {
+			return ReadBundle(r, opts.Passphrase)
+		})
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnsupportedFormat, format)
+}
+
+// ParseFormat validates a format name
+func ParseFormat(value string) (Format, error) {
+	format := Format(strings.ToLower(strings.TrimSpace(value)))
+	for _, f := range Formats {
+		if f == format {
+			return f, nil
+		}
+	}
+
+	names := make([]string, len(Formats))
+	for i, f := range Formats {
+		names[i] = string(f)
+	}
+	return "", fmt.Errorf("%w %q (valid: %s)", ErrUnsupportedFormat, value, strings.Join(names, ", "))
+}
+
+// readFile opens a single-file export and hands it to a parser
+func readFile(path string, parse func(io.Reader) ([]vault.ImportEntry, error)) ([]vault.ImportEntry, error) {
+	f, err := os.Open(path) // #nosec G304 -- User-specified export file
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer func() { _ = f.Close() }()
+
+	return parse(f)
+}
+
+// csvRows reads a CSV export with a header row into maps keyed by lowercased column name
+func csvRows(r io.Reader) ([]map[string]string, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1 // Exports are not always rectangular
+	reader.LazyQuotes = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+	for i, column := range header {
+		column = strings.TrimPrefix(column, "\ufeff") // Excel-style byte order mark
+		header[i] = strings.ToLower(strings.TrimSpace(column))
+	}
+
+	var rows []map[string]string
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CSV: %w", err)
+		}
+
+		row := make(map[string]string, len(header))
+		for i, value := range record {
+			if i < len(header) {
+				row[header[i]] = value
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// column returns the first non-empty value among alternative column names
+func column(row map[string]string, names ...string) string {
+	for _, name := range names {
+		if value := strings.TrimSpace(row[name]); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// hasColumn reports whether the CSV header contained any of the names
+func hasColumn(rows []map[string]string, names ...string) bool {
+	if len(rows) == 0 {
+		return true // Nothing to check against
+	}
+	for _, name := range names {
+		if _, ok := rows[0][name]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// applyTOTP parses a TOTP secret or otpauth:// URI onto the entry.
+// Values pass-cli cannot generate codes for are kept as a secret custom field.
+func applyTOTP(entry *vault.ImportEntry, value string) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return
+	}
+	if cfg, err := vault.ParseTOTP(value); err == nil {
+		entry.TOTP = cfg
+		return
+	}
+	entry.CustomFields = append(entry.CustomFields, vault.CustomField{Key: "totp", Value: value, Secret: true})
+}
+
+// splitTags splits a tag list separated by commas or semicolons
+func splitTags(value string) []string {
+	return strings.FieldsFunc(value, func(r rune) bool {
+		return r == ',' || r == ';'
+	})
+}
+
+// ReadBundle decrypts a bundle written by "pass-cli export --format bundle".
+// Password history, usage, and attachments are not imported.
+func ReadBundle(r io.Reader, passphrase []byte) ([]vault.ImportEntry, error) {
+	credentials, err := exporter.ReadBundle(r, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]vault.ImportEntry, 0, len(credentials))
+	for _, credential := range credentials {
+		entries = append(entries, vault.ImportEntry{
+			Service:      credential.Service,
+			Username:     credential.Username,
+			Password:     credential.Password,
+			Type:         credential.Type,
+			Category:     credential.Category,
+			Tags:         credential.Tags,
+			URL:          credential.URL,
+			Notes:        credential.Notes,
+			CustomFields: credential.CustomFields,
+			TOTP:         credential.TOTP,
+		})
+		for _, entry := range credential.PasswordHistory {
+			crypto.ClearBytes(entry.Password)
+		}
+	}
+	return entries, nil
+}
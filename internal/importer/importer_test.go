@@ -0,0 +1,325 @@
+package importer
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"pass-cli/internal/exporter"
+	"pass-cli/internal/kdbx"
+	"pass-cli/internal/vault"
+)
+
+func findEntry(t *testing.T, entries []vault.ImportEntry, service string) vault.ImportEntry {
+	t.Helper()
+	for _, entry := range entries {
+		if entry.Service == service {
+			return entry
+		}
+	}
+	t.Fatalf("entry %q not found in %d entries", service, len(entries))
+	return vault.ImportEntry{}
+}
+
+func TestReadBitwardenJSON(t *testing.T) {
+	export := `{
+  "encrypted": false,
+  "folders": [{"id": "f1", "name": "Work"}],
+  "items": [
+    {
+      "type": 1, "name": "github", "folderId": "f1", "notes": "main account",
+      "fields": [{"name": "recovery", "value": "abc", "type": 1}, {"name": "linked", "value": null, "type": 3}],
+      "login": {
+        "username": "octocat", "password": "hunter2", "totp": "JBSWY3DPEHPK3PXP",
+        "uris": [{"uri": "https://github.com"}, {"uri": "https://gist.github.com"}]
+      }
+    },
+    {"type": 2, "name": "wifi", "notes": "password is on the router"},
+    {"type": 3, "name": "visa", "card": {"cardholderName": "Jane", "number": "4111111111111111", "code": "123", "expYear": null}}
+  ]
+}`
+
+	entries, err := ReadBitwardenJSON(strings.NewReader(export))
+	if err != nil {
+		t.Fatalf("ReadBitwardenJSON failed: %v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("Expected 3 entries, got %d", len(entries))
+	}
+
+	github := findEntry(t, entries, "github")
+	if github.Username != "octocat" || string(github.Password) != "hunter2" || github.URL != "https://github.com" {
+		t.Errorf("github = %+v", github)
+	}
+	if github.Category != "Work" || github.Notes != "main account" || github.TOTP == nil {
+		t.Errorf("github category/notes/totp = %q/%q/%v", github.Category, github.Notes, github.TOTP)
+	}
+	if len(github.CustomFields) != 2 || github.CustomFields[0].Key != "uri2" || !github.CustomFields[1].Secret {
+		t.Errorf("github custom fields = %+v", github.CustomFields)
+	}
+
+	if wifi := findEntry(t, entries, "wifi"); wifi.Type != vault.TypeSecureNote {
+		t.Errorf("wifi type = %q", wifi.Type)
+	}
+
+	visa := findEntry(t, entries, "visa")
+	if len(visa.CustomFields) != 3 {
+		t.Fatalf("visa custom fields = %+v", visa.CustomFields)
+	}
+	if number, _ := vault.FindCustomField(visa.CustomFields, "card_number"); !number.Secret || number.Value != "4111111111111111" {
+		t.Errorf("card number field = %+v", number)
+	}
+
+	if _, err := ReadBitwardenJSON(strings.NewReader(`{"encrypted": true, "items": []}`)); err == nil {
+		t.Error("Expected an error for encrypted exports")
+	}
+}
+
+func TestReadOnePasswordCSV(t *testing.T) {
+	export := "\ufeffTitle,Url,Username,Password,OTPAuth,Favorite,Archived,Tags,Notes,Recovery Email\n" +
+		"github,https://github.com,octocat,hunter2,otpauth://totp/GitHub:octocat?secret=JBSWY3DPEHPK3PXP,true,false,\"dev;work stuff\",\"line 1\nline 2\",me@example.com\n" +
+		"wifi,,,\"pa\"\"ss\",,false,false,,,\n"
+
+	entries, err := ReadOnePasswordCSV(strings.NewReader(export))
+	if err != nil {
+		t.Fatalf("ReadOnePasswordCSV failed: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(entries))
+	}
+
+	github := entries[0]
+	if github.Service != "github" || github.URL != "https://github.com" || github.TOTP == nil {
+		t.Errorf("github = %+v", github)
+	}
+	if github.Notes != "line 1\nline 2" {
+		t.Errorf("github notes = %q", github.Notes)
+	}
+	if len(github.Tags) != 2 || github.Tags[1] != "work stuff" {
+		t.Errorf("github tags = %v", github.Tags)
+	}
+	if len(github.CustomFields) != 1 || github.CustomFields[0].Key != "recovery email" {
+		t.Errorf("github custom fields = %+v", github.CustomFields)
+	}
+	if string(entries[1].Password) != `pa"ss` {
+		t.Errorf("wifi password = %q", entries[1].Password)
+	}
+
+	if _, err := ReadOnePasswordCSV(strings.NewReader("name,pass\nx,y\n")); err == nil {
+		t.Error("Expected an error for a CSV without a Title column")
+	}
+}
+
+func TestReadKeePassCSV(t *testing.T) {
+	export := `"Group","Title","Username","Password","URL","Notes","TOTP","Icon","Last Modified","Created"
+"Root/Work/Cloud","aws","admin","s3cret","https://aws.amazon.com","prod","","0","",""
+"Root","mail","me","pw","","","otpauth://totp/Mail?secret=JBSWY3DPEHPK3PXP","0","",""
+`
+	entries, err := ReadKeePassCSV(strings.NewReader(export))
+	if err != nil {
+		t.Fatalf("ReadKeePassCSV failed: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(entries))
+	}
+	if entries[0].Category != "Work/Cloud" || entries[0].Username != "admin" || entries[0].Notes != "prod" {
+		t.Errorf("aws = %+v", entries[0])
+	}
+	if entries[1].Category != "" || entries[1].TOTP == nil {
+		t.Errorf("mail = %+v", entries[1])
+	}
+
+	// KeePass 2 column names
+	entries, err = ReadKeePassCSV(strings.NewReader("\"Account\",\"Login Name\",\"Password\",\"Web Site\",\"Comments\"\n\"db\",\"root\",\"pw\",\"\",\"note\"\n"))
+	if err != nil {
+		t.Fatalf("ReadKeePassCSV failed: %v", err)
+	}
+	if entries[0].Service != "db" || entries[0].Username != "root" || entries[0].Notes != "note" {
+		t.Errorf("KeePass 2 entry = %+v", entries[0])
+	}
+}
+
+func TestReadKDBX(t *testing.T) {
+	password := []byte("keepass-password")
+	expiry := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	db := &kdbx.Database{Root: kdbx.Group{
+		Name: "Root",
+		Entries: []kdbx.Entry{{Fields: []kdbx.Field{
+			{Key: kdbx.FieldTitle, Value: "router"},
+			{Key: kdbx.FieldPassword, Value: "admin", Protected: true},
+			{Key: "TimeOtp-Secret-Base32", Value: "JBSWY3DPEHPK3PXP", Protected: true},
+			{Key: "TimeOtp-Length", Value: "8"},
+			{Key: "TimeOtp-Algorithm", Value: "HMAC-SHA-256"},
+		}}},
+		Groups: []kdbx.Group{{Name: "Work", Groups: []kdbx.Group{{Name: "Email", Entries: []kdbx.Entry{{
+			Fields: []kdbx.Field{
+				{Key: kdbx.FieldTitle, Value: "mail"},
+				{Key: kdbx.FieldUserName, Value: "alice"},
+				{Key: kdbx.FieldPassword, Value: "hunter2", Protected: true},
+				{Key: kdbx.FieldURL, Value: "https://mail.example.com"},
+				{Key: kdbx.FieldNotes, Value: "note"},
+				{Key: "otp", Value: "otpauth://totp/Mail:alice?secret=JBSWY3DPEHPK3PXP&issuer=Mail", Protected: true},
+				{Key: "PIN", Value: "1234", Protected: true},
+				{Key: "Department", Value: "IT"},
+				{Key: "Empty", Value: ""},
+			},
+			Tags:      []string{"work"},
+			ExpiresAt: &expiry,
+		}}}}}},
+	}}
+
+	var buf bytes.Buffer
+	opts := kdbx.WriteOptions{Cipher: kdbx.CipherAES256, KDF: kdbx.KDFAES, Iterations: 10}
+	if err := kdbx.Write(&buf, db, password, opts); err != nil {
+		t.Fatalf("kdbx.Write failed: %v", err)
+	}
+
+	encoded := buf.Bytes()
+
+	entries, err := ReadKDBX(bytes.NewReader(encoded), password)
+	if err != nil {
+		t.Fatalf("ReadKDBX failed: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(entries))
+	}
+
+	router := findEntry(t, entries, "router")
+	if router.Category != "" || router.TOTP == nil || router.TOTP.Digits != 8 || router.TOTP.Algorithm != "SHA256" {
+		t.Errorf("router = %+v (totp %+v)", router, router.TOTP)
+	}
+	if len(router.CustomFields) != 0 {
+		t.Errorf("TimeOtp settings leaked into custom fields: %+v", router.CustomFields)
+	}
+
+	mail := findEntry(t, entries, "mail")
+	if mail.Category != "Work/Email" || mail.Username != "alice" || string(mail.Password) != "hunter2" ||
+		mail.URL != "https://mail.example.com" || mail.Notes != "note" || mail.TOTP == nil {
+		t.Errorf("mail = %+v", mail)
+	}
+	if len(mail.Tags) != 1 || mail.ExpiresAt == nil || !mail.ExpiresAt.Equal(expiry) {
+		t.Errorf("mail tags/expiry = %v/%v", mail.Tags, mail.ExpiresAt)
+	}
+	want := []vault.CustomField{{Key: "PIN", Value: "1234", Secret: true}, {Key: "Department", Value: "IT"}}
+	if len(mail.CustomFields) != len(want) || mail.CustomFields[0] != want[0] || mail.CustomFields[1] != want[1] {
+		t.Errorf("custom fields = %+v", mail.CustomFields)
+	}
+
+	if _, err := ReadKDBX(bytes.NewReader(encoded), []byte("wrong")); !errors.Is(err, kdbx.ErrInvalidPassword) {
+		t.Errorf("ReadKDBX with wrong password = %v, want ErrInvalidPassword", err)
+	}
+}
+
+func TestReadLastPassCSV(t *testing.T) {
+	export := "url,username,password,totp,extra,name,grouping,fav\n" +
+		"https://github.com,octocat,hunter2,,,github,Dev\\Code,0\n" +
+		"http://sn,,,,\"NoteType:Server\nHostname:db1\",server notes,Ops,0\n"
+
+	entries, err := ReadLastPassCSV(strings.NewReader(export))
+	if err != nil {
+		t.Fatalf("ReadLastPassCSV failed: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(entries))
+	}
+	if entries[0].Category != "Dev/Code" || entries[0].URL != "https://github.com" {
+		t.Errorf("github = %+v", entries[0])
+	}
+	if entries[1].Type != vault.TypeSecureNote || entries[1].URL != "" || !strings.Contains(entries[1].Notes, "Hostname:db1") {
+		t.Errorf("server notes = %+v", entries[1])
+	}
+}
+
+func TestReadPasswordStore(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake gpg script requires a POSIX shell")
+	}
+
+	store := t.TempDir()
+	files := map[string]string{
+		"github.gpg":         "hunter2\nlogin: octocat\nurl: https://github.com\notpauth://totp/GitHub?secret=JBSWY3DPEHPK3PXP\nrecovery: abc\nhttps://example.com/help\n",
+		"work/aws/prod.gpg":  "s3cret\n",
+		".git/objects/x.gpg": "ignored\n",
+		"work/.gpg-id":       "KEYID\n",
+		"work/readme.txt":    "not a password\n",
+		"personal/email.gpg": "pw\r\nuser: me@example.com\r\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(store, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			t.Fatalf("MkdirAll failed: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatalf("WriteFile failed: %v", err)
+		}
+	}
+
+	// Stand-in for gpg: "decrypts" by printing the last argument
+	fakeGPG := filepath.Join(t.TempDir(), "fake-gpg")
+	script := "#!/bin/sh\nfor last; do :; done\ncat \"$last\"\n"
+	if err := os.WriteFile(fakeGPG, []byte(script), 0700); err != nil { // #nosec G306 -- Test helper must be executable
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	entries, err := ReadPasswordStore(store, fakeGPG)
+	if err != nil {
+		t.Fatalf("ReadPasswordStore failed: %v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("Expected 3 entries, got %d: %+v", len(entries), entries)
+	}
+
+	github := findEntry(t, entries, "github")
+	if string(github.Password) != "hunter2" || github.Username != "octocat" || github.URL != "https://github.com" {
+		t.Errorf("github = %+v", github)
+	}
+	if github.TOTP == nil || len(github.CustomFields) != 1 || github.CustomFields[0].Key != "recovery" {
+		t.Errorf("github totp/fields = %v/%+v", github.TOTP, github.CustomFields)
+	}
+	if github.Notes != "https://example.com/help" {
+		t.Errorf("github notes = %q", github.Notes)
+	}
+	if email := findEntry(t, entries, "personal/email"); string(email.Password) != "pw" || email.Username != "me@example.com" {
+		t.Errorf("email = %+v", email)
+	}
+	findEntry(t, entries, "work/aws/prod")
+
+	if _, err := ReadPasswordStore(store, filepath.Join(t.TempDir(), "missing-gpg")); err == nil {
+		t.Error("Expected an error when gpg is missing")
+	}
+}
+
+func TestReadBundle(t *testing.T) {
+	passphrase := []byte("Bundle-Passphrase-1")
+	credentials := []vault.Credential{{
+		Service: "github", Username: "octocat", Password: []byte("hunter2"), Category: "Dev",
+		PasswordHistory: []vault.PasswordHistoryEntry{{Password: []byte("old")}},
+	}}
+
+	var buf bytes.Buffer
+	if err := exporter.WriteBundle(&buf, credentials, passphrase); err != nil {
+		t.Fatalf("WriteBundle failed: %v", err)
+	}
+
+	entries, err := ReadBundle(&buf, passphrase)
+	if err != nil {
+		t.Fatalf("ReadBundle failed: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Service != "github" || string(entries[0].Password) != "hunter2" || entries[0].Category != "Dev" {
+		t.Errorf("entries = %+v", entries)
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	if format, err := ParseFormat(" Bitwarden-JSON "); err != nil || format != FormatBitwardenJSON {
+		t.Errorf("ParseFormat = %q, %v", format, err)
+	}
+	if _, err := ParseFormat("dashlane"); err == nil {
+		t.Error("Expected an error for an unknown format")
+	}
+}
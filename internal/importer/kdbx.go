@@ -0,0 +1,99 @@
+package importer
+
+import (
+	"io"
+	"strconv"
+	"strings"
+
+	"pass-cli/internal/kdbx"
+	"pass-cli/internal/vault"
+)
+
+// KeePass fields with special meaning besides the standard ones
+const (
+	kdbxOTPField        = "otp"                   // otpauth:// URI written by KeePassXC
+	kdbxTimeOTPSecret   = "TimeOtp-Secret-Base32" // KeePass 2.47+ native TOTP
+	kdbxTimeOTPPrefix   = "TimeOtp-"
+	kdbxTimeOTPAlgoHMAC = "HMAC-"
+)
+
+// ReadKDBX decrypts a KeePass KDBX 4 database. The group path becomes the
+// category (without the top-level group), standard fields map to credential
+// fields, TOTP settings are imported, and other string fields become custom
+// fields (protected ones as secrets). Entry history and the recycle bin are
+// not imported.
+func ReadKDBX(r io.Reader, password []byte) ([]vault.ImportEntry, error) {
+	db, err := kdbx.Read(r, password)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []vault.ImportEntry
+	var walk func(group kdbx.Group, path []string)
+	walk = func(group kdbx.Group, path []string) {
+		for _, entry := range group.Entries {
+			entries = append(entries, kdbxEntry(entry, strings.Join(path, "/")))
+		}
+		for _, child := range group.Groups {
+			walk(child, append(path[:len(path):len(path)], child.Name))
+		}
+	}
+	walk(db.Root, nil)
+	return entries, nil
+}
+
+// kdbxEntry maps one KeePass entry onto an import entry
+func kdbxEntry(entry kdbx.Entry, category string) vault.ImportEntry {
+	imported := vault.ImportEntry{
+		Service:   strings.TrimSpace(entry.Get(kdbx.FieldTitle)),
+		Username:  entry.Get(kdbx.FieldUserName),
+		Password:  []byte(entry.Get(kdbx.FieldPassword)),
+		URL:       entry.Get(kdbx.FieldURL),
+		Notes:     entry.Get(kdbx.FieldNotes),
+		Category:  category,
+		Tags:      entry.Tags,
+		ExpiresAt: entry.ExpiresAt,
+	}
+
+	for _, field := range entry.Fields {
+		switch {
+		case field.Key == kdbx.FieldTitle, field.Key == kdbx.FieldUserName, field.Key == kdbx.FieldPassword,
+			field.Key == kdbx.FieldURL, field.Key == kdbx.FieldNotes:
+		case field.Key == kdbxOTPField:
+			applyTOTP(&imported, field.Value)
+		case field.Key == kdbxTimeOTPSecret:
+			applyTimeOTP(&imported, entry)
+		case strings.HasPrefix(field.Key, kdbxTimeOTPPrefix):
+			// Settings read with the secret
+		case strings.TrimSpace(field.Value) != "":
+			imported.CustomFields = append(imported.CustomFields, vault.CustomField{
+				Key:    field.Key,
+				Value:  field.Value,
+				Secret: field.Protected,
+			})
+		}
+	}
+	return imported
+}
+
+// applyTimeOTP imports KeePass 2 native TOTP settings (TimeOtp-Secret-Base32 with
+// optional TimeOtp-Length, TimeOtp-Period, and TimeOtp-Algorithm)
+func applyTimeOTP(imported *vault.ImportEntry, entry kdbx.Entry) {
+	secret := entry.Get(kdbxTimeOTPSecret)
+	cfg, err := vault.ParseTOTP(secret)
+	if err != nil {
+		applyTOTP(imported, secret) // Kept as a secret custom field
+		return
+	}
+
+	if digits, err := strconv.Atoi(entry.Get(kdbxTimeOTPPrefix + "Length")); err == nil {
+		cfg.Digits = digits
+	}
+	if period, err := strconv.Atoi(entry.Get(kdbxTimeOTPPrefix + "Period")); err == nil {
+		cfg.Period = period
+	}
+	if algorithm := entry.Get(kdbxTimeOTPPrefix + "Algorithm"); algorithm != "" {
+		cfg.Algorithm = strings.ReplaceAll(strings.TrimPrefix(algorithm, kdbxTimeOTPAlgoHMAC), "-", "")
+	}
+	imported.TOTP = cfg
+}
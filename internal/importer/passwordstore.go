@@ -0,0 +1,149 @@
+package importer
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"pass-cli/internal/crypto"
+	"pass-cli/internal/vault"
+)
+
+const defaultGPGBinary = "gpg"
+
+// passUsernameKeys and passURLKeys are the "key: value" lines mapped to built-in fields
+var (
+	passUsernameKeys = map[string]bool{"login": true, "username": true, "user": true}
+	passURLKeys      = map[string]bool{"url": true, "website": true, "site": true}
+)
+
+// DefaultPasswordStoreDir returns $PASSWORD_STORE_DIR or ~/.password-store
+func DefaultPasswordStoreDir() (string, error) {
+	if dir := os.Getenv("PASSWORD_STORE_DIR"); dir != "" {
+		return dir, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, ".password-store"), nil
+}
+
+// ReadPasswordStore decrypts every .gpg file in a password-store tree with the
+// local gpg binary (gpg-agent handles the passphrase). The file path without
+// ".gpg" becomes the service name, so folders carry over as service paths.
+// Following pass conventions, the first line is the password, "key: value"
+// lines become fields, an otpauth:// line becomes the TOTP secret, and any
+// other lines become notes.
+func ReadPasswordStore(dir, gpgBinary string) ([]vault.ImportEntry, error) {
+	if gpgBinary == "" {
+		gpgBinary = defaultGPGBinary
+	}
+	if info, err := os.Stat(dir); err != nil {
+		return nil, fmt.Errorf("failed to read password store: %w", err)
+	} else if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a password-store directory", dir)
+	}
+	if _, err := exec.LookPath(gpgBinary); err != nil {
+		return nil, fmt.Errorf("gpg is required to import a password store: %w", err)
+	}
+
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != dir && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir // .git, .extensions, ...
+			}
+			return nil
+		}
+		if strings.HasSuffix(d.Name(), ".gpg") {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read password store: %w", err)
+	}
+	sort.Strings(files)
+
+	entries := make([]vault.ImportEntry, 0, len(files))
+	for _, path := range files {
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return nil, err
+		}
+		service := strings.TrimSuffix(filepath.ToSlash(rel), ".gpg")
+
+		plaintext, err := decryptGPG(gpgBinary, path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt %s: %w", service, err)
+		}
+		entries = append(entries, parsePassEntry(service, plaintext))
+		crypto.ClearBytes(plaintext)
+	}
+	return entries, nil
+}
+
+// decryptGPG runs gpg to decrypt one file and returns the plaintext
+func decryptGPG(gpgBinary, path string) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(gpgBinary, "--quiet", "--batch", "--decrypt", path) // #nosec G204 -- Binary and path are chosen by the user
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return nil, fmt.Errorf("%w: %s", err, message)
+		}
+		return nil, err
+	}
+	return stdout.Bytes(), nil
+}
+
+// parsePassEntry maps the contents of one pass file onto an import entry
+func parsePassEntry(service string, plaintext []byte) vault.ImportEntry {
+	text := strings.ReplaceAll(string(plaintext), "\r\n", "\n")
+	lines := strings.Split(text, "\n")
+
+	entry := vault.ImportEntry{
+		Service:  service,
+		Password: []byte(lines[0]),
+	}
+
+	var notes []string
+	for _, line := range lines[1:] {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(strings.ToLower(trimmed), "otpauth://") && entry.TOTP == nil {
+			applyTOTP(&entry, trimmed)
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, ":")
+		key = strings.TrimSpace(key)
+		// Bare URLs ("https://...") are notes, not fields
+		if !ok || key == "" || strings.ContainsAny(key, " \t") || strings.HasPrefix(value, "//") {
+			notes = append(notes, line)
+			continue
+		}
+		value = strings.TrimSpace(value)
+
+		switch lower := strings.ToLower(key); {
+		case passUsernameKeys[lower] && entry.Username == "":
+			entry.Username = value
+		case passURLKeys[lower] && entry.URL == "":
+			entry.URL = value
+		default:
+			entry.CustomFields = append(entry.CustomFields, vault.CustomField{Key: key, Value: value})
+		}
+	}
+	entry.Notes = strings.TrimSpace(strings.Join(notes, "\n"))
+	return entry
+}
@@ -0,0 +1,258 @@
+package kdbx
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+
+	"golang.org/x/crypto/chacha20"
+	"golang.org/x/crypto/salsa20/salsa"
+
+	"pass-cli/internal/crypto"
+)
+
+const (
+	blockSize        = 1024 * 1024    // HMAC block size used when writing
+	headerBlockIndex = math.MaxUint64 // Block index used for the header HMAC
+)
+
+// salsa20Nonce is the fixed nonce of the Salsa20 inner random stream
+var salsa20Nonce = []byte{0xE8, 0x30, 0x09, 0x4B, 0x97, 0x20, 0x5D, 0x2A}
+
+// compositeKey hashes the password the way KeePass does for password-only databases
+func compositeKey(password []byte) []byte {
+	passwordHash := sha256.Sum256(password)
+	key := sha256.Sum256(passwordHash[:])
+	return key[:]
+}
+
+// transformKey runs the KDF from the header on the composite key
+func transformKey(params variantDictionary, key []byte) ([]byte, error) {
+	uuid := params.bytes("$UUID")
+	switch {
+	case bytes.Equal(uuid, kdfAESUUID):
+		rounds, ok := params.uint64("R")
+		seed := params.bytes("S")
+		if !ok || len(seed) != 32 {
+			return nil, fmt.Errorf("%w: invalid AES-KDF parameters", ErrCorrupted)
+		}
+		return aesKDF(key, seed, rounds)
+
+	case bytes.Equal(uuid, kdfArgon2dUUID), bytes.Equal(uuid, kdfArgon2idUUID):
+		mode := argon2d
+		if bytes.Equal(uuid, kdfArgon2idUUID) {
+			mode = argon2id
+		}
+		salt := params.bytes("S")
+		parallelism, okP := params.uint32("P")
+		memory, okM := params.uint64("M")
+		iterations, okI := params.uint64("I")
+		version, okV := params.uint32("V")
+		if !okP || !okM || !okI || !okV || len(salt) == 0 {
+			return nil, fmt.Errorf("%w: invalid Argon2 parameters", ErrCorrupted)
+		}
+		if version != argon2Version {
+			return nil, fmt.Errorf("%w: Argon2 version %#x", ErrUnsupportedVersion, version)
+		}
+		// Memory is capped like vault KDF parameters, so a crafted header cannot exhaust RAM
+		if iterations < 1 || iterations > math.MaxUint32 || parallelism < 1 || parallelism > math.MaxUint8 ||
+			memory/1024 > crypto.MaxArgon2Memory {
+			return nil, fmt.Errorf("%w: Argon2 parameters out of range", ErrCorrupted)
+		}
+		return deriveKey(mode, key, salt, params.bytes("K"), params.bytes("A"),
+			uint32(iterations), uint32(memory/1024), uint8(parallelism), 32), nil // #nosec G115 -- Ranges checked above
+	}
+	return nil, fmt.Errorf("%w: unknown key derivation function", ErrUnsupportedVersion)
+}
+
+// aesKDF encrypts the key with AES-256-ECB rounds times and hashes the result
+func aesKDF(key, seed []byte, rounds uint64) ([]byte, error) {
+	block, err := aes.NewCipher(seed)
+	if err != nil {
+		return nil, err
+	}
+	transformed := append([]byte(nil), key...)
+	for i := uint64(0); i < rounds; i++ {
+		block.Encrypt(transformed[:16], transformed[:16])
+		block.Encrypt(transformed[16:], transformed[16:])
+	}
+	sum := sha256.Sum256(transformed)
+	return sum[:], nil
+}
+
+// payloadKeys derives the cipher key and the HMAC base key from the transformed key
+func payloadKeys(masterSeed, transformed []byte) (cipherKey, hmacKey []byte) {
+	cipherHash := sha256.New()
+	cipherHash.Write(masterSeed)
+	cipherHash.Write(transformed)
+
+	hmacHash := sha512.New()
+	hmacHash.Write(masterSeed)
+	hmacHash.Write(transformed)
+	hmacHash.Write([]byte{0x01})
+	return cipherHash.Sum(nil), hmacHash.Sum(nil)
+}
+
+// blockHMAC authenticates one block (or, with headerBlockIndex, the header)
+func blockHMAC(hmacKey []byte, index uint64, data []byte, withSize bool) []byte {
+	var indexBytes [8]byte
+	binary.LittleEndian.PutUint64(indexBytes[:], index)
+
+	keyHash := sha512.New()
+	keyHash.Write(indexBytes[:])
+	keyHash.Write(hmacKey)
+
+	mac := hmac.New(sha256.New, keyHash.Sum(nil))
+	if withSize {
+		var sizeBytes [4]byte
+		binary.LittleEndian.PutUint32(sizeBytes[:], uint32(len(data))) // #nosec G115 -- Blocks are at most blockSize
+		mac.Write(indexBytes[:])
+		mac.Write(sizeBytes[:])
+	}
+	mac.Write(data)
+	return mac.Sum(nil)
+}
+
+// readBlocks verifies and joins the HMAC block stream that follows the header
+func readBlocks(r io.Reader, hmacKey []byte) ([]byte, error) {
+	var out bytes.Buffer
+	for index := uint64(0); ; index++ {
+		var prefix struct {
+			MAC  [32]byte
+			Size int32
+		}
+		if err := binary.Read(r, binary.LittleEndian, &prefix); err != nil {
+			return nil, fmt.Errorf("%w: truncated block stream", ErrCorrupted)
+		}
+		if prefix.Size < 0 {
+			return nil, fmt.Errorf("%w: invalid block size", ErrCorrupted)
+		}
+		data := make([]byte, prefix.Size)
+		if _, err := io.ReadFull(r, data); err != nil {
+			return nil, fmt.Errorf("%w: truncated block stream", ErrCorrupted)
+		}
+		if !hmac.Equal(prefix.MAC[:], blockHMAC(hmacKey, index, data, true)) {
+			return nil, fmt.Errorf("%w: block %d failed authentication", ErrCorrupted, index)
+		}
+		if prefix.Size == 0 {
+			return out.Bytes(), nil
+		}
+		out.Write(data)
+	}
+}
+
+// writeBlocks splits data into HMAC blocks followed by the empty final block
+func writeBlocks(w *bytes.Buffer, data []byte, hmacKey []byte) {
+	index := uint64(0)
+	for {
+		n := min(len(data), blockSize)
+		chunk := data[:n]
+		w.Write(blockHMAC(hmacKey, index, chunk, true))
+		_ = binary.Write(w, binary.LittleEndian, int32(n)) // #nosec G115 -- n <= blockSize
+		w.Write(chunk)
+		if n == 0 {
+			return
+		}
+		data = data[n:]
+		index++
+	}
+}
+
+// decryptPayload decrypts the joined blocks with the header cipher
+func decryptPayload(cipherID, key, iv, data []byte) ([]byte, error) {
+	switch {
+	case bytes.Equal(cipherID, cipherAES256UUID):
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, err
+		}
+		if len(iv) != aes.BlockSize || len(data) == 0 || len(data)%aes.BlockSize != 0 {
+			return nil, fmt.Errorf("%w: invalid AES payload", ErrCorrupted)
+		}
+		plaintext := make([]byte, len(data))
+		cipher.NewCBCDecrypter(block, iv).CryptBlocks(plaintext, data)
+
+		padding := int(plaintext[len(plaintext)-1])
+		if padding == 0 || padding > aes.BlockSize {
+			return nil, fmt.Errorf("%w: invalid padding", ErrCorrupted)
+		}
+		return plaintext[:len(plaintext)-padding], nil
+
+	case bytes.Equal(cipherID, cipherChaCha20UUID):
+		stream, err := chacha20.NewUnauthenticatedCipher(key, iv)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrCorrupted, err)
+		}
+		plaintext := make([]byte, len(data))
+		stream.XORKeyStream(plaintext, data)
+		return plaintext, nil
+	}
+	return nil, fmt.Errorf("%w: unknown cipher (only AES-256 and ChaCha20 are supported)", ErrUnsupportedVersion)
+}
+
+// encryptPayload encrypts the payload with the header cipher
+func encryptPayload(cipherID, key, iv, data []byte) ([]byte, error) {
+	if bytes.Equal(cipherID, cipherChaCha20UUID) {
+		stream, err := chacha20.NewUnauthenticatedCipher(key, iv)
+		if err != nil {
+			return nil, err
+		}
+		ciphertext := make([]byte, len(data))
+		stream.XORKeyStream(ciphertext, data)
+		return ciphertext, nil
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	padding := aes.BlockSize - len(data)%aes.BlockSize
+	padded := make([]byte, len(data)+padding)
+	copy(padded, data)
+	for i := len(data); i < len(padded); i++ {
+		padded[i] = byte(padding)
+	}
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(padded, padded)
+	return padded, nil
+}
+
+// newInnerStream creates the keystream that protects values inside the XML
+func newInnerStream(id uint32, key []byte) (cipher.Stream, error) {
+	switch id {
+	case innerStreamChaCha20:
+		hash := sha512.Sum512(key)
+		return chacha20.NewUnauthenticatedCipher(hash[:32], hash[32:44])
+	case innerStreamSalsa20:
+		stream := &salsa20Stream{key: sha256.Sum256(key)}
+		copy(stream.counter[:8], salsa20Nonce)
+		return stream, nil
+	}
+	return nil, fmt.Errorf("%w: inner random stream %d", ErrUnsupportedVersion, id)
+}
+
+// salsa20Stream is a Salsa20 keystream usable across several XORKeyStream calls
+type salsa20Stream struct {
+	key     [32]byte
+	counter [16]byte // Nonce, then the little-endian block counter
+	block   [64]byte
+	used    int
+}
+
+func (s *salsa20Stream) XORKeyStream(dst, src []byte) {
+	for i := range src {
+		if s.used == 0 {
+			var zero [64]byte
+			salsa.XORKeyStream(s.block[:], zero[:], &s.counter, &s.key)
+			binary.LittleEndian.PutUint64(s.counter[8:], binary.LittleEndian.Uint64(s.counter[8:])+1)
+		}
+		dst[i] = src[i] ^ s.block[s.used]
+		s.used = (s.used + 1) % len(s.block)
+	}
+}
@@ -0,0 +1,205 @@
+package kdbx
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+)
+
+// File signature and versions
+const (
+	signature1 uint32 = 0x9AA2D903
+	signature2 uint32 = 0xB54BFB67
+
+	versionMajorMask uint32 = 0xFFFF0000
+	version4         uint32 = 0x00040000 // Written by this package; 4.x files are read
+)
+
+// Outer header field IDs
+const (
+	headerEnd              = 0
+	headerCipherID         = 2
+	headerCompression      = 3
+	headerMasterSeed       = 4
+	headerEncryptionIV     = 7
+	headerKDFParameters    = 11
+	headerPublicCustomData = 12
+)
+
+// Inner header field IDs
+const (
+	innerHeaderEnd       = 0
+	innerHeaderStreamID  = 1
+	innerHeaderStreamKey = 2
+	innerHeaderBinary    = 3
+)
+
+// Inner random stream IDs (protect values such as passwords inside the XML)
+const (
+	innerStreamSalsa20  uint32 = 2
+	innerStreamChaCha20 uint32 = 3
+)
+
+// Cipher and KDF identifiers
+var (
+	cipherAES256UUID   = mustDecodeHex("31c1f2e6bf714350be5805216afc5aff")
+	cipherChaCha20UUID = mustDecodeHex("d6038a2b8b6f4cb5a524339a31dbb59a")
+	kdfAESUUID         = mustDecodeHex("c9d9f39a628a4460bf740d08c18a4fea")
+	kdfArgon2dUUID     = mustDecodeHex("ef636ddf8c29444b91f7a9a403e30a0c")
+	kdfArgon2idUUID    = mustDecodeHex("9e298b1956db4773b23dfc3ec6f0a1e6")
+)
+
+// outerHeader holds the unencrypted header fields needed to decrypt the payload
+type outerHeader struct {
+	cipherID   []byte
+	compressed bool
+	masterSeed []byte
+	iv         []byte
+	kdf        variantDictionary
+}
+
+// readOuterHeader parses the signature, version, and header fields and returns
+// the header with the number of bytes it occupies (the range covered by the
+// header hash and HMAC)
+func readOuterHeader(data []byte) (*outerHeader, int, error) {
+	r := bytes.NewReader(data)
+
+	var prefix [3]uint32
+	if err := binary.Read(r, binary.LittleEndian, &prefix); err != nil {
+		return nil, 0, ErrNotKDBX
+	}
+	if prefix[0] != signature1 || prefix[1] != signature2 {
+		return nil, 0, ErrNotKDBX
+	}
+	if prefix[2]&versionMajorMask != version4 {
+		return nil, 0, fmt.Errorf("%w: %d.%d (only KDBX 4 is supported; save the database in KDBX 4 format)",
+			ErrUnsupportedVersion, prefix[2]>>16, prefix[2]&0xFFFF)
+	}
+
+	header := &outerHeader{}
+	for {
+		id, value, err := readHeaderField(r)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		switch id {
+		case headerEnd:
+			return header, len(data) - r.Len(), nil
+		case headerCipherID:
+			header.cipherID = value
+		case headerCompression:
+			if len(value) != 4 {
+				return nil, 0, fmt.Errorf("%w: invalid compression flags", ErrCorrupted)
+			}
+			header.compressed = binary.LittleEndian.Uint32(value) != 0
+		case headerMasterSeed:
+			header.masterSeed = value
+		case headerEncryptionIV:
+			header.iv = value
+		case headerKDFParameters:
+			if header.kdf, err = parseVariantDictionary(value); err != nil {
+				return nil, 0, err
+			}
+		}
+		// Unknown fields (including public custom data) are ignored
+	}
+}
+
+// writeOuterHeader encodes the signature, version, and header fields
+func writeOuterHeader(header *outerHeader) []byte {
+	var buf bytes.Buffer
+	_ = binary.Write(&buf, binary.LittleEndian, [3]uint32{signature1, signature2, version4})
+
+	compression := make([]byte, 4)
+	if header.compressed {
+		binary.LittleEndian.PutUint32(compression, 1)
+	}
+	writeHeaderField(&buf, headerCipherID, header.cipherID)
+	writeHeaderField(&buf, headerCompression, compression)
+	writeHeaderField(&buf, headerMasterSeed, header.masterSeed)
+	writeHeaderField(&buf, headerEncryptionIV, header.iv)
+	writeHeaderField(&buf, headerKDFParameters, header.kdf.encode())
+	writeHeaderField(&buf, headerEnd, []byte("\r\n\r\n"))
+	return buf.Bytes()
+}
+
+// readHeaderField reads one [id][uint32 size][value] header field
+func readHeaderField(r *bytes.Reader) (byte, []byte, error) {
+	id, err := r.ReadByte()
+	if err != nil {
+		return 0, nil, fmt.Errorf("%w: truncated header", ErrCorrupted)
+	}
+	var size uint32
+	if err := binary.Read(r, binary.LittleEndian, &size); err != nil {
+		return 0, nil, fmt.Errorf("%w: truncated header", ErrCorrupted)
+	}
+	if int64(size) > int64(r.Len()) {
+		return 0, nil, fmt.Errorf("%w: header field exceeds file size", ErrCorrupted)
+	}
+	value := make([]byte, size)
+	if _, err := io.ReadFull(r, value); err != nil {
+		return 0, nil, fmt.Errorf("%w: truncated header", ErrCorrupted)
+	}
+	return id, value, nil
+}
+
+// writeHeaderField writes one [id][uint32 size][value] header field
+func writeHeaderField(buf *bytes.Buffer, id byte, value []byte) {
+	buf.WriteByte(id)
+	_ = binary.Write(buf, binary.LittleEndian, uint32(len(value))) // #nosec G115 -- Header values are small
+	buf.Write(value)
+}
+
+// innerHeader holds the inner random stream settings from the decrypted payload
+type innerHeader struct {
+	streamID  uint32
+	streamKey []byte
+}
+
+// readInnerHeader parses the inner header and returns the remaining XML
+func readInnerHeader(payload []byte) (*innerHeader, []byte, error) {
+	r := bytes.NewReader(payload)
+	header := &innerHeader{}
+	for {
+		id, value, err := readHeaderField(r)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		switch id {
+		case innerHeaderEnd:
+			return header, payload[len(payload)-r.Len():], nil
+		case innerHeaderStreamID:
+			if len(value) != 4 {
+				return nil, nil, fmt.Errorf("%w: invalid inner stream ID", ErrCorrupted)
+			}
+			header.streamID = binary.LittleEndian.Uint32(value)
+		case innerHeaderStreamKey:
+			header.streamKey = value
+		case innerHeaderBinary:
+			// Attachments are not imported
+		}
+	}
+}
+
+// writeInnerHeader encodes the inner header (no attachments)
+func writeInnerHeader(header *innerHeader) []byte {
+	var buf bytes.Buffer
+	streamID := make([]byte, 4)
+	binary.LittleEndian.PutUint32(streamID, header.streamID)
+	writeHeaderField(&buf, innerHeaderStreamID, streamID)
+	writeHeaderField(&buf, innerHeaderStreamKey, header.streamKey)
+	writeHeaderField(&buf, innerHeaderEnd, nil)
+	return buf.Bytes()
+}
+
+func mustDecodeHex(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
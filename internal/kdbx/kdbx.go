@@ -0,0 +1,475 @@
+// Package kdbx reads and writes KeePass KDBX 4 databases protected by a password.
+//
+// Reading supports the AES-256 and ChaCha20 ciphers with the AES-KDF, Argon2d,
+// and Argon2id key derivation functions. Key files, attachments, and KDBX 3
+// databases are not supported.
+package kdbx
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+var (
+	ErrNotKDBX            = errors.New("not a KeePass database")
+	ErrUnsupportedVersion = errors.New("unsupported KeePass database")
+	ErrInvalidPassword    = errors.New("invalid KeePass database password")
+	ErrCorrupted          = errors.New("corrupted KeePass database")
+)
+
+// Standard entry field keys
+const (
+	FieldTitle    = "Title"
+	FieldUserName = "UserName"
+	FieldPassword = "Password"
+	FieldURL      = "URL"
+	FieldNotes    = "Notes"
+)
+
+// Database is the decrypted content of a KDBX file
+type Database struct {
+	Name string
+	Root Group // Top-level group; its name is not part of group paths
+}
+
+// Group is a folder of entries and subgroups
+type Group struct {
+	Name    string
+	Notes   string
+	Entries []Entry
+	Groups  []Group
+}
+
+// Entry is a KeePass entry. Fields holds the standard and custom string fields in file order.
+type Entry struct {
+	Fields     []Field
+	Tags       []string
+	CreatedAt  time.Time
+	ModifiedAt time.Time
+	ExpiresAt  *time.Time // nil = never expires
+	History    []Entry    // Previous versions, oldest first
+}
+
+// Field is a string field; protected fields are encrypted inside the file
+type Field struct {
+	Key       string
+	Value     string
+	Protected bool
+}
+
+// Get returns the value of a field, or "" if it is not set
+func (e *Entry) Get(key string) string {
+	for _, field := range e.Fields {
+		if field.Key == key {
+			return field.Value
+		}
+	}
+	return ""
+}
+
+// Cipher selects the payload cipher when writing
+type Cipher string
+
+const (
+	CipherChaCha20 Cipher = "chacha20"
+	CipherAES256   Cipher = "aes256"
+)
+
+// KDF selects the key derivation function when writing
+type KDF string
+
+const (
+	KDFArgon2id KDF = "argon2id"
+	KDFArgon2d  KDF = "argon2d"
+	KDFAES      KDF = "aes-kdf"
+)
+
+// Defaults for new databases, close to what KeePassXC writes
+const (
+	DefaultArgon2Iterations  = 2
+	DefaultArgon2Memory      = 64 * 1024 * 1024 // bytes
+	DefaultArgon2Parallelism = 2
+	DefaultAESRounds         = 1_000_000
+)
+
+// WriteOptions configures the encryption of a written database (zero values use defaults)
+type WriteOptions struct {
+	Cipher      Cipher // Default ChaCha20
+	KDF         KDF    // Default Argon2id
+	Iterations  uint64 // Argon2 passes or AES-KDF rounds
+	Memory      uint64 // Argon2 memory in bytes
+	Parallelism uint32 // Argon2 lanes
+}
+
+// Validate checks the cipher and key derivation function names
+func (o WriteOptions) Validate() error {
+	switch o.Cipher {
+	case CipherChaCha20, CipherAES256, "":
+	default:
+		return fmt.Errorf("unsupported cipher %q (valid: %s, %s)", o.Cipher, CipherChaCha20, CipherAES256)
+	}
+	switch o.KDF {
+	case KDFArgon2id, KDFArgon2d, KDFAES, "":
+	default:
+		return fmt.Errorf("unsupported key derivation function %q (valid: %s, %s, %s)", o.KDF, KDFArgon2id, KDFArgon2d, KDFAES)
+	}
+	return nil
+}
+
+// Read decrypts and parses a KDBX 4 database. The recycle bin is left out.
+// A wrong password returns ErrInvalidPassword.
+func Read(r io.Reader, password []byte) (*Database, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	header, headerLen, err := readOuterHeader(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(header.masterSeed) != 32 || header.kdf == nil {
+		return nil, fmt.Errorf("%w: missing header fields", ErrCorrupted)
+	}
+	if len(data) < headerLen+64 {
+		return nil, fmt.Errorf("%w: truncated header", ErrCorrupted)
+	}
+	headerBytes := data[:headerLen]
+	headerHash := sha256.Sum256(headerBytes)
+	if !hmac.Equal(headerHash[:], data[headerLen:headerLen+32]) {
+		return nil, fmt.Errorf("%w: header checksum mismatch", ErrCorrupted)
+	}
+
+	transformed, err := transformKey(header.kdf, compositeKey(password))
+	if err != nil {
+		return nil, err
+	}
+	cipherKey, hmacKey := payloadKeys(header.masterSeed, transformed)
+	if !hmac.Equal(data[headerLen+32:headerLen+64], blockHMAC(hmacKey, headerBlockIndex, headerBytes, false)) {
+		return nil, ErrInvalidPassword
+	}
+
+	ciphertext, err := readBlocks(bytes.NewReader(data[headerLen+64:]), hmacKey)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := decryptPayload(header.cipherID, cipherKey, header.iv, ciphertext)
+	if err != nil {
+		return nil, err
+	}
+	if header.compressed {
+		if payload, err = gunzip(payload); err != nil {
+			return nil, err
+		}
+	}
+
+	inner, document, err := readInnerHeader(payload)
+	if err != nil {
+		return nil, err
+	}
+	stream, err := newInnerStream(inner.streamID, inner.streamKey)
+	if err != nil {
+		return nil, err
+	}
+	if document, err = unprotectXML(document, stream); err != nil {
+		return nil, err
+	}
+
+	var file xmlFile
+	if err := xml.Unmarshal(document, &file); err != nil {
+		return nil, fmt.Errorf("%w: invalid XML: %v", ErrCorrupted, err)
+	}
+
+	recycleBin := ""
+	if parseBool(file.Meta.RecycleBinEnabled) {
+		recycleBin = file.Meta.RecycleBinUUID
+	}
+	return &Database{
+		Name: file.Meta.DatabaseName,
+		Root: fromXMLGroup(file.Root.Group, recycleBin),
+	}, nil
+}
+
+// Write encrypts db as a KDBX 4 database
+func Write(w io.Writer, db *Database, password []byte, opts WriteOptions) error {
+	if len(password) == 0 {
+		return fmt.Errorf("a password is required to write a KeePass database")
+	}
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+
+	header := &outerHeader{compressed: true}
+	var err error
+	if header.masterSeed, err = randomBytes(32); err != nil {
+		return err
+	}
+	switch opts.Cipher {
+	case CipherChaCha20, "":
+		header.cipherID = cipherChaCha20UUID
+		header.iv, err = randomBytes(12)
+	case CipherAES256:
+		header.cipherID = cipherAES256UUID
+		header.iv, err = randomBytes(16)
+	}
+	if err != nil {
+		return err
+	}
+	if header.kdf, err = kdfParameters(opts); err != nil {
+		return err
+	}
+
+	// Payload: inner header and XML with protected values, compressed and encrypted
+	streamKey, err := randomBytes(64)
+	if err != nil {
+		return err
+	}
+	stream, err := newInnerStream(innerStreamChaCha20, streamKey)
+	if err != nil {
+		return err
+	}
+	document, err := xml.MarshalIndent(toXMLFile(db), "", "\t")
+	if err != nil {
+		return err
+	}
+	if document, err = protectXML(document, stream); err != nil {
+		return err
+	}
+
+	var payload bytes.Buffer
+	gz := gzip.NewWriter(&payload)
+	_, _ = gz.Write(writeInnerHeader(&innerHeader{streamID: innerStreamChaCha20, streamKey: streamKey}))
+	_, _ = gz.Write([]byte(xml.Header))
+	_, _ = gz.Write(document)
+	if err := gz.Close(); err != nil {
+		return err
+	}
+
+	transformed, err := transformKey(header.kdf, compositeKey(password))
+	if err != nil {
+		return err
+	}
+	cipherKey, hmacKey := payloadKeys(header.masterSeed, transformed)
+	ciphertext, err := encryptPayload(header.cipherID, cipherKey, header.iv, payload.Bytes())
+	if err != nil {
+		return err
+	}
+
+	var out bytes.Buffer
+	headerBytes := writeOuterHeader(header)
+	headerHash := sha256.Sum256(headerBytes)
+	out.Write(headerBytes)
+	out.Write(headerHash[:])
+	out.Write(blockHMAC(hmacKey, headerBlockIndex, headerBytes, false))
+	writeBlocks(&out, ciphertext, hmacKey)
+
+	_, err = w.Write(out.Bytes())
+	return err
+}
+
+// kdfParameters builds the KDF header parameters with a fresh salt
+func kdfParameters(opts WriteOptions) (variantDictionary, error) {
+	salt, err := randomBytes(32)
+	if err != nil {
+		return nil, err
+	}
+
+	var params variantDictionary
+	switch opts.KDF {
+	case KDFAES:
+		params.setBytes("$UUID", kdfAESUUID)
+		params.setUint64("R", withDefault(opts.Iterations, DefaultAESRounds))
+		params.setBytes("S", salt)
+	case KDFArgon2id, KDFArgon2d, "":
+		uuid := kdfArgon2idUUID
+		if opts.KDF == KDFArgon2d {
+			uuid = kdfArgon2dUUID
+		}
+		params.setBytes("$UUID", uuid)
+		params.setBytes("S", salt)
+		params.setUint32("P", uint32(withDefault(uint64(opts.Parallelism), DefaultArgon2Parallelism))) // #nosec G115 -- Validated by transformKey
+		params.setUint64("M", withDefault(opts.Memory, DefaultArgon2Memory))
+		params.setUint64("I", withDefault(opts.Iterations, DefaultArgon2Iterations))
+		params.setUint32("V", argon2Version)
+	}
+	return params, nil
+}
+
+// fromXMLGroup converts a parsed group, skipping the recycle bin
+func fromXMLGroup(g xmlGroup, recycleBin string) Group {
+	group := Group{Name: g.Name, Notes: g.Notes}
+	for _, entry := range g.Entries {
+		group.Entries = append(group.Entries, fromXMLEntry(entry))
+	}
+	for _, child := range g.Groups {
+		if recycleBin != "" && child.UUID == recycleBin {
+			continue
+		}
+		group.Groups = append(group.Groups, fromXMLGroup(child, recycleBin))
+	}
+	return group
+}
+
+// fromXMLEntry converts a parsed entry and its history
+func fromXMLEntry(e xmlEntry) Entry {
+	entry := Entry{
+		Tags: strings.FieldsFunc(e.Tags, func(r rune) bool {
+			return r == ';' || r == ','
+		}),
+		CreatedAt:  parseTime(e.Times.CreationTime),
+		ModifiedAt: parseTime(e.Times.LastModificationTime),
+	}
+	for i := range entry.Tags {
+		entry.Tags[i] = strings.TrimSpace(entry.Tags[i])
+	}
+	if parseBool(e.Times.Expires) {
+		expiry := parseTime(e.Times.ExpiryTime)
+		entry.ExpiresAt = &expiry
+	}
+	for _, s := range e.Strings {
+		entry.Fields = append(entry.Fields, Field{
+			Key:       s.Key,
+			Value:     s.Value.Value,
+			Protected: parseBool(s.Value.ProtectInMemory),
+		})
+	}
+	if e.History != nil {
+		for _, previous := range e.History.Entries {
+			entry.History = append(entry.History, fromXMLEntry(previous))
+		}
+	}
+	return entry
+}
+
+// toXMLFile converts a database for writing, with fresh UUIDs
+func toXMLFile(db *Database) xmlFile {
+	name := db.Name
+	if name == "" {
+		name = db.Root.Name
+	}
+	return xmlFile{
+		Meta: xmlMeta{
+			Generator:    "pass-cli",
+			DatabaseName: name,
+			MemoryProtection: xmlMemoryProtection{
+				ProtectTitle:    formatBool(false),
+				ProtectUserName: formatBool(false),
+				ProtectPassword: formatBool(true),
+				ProtectURL:      formatBool(false),
+				ProtectNotes:    formatBool(false),
+			},
+			RecycleBinEnabled: formatBool(false),
+			RecycleBinUUID:    base64.StdEncoding.EncodeToString(make([]byte, 16)),
+		},
+		Root: xmlRoot{Group: toXMLGroup(db.Root, time.Now())},
+	}
+}
+
+func toXMLGroup(g Group, now time.Time) xmlGroup {
+	group := xmlGroup{
+		UUID:   newUUID(),
+		Name:   g.Name,
+		Notes:  g.Notes,
+		IconID: 48, // Folder
+		Times:  toXMLTimes(now, now, nil),
+	}
+	for _, entry := range g.Entries {
+		group.Entries = append(group.Entries, toXMLEntry(entry, newUUID(), now))
+	}
+	for _, child := range g.Groups {
+		group.Groups = append(group.Groups, toXMLGroup(child, now))
+	}
+	return group
+}
+
+func toXMLEntry(e Entry, uuid string, now time.Time) xmlEntry {
+	created, modified := e.CreatedAt, e.ModifiedAt
+	if created.IsZero() {
+		created = now
+	}
+	if modified.IsZero() {
+		modified = created
+	}
+
+	entry := xmlEntry{
+		UUID:  uuid,
+		Tags:  strings.Join(e.Tags, ";"),
+		Times: toXMLTimes(created, modified, e.ExpiresAt),
+	}
+	for _, field := range e.Fields {
+		value := xmlValue{Value: field.Value}
+		if field.Protected {
+			value.ProtectInMemory = "True"
+		}
+		entry.Strings = append(entry.Strings, xmlString{Key: field.Key, Value: value})
+	}
+	if len(e.History) > 0 {
+		entry.History = &xmlHistory{}
+		for _, previous := range e.History {
+			// History versions share the entry's UUID
+			entry.History.Entries = append(entry.History.Entries, toXMLEntry(previous, uuid, now))
+		}
+	}
+	return entry
+}
+
+func toXMLTimes(created, modified time.Time, expires *time.Time) xmlTimes {
+	times := xmlTimes{
+		CreationTime:         formatTime(created),
+		LastModificationTime: formatTime(modified),
+		LastAccessTime:       formatTime(modified),
+		ExpiryTime:           formatTime(modified),
+		Expires:              formatBool(expires != nil),
+		LocationChanged:      formatTime(modified),
+	}
+	if expires != nil {
+		times.ExpiryTime = formatTime(*expires)
+	}
+	return times
+}
+
+// gunzip decompresses the payload
+func gunzip(data []byte) ([]byte, error) {
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCorrupted, err)
+	}
+	defer func() { _ = reader.Close() }()
+
+	out, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCorrupted, err)
+	}
+	return out, nil
+}
+
+func randomBytes(n int) ([]byte, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return nil, fmt.Errorf("failed to generate random bytes: %w", err)
+	}
+	return b, nil
+}
+
+// newUUID returns a random base64-encoded KeePass UUID
+func newUUID() string {
+	b, _ := randomBytes(16)
+	return base64.StdEncoding.EncodeToString(b)
+}
+
+func withDefault(value, fallback uint64) uint64 {
+	if value == 0 {
+		return fallback
+	}
+	return value
+}
@@ -0,0 +1,252 @@
+package kdbx
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// testOptions keeps key derivation cheap in tests
+func testOptions(cipher Cipher, kdf KDF) WriteOptions {
+	opts := WriteOptions{Cipher: cipher, KDF: kdf, Iterations: 1, Memory: 1024 * 1024, Parallelism: 2}
+	if kdf == KDFAES {
+		opts.Iterations = 100
+	}
+	return opts
+}
+
+func testDatabase() *Database {
+	expiry := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+	created := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	return &Database{
+		Name: "Test",
+		Root: Group{
+			Name: "Root",
+			Entries: []Entry{{
+				Fields: []Field{
+					{Key: FieldTitle, Value: "top-level"},
+					{Key: FieldPassword, Value: "top-secret", Protected: true},
+				},
+			}},
+			Groups: []Group{{
+				Name: "Work",
+				Groups: []Group{{
+					Name: "Email",
+					Entries: []Entry{{
+						Fields: []Field{
+							{Key: FieldTitle, Value: "mail"},
+							{Key: FieldUserName, Value: "alice"},
+							{Key: FieldPassword, Value: "p<a>ss&word", Protected: true},
+							{Key: FieldURL, Value: "https://mail.example.com"},
+							{Key: FieldNotes, Value: "line 1\nline 2"},
+							{Key: "PIN", Value: "1234", Protected: true},
+							{Key: "Department", Value: "IT"},
+						},
+						Tags:       []string{"work", "email"},
+						CreatedAt:  created,
+						ModifiedAt: created.Add(time.Hour),
+						ExpiresAt:  &expiry,
+						History: []Entry{{
+							Fields: []Field{
+								{Key: FieldTitle, Value: "mail"},
+								{Key: FieldPassword, Value: "old-password", Protected: true},
+							},
+							CreatedAt:  created,
+							ModifiedAt: created,
+						}},
+					}},
+				}},
+			}},
+		},
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	password := []byte("keepass-password")
+
+	for _, cipher := range []Cipher{CipherChaCha20, CipherAES256} {
+		for _, kdf := range []KDF{KDFArgon2id, KDFArgon2d, KDFAES} {
+			t.Run(fmt.Sprintf("%s/%s", cipher, kdf), func(t *testing.T) {
+				var buf bytes.Buffer
+				if err := Write(&buf, testDatabase(), password, testOptions(cipher, kdf)); err != nil {
+					t.Fatalf("Write() error = %v", err)
+				}
+
+				db, err := Read(bytes.NewReader(buf.Bytes()), password)
+				if err != nil {
+					t.Fatalf("Read() error = %v", err)
+				}
+
+				if db.Name != "Test" || db.Root.Name != "Root" {
+					t.Errorf("names = %q/%q", db.Name, db.Root.Name)
+				}
+				if len(db.Root.Entries) != 1 || db.Root.Entries[0].Get(FieldPassword) != "top-secret" {
+					t.Errorf("root entries = %+v", db.Root.Entries)
+				}
+				if len(db.Root.Groups) != 1 || len(db.Root.Groups[0].Groups) != 1 {
+					t.Fatalf("groups = %+v", db.Root.Groups)
+				}
+
+				email := db.Root.Groups[0].Groups[0]
+				if email.Name != "Email" || len(email.Entries) != 1 {
+					t.Fatalf("nested group = %+v", email)
+				}
+				entry := email.Entries[0]
+				want := testDatabase().Root.Groups[0].Groups[0].Entries[0]
+				if len(entry.Fields) != len(want.Fields) {
+					t.Fatalf("fields = %+v", entry.Fields)
+				}
+				for i, field := range want.Fields {
+					if entry.Fields[i] != field {
+						t.Errorf("field %d = %+v, want %+v", i, entry.Fields[i], field)
+					}
+				}
+				if len(entry.Tags) != 2 || entry.Tags[0] != "work" || entry.Tags[1] != "email" {
+					t.Errorf("tags = %v", entry.Tags)
+				}
+				if !entry.CreatedAt.Equal(want.CreatedAt) || !entry.ModifiedAt.Equal(want.ModifiedAt) {
+					t.Errorf("times = %v/%v", entry.CreatedAt, entry.ModifiedAt)
+				}
+				if entry.ExpiresAt == nil || !entry.ExpiresAt.Equal(*want.ExpiresAt) {
+					t.Errorf("expiry = %v", entry.ExpiresAt)
+				}
+				if len(entry.History) != 1 || entry.History[0].Get(FieldPassword) != "old-password" {
+					t.Errorf("history = %+v", entry.History)
+				}
+			})
+		}
+	}
+}
+
+func TestRead_WrongPassword(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, testDatabase(), []byte("right"), testOptions(CipherChaCha20, KDFArgon2id)); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	if _, err := Read(bytes.NewReader(buf.Bytes()), []byte("wrong")); !errors.Is(err, ErrInvalidPassword) {
+		t.Errorf("Read() error = %v, want ErrInvalidPassword", err)
+	}
+}
+
+func TestRead_Tampered(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, testDatabase(), []byte("pw"), testOptions(CipherAES256, KDFAES)); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	data := buf.Bytes()
+	data[len(data)-50] ^= 0xFF
+
+	if _, err := Read(bytes.NewReader(data), []byte("pw")); !errors.Is(err, ErrCorrupted) {
+		t.Errorf("Read() error = %v, want ErrCorrupted", err)
+	}
+}
+
+func TestRead_NotKDBX(t *testing.T) {
+	if _, err := Read(bytes.NewReader([]byte("service,username,password\n")), []byte("pw")); !errors.Is(err, ErrNotKDBX) {
+		t.Errorf("Read() error = %v, want ErrNotKDBX", err)
+	}
+
+	kdbx3 := []byte{0x03, 0xD9, 0xA2, 0x9A, 0x67, 0xFB, 0x4B, 0xB5, 0x01, 0x00, 0x03, 0x00}
+	if _, err := Read(bytes.NewReader(kdbx3), []byte("pw")); !errors.Is(err, ErrUnsupportedVersion) {
+		t.Errorf("Read() error = %v, want ErrUnsupportedVersion", err)
+	}
+}
+
+func TestRead_SkipsRecycleBin(t *testing.T) {
+	document := []byte(`<?xml version="1.0" encoding="utf-8" standalone="yes"?>
+<KeePassFile>
+	<Meta><RecycleBinEnabled>True</RecycleBinEnabled><RecycleBinUUID>YmluYmluYmluYmluYmluYg==</RecycleBinUUID></Meta>
+	<Root><Group><Name>Root</Name>
+		<Group><UUID>YmluYmluYmluYmluYmluYg==</UUID><Name>Recycle Bin</Name></Group>
+		<Group><UUID>a2VlcGtlZXBrZWVwa2VlcA==</UUID><Name>Keep</Name></Group>
+	</Group></Root>
+</KeePassFile>`)
+
+	var file xmlFile
+	stream, _ := newInnerStream(innerStreamChaCha20, []byte("key"))
+	document, err := unprotectXML(append([]byte("\xef\xbb\xbf"), document...), stream)
+	if err != nil {
+		t.Fatalf("unprotectXML() error = %v", err)
+	}
+	if err := xml.Unmarshal(document, &file); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	root := fromXMLGroup(file.Root.Group, file.Meta.RecycleBinUUID)
+	if len(root.Groups) != 1 || root.Groups[0].Name != "Keep" {
+		t.Errorf("groups = %+v", root.Groups)
+	}
+}
+
+func TestProtectXML_Salsa20(t *testing.T) {
+	document := []byte(`<Entry><String><Key>Password</Key><Value ProtectInMemory="True">secret</Value></String>` +
+		`<String><Key>PIN</Key><Value ProtectInMemory="True">1234</Value></String></Entry>`)
+	key := []byte("inner stream key")
+
+	protect, _ := newInnerStream(innerStreamSalsa20, key)
+	protected, err := protectXML(document, protect)
+	if err != nil {
+		t.Fatalf("protectXML() error = %v", err)
+	}
+	if bytes.Contains(protected, []byte("secret")) || !bytes.Contains(protected, []byte(`Protected="True"`)) {
+		t.Fatalf("protected document = %s", protected)
+	}
+
+	unprotect, _ := newInnerStream(innerStreamSalsa20, key)
+	plain, err := unprotectXML(protected, unprotect)
+	if err != nil {
+		t.Fatalf("unprotectXML() error = %v", err)
+	}
+	if !bytes.Equal(plain, document) {
+		t.Errorf("round trip = %s", plain)
+	}
+}
+
+func TestArgon2(t *testing.T) {
+	// RFC 9106 section 5.1 test vector
+	password := bytes.Repeat([]byte{0x01}, 32)
+	salt := bytes.Repeat([]byte{0x02}, 16)
+	secret := bytes.Repeat([]byte{0x03}, 8)
+	data := bytes.Repeat([]byte{0x04}, 12)
+	got := deriveKey(argon2d, password, salt, secret, data, 3, 32, 4, 32)
+	want := "512b391b6f1162975371d30919734294f868e3be3984f3c1a13a4db9fabe4acb"
+	if hex.EncodeToString(got) != want {
+		t.Errorf("Argon2d = %x, want %s", got, want)
+	}
+
+	// Argon2id must match the x/crypto implementation
+	got = deriveKey(argon2id, []byte("password"), []byte("somesalt"), nil, nil, 2, 256, 2, 32)
+	if want := argon2.IDKey([]byte("password"), []byte("somesalt"), 2, 256, 2, 32); !bytes.Equal(got, want) {
+		t.Errorf("Argon2id = %x, want %x", got, want)
+	}
+}
+
+func TestArgon2MemoryLimit(t *testing.T) {
+	params, err := kdfParameters(WriteOptions{KDF: KDFArgon2id, Iterations: 1, Parallelism: 1, Memory: 4 << 40})
+	if err != nil {
+		t.Fatalf("kdfParameters() error = %v", err)
+	}
+	if _, err := transformKey(params, make([]byte, 32)); !errors.Is(err, ErrCorrupted) {
+		t.Errorf("transformKey() with 4 TiB of memory error = %v, want ErrCorrupted", err)
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	want := time.Date(2024, 2, 29, 12, 30, 0, 0, time.UTC)
+	if got := parseTime(formatTime(want)); !got.Equal(want) {
+		t.Errorf("parseTime(formatTime()) = %v", got)
+	}
+	if got := parseTime("2024-02-29T12:30:00Z"); !got.Equal(want) {
+		t.Errorf("parseTime(ISO) = %v", got)
+	}
+	if got := parseTime(""); !got.IsZero() {
+		t.Errorf("parseTime(\"\") = %v", got)
+	}
+}
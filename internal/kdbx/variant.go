@@ -0,0 +1,148 @@
+package kdbx
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// Variant dictionary value types
+const (
+	variantEnd       byte = 0x00
+	variantUInt32    byte = 0x04
+	variantUInt64    byte = 0x05
+	variantBool      byte = 0x08
+	variantInt32     byte = 0x0C
+	variantInt64     byte = 0x0D
+	variantString    byte = 0x18
+	variantByteArray byte = 0x42
+)
+
+const (
+	variantVersion      uint16 = 0x0100
+	variantCriticalMask uint16 = 0xFF00
+)
+
+// variantItem is one typed entry of a variant dictionary
+type variantItem struct {
+	name  string
+	kind  byte
+	value []byte
+}
+
+// variantDictionary is the typed key/value list KDBX 4 uses for KDF parameters
+type variantDictionary []variantItem
+
+// parseVariantDictionary decodes a serialized variant dictionary
+func parseVariantDictionary(data []byte) (variantDictionary, error) {
+	r := bytes.NewReader(data)
+
+	var version uint16
+	if err := binary.Read(r, binary.LittleEndian, &version); err != nil {
+		return nil, fmt.Errorf("%w: truncated KDF parameters", ErrCorrupted)
+	}
+	if version&variantCriticalMask > variantVersion&variantCriticalMask {
+		return nil, fmt.Errorf("%w: KDF parameter format %#x", ErrUnsupportedVersion, version)
+	}
+
+	var dict variantDictionary
+	for {
+		kind, err := r.ReadByte()
+		if err != nil {
+			return nil, fmt.Errorf("%w: truncated KDF parameters", ErrCorrupted)
+		}
+		if kind == variantEnd {
+			return dict, nil
+		}
+
+		name, err := readVariantBytes(r)
+		if err != nil {
+			return nil, err
+		}
+		value, err := readVariantBytes(r)
+		if err != nil {
+			return nil, err
+		}
+		dict = append(dict, variantItem{name: string(name), kind: kind, value: value})
+	}
+}
+
+// readVariantBytes reads an int32 length-prefixed byte string
+func readVariantBytes(r *bytes.Reader) ([]byte, error) {
+	var size int32
+	if err := binary.Read(r, binary.LittleEndian, &size); err != nil || size < 0 || int64(size) > int64(r.Len()) {
+		return nil, fmt.Errorf("%w: invalid KDF parameters", ErrCorrupted)
+	}
+	value := make([]byte, size)
+	if _, err := io.ReadFull(r, value); err != nil {
+		return nil, fmt.Errorf("%w: invalid KDF parameters", ErrCorrupted)
+	}
+	return value, nil
+}
+
+// encode serializes the dictionary
+func (d variantDictionary) encode() []byte {
+	var buf bytes.Buffer
+	_ = binary.Write(&buf, binary.LittleEndian, variantVersion)
+	for _, item := range d {
+		buf.WriteByte(item.kind)
+		_ = binary.Write(&buf, binary.LittleEndian, int32(len(item.name))) // #nosec G115 -- Parameter names are short
+		buf.WriteString(item.name)
+		_ = binary.Write(&buf, binary.LittleEndian, int32(len(item.value))) // #nosec G115 -- Parameter values are short
+		buf.Write(item.value)
+	}
+	buf.WriteByte(variantEnd)
+	return buf.Bytes()
+}
+
+// get returns the item with the given name and type
+func (d variantDictionary) get(name string, kind byte) ([]byte, bool) {
+	for _, item := range d {
+		if item.name == name && item.kind == kind {
+			return item.value, true
+		}
+	}
+	return nil, false
+}
+
+// bytes returns a byte array parameter (nil if absent)
+func (d variantDictionary) bytes(name string) []byte {
+	value, _ := d.get(name, variantByteArray)
+	return value
+}
+
+// uint32 returns a UInt32 parameter
+func (d variantDictionary) uint32(name string) (uint32, bool) {
+	value, ok := d.get(name, variantUInt32)
+	if !ok || len(value) != 4 {
+		return 0, false
+	}
+	return binary.LittleEndian.Uint32(value), true
+}
+
+// uint64 returns a UInt64 parameter
+func (d variantDictionary) uint64(name string) (uint64, bool) {
+	value, ok := d.get(name, variantUInt64)
+	if !ok || len(value) != 8 {
+		return 0, false
+	}
+	return binary.LittleEndian.Uint64(value), true
+}
+
+// setBytes, setUint32, and setUint64 append typed parameters
+func (d *variantDictionary) setBytes(name string, value []byte) {
+	*d = append(*d, variantItem{name: name, kind: variantByteArray, value: value})
+}
+
+func (d *variantDictionary) setUint32(name string, value uint32) {
+	b := make([]byte, 4)
+	binary.LittleEndian.PutUint32(b, value)
+	*d = append(*d, variantItem{name: name, kind: variantUInt32, value: b})
+}
+
+func (d *variantDictionary) setUint64(name string, value uint64) {
+	b := make([]byte, 8)
+	binary.LittleEndian.PutUint64(b, value)
+	*d = append(*d, variantItem{name: name, kind: variantUInt64, value: b})
+}
@@ -0,0 +1,235 @@
+package kdbx
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// kdbxEpochOffset is the number of seconds between 0001-01-01 and the Unix epoch.
+// KDBX 4 stores times as base64 little-endian seconds since 0001-01-01 UTC.
+const kdbxEpochOffset = 62135596800
+
+type xmlFile struct {
+	XMLName xml.Name `xml:"KeePassFile"`
+	Meta    xmlMeta  `xml:"Meta"`
+	Root    xmlRoot  `xml:"Root"`
+}
+
+type xmlMeta struct {
+	Generator         string              `xml:"Generator"`
+	DatabaseName      string              `xml:"DatabaseName"`
+	MemoryProtection  xmlMemoryProtection `xml:"MemoryProtection"`
+	RecycleBinEnabled string              `xml:"RecycleBinEnabled"`
+	RecycleBinUUID    string              `xml:"RecycleBinUUID"`
+}
+
+type xmlMemoryProtection struct {
+	ProtectTitle    string `xml:"ProtectTitle"`
+	ProtectUserName string `xml:"ProtectUserName"`
+	ProtectPassword string `xml:"ProtectPassword"`
+	ProtectURL      string `xml:"ProtectURL"`
+	ProtectNotes    string `xml:"ProtectNotes"`
+}
+
+type xmlRoot struct {
+	Group          xmlGroup `xml:"Group"`
+	DeletedObjects struct{} `xml:"DeletedObjects"`
+}
+
+type xmlGroup struct {
+	UUID    string     `xml:"UUID"`
+	Name    string     `xml:"Name"`
+	Notes   string     `xml:"Notes"`
+	IconID  int        `xml:"IconID"`
+	Times   xmlTimes   `xml:"Times"`
+	Entries []xmlEntry `xml:"Entry"`
+	Groups  []xmlGroup `xml:"Group"`
+}
+
+type xmlEntry struct {
+	UUID    string      `xml:"UUID"`
+	IconID  int         `xml:"IconID"`
+	Tags    string      `xml:"Tags"`
+	Times   xmlTimes    `xml:"Times"`
+	Strings []xmlString `xml:"String"`
+	History *xmlHistory `xml:"History,omitempty"`
+}
+
+type xmlHistory struct {
+	Entries []xmlEntry `xml:"Entry"`
+}
+
+type xmlString struct {
+	Key   string   `xml:"Key"`
+	Value xmlValue `xml:"Value"`
+}
+
+// xmlValue is a string value. Protected values are stored XORed with the inner
+// random stream and base64-encoded; after unprotectXML they carry
+// ProtectInMemory instead and hold plaintext.
+type xmlValue struct {
+	Value           string `xml:",chardata"`
+	Protected       string `xml:"Protected,attr,omitempty"`
+	ProtectInMemory string `xml:"ProtectInMemory,attr,omitempty"`
+}
+
+type xmlTimes struct {
+	CreationTime         string `xml:"CreationTime"`
+	LastModificationTime string `xml:"LastModificationTime"`
+	LastAccessTime       string `xml:"LastAccessTime"`
+	ExpiryTime           string `xml:"ExpiryTime"`
+	Expires              string `xml:"Expires"`
+	UsageCount           int    `xml:"UsageCount"`
+	LocationChanged      string `xml:"LocationChanged"`
+}
+
+// unprotectXML decodes protected values in document order, which is the order
+// the inner random stream was applied in
+func unprotectXML(data []byte, stream cipher.Stream) ([]byte, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")) // UTF-8 byte order mark
+	return rewriteValues(data, func(start *xml.StartElement, value []byte) ([]byte, error) {
+		if !removeAttr(start, "Protected") {
+			return value, nil
+		}
+		start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: "ProtectInMemory"}, Value: "True"})
+
+		decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(value)))
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid protected value", ErrCorrupted)
+		}
+		stream.XORKeyStream(decoded, decoded)
+		return decoded, nil
+	})
+}
+
+// protectXML is the inverse of unprotectXML for values marked ProtectInMemory
+func protectXML(data []byte, stream cipher.Stream) ([]byte, error) {
+	return rewriteValues(data, func(start *xml.StartElement, value []byte) ([]byte, error) {
+		if !removeAttr(start, "ProtectInMemory") {
+			return value, nil
+		}
+		start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: "Protected"}, Value: "True"})
+
+		encrypted := make([]byte, len(value))
+		stream.XORKeyStream(encrypted, value)
+		return []byte(base64.StdEncoding.EncodeToString(encrypted)), nil
+	})
+}
+
+// rewriteValues re-encodes an XML document, passing every <Value> element's
+// start tag and text through rewrite
+func rewriteValues(data []byte, rewrite func(start *xml.StartElement, value []byte) ([]byte, error)) ([]byte, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	var out bytes.Buffer
+	encoder := xml.NewEncoder(&out)
+
+	for {
+		token, err := decoder.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid XML: %v", ErrCorrupted, err)
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "Value" {
+			if err := encoder.EncodeToken(xml.CopyToken(token)); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		// Collect the text up to </Value>
+		start = start.Copy()
+		var value []byte
+		for {
+			inner, err := decoder.RawToken()
+			if err != nil {
+				return nil, fmt.Errorf("%w: invalid XML: %v", ErrCorrupted, err)
+			}
+			if text, ok := inner.(xml.CharData); ok {
+				value = append(value, text...)
+				continue
+			}
+			if _, ok := inner.(xml.EndElement); ok {
+				break
+			}
+		}
+
+		value, err = rewrite(&start, value)
+		if err != nil {
+			return nil, err
+		}
+		if err := encoder.EncodeToken(start); err != nil {
+			return nil, err
+		}
+		if len(value) > 0 {
+			if err := encoder.EncodeToken(xml.CharData(value)); err != nil {
+				return nil, err
+			}
+		}
+		if err := encoder.EncodeToken(start.End()); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := encoder.Flush(); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// removeAttr deletes attr="True" from the element and reports whether it was set
+func removeAttr(start *xml.StartElement, name string) bool {
+	for i, attr := range start.Attr {
+		if attr.Name.Local == name {
+			start.Attr = append(start.Attr[:i], start.Attr[i+1:]...)
+			return strings.EqualFold(attr.Value, "True")
+		}
+	}
+	return false
+}
+
+// parseTime reads a KDBX 4 (base64 seconds) or KDBX 3 (ISO 8601) timestamp
+func parseTime(value string) time.Time {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}
+	}
+	if raw, err := base64.StdEncoding.DecodeString(value); err == nil && len(raw) == 8 {
+		seconds := int64(binary.LittleEndian.Uint64(raw)) // #nosec G115 -- Two's complement round trip
+		return time.Unix(seconds-kdbxEpochOffset, 0).UTC()
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.UTC()
+	}
+	return time.Time{}
+}
+
+// formatTime writes a KDBX 4 timestamp
+func formatTime(t time.Time) string {
+	var raw [8]byte
+	binary.LittleEndian.PutUint64(raw[:], uint64(t.Unix()+kdbxEpochOffset)) // #nosec G115 -- Two's complement round trip
+	return base64.StdEncoding.EncodeToString(raw[:])
+}
+
+// parseBool reads a KeePass boolean ("True"/"False")
+func parseBool(value string) bool {
+	return strings.EqualFold(strings.TrimSpace(value), "True")
+}
+
+// formatBool writes a KeePass boolean
+func formatBool(value bool) string {
+	if value {
+		return "True"
+	}
+	return "False"
+}
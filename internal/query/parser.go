@@ -0,0 +1,388 @@
+package query
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF    tokenKind = iota
+	tokenWord             // Field name, search word or unquoted value
+	tokenString           // Quoted value
+	tokenOp               // Comparison operator
+	tokenAnd
+	tokenOr
+	tokenNot
+	tokenLParen
+	tokenRParen
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func (t token) String() string {
+	if t.kind == tokenEOF {
+		return "end of query"
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+// comparisonOps are the comparison operators, longest first
+var comparisonOps = []string{"==", "!=", "!~", "<=", ">=", "~", "<", ">", "=", ":"}
+
+// keywords are the word forms of the boolean operators
+var keywords = map[string]tokenKind{
+	"and": tokenAnd,
+	"or":  tokenOr,
+	"not": tokenNot,
+}
+
+// tokenize splits an expression into tokens
+func tokenize(input string) ([]token, error) {
+	var tokens []token
+	expectValue := false // The previous token was a comparison operator
+	for i := 0; i < len(input); {
+		c := input[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+			continue
+		case c == '(':
+			tokens = append(tokens, token{tokenLParen, "(", i})
+			i++
+		case c == ')':
+			tokens = append(tokens, token{tokenRParen, ")", i})
+			i++
+		case strings.HasPrefix(input[i:], "&&"):
+			tokens = append(tokens, token{tokenAnd, "&&", i})
+			i += 2
+		case strings.HasPrefix(input[i:], "||"):
+			tokens = append(tokens, token{tokenOr, "||", i})
+			i += 2
+		case c == '"' || c == '\'':
+			text, end, err := readQuoted(input, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{tokenString, text, i})
+			i = end
+		case expectValue:
+			// Values run to the next space so URLs and globs need no quotes
+			start := i
+			for i < len(input) && isValueByte(input, i) {
+				i++
+			}
+			tokens = append(tokens, token{tokenWord, input[start:i], start})
+		default:
+			if op := matchOp(input[i:]); op != "" {
+				start := i
+				i += len(op)
+				// "uses:>5" is the same as "uses>5"
+				if op == ":" {
+					if ordered := matchOp(input[i:]); ordered != "" && ordered != ":" && ordered != "~" && ordered != "!~" {
+						op = ordered
+						i += len(ordered)
+					}
+				}
+				if op == "=" {
+					op = "=="
+				}
+				tokens = append(tokens, token{tokenOp, op, start})
+				expectValue = true
+				continue
+			}
+			if c == '!' {
+				tokens = append(tokens, token{tokenNot, "!", i})
+				i++
+				continue
+			}
+			start := i
+			for i < len(input) && isWordByte(input, start, i) {
+				i++
+			}
+			if i == start {
+				return nil, &ParseError{Pos: i, Msg: fmt.Sprintf("unexpected character %q", c)}
+			}
+			word := input[start:i]
+			if kind, ok := keywords[strings.ToLower(word)]; ok {
+				tokens = append(tokens, token{kind, word, start})
+			} else {
+				tokens = append(tokens, token{tokenWord, word, start})
+			}
+		}
+		expectValue = false
+	}
+	return append(tokens, token{kind: tokenEOF, pos: len(input)}), nil
+}
+
+// matchOp returns the comparison operator at the start of s, if any
+func matchOp(s string) string {
+	for _, op := range comparisonOps {
+		if strings.HasPrefix(s, op) {
+			return op
+		}
+	}
+	return ""
+}
+
+// isWordByte reports whether the byte at i continues the unquoted word that
+// began at start. A colon ends the word only when it follows a field-like
+// name, so "https://example.com" stays one search word.
+func isWordByte(input string, start, i int) bool {
+	c := input[i]
+	if unicode.IsSpace(rune(c)) || c == '(' || c == ')' || c == '"' || c == '\'' {
+		return false
+	}
+	rest := input[i:]
+	if c == ':' {
+		return !isFieldName(input[start:i]) || strings.HasPrefix(rest, "://")
+	}
+	return matchOp(rest) == "" && !strings.HasPrefix(rest, "&&") && !strings.HasPrefix(rest, "||")
+}
+
+// isValueByte reports whether the byte at i continues an unquoted value
+func isValueByte(input string, i int) bool {
+	c := input[i]
+	if unicode.IsSpace(rune(c)) || c == '(' || c == ')' {
+		return false
+	}
+	rest := input[i:]
+	return !strings.HasPrefix(rest, "&&") && !strings.HasPrefix(rest, "||")
+}
+
+// isFieldName reports whether s looks like a field name (letters and dashes)
+func isFieldName(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if !unicode.IsLetter(r) && r != '-' {
+			return false
+		}
+	}
+	return true
+}
+
+// readQuoted reads a quoted string starting at input[start]; backslash escapes
+// the quote character and itself
+func readQuoted(input string, start int) (string, int, error) {
+	quote := input[start]
+	var b strings.Builder
+	for i := start + 1; i < len(input); i++ {
+		c := input[i]
+		switch {
+		case c == '\\' && i+1 < len(input) && (input[i+1] == quote || input[i+1] == '\\'):
+			b.WriteByte(input[i+1])
+			i++
+		case c == quote:
+			return b.String(), i + 1, nil
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", 0, &ParseError{Pos: start, Msg: "unterminated string"}
+}
+
+// parser is a recursive-descent parser:
+//
+//	or    = and { ("||" | "or") and }
+//	and   = unary { ["&&" | "and"] unary }
+//	unary = ("!" | "not") unary | "(" or ")" | term
+//	term  = field op value | word
+type parser struct {
+	tokens []token
+	pos    int
+	now    time.Time // Reference time for ages such as 30d
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokenOr {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		switch p.peek().kind {
+		case tokenAnd:
+			p.next()
+		case tokenWord, tokenString, tokenNot, tokenLParen:
+			// Terms written next to each other must all match
+		default:
+			return left, nil
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = andNode{left, right}
+	}
+}
+
+func (p *parser) parseUnary() (node, error) {
+	switch p.peek().kind {
+	case tokenNot:
+		p.next()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{operand}, nil
+	case tokenLParen:
+		p.next()
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokenRParen {
+			return nil, &ParseError{Pos: closing.pos, Msg: fmt.Sprintf("expected \")\", found %s", closing)}
+		}
+		return inner, nil
+	}
+	return p.parseTerm()
+}
+
+func (p *parser) parseTerm() (node, error) {
+	name := p.next()
+	if name.kind != tokenWord && name.kind != tokenString {
+		return nil, &ParseError{Pos: name.pos, Msg: fmt.Sprintf("expected a search term, found %s", name)}
+	}
+	if name.kind == tokenString || p.peek().kind != tokenOp {
+		return textNode{value: strings.ToLower(name.text)}, nil
+	}
+
+	canonical, f, ok := lookupField(name.text)
+	if !ok {
+		return nil, &ParseError{Pos: name.pos, Msg: fmt.Sprintf("unknown field %q (valid: %s)", name.text, strings.Join(FieldNames(), ", "))}
+	}
+	op := p.next()
+	value := p.next()
+	if value.kind != tokenWord && value.kind != tokenString {
+		return nil, &ParseError{Pos: value.pos, Msg: fmt.Sprintf("expected a value after %s, found %s", op.text, value)}
+	}
+
+	switch f.kind {
+	case dateField:
+		return p.dateComparison(canonical, f, op, value)
+	case numberField:
+		return numberComparison(canonical, f, op, value)
+	}
+	return textComparison(canonical, f, op, value)
+}
+
+func textComparison(name string, f field, op, value token) (node, error) {
+	switch op.text {
+	case "==", "!=", "~", "!~", ":":
+	default:
+		return nil, &ParseError{Pos: op.pos, Msg: fmt.Sprintf("%s only supports :, ==, !=, ~ and !~", name)}
+	}
+
+	values := f.text
+	text := strings.ToLower(value.text)
+	if op.text == ":" && value.kind == tokenWord && strings.ContainsAny(text, "*?") {
+		if name == "url" {
+			values = withHost(values)
+		}
+		return globNode{values: values, pattern: text}, nil
+	}
+	return compareNode{values: values, op: op.text, value: text}, nil
+}
+
+func numberComparison(name string, f field, op, value token) (node, error) {
+	if op.text == "~" || op.text == "!~" {
+		return nil, &ParseError{Pos: op.pos, Msg: fmt.Sprintf("%s only supports :, ==, !=, <, <=, > and >=", name)}
+	}
+	n, err := strconv.Atoi(value.text)
+	if err != nil || n < 0 {
+		return nil, &ParseError{Pos: value.pos, Msg: fmt.Sprintf("%s needs a whole number, found %q", name, value.text)}
+	}
+	return numberNode{number: f.number, op: op.text, value: n}, nil
+}
+
+func (p *parser) dateComparison(name string, f field, op, value token) (node, error) {
+	if op.text == "~" || op.text == "!~" {
+		return nil, &ParseError{Pos: op.pos, Msg: fmt.Sprintf("%s only supports :, ==, !=, <, <=, > and >=", name)}
+	}
+	ordered := op.text != ":" && op.text != "==" && op.text != "!="
+
+	text := strings.ToLower(value.text)
+	if text == "never" {
+		if ordered {
+			return nil, &ParseError{Pos: op.pos, Msg: fmt.Sprintf("%s:never only supports :, == and !=", name)}
+		}
+		return dateNode{date: f.date, op: op.text, never: true}, nil
+	}
+
+	if day, err := time.ParseInLocation("2006-01-02", text, time.Local); err == nil {
+		return dateNode{date: f.date, op: op.text, day: day}, nil
+	}
+
+	cutoff, ok := ageCutoff(p.now, text)
+	if !ok {
+		return nil, &ParseError{Pos: value.pos, Msg: fmt.Sprintf("%s needs a date (YYYY-MM-DD), an age (30d, 2w, 6m, 1y) or never, found %q", name, value.text)}
+	}
+	if !ordered {
+		return nil, &ParseError{Pos: op.pos, Msg: fmt.Sprintf("ages only work with <, <=, > and >= (for example %s>%s)", name, value.text)}
+	}
+	// An older age is an earlier time: "used>90d" means used before now-90d
+	flipped := map[string]string{"<": ">", "<=": ">=", ">": "<", ">=": "<="}[op.text]
+	return dateNode{date: f.date, op: flipped, cutoff: cutoff}, nil
+}
+
+// ageCutoff converts an age such as 30d into the time that long before now
+func ageCutoff(now time.Time, age string) (time.Time, bool) {
+	if len(age) < 2 {
+		return time.Time{}, false
+	}
+	n, err := strconv.Atoi(age[:len(age)-1])
+	if err != nil || n < 0 {
+		return time.Time{}, false
+	}
+	switch age[len(age)-1] {
+	case 'h':
+		return now.Add(-time.Duration(n) * time.Hour), true
+	case 'd':
+		return now.AddDate(0, 0, -n), true
+	case 'w':
+		return now.AddDate(0, 0, -7*n), true
+	case 'm':
+		return now.AddDate(0, -n, 0), true
+	case 'y':
+		return now.AddDate(-n, 0, 0), true
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,354 @@
+// Package query parses search and filter expressions over credential metadata,
+// such as
+//
+//	github user:alice
+//	cat:db and (url:*.internal or tag:prod)
+//	category=="Cloud" && url~"aws"
+//	used>90d uses<3 !tag==legacy
+//
+// A bare word matches credentials whose service, username, URL or category
+// contains it. Terms written next to each other must all match; "and" (&&),
+// "or" (||), "not" (!) and parentheses combine them explicitly.
+//
+// Text comparisons are case-insensitive. "==" and "!=" compare whole values,
+// "~" and "!~" test whether the value contains the text, and ":" does the
+// same unless the value has * or ? wildcards, in which case the pattern must
+// match the whole value (or, for url, the host). For tags, "==" is true when
+// the credential has the tag and "~" when any tag contains the text.
+//
+// The date fields (created, updated, used) and the usage count (uses) also
+// take <, <=, > and >=, written either as "uses>5" or "uses:>5". Dates are
+// YYYY-MM-DD or ages such as 12h, 30d, 2w, 6m or 1y: "created<2024-01-01"
+// means created before 2024 and "used>90d" means last used more than 90 days
+// ago. Credentials that were never used count as used longest ago, and
+// "used:never" matches them.
+package query
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"pass-cli/internal/vault"
+)
+
+// Query is a parsed filter expression
+type Query struct {
+	source string
+	root   node
+}
+
+// Parse compiles a filter expression
+func Parse(input string) (*Query, error) {
+	tokens, err := tokenize(input)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens, now: time.Now()}
+
+	if p.peek().kind == tokenEOF {
+		return nil, &ParseError{Pos: 0, Msg: "empty expression"}
+	}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if next := p.peek(); next.kind != tokenEOF {
+		return nil, &ParseError{Pos: next.pos, Msg: fmt.Sprintf("unexpected %s", next)}
+	}
+	return &Query{source: input, root: root}, nil
+}
+
+// Match reports whether a credential satisfies the query
+func (q *Query) Match(cred *vault.CredentialMetadata) bool {
+	return q.root.match(cred)
+}
+
+// String returns the expression as written
+func (q *Query) String() string {
+	return q.source
+}
+
+// Words returns the search words when the query is nothing but bare words
+// (joined by spaces or "and"), so callers can rank results by fuzzy score
+func (q *Query) Words() ([]string, bool) {
+	return bareWords(q.root)
+}
+
+func bareWords(n node) ([]string, bool) {
+	switch n := n.(type) {
+	case textNode:
+		return []string{n.value}, true
+	case andNode:
+		left, ok := bareWords(n.left)
+		if !ok {
+			return nil, false
+		}
+		right, ok := bareWords(n.right)
+		if !ok {
+			return nil, false
+		}
+		return append(left, right...), true
+	}
+	return nil, false
+}
+
+// ParseError describes a syntax error and where it occurred
+type ParseError struct {
+	Pos int // Byte offset in the expression
+	Msg string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("invalid query at position %d: %s", e.Pos+1, e.Msg)
+}
+
+type fieldKind int
+
+const (
+	textField   fieldKind = iota
+	dateField             // Compared by day or by age
+	numberField           // Compared numerically
+)
+
+// field reads the value a comparison checks; only the accessor for its kind is set
+type field struct {
+	kind   fieldKind
+	text   func(cred *vault.CredentialMetadata) []string // Several values for tags
+	date   func(cred *vault.CredentialMetadata) time.Time
+	number func(cred *vault.CredentialMetadata) int
+}
+
+// fields maps field names to accessors
+var fields = map[string]field{
+	"service":  {kind: textField, text: func(c *vault.CredentialMetadata) []string { return []string{c.Service} }},
+	"username": {kind: textField, text: func(c *vault.CredentialMetadata) []string { return []string{c.Username} }},
+	"category": {kind: textField, text: func(c *vault.CredentialMetadata) []string { return []string{c.Category} }},
+	"url":      {kind: textField, text: func(c *vault.CredentialMetadata) []string { return []string{c.URL} }},
+	"notes":    {kind: textField, text: func(c *vault.CredentialMetadata) []string { return []string{c.Notes} }},
+	"type":     {kind: textField, text: func(c *vault.CredentialMetadata) []string { return []string{string(c.Type)} }},
+	"tag":      {kind: textField, text: func(c *vault.CredentialMetadata) []string { return c.Tags }},
+	"created":  {kind: dateField, date: func(c *vault.CredentialMetadata) time.Time { return c.CreatedAt }},
+	"updated":  {kind: dateField, date: func(c *vault.CredentialMetadata) time.Time { return c.UpdatedAt }},
+	"used":     {kind: dateField, date: func(c *vault.CredentialMetadata) time.Time { return c.LastAccessed }},
+	"uses":     {kind: numberField, number: func(c *vault.CredentialMetadata) int { return c.UsageCount }},
+}
+
+var fieldAliases = map[string]string{
+	"name":      "service",
+	"user":      "username",
+	"cat":       "category",
+	"tags":      "tag",
+	"last-used": "used",
+	"lastused":  "used",
+	"usage":     "uses",
+}
+
+// FieldNames lists the field names accepted in expressions, sorted
+func FieldNames() []string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// lookupField resolves a field name or alias to its canonical name and accessor
+func lookupField(name string) (string, field, bool) {
+	name = strings.ToLower(name)
+	if canonical, ok := fieldAliases[name]; ok {
+		name = canonical
+	}
+	f, ok := fields[name]
+	return name, f, ok
+}
+
+// node is an expression tree node
+type node interface {
+	match(cred *vault.CredentialMetadata) bool
+}
+
+type andNode struct{ left, right node }
+
+func (n andNode) match(cred *vault.CredentialMetadata) bool {
+	return n.left.match(cred) && n.right.match(cred)
+}
+
+type orNode struct{ left, right node }
+
+func (n orNode) match(cred *vault.CredentialMetadata) bool {
+	return n.left.match(cred) || n.right.match(cred)
+}
+
+type notNode struct{ operand node }
+
+func (n notNode) match(cred *vault.CredentialMetadata) bool {
+	return !n.operand.match(cred)
+}
+
+// textNode is a bare search word, matched against service, username, URL and category
+type textNode struct {
+	value string // Lowercased
+}
+
+func (n textNode) match(cred *vault.CredentialMetadata) bool {
+	return anyValue([]string{cred.Service, cred.Username, cred.URL, cred.Category}, func(v string) bool {
+		return strings.Contains(v, n.value)
+	})
+}
+
+// compareNode tests a text field against a value
+type compareNode struct {
+	values func(cred *vault.CredentialMetadata) []string
+	op     string
+	value  string // Lowercased
+}
+
+func (n compareNode) match(cred *vault.CredentialMetadata) bool {
+	values := n.values(cred)
+	switch n.op {
+	case "==":
+		return anyValue(values, func(v string) bool { return v == n.value })
+	case "!=":
+		return !anyValue(values, func(v string) bool { return v == n.value })
+	case "~", ":":
+		return anyValue(values, func(v string) bool { return strings.Contains(v, n.value) })
+	case "!~":
+		return !anyValue(values, func(v string) bool { return strings.Contains(v, n.value) })
+	}
+	return false
+}
+
+// globNode tests a text field against a wildcard pattern
+type globNode struct {
+	values  func(cred *vault.CredentialMetadata) []string
+	pattern string // Lowercased
+}
+
+func (n globNode) match(cred *vault.CredentialMetadata) bool {
+	return anyValue(n.values(cred), func(v string) bool { return globMatch(n.pattern, v) })
+}
+
+// dateNode compares a date field by calendar day, by age, or against "never"
+type dateNode struct {
+	date   func(cred *vault.CredentialMetadata) time.Time
+	op     string
+	day    time.Time // Start of the day for YYYY-MM-DD values
+	cutoff time.Time // Now minus the age for relative values
+	never  bool
+}
+
+func (n dateNode) match(cred *vault.CredentialMetadata) bool {
+	t := n.date(cred)
+	switch {
+	case n.never:
+		return t.IsZero() == (n.op != "!=")
+	case !n.cutoff.IsZero():
+		return compareResult(n.op, t.Compare(n.cutoff))
+	default:
+		return compareResult(n.op, startOfDay(t).Compare(n.day))
+	}
+}
+
+// numberNode compares a numeric field
+type numberNode struct {
+	number func(cred *vault.CredentialMetadata) int
+	op     string
+	value  int
+}
+
+func (n numberNode) match(cred *vault.CredentialMetadata) bool {
+	v := n.number(cred)
+	switch {
+	case v < n.value:
+		return compareResult(n.op, -1)
+	case v > n.value:
+		return compareResult(n.op, 1)
+	}
+	return compareResult(n.op, 0)
+}
+
+// compareResult applies an operator to the result of a three-way comparison
+func compareResult(op string, c int) bool {
+	switch op {
+	case "==", ":":
+		return c == 0
+	case "!=":
+		return c != 0
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	case ">":
+		return c > 0
+	case ">=":
+		return c >= 0
+	}
+	return false
+}
+
+// anyValue reports whether any lowercased value satisfies test
+func anyValue(values []string, test func(string) bool) bool {
+	for _, value := range values {
+		if test(strings.ToLower(value)) {
+			return true
+		}
+	}
+	return false
+}
+
+// globMatch reports whether s matches pattern as a whole, where * matches any
+// run of characters and ? matches a single character
+func globMatch(pattern, s string) bool {
+	p, str := []rune(pattern), []rune(s)
+	pi, si := 0, 0
+	star, mark := -1, 0
+	for si < len(str) {
+		switch {
+		case pi < len(p) && (p[pi] == '?' || p[pi] == str[si]):
+			pi++
+			si++
+		case pi < len(p) && p[pi] == '*':
+			star, mark = pi, si
+			pi++
+		case star >= 0:
+			// Let the last * absorb one more character and retry
+			pi = star + 1
+			mark++
+			si = mark
+		default:
+			return false
+		}
+	}
+	for pi < len(p) && p[pi] == '*' {
+		pi++
+	}
+	return pi == len(p)
+}
+
+// withHost adds the host of each URL so patterns like *.internal match
+// "https://db.internal/login"
+func withHost(values func(cred *vault.CredentialMetadata) []string) func(cred *vault.CredentialMetadata) []string {
+	return func(cred *vault.CredentialMetadata) []string {
+		urls := values(cred)
+		result := append([]string(nil), urls...)
+		for _, raw := range urls {
+			if !strings.Contains(raw, "://") {
+				raw = "https://" + raw
+			}
+			if u, err := url.Parse(raw); err == nil && u.Hostname() != "" {
+				result = append(result, u.Hostname())
+			}
+		}
+		return result
+	}
+}
+
+// startOfDay truncates t to midnight in the local time zone
+func startOfDay(t time.Time) time.Time {
+	t = t.Local()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+}
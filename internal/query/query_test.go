@@ -0,0 +1,202 @@
+package query
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"pass-cli/internal/vault"
+)
+
+func TestQuery_Match(t *testing.T) {
+	aws := &vault.CredentialMetadata{
+		Service: "aws-prod", Username: "admin", Category: "Cloud", URL: "https://console.aws.amazon.com",
+		Type: vault.TypeLogin, Tags: []string{"prod", "billing"},
+	}
+
+	tests := []struct {
+		query string
+		want  bool
+	}{
+		{`category=="Cloud" && url~"aws"`, true},
+		{`category=="cloud"`, true},
+		{`category=="Cloud/AWS"`, false},
+		{`cat==Cloud && user==admin`, true},
+		{`url~"gcp" || service~prod`, true},
+		{`url~"gcp" || service~dev`, false},
+		{`!(tag==legacy) && tag==prod`, true},
+		{`tags~bill`, true},
+		{`tag!=prod`, false},
+		{`url!~aws`, false},
+		{`type==login && !type==secure-note`, true},
+		{`name=='aws-prod'`, true},
+		{`notes==""`, true},
+		{`category=="Cloud" || url~gcp && tag==none`, true}, // && binds tighter
+		{`(category=="Cloud" || url~gcp) && tag==none`, false},
+		{`service=="say \"hi\""`, false},
+		{`aws`, true},
+		{`amazon admin`, true},
+		{`amazon guest`, false},
+		{`"console.aws"`, true},
+		{`billing`, false}, // bare words skip tags and notes
+		{`user:adm cat:cloud`, true},
+		{`user:alice or cat:cloud`, true},
+		{`not tag:prod`, false},
+		{`cat:db and (url:*.amazon.com or tag:prod)`, false},
+		{`url:*.aws.amazon.com`, true},
+		{`url:*.internal`, false},
+		{`url:https://console.aws.amazon.com`, true},
+		{`name:aws-*`, true},
+		{`name:aws-?rod`, true},
+		{`name:*dev`, false},
+		{`tag:bill*`, true},
+		{`user=admin`, true},
+		{`USER:ADMIN AND Service~PROD`, true},
+	}
+	for _, tt := range tests {
+		q, err := Parse(tt.query)
+		if err != nil {
+			t.Errorf("Parse(%s) failed: %v", tt.query, err)
+			continue
+		}
+		if got := q.Match(aws); got != tt.want {
+			t.Errorf("Match(%s) = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		query string
+		pos   int
+	}{
+		{``, 0},
+		{`color=="red"`, 0},
+		{`category=="Cloud" &&`, 20},
+		{`(category=="Cloud"`, 18},
+		{`url~"aws`, 4},
+		{`category==`, 10},
+		{`usr:alice`, 0},
+		{`user:`, 5},
+		{`aws and`, 7},
+		{`(aws))`, 5},
+		{`user<alice`, 4},
+		{`uses~5`, 4},
+		{`uses>many`, 5},
+		{`created>yesterday`, 8},
+		{`used:30d`, 4},
+		{`used>never`, 4},
+	}
+	for _, tt := range tests {
+		_, err := Parse(tt.query)
+		var parseErr *ParseError
+		if !errors.As(err, &parseErr) {
+			t.Errorf("Parse(%s) error = %v, want ParseError", tt.query, err)
+			continue
+		}
+		if parseErr.Pos != tt.pos {
+			t.Errorf("Parse(%s) position = %d, want %d (%v)", tt.query, parseErr.Pos, tt.pos, err)
+		}
+	}
+}
+
+func TestQuery_MatchDatesAndUsage(t *testing.T) {
+	now := time.Now()
+	cred := &vault.CredentialMetadata{
+		Service:      "db",
+		CreatedAt:    time.Date(2024, 3, 15, 12, 0, 0, 0, time.Local),
+		UpdatedAt:    now.AddDate(0, 0, -10),
+		LastAccessed: now.AddDate(0, 0, -100),
+		UsageCount:   4,
+	}
+	neverUsed := &vault.CredentialMetadata{Service: "old", CreatedAt: now}
+
+	tests := []struct {
+		query string
+		cred  *vault.CredentialMetadata
+		want  bool
+	}{
+		{`created:2024-03-15`, cred, true},
+		{`created==2024-03-16`, cred, false},
+		{`created<2024-03-15`, cred, false},
+		{`created<=2024-03-15`, cred, true},
+		{`created>2024-01-01`, cred, true},
+		{`created:>=2024-03-16`, cred, false},
+		{`created!=2024-03-15`, cred, false},
+		{`updated<30d`, cred, true},
+		{`updated<1w`, cred, false},
+		{`updated>1w`, cred, true},
+		{`used>90d`, cred, true},
+		{`used>6m`, cred, false},
+		{`last-used<1y`, cred, true},
+		{`used:never`, cred, false},
+		{`used!=never`, cred, true},
+		{`used:never`, neverUsed, true},
+		{`used>90d`, neverUsed, true},
+		{`used<2024-01-01`, neverUsed, true},
+		{`uses>3`, cred, true},
+		{`uses:>4`, cred, false},
+		{`uses>=4 && uses<=4`, cred, true},
+		{`uses:4`, cred, true},
+		{`uses!=4`, cred, false},
+		{`uses==0`, neverUsed, true},
+		{`created>2024-01-01 uses<10 db`, cred, true},
+	}
+	for _, tt := range tests {
+		q, err := Parse(tt.query)
+		if err != nil {
+			t.Errorf("Parse(%s) failed: %v", tt.query, err)
+			continue
+		}
+		if got := q.Match(tt.cred); got != tt.want {
+			t.Errorf("Match(%s) on %s = %v, want %v", tt.query, tt.cred.Service, got, tt.want)
+		}
+	}
+}
+
+func TestGlobMatch(t *testing.T) {
+	tests := []struct {
+		pattern, s string
+		want       bool
+	}{
+		{"*", "", true},
+		{"*.internal", "db.internal", true},
+		{"*.internal", "db.internal.example.com", false},
+		{"a*b*c", "axxbyyc", true},
+		{"a*b*c", "axxbyy", false},
+		{"??", "ab", true},
+		{"??", "abc", false},
+		{"*ü*", "grün", true},
+	}
+	for _, tt := range tests {
+		if got := globMatch(tt.pattern, tt.s); got != tt.want {
+			t.Errorf("globMatch(%q, %q) = %v, want %v", tt.pattern, tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestQuery_Words(t *testing.T) {
+	tests := []struct {
+		query string
+		want  []string
+		ok    bool
+	}{
+		{`aws`, []string{"aws"}, true},
+		{`AWS prod`, []string{"aws", "prod"}, true},
+		{`aws and "prod eu"`, []string{"aws", "prod eu"}, true},
+		{`aws or prod`, nil, false},
+		{`aws user:admin`, nil, false},
+		{`not aws`, nil, false},
+	}
+	for _, tt := range tests {
+		q, err := Parse(tt.query)
+		if err != nil {
+			t.Fatalf("Parse(%s) failed: %v", tt.query, err)
+		}
+		got, ok := q.Words()
+		if ok != tt.ok || !slices.Equal(got, tt.want) {
+			t.Errorf("Words(%s) = %v, %v, want %v, %v", tt.query, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -29,6 +29,15 @@ const (
 	EventVaultUnlock         = "vault_unlock"          // FR-019
 	EventVaultLock           = "vault_lock"            // FR-019
 	EventVaultPasswordChange = "vault_password_change" // FR-019
+	EventVaultSync           = "vault_sync"            // Merged with another copy of the vault
+	EventVaultRestore        = "vault_restore"         // Replaced by a snapshot
+	EventVaultKDFUpgrade     = "vault_kdf_upgrade"     // Key re-derived with new KDF parameters
+	EventVaultRekey          = "vault_rekey"           // Vault re-encrypted with a new data key
+	EventVaultExport         = "vault_export"          // Credentials written out by export (format recorded)
+	EventKeySlotAdd          = "keyslot_add"           // New way to unlock the vault added
+	EventKeySlotRemove       = "keyslot_remove"        // Way to unlock the vault removed
+	EventShareSend           = "share_send"            // Credential encrypted to other users' public keys
+	EventShareReceive        = "share_receive"         // Credential merged from another user's share bundle
 	// #nosec G101 -- False positive: event type name, not actual credentials
 	EventCredentialAccess    = "credential_access"     // FR-020 (get)
 	// #nosec G101 -- False positive: event type name, not actual credentials
@@ -37,6 +46,14 @@ const (
 	EventCredentialUpdate    = "credential_update"     // FR-020
 	// #nosec G101 -- False positive: event type name, not actual credentials
 	EventCredentialDelete    = "credential_delete"     // FR-020
+	// #nosec G101 -- False positive: event type name, not actual credentials
+	EventCredentialRestore   = "credential_restore"    // Restored from trash
+	// #nosec G101 -- False positive: event type name, not actual credentials
+	EventCredentialPurge     = "credential_purge"      // Permanently removed from trash
+	// #nosec G101 -- False positive: event type name, not actual credentials
+	EventCredentialRename    = "credential_rename"     // Service name changed
+	// #nosec G101 -- False positive: event type name, not actual credentials
+	EventCredentialCopy      = "credential_copy"       // Duplicated under a new service name
 )
 
 // Outcome constants
@@ -0,0 +1,136 @@
+package security
+
+import (
+	"bufio"
+	"crypto/sha1" // #nosec G505 -- SHA-1 is the hash format of Have I Been Pwned lists, not used for security
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// breachPrefixLength is the length of the hash prefixes used by HIBP range files
+const breachPrefixLength = 5
+
+// LookupBreachedHashes checks SHA-1 password hashes against a local Have I
+// Been Pwned style list and returns how often each hash was seen. Hashes not
+// in the list are absent from the result.
+//
+// path may be a file of "HASH:COUNT" lines (the full downloadable list), a
+// range file named after a 5-character hash prefix holding "SUFFIX:COUNT"
+// lines (as returned by the range API), or a directory of such range files.
+// The list is streamed, so even the full multi-gigabyte file is not loaded
+// into memory.
+func LookupBreachedHashes(path string, hashes [][sha1.Size]byte) (map[[sha1.Size]byte]int, error) {
+	wanted := make(map[string][sha1.Size]byte, len(hashes))
+	for _, hash := range hashes {
+		wanted[strings.ToUpper(hex.EncodeToString(hash[:]))] = hash
+	}
+	found := make(map[[sha1.Size]byte]int)
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open breach list: %w", err)
+	}
+	if !info.IsDir() {
+		if err := scanBreachFile(path, rangePrefix(path), wanted, found); err != nil {
+			return nil, err
+		}
+		return found, nil
+	}
+
+	// Directory of range files: only open the prefixes we need
+	prefixes := make(map[string]bool)
+	for hash := range wanted {
+		prefixes[hash[:breachPrefixLength]] = true
+	}
+	for prefix := range prefixes {
+		for _, name := range []string{prefix, prefix + ".txt", strings.ToLower(prefix), strings.ToLower(prefix) + ".txt"} {
+			file := filepath.Join(path, name)
+			if _, err := os.Stat(file); err != nil {
+				continue
+			}
+			if err := scanBreachFile(file, prefix, wanted, found); err != nil {
+				return nil, err
+			}
+			break
+		}
+	}
+	return found, nil
+}
+
+// rangePrefix returns the hash prefix a range file is named after, or "" for full lists
+func rangePrefix(path string) string {
+	base := filepath.Base(path)
+	base = strings.TrimSuffix(base, filepath.Ext(base))
+	if len(base) != breachPrefixLength {
+		return ""
+	}
+	if _, err := hex.DecodeString(base + "0"); err != nil {
+		return ""
+	}
+	return strings.ToUpper(base)
+}
+
+// scanBreachFile records the wanted hashes listed in one breach file
+func scanBreachFile(path, prefix string, wanted map[string][sha1.Size]byte, found map[[sha1.Size]byte]int) error {
+	// #nosec G304 -- Breach list path is provided by the user
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open breach list: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	if err := scanBreachList(f, prefix, wanted, found); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+// scanBreachList reads "HASH[:COUNT]" lines; with a prefix, lines hold only the hash suffix
+func scanBreachList(r io.Reader, prefix string, wanted map[string][sha1.Size]byte, found map[[sha1.Size]byte]int) error {
+	scanner := bufio.NewScanner(r)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		hashText, countText, hasCount := strings.Cut(line, ":")
+		hashText = strings.ToUpper(strings.TrimSpace(hashText))
+		if prefix != "" && len(hashText) == 2*sha1.Size-breachPrefixLength {
+			hashText = prefix + hashText
+		}
+		if len(hashText) != 2*sha1.Size {
+			return fmt.Errorf("line %d: not a SHA-1 hash", lineNumber)
+		}
+
+		hash, ok := wanted[hashText]
+		if !ok {
+			continue
+		}
+		count := 1
+		if hasCount {
+			n, err := strconv.Atoi(strings.TrimSpace(countText))
+			if err != nil || n < 0 {
+				return fmt.Errorf("line %d: invalid count %q", lineNumber, countText)
+			}
+			count = n
+		}
+		// Range API padding entries have a count of zero
+		if count > 0 {
+			found[hash] += count
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		if errors.Is(err, bufio.ErrTooLong) {
+			return errors.New("not a breach list (line too long)")
+		}
+		return err
+	}
+	return nil
+}
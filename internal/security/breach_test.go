@@ -0,0 +1,73 @@
+package security
+
+import (
+	"crypto/sha1" // #nosec G505 -- matches the breach list hash format
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLookupBreachedHashes(t *testing.T) {
+	pwned := sha1.Sum([]byte("password1"))    // #nosec G401 -- test data
+	padding := sha1.Sum([]byte("padding"))    // #nosec G401 -- test data
+	safe := sha1.Sum([]byte("N0t-In-A-List")) // #nosec G401 -- test data
+	pwnedHex := strings.ToUpper(hex.EncodeToString(pwned[:]))
+	paddingHex := strings.ToUpper(hex.EncodeToString(padding[:]))
+	hashes := [][sha1.Size]byte{pwned, padding, safe}
+
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	tests := []struct {
+		name string
+		path string
+	}{
+		{"full list", write("pwned.txt", "# header\n0000000000000000000000000000000000000000:3\r\n"+strings.ToLower(pwnedHex)+":1234\r\n"+paddingHex+":0\n")},
+		{"range file", write(pwnedHex[:5]+".txt", "00000000000000000000000000000000000:1\n"+pwnedHex[5:]+":1234\n")},
+		{"range directory", filepath.Dir(write("ranges/"+pwnedHex[:5], pwnedHex[5:]+":1000\n"+pwnedHex[5:]+":234\n"))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			found, err := LookupBreachedHashes(tt.path, hashes)
+			if err != nil {
+				t.Fatalf("LookupBreachedHashes() failed: %v", err)
+			}
+			if found[pwned] != 1234 {
+				t.Errorf("count for pwned hash = %d, want 1234", found[pwned])
+			}
+			if len(found) != 1 {
+				t.Errorf("found %d hashes, want 1 (padding and safe hashes excluded)", len(found))
+			}
+		})
+	}
+}
+
+func TestLookupBreachedHashes_Errors(t *testing.T) {
+	dir := t.TempDir()
+	hash := sha1.Sum([]byte("password1")) // #nosec G401 -- test data
+
+	if _, err := LookupBreachedHashes(filepath.Join(dir, "missing.txt"), [][sha1.Size]byte{hash}); err == nil {
+		t.Error("expected error for missing file")
+	}
+
+	ntlm := filepath.Join(dir, "ntlm.txt")
+	if err := os.WriteFile(ntlm, []byte("8846F7EAEE8FB117AD06BDD830B7586C:10\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	_, err := LookupBreachedHashes(ntlm, [][sha1.Size]byte{hash})
+	if err == nil || !strings.Contains(err.Error(), "line 1: not a SHA-1 hash") {
+		t.Errorf("error = %v, want line 1 not a SHA-1 hash", err)
+	}
+}
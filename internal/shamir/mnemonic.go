@@ -0,0 +1,148 @@
+package shamir
+
+import (
+	"crypto/sha256"
+	_ "embed"
+	"fmt"
+	"strings"
+)
+
+// wordlist is the BIP-39 English wordlist: 2048 words, each identified by
+// its first four letters
+//
+//go:embed wordlist.txt
+var wordlist string
+
+const bitsPerWord = 11
+
+var (
+	words       = strings.Fields(wordlist)
+	wordIndices = indexWords(words)
+)
+
+func indexWords(words []string) map[string]int {
+	indices := make(map[string]int, len(words))
+	for i, word := range words {
+		indices[wordPrefix(word)] = i
+	}
+	return indices
+}
+
+func wordPrefix(word string) string {
+	if len(word) > 4 {
+		return word[:4]
+	}
+	return word
+}
+
+// Mnemonic encodes the share as words for printing. The set ID, threshold and
+// evaluation point come first, and a checksum fills the last word so that
+// mistyped words are detected.
+func (s Share) Mnemonic() string {
+	payload := s.payload()
+	bits := appendBits(nil, payload, len(payload)*8)
+	bits = appendBits(bits, checksum(payload), checksumBits(len(payload)))
+
+	encoded := make([]string, 0, len(bits)/bitsPerWord)
+	for i := 0; i < len(bits); i += bitsPerWord {
+		index := 0
+		for _, bit := range bits[i : i+bitsPerWord] {
+			index = index<<1 | int(bit)
+		}
+		encoded = append(encoded, words[index])
+	}
+	return strings.Join(encoded, " ")
+}
+
+// ParseMnemonic decodes a share written by Mnemonic. Case and spacing are
+// ignored, and words may be shortened to their first four letters.
+func ParseMnemonic(mnemonic string) (Share, error) {
+	fields := strings.Fields(strings.ToLower(mnemonic))
+	if len(fields) == 0 {
+		return Share{}, fmt.Errorf("%w: no words", ErrInvalidShare)
+	}
+
+	bits := make([]byte, 0, len(fields)*bitsPerWord)
+	for n, field := range fields {
+		index, ok := wordIndices[wordPrefix(field)]
+		if !ok || !strings.HasPrefix(words[index], field) || (len(field) < 4 && field != words[index]) {
+			return Share{}, fmt.Errorf("%w: unknown word %d %q", ErrInvalidShare, n+1, field)
+		}
+		for i := bitsPerWord - 1; i >= 0; i-- {
+			bits = append(bits, byte(index>>i&1))
+		}
+	}
+
+	// A word count fits at most two payload sizes, the smaller with an extra word
+	// of checksum (see checksumBits); the first whose checksum matches wins
+	var payload []byte
+	sized := false
+	for size := 5; size*8 < len(bits); size++ {
+		if size*8+checksumBits(size) != len(bits) {
+			continue
+		}
+		sized = true
+		candidate := packBits(bits[:size*8])
+		want := appendBits(nil, checksum(candidate), checksumBits(size))
+		if string(want) == string(bits[size*8:]) {
+			payload = candidate
+			break
+		}
+	}
+	if !sized {
+		return Share{}, fmt.Errorf("%w: wrong number of words", ErrInvalidShare)
+	}
+	if payload == nil {
+		return Share{}, fmt.Errorf("%w: checksum mismatch (mistyped word?)", ErrInvalidShare)
+	}
+
+	share := Share{
+		SetID:     uint16(payload[0])<<8 | uint16(payload[1]),
+		Threshold: int(payload[2]),
+		X:         payload[3],
+		Y:         payload[4:],
+	}
+	if share.Threshold < 2 || share.X == 0 {
+		return Share{}, ErrInvalidShare
+	}
+	return share, nil
+}
+
+func (s Share) payload() []byte {
+	payload := []byte{byte(s.SetID >> 8), byte(s.SetID), byte(s.Threshold), s.X}
+	return append(payload, s.Y...)
+}
+
+// checksumBits pads a payload of size bytes to whole words, with at least one
+// checksum bit. Padding of nine bits or more would leave room for another
+// payload byte, so the word count alone could not tell size from size+1; those
+// sizes get a whole extra word of checksum, which ParseMnemonic checks first.
+func checksumBits(size int) int {
+	n := bitsPerWord - size*8%bitsPerWord
+	if n > 8 {
+		n += bitsPerWord
+	}
+	return n
+}
+
+func checksum(payload []byte) []byte {
+	sum := sha256.Sum256(payload)
+	return sum[:]
+}
+
+// appendBits appends the first n bits of data, most significant first, one
+// bit per byte
+func appendBits(bits, data []byte, n int) []byte {
+	for i := 0; i < n; i++ {
+		bits = append(bits, data[i/8]>>(7-i%8)&1)
+	}
+	return bits
+}
+
+func packBits(bits []byte) []byte {
+	data := make([]byte, len(bits)/8)
+	for i, bit := range bits {
+		data[i/8] |= bit << (7 - i%8)
+	}
+	return data
+}
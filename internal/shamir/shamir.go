@@ -0,0 +1,146 @@
+// Package shamir splits a secret into shares with Shamir's Secret Sharing over
+// GF(256). Any threshold of the shares recovers the secret; fewer reveal
+// nothing about it. Shares are written down as mnemonics of BIP-39 words.
+package shamir
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+)
+
+// MaxShares is the largest number of shares, one per non-zero field element
+const MaxShares = 255
+
+var (
+	ErrInvalidShare     = errors.New("invalid recovery share")
+	ErrNotEnoughShares  = errors.New("not enough recovery shares")
+	ErrMismatchedShares = errors.New("recovery shares belong to different sets")
+	ErrDuplicateShare   = errors.New("duplicate recovery share")
+)
+
+// Share is one point of the polynomials that hide the secret. Shares of the
+// same split carry the same SetID and Threshold.
+type Share struct {
+	SetID     uint16 // Random, identifies the shares of one split
+	Threshold int    // Number of shares needed to recover the secret
+	X         byte   // Evaluation point, 1..255
+	Y         []byte // One byte per byte of the secret
+}
+
+// Split splits secret into n shares, any threshold of which recover it
+func Split(secret []byte, n, threshold int) ([]Share, error) {
+	if len(secret) == 0 {
+		return nil, errors.New("secret is empty")
+	}
+	if threshold < 2 || threshold > n {
+		return nil, fmt.Errorf("threshold must be between 2 and the number of shares (%d)", n)
+	}
+	if n > MaxShares {
+		return nil, fmt.Errorf("at most %d shares are supported", MaxShares)
+	}
+
+	var id [2]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		return nil, fmt.Errorf("failed to generate share set ID: %w", err)
+	}
+	setID := uint16(id[0])<<8 | uint16(id[1])
+
+	shares := make([]Share, n)
+	for i := range shares {
+		shares[i] = Share{SetID: setID, Threshold: threshold, X: byte(i + 1), Y: make([]byte, len(secret))}
+	}
+
+	// One random polynomial of degree threshold-1 per secret byte, with the
+	// secret byte as its constant term
+	coefficients := make([]byte, threshold)
+	defer clear(coefficients)
+	for b, s := range secret {
+		if _, err := rand.Read(coefficients[1:]); err != nil {
+			return nil, fmt.Errorf("failed to generate polynomial: %w", err)
+		}
+		coefficients[0] = s
+		for i := range shares {
+			shares[i].Y[b] = evaluate(coefficients, shares[i].X)
+		}
+	}
+	return shares, nil
+}
+
+// Combine recovers the secret from at least Threshold shares of one split
+func Combine(shares []Share) ([]byte, error) {
+	if len(shares) == 0 {
+		return nil, ErrNotEnoughShares
+	}
+	first := shares[0]
+	if len(shares) < first.Threshold {
+		return nil, fmt.Errorf("%w: have %d, need %d", ErrNotEnoughShares, len(shares), first.Threshold)
+	}
+
+	seen := make(map[byte]bool, len(shares))
+	for _, share := range shares {
+		if share.X == 0 || len(share.Y) == 0 {
+			return nil, ErrInvalidShare
+		}
+		if share.SetID != first.SetID || share.Threshold != first.Threshold || len(share.Y) != len(first.Y) {
+			return nil, ErrMismatchedShares
+		}
+		if seen[share.X] {
+			return nil, fmt.Errorf("%w: share %d", ErrDuplicateShare, share.X)
+		}
+		seen[share.X] = true
+	}
+	shares = shares[:first.Threshold]
+
+	// Lagrange interpolation at x = 0
+	secret := make([]byte, len(first.Y))
+	for i, share := range shares {
+		basis := byte(1)
+		for j, other := range shares {
+			if i != j {
+				basis = mul(basis, div(other.X, other.X^share.X))
+			}
+		}
+		for b := range secret {
+			secret[b] ^= mul(share.Y[b], basis)
+		}
+	}
+	return secret, nil
+}
+
+// evaluate computes the polynomial at x with Horner's method
+func evaluate(coefficients []byte, x byte) byte {
+	var y byte
+	for i := len(coefficients) - 1; i >= 0; i-- {
+		y = mul(y, x) ^ coefficients[i]
+	}
+	return y
+}
+
+// GF(256) with the AES polynomial x^8 + x^4 + x^3 + x + 1; addition is XOR
+var expTable, logTable [256]byte
+
+func init() {
+	x := byte(1)
+	for i := 0; i < 255; i++ {
+		expTable[i] = x
+		logTable[x] = byte(i)
+		// Multiply by the generator 3
+		x ^= x<<1 ^ byte(int8(x)>>7)&0x1b
+	}
+	expTable[255] = expTable[0]
+}
+
+func mul(a, b byte) byte {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	return expTable[(int(logTable[a])+int(logTable[b]))%255]
+}
+
+func div(a, b byte) byte {
+	if a == 0 {
+		return 0
+	}
+	return expTable[(int(logTable[a])-int(logTable[b])+255)%255]
+}
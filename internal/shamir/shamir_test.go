@@ -0,0 +1,156 @@
+package shamir
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSplitCombine(t *testing.T) {
+	secret := []byte("0123456789abcdefghij")
+
+	shares, err := Split(secret, 5, 3)
+	if err != nil {
+		t.Fatalf("Split() failed: %v", err)
+	}
+	if len(shares) != 5 {
+		t.Fatalf("expected 5 shares, got %d", len(shares))
+	}
+
+	// Any three shares recover the secret
+	for _, pick := range [][]int{{0, 1, 2}, {4, 2, 0}, {1, 3, 4}, {0, 1, 2, 3, 4}} {
+		var subset []Share
+		for _, i := range pick {
+			subset = append(subset, shares[i])
+		}
+		got, err := Combine(subset)
+		if err != nil {
+			t.Fatalf("Combine(%v) failed: %v", pick, err)
+		}
+		if !bytes.Equal(got, secret) {
+			t.Errorf("Combine(%v) = %q, want %q", pick, got, secret)
+		}
+	}
+
+	if _, err := Combine(shares[:2]); !errors.Is(err, ErrNotEnoughShares) {
+		t.Errorf("expected ErrNotEnoughShares, got %v", err)
+	}
+	if _, err := Combine([]Share{shares[0], shares[0], shares[1]}); !errors.Is(err, ErrDuplicateShare) {
+		t.Errorf("expected ErrDuplicateShare, got %v", err)
+	}
+
+	other, err := Split(secret, 3, 3)
+	if err != nil {
+		t.Fatalf("Split() failed: %v", err)
+	}
+	other[0].SetID = shares[0].SetID + 1
+	if _, err := Combine([]Share{shares[0], shares[1], other[0]}); !errors.Is(err, ErrMismatchedShares) {
+		t.Errorf("expected ErrMismatchedShares, got %v", err)
+	}
+}
+
+func TestSplitInvalid(t *testing.T) {
+	tests := []struct {
+		name         string
+		n, threshold int
+	}{
+		{"threshold 1", 3, 1},
+		{"threshold above shares", 3, 4},
+		{"too many shares", MaxShares + 1, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Split([]byte("secret"), tt.n, tt.threshold); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestFieldArithmetic(t *testing.T) {
+	for a := 1; a < 256; a++ {
+		for b := 1; b < 256; b++ {
+			if got := div(mul(byte(a), byte(b)), byte(b)); got != byte(a) {
+				t.Fatalf("(%d * %d) / %d = %d", a, b, b, got)
+			}
+		}
+	}
+	// Known AES field product
+	if got := mul(0x57, 0x83); got != 0xc1 {
+		t.Errorf("0x57 * 0x83 = %#x, want 0xc1", got)
+	}
+}
+
+func TestMnemonic(t *testing.T) {
+	if len(words) != 2048 {
+		t.Fatalf("expected 2048 words, got %d", len(words))
+	}
+
+	shares, err := Split([]byte("0123456789abcdefghij"), 3, 2)
+	if err != nil {
+		t.Fatalf("Split() failed: %v", err)
+	}
+	mnemonic := shares[1].Mnemonic()
+	if n := len(strings.Fields(mnemonic)); n != 18 {
+		t.Errorf("expected 18 words for a 20-byte secret, got %d", n)
+	}
+
+	// Case, spacing and four-letter abbreviations are accepted
+	var abbreviated []string
+	for _, word := range strings.Fields(mnemonic) {
+		abbreviated = append(abbreviated, strings.ToUpper(wordPrefix(word)))
+	}
+	for _, input := range []string{mnemonic, "  " + strings.Join(abbreviated, "\n  ")} {
+		share, err := ParseMnemonic(input)
+		if err != nil {
+			t.Fatalf("ParseMnemonic(%q) failed: %v", input, err)
+		}
+		if share.SetID != shares[1].SetID || share.Threshold != 2 || share.X != 2 || !bytes.Equal(share.Y, shares[1].Y) {
+			t.Errorf("ParseMnemonic() = %+v, want %+v", share, shares[1])
+		}
+	}
+
+	// A fixed share, so the 6-bit checksum is known to catch the swap
+	fixed := Share{SetID: 0x1234, Threshold: 2, X: 2, Y: []byte("0123456789abcdefghij")}
+	fields := strings.Fields(fixed.Mnemonic())
+	swapped := append([]string{fields[1], fields[0]}, fields[2:]...)
+	if _, err := ParseMnemonic(strings.Join(swapped, " ")); !errors.Is(err, ErrInvalidShare) {
+		t.Errorf("expected swapped words to fail the checksum, got %v", err)
+	}
+	for _, input := range []string{"", "notaword " + mnemonic, strings.Join(fields[1:], " ")} {
+		if _, err := ParseMnemonic(input); !errors.Is(err, ErrInvalidShare) {
+			t.Errorf("ParseMnemonic(%q): expected ErrInvalidShare, got %v", input, err)
+		}
+	}
+}
+
+func TestMnemonicRoundTrip(t *testing.T) {
+	for _, size := range []int{1, 3, 7, 10, 14, 16, 18, 20, 29, 32, 36, 64} {
+		secret := make([]byte, size)
+		for i := range secret {
+			secret[i] = byte(i*7 + size)
+		}
+		shares, err := Split(secret, 3, 2)
+		if err != nil {
+			t.Fatalf("Split(%d bytes) failed: %v", size, err)
+		}
+
+		parsed := make([]Share, 0, len(shares))
+		for _, share := range shares {
+			got, err := ParseMnemonic(share.Mnemonic())
+			if err != nil {
+				t.Fatalf("%d-byte secret: ParseMnemonic() failed: %v", size, err)
+			}
+			if got.SetID != share.SetID || got.Threshold != share.Threshold || got.X != share.X || !bytes.Equal(got.Y, share.Y) {
+				t.Fatalf("%d-byte secret: ParseMnemonic() = %+v, want %+v", size, got, share)
+			}
+			parsed = append(parsed, got)
+		}
+
+		combined, err := Combine(parsed[1:])
+		if err != nil || !bytes.Equal(combined, secret) {
+			t.Errorf("%d-byte secret: Combine() = %x, %v", size, combined, err)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package share
+
+import (
+	"crypto/ecdh"
+	"crypto/hkdf"
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"strings"
+
+	"filippo.io/age"
+
+	"pass-cli/internal/crypto"
+)
+
+// authInfo separates bundle authentication keys from other uses of the
+// X25519 shared secret
+const authInfo = "pass-cli-share auth v2"
+
+const bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+// authTag authenticates body from the sender to one recipient. The key comes
+// from the X25519 shared secret of the sender's identity and the recipient's
+// public key, which only the two of them can compute, so a valid tag proves the
+// bundle was written by the holder of the sender's identity (or the recipient).
+func authTag(identity *age.X25519Identity, peer *age.X25519Recipient, from, to string, body []byte) ([]byte, error) {
+	secretKey, err := bech32Data(identity.String())
+	if err != nil {
+		return nil, err
+	}
+	defer crypto.ClearBytes(secretKey)
+	publicKey, err := bech32Data(peer.String())
+	if err != nil {
+		return nil, err
+	}
+
+	private, err := ecdh.X25519().NewPrivateKey(secretKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid identity: %w", err)
+	}
+	public, err := ecdh.X25519().NewPublicKey(publicKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key: %w", err)
+	}
+	shared, err := private.ECDH(public)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute shared secret: %w", err)
+	}
+	defer crypto.ClearBytes(shared)
+
+	key, err := hkdf.Key(sha256.New, shared, nil, authInfo+" "+from+" "+to, sha256.Size)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive authentication key: %w", err)
+	}
+	defer crypto.ClearBytes(key)
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write(body)
+	return mac.Sum(nil), nil
+}
+
+// bech32Data returns the bytes encoded in a bech32 string that age has
+// already parsed (and so checksummed), such as "age1..." or "AGE-SECRET-KEY-1..."
+func bech32Data(s string) ([]byte, error) {
+	s = strings.ToLower(s)
+	sep := strings.LastIndexByte(s, '1')
+	if sep < 0 || len(s)-sep-1 < 6 {
+		return nil, errors.New("invalid bech32 string")
+	}
+
+	// Regroup the 5-bit characters (minus the 6-character checksum) into bytes;
+	// leftover bits are padding
+	data := make([]byte, 0, (len(s)-sep-7)*5/8)
+	acc, bits := 0, 0
+	for _, c := range s[sep+1 : len(s)-6] {
+		value := strings.IndexRune(bech32Charset, c)
+		if value < 0 {
+			return nil, errors.New("invalid bech32 string")
+		}
+		acc = acc<<5 | value
+		bits += 5
+		if bits >= 8 {
+			bits -= 8
+			data = append(data, byte(acc>>bits))
+			acc &= 1<<bits - 1
+		}
+	}
+	return data, nil
+}
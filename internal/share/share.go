@@ -0,0 +1,206 @@
+// Package share reads and writes share bundles: credentials encrypted with age
+// to the X25519 public keys of other pass-cli users. Bundles are ASCII-armored
+// so they can be pasted into chat or email, and can be inspected with the age
+// command line tool by anyone holding a recipient identity. Each bundle carries
+// an authentication tag per recipient, so recipients can verify the sender.
+package share
+
+import (
+	"bufio"
+	"crypto/hmac"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"filippo.io/age"
+	"filippo.io/age/armor"
+
+	"pass-cli/internal/crypto"
+	"pass-cli/internal/vault"
+)
+
+const (
+	bundleFormatName = "pass-cli-share"
+	bundleVersion    = 2 // Version 1 bundles did not authenticate the sender
+)
+
+var (
+	ErrNotBundle        = errors.New("not a pass-cli share bundle")
+	ErrNotRecipient     = errors.New("share bundle is not encrypted to your identity")
+	ErrUnverifiedSender = errors.New("share bundle sender could not be verified")
+)
+
+// Bundle is a decrypted share bundle
+type Bundle struct {
+	From        string    // Sender's public key, verified by the bundle's authentication tag
+	CreatedAt   time.Time // When the sender created the bundle
+	Credentials []vault.Credential
+}
+
+// bundlePayload is what age encrypts. Body holds the bundleBody JSON, and Auth
+// one authentication tag of Body per recipient public key (see authTag).
+type bundlePayload struct {
+	Format  string            `json:"format"`
+	Version int               `json:"version"`
+	From    string            `json:"from"`
+	Body    []byte            `json:"body"`
+	Auth    map[string][]byte `json:"auth"`
+}
+
+type bundleBody struct {
+	CreatedAt   time.Time          `json:"created_at"`
+	Credentials []vault.Credential `json:"credentials"`
+}
+
+// ParseRecipients parses public keys ("age1...")
+func ParseRecipients(keys []string) ([]*age.X25519Recipient, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("at least one recipient is required")
+	}
+	recipients := make([]*age.X25519Recipient, 0, len(keys))
+	for _, key := range keys {
+		recipient, err := age.ParseX25519Recipient(strings.TrimSpace(key))
+		if err != nil {
+			return nil, fmt.Errorf("invalid public key %q: %w", key, err)
+		}
+		recipients = append(recipients, recipient)
+	}
+	return recipients, nil
+}
+
+// Write encrypts credentials to recipients. Password history, usage, and
+// attachments stay in the sender's vault.
+func Write(w io.Writer, from *age.X25519Identity, recipients []*age.X25519Recipient, credentials []vault.Credential) error {
+	to := make([]age.Recipient, len(recipients))
+	for i, recipient := range recipients {
+		to[i] = recipient
+	}
+
+	shared := make([]vault.Credential, len(credentials))
+	for i, credential := range credentials {
+		credential.PasswordHistory = nil
+		credential.Attachments = nil
+		credential.UsageRecord = nil
+		shared[i] = credential
+	}
+
+	body, err := json.Marshal(bundleBody{CreatedAt: time.Now().UTC(), Credentials: shared})
+	if err != nil {
+		return fmt.Errorf("failed to encode share bundle: %w", err)
+	}
+	defer crypto.ClearBytes(body)
+
+	sender := from.Recipient().String()
+	auth := make(map[string][]byte, len(recipients))
+	for _, recipient := range recipients {
+		tag, err := authTag(from, recipient, sender, recipient.String(), body)
+		if err != nil {
+			return fmt.Errorf("failed to authenticate share bundle: %w", err)
+		}
+		auth[recipient.String()] = tag
+	}
+
+	plaintext, err := json.Marshal(bundlePayload{
+		Format:  bundleFormatName,
+		Version: bundleVersion,
+		From:    sender,
+		Body:    body,
+		Auth:    auth,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode share bundle: %w", err)
+	}
+	defer crypto.ClearBytes(plaintext)
+
+	armored := armor.NewWriter(w)
+	encrypted, err := age.Encrypt(armored, to...)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt share bundle: %w", err)
+	}
+	if _, err := encrypted.Write(plaintext); err != nil {
+		return fmt.Errorf("failed to encrypt share bundle: %w", err)
+	}
+	if err := encrypted.Close(); err != nil {
+		return fmt.Errorf("failed to encrypt share bundle: %w", err)
+	}
+	return armored.Close()
+}
+
+// Read decrypts a bundle written by Write, armored or not, and verifies that
+// it was written by the sender it names
+func Read(r io.Reader, identity *age.X25519Identity) (*Bundle, error) {
+	buffered := bufio.NewReader(r)
+	var src io.Reader = buffered
+	if start, _ := buffered.Peek(len(armor.Header)); string(start) == armor.Header {
+		src = armor.NewReader(buffered)
+	}
+
+	decrypted, err := age.Decrypt(src, identity)
+	if err != nil {
+		var noMatch *age.NoIdentityMatchError
+		if errors.As(err, &noMatch) {
+			return nil, ErrNotRecipient
+		}
+		return nil, fmt.Errorf("%w: %v", ErrNotBundle, err)
+	}
+	plaintext, err := io.ReadAll(decrypted)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt share bundle: %w", err)
+	}
+	defer crypto.ClearBytes(plaintext)
+
+	var payload bundlePayload
+	if err := json.Unmarshal(plaintext, &payload); err != nil || payload.Format != bundleFormatName {
+		return nil, ErrNotBundle
+	}
+	defer crypto.ClearBytes(payload.Body)
+	if payload.Version > bundleVersion {
+		return nil, fmt.Errorf("share bundle version %d is newer than this pass-cli supports", payload.Version)
+	}
+	if payload.Version < bundleVersion {
+		return nil, fmt.Errorf("%w: version %d bundles are not authenticated, ask the sender to share again", ErrUnverifiedSender, payload.Version)
+	}
+
+	sender, err := age.ParseX25519Recipient(payload.From)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid sender public key", ErrUnverifiedSender)
+	}
+	to := identity.Recipient().String()
+	expected, err := authTag(identity, sender, payload.From, to, payload.Body)
+	if err != nil {
+		return nil, err
+	}
+	if !hmac.Equal(expected, payload.Auth[to]) {
+		return nil, fmt.Errorf("%w: the bundle was not written by %s", ErrUnverifiedSender, payload.From)
+	}
+
+	var body bundleBody
+	if err := json.Unmarshal(payload.Body, &body); err != nil {
+		return nil, ErrNotBundle
+	}
+	return &Bundle{From: payload.From, CreatedAt: body.CreatedAt, Credentials: body.Credentials}, nil
+}
+
+// Entries converts the bundle's credentials for VaultService.ReceiveShare
+func (b *Bundle) Entries() []vault.ImportEntry {
+	entries := make([]vault.ImportEntry, 0, len(b.Credentials))
+	for _, credential := range b.Credentials {
+		entries = append(entries, vault.ImportEntry{
+			Service:      credential.Service,
+			Username:     credential.Username,
+			Password:     credential.Password,
+			Type:         credential.Type,
+			Category:     credential.Category,
+			Tags:         credential.Tags,
+			URL:          credential.URL,
+			Notes:        credential.Notes,
+			CustomFields: credential.CustomFields,
+			TOTP:         credential.TOTP,
+			ExpiresAt:    credential.ExpiresAt,
+		})
+	}
+	return entries
+}
@@ -0,0 +1,193 @@
+package share
+
+import (
+	"bytes"
+	"crypto/ecdh"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"filippo.io/age"
+
+	"pass-cli/internal/vault"
+)
+
+func newIdentity(t *testing.T) *age.X25519Identity {
+	t.Helper()
+	identity, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatalf("GenerateX25519Identity() failed: %v", err)
+	}
+	return identity
+}
+
+func TestWriteRead(t *testing.T) {
+	sender, alice, bob, eve := newIdentity(t), newIdentity(t), newIdentity(t), newIdentity(t)
+
+	recipients, err := ParseRecipients([]string{alice.Recipient().String(), " " + bob.Recipient().String() + "\n"})
+	if err != nil {
+		t.Fatalf("ParseRecipients() failed: %v", err)
+	}
+
+	credentials := []vault.Credential{{
+		Service:         "github",
+		Username:        "octocat",
+		Password:        []byte("s3cret"),
+		Tags:            []string{"work"},
+		PasswordHistory: []vault.PasswordHistoryEntry{{Password: []byte("old")}},
+		UsageRecord:     map[string]vault.UsageRecord{"/home/me/project": {}},
+	}}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, sender, recipients, credentials); err != nil {
+		t.Fatalf("Write() failed: %v", err)
+	}
+	if !strings.HasPrefix(buf.String(), "-----BEGIN AGE ENCRYPTED FILE-----") {
+		t.Error("expected an ASCII-armored bundle")
+	}
+	if strings.Contains(buf.String(), "s3cret") {
+		t.Error("bundle contains the plaintext password")
+	}
+
+	// Every recipient can read the bundle
+	for _, identity := range []*age.X25519Identity{alice, bob} {
+		bundle, err := Read(bytes.NewReader(buf.Bytes()), identity)
+		if err != nil {
+			t.Fatalf("Read() failed: %v", err)
+		}
+		if bundle.From != sender.Recipient().String() {
+			t.Errorf("From = %q, want the sender's public key", bundle.From)
+		}
+		if bundle.CreatedAt.IsZero() {
+			t.Error("CreatedAt not set")
+		}
+		if len(bundle.Credentials) != 1 {
+			t.Fatalf("expected 1 credential, got %d", len(bundle.Credentials))
+		}
+		got := bundle.Credentials[0]
+		if got.Service != "github" || got.Username != "octocat" || string(got.Password) != "s3cret" {
+			t.Errorf("unexpected credential: %+v", got)
+		}
+		if len(got.PasswordHistory) != 0 || len(got.UsageRecord) != 0 {
+			t.Error("password history and usage should not be shared")
+		}
+
+		entries := bundle.Entries()
+		if len(entries) != 1 || entries[0].Service != "github" || len(entries[0].Tags) != 1 {
+			t.Errorf("unexpected entries: %+v", entries)
+		}
+	}
+
+	// The sender's copy is left alone
+	if len(credentials[0].PasswordHistory) != 1 {
+		t.Error("Write() modified the caller's credentials")
+	}
+
+	if _, err := Read(bytes.NewReader(buf.Bytes()), eve); !errors.Is(err, ErrNotRecipient) {
+		t.Errorf("expected ErrNotRecipient, got %v", err)
+	}
+}
+
+func TestReadRejectsOtherFiles(t *testing.T) {
+	identity := newIdentity(t)
+
+	if _, err := Read(strings.NewReader("service,username,password\n"), identity); !errors.Is(err, ErrNotBundle) {
+		t.Errorf("expected ErrNotBundle for plaintext, got %v", err)
+	}
+
+	// An age file for the right identity that pass-cli did not write
+	var buf bytes.Buffer
+	w, err := age.Encrypt(&buf, identity.Recipient())
+	if err != nil {
+		t.Fatalf("Encrypt() failed: %v", err)
+	}
+	_, _ = w.Write([]byte(`{"hello": "world"}`))
+	_ = w.Close()
+	if _, err := Read(&buf, identity); !errors.Is(err, ErrNotBundle) {
+		t.Errorf("expected ErrNotBundle for a foreign age file, got %v", err)
+	}
+}
+
+// encryptPayload writes a bundle payload as Write would, without its checks
+func encryptPayload(t *testing.T, payload bundlePayload, to *age.X25519Recipient) *bytes.Buffer {
+	t.Helper()
+	plaintext, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatalf("Marshal() failed: %v", err)
+	}
+	var buf bytes.Buffer
+	w, err := age.Encrypt(&buf, to)
+	if err != nil {
+		t.Fatalf("Encrypt() failed: %v", err)
+	}
+	_, _ = w.Write(plaintext)
+	_ = w.Close()
+	return &buf
+}
+
+func TestReadVerifiesSender(t *testing.T) {
+	sender, alice, eve := newIdentity(t), newIdentity(t), newIdentity(t)
+	from, to := sender.Recipient().String(), alice.Recipient().String()
+	body, _ := json.Marshal(bundleBody{Credentials: []vault.Credential{{Service: "github", Password: []byte("forged")}}})
+
+	// Eve knows both public keys but not the sender's identity
+	tag, err := authTag(eve, alice.Recipient(), from, to, body)
+	if err != nil {
+		t.Fatalf("authTag() failed: %v", err)
+	}
+	forged := bundlePayload{Format: bundleFormatName, Version: bundleVersion, From: from, Body: body, Auth: map[string][]byte{to: tag}}
+	if _, err := Read(encryptPayload(t, forged, alice.Recipient()), alice); !errors.Is(err, ErrUnverifiedSender) {
+		t.Errorf("expected ErrUnverifiedSender for a forged sender, got %v", err)
+	}
+
+	// The sender's own tag does not cover a changed body
+	tag, _ = authTag(sender, alice.Recipient(), from, to, body)
+	tampered := bundlePayload{Format: bundleFormatName, Version: bundleVersion, From: from, Body: append(body[:len(body)-1:len(body)-1], ' ', '}'), Auth: map[string][]byte{to: tag}}
+	if _, err := Read(encryptPayload(t, tampered, alice.Recipient()), alice); !errors.Is(err, ErrUnverifiedSender) {
+		t.Errorf("expected ErrUnverifiedSender for a changed body, got %v", err)
+	}
+
+	// Bundles without authentication are rejected
+	unsigned := bundlePayload{Format: bundleFormatName, Version: 1, From: from}
+	if _, err := Read(encryptPayload(t, unsigned, alice.Recipient()), alice); !errors.Is(err, ErrUnverifiedSender) {
+		t.Errorf("expected ErrUnverifiedSender for a version 1 bundle, got %v", err)
+	}
+
+	genuine := bundlePayload{Format: bundleFormatName, Version: bundleVersion, From: from, Body: body, Auth: map[string][]byte{to: tag}}
+	if _, err := Read(encryptPayload(t, genuine, alice.Recipient()), alice); err != nil {
+		t.Errorf("Read() of a genuine bundle failed: %v", err)
+	}
+}
+
+func TestBech32Keys(t *testing.T) {
+	identity := newIdentity(t)
+	secretKey, err := bech32Data(identity.String())
+	if err != nil {
+		t.Fatalf("bech32Data(identity) failed: %v", err)
+	}
+	publicKey, err := bech32Data(identity.Recipient().String())
+	if err != nil {
+		t.Fatalf("bech32Data(recipient) failed: %v", err)
+	}
+
+	private, err := ecdh.X25519().NewPrivateKey(secretKey)
+	if err != nil {
+		t.Fatalf("NewPrivateKey() failed: %v", err)
+	}
+	if !bytes.Equal(private.PublicKey().Bytes(), publicKey) {
+		t.Error("decoded public key does not match the decoded identity")
+	}
+}
+
+func TestParseRecipients(t *testing.T) {
+	if _, err := ParseRecipients(nil); err == nil {
+		t.Error("expected an error without recipients")
+	}
+	if _, err := ParseRecipients([]string{"ssh-ed25519 AAAA"}); err == nil {
+		t.Error("expected an error for a non-age key")
+	}
+	if _, err := ParseRecipients([]string{newIdentity(t).String()}); err == nil {
+		t.Error("expected an error for a secret key")
+	}
+}
@@ -0,0 +1,461 @@
+package storage
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"pass-cli/internal/crypto"
+)
+
+// EnvelopeVersion is the metadata version of vaults whose payload is encrypted
+// with a random data key wrapped by key slots. Version 1 vaults encrypt the
+// payload directly with the key derived from the master password.
+const EnvelopeVersion = 2
+
+// Key slot types
+const (
+	KeySlotPassword        = "password"         // Master password
+	KeySlotPasswordKeyFile = "password+keyfile" // Master password combined with a key file
+	KeySlotKeyFile         = "keyfile"          // Contents of a key file
+	KeySlotRecovery        = "recovery"         // Generated recovery code
+	KeySlotShares          = "shares"           // Recovery key split into Shamir shares
+)
+
+var (
+	ErrKeySlotNotFound  = errors.New("key slot not found")
+	ErrNoMatchingSlot   = fmt.Errorf("no key slot matches (invalid password?): %w", crypto.ErrDecryptionFailed)
+	ErrLastKeySlot      = errors.New("cannot remove the last key slot")
+	ErrNotEnvelopeVault = errors.New("vault does not use key slots")
+)
+
+// KeySlot holds the vault's data key encrypted under a key derived from one
+// unlock secret. Each slot has its own salt and key derivation parameters.
+type KeySlot struct {
+	ID          int       `json:"id"`
+	Type        string    `json:"type"`
+	Label       string    `json:"label,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	Salt        []byte    `json:"salt"`
+	KDF         string    `json:"kdf"`
+	Iterations  int       `json:"iterations,omitempty"`  // PBKDF2 iteration count
+	Memory      uint32    `json:"memory,omitempty"`      // Argon2id memory in KiB
+	TimeCost    uint32    `json:"time_cost,omitempty"`   // Argon2id passes
+	Parallelism uint8     `json:"parallelism,omitempty"` // Argon2id threads
+	WrappedKey  []byte    `json:"wrapped_key"`           // Data key encrypted with AES-256-GCM
+}
+
+// KDFParams returns the key derivation parameters of the slot
+func (k KeySlot) KDFParams() crypto.KDFParams {
+	if k.KDF == crypto.KDFArgon2id {
+		return crypto.KDFParams{Algorithm: crypto.KDFArgon2id, Memory: k.Memory, Time: k.TimeCost, Threads: k.Parallelism}
+	}
+	return crypto.PBKDF2Params(k.Iterations)
+}
+
+func (k *KeySlot) setKDFParams(params crypto.KDFParams) {
+	k.KDF = params.Algorithm
+	k.Iterations, k.Memory, k.TimeCost, k.Parallelism = 0, 0, 0, 0
+	if params.Algorithm == crypto.KDFArgon2id {
+		k.Memory, k.TimeCost, k.Parallelism = params.Memory, params.Time, params.Threads
+	} else {
+		k.Iterations = params.Iterations
+	}
+}
+
+// HasKeySlots reports whether the vault uses envelope encryption
+func (s *StorageService) HasKeySlots() (bool, error) {
+	encryptedVault, err := s.loadEncryptedVault()
+	if err != nil {
+		return false, err
+	}
+	return len(encryptedVault.KeySlots) > 0, nil
+}
+
+// ListKeySlots returns the vault's key slots without their salts and wrapped keys
+func (s *StorageService) ListKeySlots() ([]KeySlot, error) {
+	encryptedVault, err := s.loadEncryptedVault()
+	if err != nil {
+		return nil, err
+	}
+	slots := make([]KeySlot, len(encryptedVault.KeySlots))
+	for i, slot := range encryptedVault.KeySlots {
+		slot.Salt, slot.WrappedKey = nil, nil
+		slots[i] = slot
+	}
+	return slots, nil
+}
+
+// OpenDataKey returns the key that encrypts the vault payload and the ID of
+// the slot that unlocked it. Only slots of the given types are tried. Version 1
+// vaults have no slots: the key derived from the password is returned with ID 0.
+func (s *StorageService) OpenDataKey(secret []byte, types ...string) ([]byte, int, error) {
+	encryptedVault, err := s.loadEncryptedVault()
+	if err != nil {
+		return nil, 0, err
+	}
+	return s.openDataKey(encryptedVault, secret, types)
+}
+
+func (s *StorageService) openDataKey(encryptedVault *EncryptedVault, secret []byte, types []string) ([]byte, int, error) {
+	if len(encryptedVault.KeySlots) == 0 {
+		key, err := s.cryptoService.DeriveKeyWithParams(secret, encryptedVault.Metadata.Salt, encryptedVault.Metadata.KDFParams())
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to derive key: %w", err)
+		}
+		return key, 0, nil
+	}
+
+	for _, slot := range encryptedVault.KeySlots {
+		if !containsType(types, slot.Type) {
+			continue
+		}
+		slotKey, err := s.cryptoService.DeriveKeyWithParams(secret, slot.Salt, slot.KDFParams())
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to derive key for slot %d: %w", slot.ID, err)
+		}
+		dataKey, err := s.cryptoService.Decrypt(slot.WrappedKey, slotKey)
+		s.cryptoService.ClearKey(slotKey)
+		if err == nil {
+			return dataKey, slot.ID, nil
+		}
+	}
+	return nil, 0, ErrNoMatchingSlot
+}
+
+func containsType(types []string, slotType string) bool {
+	if len(types) == 0 {
+		return true
+	}
+	for _, t := range types {
+		if t == slotType {
+			return true
+		}
+	}
+	return false
+}
+
+// LoadVaultWithKey decrypts the vault payload with a key from OpenDataKey
+func (s *StorageService) LoadVaultWithKey(dataKey []byte) ([]byte, error) {
+	encryptedVault, err := s.loadEncryptedVault()
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := s.cryptoService.Decrypt(encryptedVault.Data, dataKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt vault (invalid password?): %w", err)
+	}
+	return plaintext, nil
+}
+
+// SaveVaultWithKey encrypts and saves the vault payload with a key from
+// OpenDataKey. No key derivation takes place, and key slots are kept as they are.
+func (s *StorageService) SaveVaultWithKey(data []byte, dataKey []byte) error {
+	return s.saveVaultWithKey(data, dataKey, true)
+}
+
+// SaveVaultWithKeyNoSnapshot saves like SaveVaultWithKey but takes no snapshot.
+// It is meant for saves that leave the credentials alone (usage tracking, audit
+// settings), which would otherwise push real changes out of snapshot retention.
+func (s *StorageService) SaveVaultWithKeyNoSnapshot(data []byte, dataKey []byte) error {
+	return s.saveVaultWithKey(data, dataKey, false)
+}
+
+func (s *StorageService) saveVaultWithKey(data []byte, dataKey []byte, snapshot bool) error {
+	encryptedVault, err := s.loadEncryptedVault()
+	if err != nil {
+		return err
+	}
+	encryptedVault.Metadata.UpdatedAt = time.Now()
+
+	if err := s.createBackup(); err != nil {
+		return fmt.Errorf("failed to create backup: %w", err)
+	}
+
+	if err := s.writeVault(data, dataKey, encryptedVault.Metadata, encryptedVault.KeySlots); err != nil {
+		// Restore from backup on failure
+		if restoreErr := s.restoreFromBackup(); restoreErr != nil {
+			return fmt.Errorf("save failed and backup restore failed: %v (original error: %w)", restoreErr, err)
+		}
+		return fmt.Errorf("failed to save vault: %w", err)
+	}
+
+	if snapshot {
+		s.snapshotAfterSave(encryptedVault.Metadata)
+	}
+	return nil
+}
+
+// ConvertToKeySlots re-encrypts a version 1 vault with a new random data key
+// and wraps that key in slot 1 of slotType, derived from secret with params.
+// Returns the data key.
+func (s *StorageService) ConvertToKeySlots(data []byte, slotType string, secret []byte, params crypto.KDFParams) ([]byte, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	encryptedVault, err := s.loadEncryptedVault()
+	if err != nil {
+		return nil, err
+	}
+	if len(encryptedVault.KeySlots) > 0 {
+		return nil, errors.New("vault already uses key slots")
+	}
+
+	// T036d: Pre-flight checks before migration (FR-012)
+	if err := s.preflightChecks(); err != nil {
+		return nil, fmt.Errorf("pre-flight check failed: %w", err)
+	}
+
+	dataKey, err := s.cryptoService.SecureRandom(crypto.KeyLength)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate data key: %w", err)
+	}
+	slot, err := s.newKeySlot(1, slotType, "", secret, params, dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata := envelopeMetadata(encryptedVault.Metadata)
+	if err := s.createBackup(); err != nil {
+		return nil, fmt.Errorf("failed to create backup: %w", err)
+	}
+	if err := s.writeVault(data, dataKey, metadata, []KeySlot{*slot}); err != nil {
+		if restoreErr := s.restoreFromBackup(); restoreErr != nil {
+			return nil, fmt.Errorf("save failed and backup restore failed: %v (original error: %w)", restoreErr, err)
+		}
+		return nil, fmt.Errorf("failed to save vault: %w", err)
+	}
+
+	s.snapshotAfterSave(metadata)
+	return dataKey, nil
+}
+
+// AddKeySlot wraps the data key under a new secret and returns the new slot's ID
+func (s *StorageService) AddKeySlot(dataKey []byte, slotType, label string, secret []byte, params crypto.KDFParams) (int, error) {
+	if err := params.Validate(); err != nil {
+		return 0, err
+	}
+
+	var id int
+	err := s.updateKeySlots(dataKey, func(slots []KeySlot) ([]KeySlot, error) {
+		for _, slot := range slots {
+			id = max(id, slot.ID)
+		}
+		id++
+		slot, err := s.newKeySlot(id, slotType, label, secret, params, dataKey)
+		if err != nil {
+			return nil, err
+		}
+		return append(slots, *slot), nil
+	})
+	return id, err
+}
+
+// UpdateKeySlot wraps the data key under a new secret and key derivation
+// parameters in an existing slot, which becomes slotType (e.g. to add or drop
+// a key file requirement); the payload is not re-encrypted
+func (s *StorageService) UpdateKeySlot(dataKey []byte, id int, slotType string, secret []byte, params crypto.KDFParams) error {
+	if err := params.Validate(); err != nil {
+		return err
+	}
+
+	return s.updateKeySlots(dataKey, func(slots []KeySlot) ([]KeySlot, error) {
+		for i, slot := range slots {
+			if slot.ID != id {
+				continue
+			}
+			updated, err := s.newKeySlot(id, slotType, slot.Label, secret, params, dataKey)
+			if err != nil {
+				return nil, err
+			}
+			slots[i] = *updated
+			return slots, nil
+		}
+		return nil, fmt.Errorf("%w: %d", ErrKeySlotNotFound, id)
+	})
+}
+
+// RemoveKeySlot deletes a slot; the last remaining slot cannot be removed
+func (s *StorageService) RemoveKeySlot(dataKey []byte, id int) error {
+	return s.updateKeySlots(dataKey, func(slots []KeySlot) ([]KeySlot, error) {
+		for i, slot := range slots {
+			if slot.ID != id {
+				continue
+			}
+			if len(slots) == 1 {
+				return nil, ErrLastKeySlot
+			}
+			return append(slots[:i], slots[i+1:]...), nil
+		}
+		return nil, fmt.Errorf("%w: %d", ErrKeySlotNotFound, id)
+	})
+}
+
+// Rekey re-encrypts the vault payload with a new random data key and wraps it
+// in the slots whose secrets are given, by slot ID; other slots are dropped.
+// Each secret must open its slot, and each kept slot keeps its ID, type, label
+// and key derivation. Snapshots that dataKey opens are re-encrypted the same
+// way, the others and the backup are removed, so nothing left on disk opens
+// with the old key or a dropped slot. Returns the new data key.
+func (s *StorageService) Rekey(data []byte, dataKey []byte, secrets map[int][]byte) ([]byte, error) {
+	encryptedVault, err := s.loadEncryptedVault()
+	if err != nil {
+		return nil, err
+	}
+	if len(encryptedVault.KeySlots) == 0 {
+		return nil, ErrNotEnvelopeVault
+	}
+	plaintext, err := s.cryptoService.Decrypt(encryptedVault.Data, dataKey)
+	if err != nil {
+		return nil, fmt.Errorf("data key does not match vault: %w", err)
+	}
+	crypto.ClearBytes(plaintext)
+
+	newKey, err := s.cryptoService.SecureRandom(crypto.KeyLength)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate data key: %w", err)
+	}
+	slots, err := s.rewrapKeySlots(encryptedVault.KeySlots, dataKey, newKey, secrets)
+	if err != nil {
+		crypto.ClearBytes(newKey)
+		return nil, err
+	}
+
+	metadata := encryptedVault.Metadata
+	metadata.UpdatedAt = time.Now()
+	if err := s.createBackup(); err != nil {
+		crypto.ClearBytes(newKey)
+		return nil, fmt.Errorf("failed to create backup: %w", err)
+	}
+	if err := s.writeVault(data, newKey, metadata, slots); err != nil {
+		crypto.ClearBytes(newKey)
+		if restoreErr := s.restoreFromBackup(); restoreErr != nil {
+			return nil, fmt.Errorf("save failed and backup restore failed: %v (original error: %w)", restoreErr, err)
+		}
+		return nil, fmt.Errorf("failed to save vault: %w", err)
+	}
+
+	// The vault now only opens with the new key; older copies must not linger.
+	// Failures only warn: the vault itself was saved successfully.
+	if err := s.RemoveBackup(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to remove %s%s, which opens with the old key: %v\n", s.vaultPath, BackupSuffix, err)
+	}
+	if err := s.rekeySnapshots(dataKey, newKey, slots); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: snapshots in %s may still open with the old key: %v\n", s.SnapshotDir(), err)
+	}
+	s.snapshotAfterSave(metadata)
+	return newKey, nil
+}
+
+// rewrapKeySlots wraps newKey in each slot that has a secret, after checking
+// that the secret unwraps dataKey from it
+func (s *StorageService) rewrapKeySlots(slots []KeySlot, dataKey, newKey []byte, secrets map[int][]byte) ([]KeySlot, error) {
+	var rewrapped []KeySlot
+	for _, slot := range slots {
+		secret, ok := secrets[slot.ID]
+		if !ok {
+			continue
+		}
+		slotKey, err := s.cryptoService.DeriveKeyWithParams(secret, slot.Salt, slot.KDFParams())
+		if err != nil {
+			return nil, fmt.Errorf("failed to derive key for slot %d: %w", slot.ID, err)
+		}
+		unwrapped, err := s.cryptoService.Decrypt(slot.WrappedKey, slotKey)
+		s.cryptoService.ClearKey(slotKey)
+		if err != nil || !bytes.Equal(unwrapped, dataKey) {
+			crypto.ClearBytes(unwrapped)
+			return nil, fmt.Errorf("secret does not open key slot %d: %w", slot.ID, ErrNoMatchingSlot)
+		}
+		crypto.ClearBytes(unwrapped)
+
+		updated, err := s.newKeySlot(slot.ID, slot.Type, slot.Label, secret, slot.KDFParams(), newKey)
+		if err != nil {
+			return nil, err
+		}
+		updated.CreatedAt = slot.CreatedAt
+		rewrapped = append(rewrapped, *updated)
+	}
+	if len(rewrapped) == 0 {
+		return nil, ErrLastKeySlot
+	}
+	return rewrapped, nil
+}
+
+// updateKeySlots rewrites the key slots of an envelope vault, keeping the
+// encrypted payload. dataKey must open the vault so a wrong key cannot be
+// wrapped into a slot.
+func (s *StorageService) updateKeySlots(dataKey []byte, update func([]KeySlot) ([]KeySlot, error)) error {
+	encryptedVault, err := s.loadEncryptedVault()
+	if err != nil {
+		return err
+	}
+	if len(encryptedVault.KeySlots) == 0 {
+		return ErrNotEnvelopeVault
+	}
+	plaintext, err := s.cryptoService.Decrypt(encryptedVault.Data, dataKey)
+	if err != nil {
+		return fmt.Errorf("data key does not match vault: %w", err)
+	}
+	crypto.ClearBytes(plaintext)
+
+	slots, err := update(append([]KeySlot(nil), encryptedVault.KeySlots...))
+	if err != nil {
+		return err
+	}
+	encryptedVault.KeySlots = slots
+	encryptedVault.Metadata.UpdatedAt = time.Now()
+
+	if err := s.createBackup(); err != nil {
+		return fmt.Errorf("failed to create backup: %w", err)
+	}
+	if err := s.writeEncryptedVault(encryptedVault); err != nil {
+		if restoreErr := s.restoreFromBackup(); restoreErr != nil {
+			return fmt.Errorf("save failed and backup restore failed: %v (original error: %w)", restoreErr, err)
+		}
+		return fmt.Errorf("failed to save vault: %w", err)
+	}
+	return nil
+}
+
+// newKeySlot derives a slot key from secret with a fresh salt and wraps dataKey with it
+func (s *StorageService) newKeySlot(id int, slotType, label string, secret []byte, params crypto.KDFParams, dataKey []byte) (*KeySlot, error) {
+	salt, err := s.cryptoService.GenerateSalt()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate salt: %w", err)
+	}
+	slotKey, err := s.cryptoService.DeriveKeyWithParams(secret, salt, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive key: %w", err)
+	}
+	defer s.cryptoService.ClearKey(slotKey)
+
+	wrapped, err := s.cryptoService.Encrypt(dataKey, slotKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to wrap data key: %w", err)
+	}
+
+	slot := &KeySlot{
+		ID:         id,
+		Type:       slotType,
+		Label:      label,
+		CreatedAt:  time.Now(),
+		Salt:       salt,
+		WrappedKey: wrapped,
+	}
+	slot.setKDFParams(params)
+	return slot, nil
+}
+
+// envelopeMetadata converts version 1 metadata: the salt and key derivation
+// parameters move into the key slots
+func envelopeMetadata(metadata VaultMetadata) VaultMetadata {
+	metadata.Version = EnvelopeVersion
+	metadata.UpdatedAt = time.Now()
+	metadata.Salt = nil
+	metadata.KDF, metadata.Iterations, metadata.Memory, metadata.TimeCost, metadata.Parallelism = "", 0, 0, 0, 0
+	return metadata
+}
@@ -0,0 +1,179 @@
+package storage
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"pass-cli/internal/crypto"
+)
+
+// testSlotKDF keeps the extra key slots cheap to derive
+var testSlotKDF = crypto.KDFParams{Algorithm: crypto.KDFArgon2id, Memory: crypto.MinArgon2Memory, Time: crypto.MinArgon2Time, Threads: 1}
+
+func TestStorageService_KeySlots(t *testing.T) {
+	storage, password := setupSnapshotStorage(t)
+
+	payload := []byte(`{"credentials":{}}`)
+	if err := storage.SaveVault(payload, password); err != nil {
+		t.Fatalf("SaveVault failed: %v", err)
+	}
+
+	dataKey, id, err := storage.OpenDataKey([]byte(password), KeySlotPassword)
+	if err != nil {
+		t.Fatalf("OpenDataKey failed: %v", err)
+	}
+	if id != 1 {
+		t.Errorf("Expected slot 1 to open, got %d", id)
+	}
+
+	recoveryID, err := storage.AddKeySlot(dataKey, KeySlotRecovery, "safe", []byte("RECOVERYCODE"), testSlotKDF)
+	if err != nil {
+		t.Fatalf("AddKeySlot failed: %v", err)
+	}
+	if recoveryID != 2 {
+		t.Errorf("Expected new slot ID 2, got %d", recoveryID)
+	}
+
+	// Slots are only tried for the requested types
+	if _, _, err := storage.OpenDataKey([]byte("RECOVERYCODE"), KeySlotPassword); !errors.Is(err, ErrNoMatchingSlot) {
+		t.Errorf("Expected ErrNoMatchingSlot, got %v", err)
+	}
+	recoveryKey, id, err := storage.OpenDataKey([]byte("RECOVERYCODE"), KeySlotRecovery)
+	if err != nil {
+		t.Fatalf("OpenDataKey with recovery slot failed: %v", err)
+	}
+	if id != recoveryID || !bytes.Equal(recoveryKey, dataKey) {
+		t.Error("Recovery slot should unwrap the same data key")
+	}
+
+	// Rewriting a slot keeps the payload and the other slots
+	if err := storage.UpdateKeySlot(dataKey, 1, KeySlotPassword, []byte("new-password"), testSlotKDF); err != nil {
+		t.Fatalf("UpdateKeySlot failed: %v", err)
+	}
+	if _, err := storage.LoadVault(password); !errors.Is(err, crypto.ErrDecryptionFailed) {
+		t.Errorf("Expected the old password to fail, got %v", err)
+	}
+	data, err := storage.LoadVault("new-password")
+	if err != nil {
+		t.Fatalf("LoadVault with new password failed: %v", err)
+	}
+	if !bytes.Equal(data, payload) {
+		t.Errorf("Payload changed: %s", data)
+	}
+
+	slots, err := storage.ListKeySlots()
+	if err != nil {
+		t.Fatalf("ListKeySlots failed: %v", err)
+	}
+	if len(slots) != 2 || slots[1].Label != "safe" || slots[1].WrappedKey != nil {
+		t.Errorf("Unexpected key slots: %+v", slots)
+	}
+
+	if err := storage.RemoveKeySlot(dataKey, recoveryID); err != nil {
+		t.Fatalf("RemoveKeySlot failed: %v", err)
+	}
+	if err := storage.RemoveKeySlot(dataKey, 1); !errors.Is(err, ErrLastKeySlot) {
+		t.Errorf("Expected ErrLastKeySlot, got %v", err)
+	}
+	if err := storage.UpdateKeySlot(dataKey, recoveryID, KeySlotRecovery, []byte("x"), testSlotKDF); !errors.Is(err, ErrKeySlotNotFound) {
+		t.Errorf("Expected ErrKeySlotNotFound, got %v", err)
+	}
+
+	// A key that does not open the payload is never wrapped into a slot
+	wrongKey := make([]byte, crypto.KeyLength)
+	if _, err := storage.AddKeySlot(wrongKey, KeySlotPassword, "", []byte("other"), testSlotKDF); err == nil {
+		t.Error("Expected AddKeySlot with a wrong data key to fail")
+	}
+}
+
+func TestStorageService_ConvertToKeySlots(t *testing.T) {
+	storage, password := setupSnapshotStorage(t)
+
+	payload := []byte(`{"credentials":{}}`)
+	if err := storage.SaveVaultWithIterationsUnsafe(payload, password, crypto.LegacyIterations); err != nil {
+		t.Fatalf("SaveVaultWithIterationsUnsafe failed: %v", err)
+	}
+	if hasSlots, _ := storage.HasKeySlots(); hasSlots {
+		t.Fatal("Expected a version 1 vault")
+	}
+	if _, err := storage.AddKeySlot(make([]byte, crypto.KeyLength), KeySlotRecovery, "", []byte("x"), testSlotKDF); !errors.Is(err, ErrNotEnvelopeVault) {
+		t.Errorf("Expected ErrNotEnvelopeVault, got %v", err)
+	}
+
+	dataKey, err := storage.ConvertToKeySlots(payload, KeySlotPassword, []byte(password), testSlotKDF)
+	if err != nil {
+		t.Fatalf("ConvertToKeySlots failed: %v", err)
+	}
+	data, err := storage.LoadVaultWithKey(dataKey)
+	if err != nil {
+		t.Fatalf("LoadVaultWithKey failed: %v", err)
+	}
+	if !bytes.Equal(data, payload) {
+		t.Errorf("Payload changed: %s", data)
+	}
+	if _, err := storage.LoadVault(password); err != nil {
+		t.Errorf("LoadVault after conversion failed: %v", err)
+	}
+	if err := storage.ValidateVault(); err != nil {
+		t.Errorf("ValidateVault after conversion failed: %v", err)
+	}
+}
+
+func TestStorageService_Rekey(t *testing.T) {
+	storage, password := setupSnapshotStorage(t)
+
+	payload := []byte(`{"credentials":{}}`)
+	if err := storage.SaveVault(payload, password); err != nil {
+		t.Fatalf("SaveVault failed: %v", err)
+	}
+	dataKey, _, err := storage.OpenDataKey([]byte(password), KeySlotPassword)
+	if err != nil {
+		t.Fatalf("OpenDataKey failed: %v", err)
+	}
+	recoveryID, err := storage.AddKeySlot(dataKey, KeySlotRecovery, "safe", []byte("RECOVERYCODE"), testSlotKDF)
+	if err != nil {
+		t.Fatalf("AddKeySlot failed: %v", err)
+	}
+	if err := storage.SaveVaultWithKey(payload, dataKey); err != nil {
+		t.Fatalf("SaveVaultWithKey failed: %v", err)
+	}
+
+	// Secrets are checked against their slots, and at least one slot must remain
+	if _, err := storage.Rekey(payload, dataKey, map[int][]byte{1: []byte("wrong")}); !errors.Is(err, ErrNoMatchingSlot) {
+		t.Errorf("Expected ErrNoMatchingSlot for a wrong secret, got %v", err)
+	}
+	if _, err := storage.Rekey(payload, dataKey, nil); !errors.Is(err, ErrLastKeySlot) {
+		t.Errorf("Expected ErrLastKeySlot without secrets, got %v", err)
+	}
+
+	newKey, err := storage.Rekey(payload, dataKey, map[int][]byte{1: []byte(password)})
+	if err != nil {
+		t.Fatalf("Rekey failed: %v", err)
+	}
+	if bytes.Equal(newKey, dataKey) {
+		t.Fatal("Rekey kept the data key")
+	}
+	if _, err := storage.LoadVaultWithKey(dataKey); err == nil {
+		t.Error("Expected the old data key to fail")
+	}
+	if data, err := storage.LoadVault(password); err != nil || !bytes.Equal(data, payload) {
+		t.Errorf("LoadVault after rekey = %s, %v", data, err)
+	}
+	if _, _, err := storage.OpenDataKey([]byte("RECOVERYCODE"), KeySlotRecovery); !errors.Is(err, ErrNoMatchingSlot) {
+		t.Errorf("Expected dropped slot %d to fail, got %v", recoveryID, err)
+	}
+
+	snapshots, err := storage.ListSnapshots()
+	if err != nil || len(snapshots) == 0 {
+		t.Fatalf("Expected snapshots, got %v (%v)", snapshots, err)
+	}
+	for _, snapshot := range snapshots {
+		if _, err := storage.LoadSnapshotWithKey(snapshot.ID, newKey); err != nil {
+			t.Errorf("Snapshot %s does not open with the new key: %v", snapshot.ID, err)
+		}
+		if _, err := storage.LoadSnapshot(snapshot.ID, password); err != nil {
+			t.Errorf("Snapshot %s does not open with the kept password: %v", snapshot.ID, err)
+		}
+	}
+}
@@ -0,0 +1,405 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"pass-cli/internal/crypto"
+)
+
+const (
+	// SnapshotDirSuffix names the directory next to the vault that holds its snapshots
+	SnapshotDirSuffix = ".snapshots"
+	// DefaultSnapshotKeep is the number of snapshots kept when no retention is configured
+	DefaultSnapshotKeep = 10
+
+	snapshotIDLayout = "20060102-150405" // UTC save time
+	snapshotExt      = ".enc"
+)
+
+// ErrSnapshotNotFound is returned for unknown snapshot IDs
+var ErrSnapshotNotFound = errors.New("snapshot not found")
+
+// SnapshotRetention controls how many snapshots are kept
+type SnapshotRetention struct {
+	Keep   int           // Number of snapshots kept (0 = snapshots disabled)
+	MaxAge time.Duration // Snapshots older than this are removed (0 = no age limit)
+}
+
+// SnapshotInfo describes a stored snapshot
+type SnapshotInfo struct {
+	ID        string
+	CreatedAt time.Time
+	Size      int64
+
+	seq int // Disambiguates snapshots taken within the same second
+}
+
+// SnapshotDir returns the directory holding the vault's snapshots
+func (s *StorageService) SnapshotDir() string {
+	return s.vaultPath + SnapshotDirSuffix
+}
+
+// GetSnapshotRetention returns the effective retention policy stored in the vault metadata
+func (s *StorageService) GetSnapshotRetention() (SnapshotRetention, error) {
+	encryptedVault, err := s.loadEncryptedVault()
+	if err != nil {
+		return SnapshotRetention{}, err
+	}
+	return snapshotRetention(encryptedVault.Metadata), nil
+}
+
+// SetSnapshotRetention stores a new retention policy and prunes existing snapshots to match.
+// Only the plaintext metadata changes, so the vault data is not re-encrypted.
+func (s *StorageService) SetSnapshotRetention(retention SnapshotRetention) error {
+	if retention.Keep < 0 || retention.MaxAge < 0 {
+		return fmt.Errorf("snapshot retention cannot be negative")
+	}
+
+	encryptedVault, err := s.loadEncryptedVault()
+	if err != nil {
+		return err
+	}
+
+	encryptedVault.Metadata.SnapshotKeep = retention.Keep
+	if retention.Keep == 0 {
+		encryptedVault.Metadata.SnapshotKeep = -1 // Disabled (0 means default)
+	}
+	encryptedVault.Metadata.SnapshotMaxAge = retention.MaxAge
+
+	jsonData, err := json.Marshal(encryptedVault)
+	if err != nil {
+		return fmt.Errorf("failed to marshal vault data: %w", err)
+	}
+	if err := s.atomicWrite(s.vaultPath, jsonData); err != nil {
+		return err
+	}
+
+	return s.pruneSnapshots(retention)
+}
+
+// ListSnapshots returns the stored snapshots, newest first
+func (s *StorageService) ListSnapshots() ([]SnapshotInfo, error) {
+	entries, err := os.ReadDir(s.SnapshotDir())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot directory: %w", err)
+	}
+
+	var snapshots []SnapshotInfo
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, snapshotExt) {
+			continue
+		}
+		info, ok := parseSnapshotID(strings.TrimSuffix(name, snapshotExt))
+		if !ok {
+			continue // Not a snapshot file
+		}
+		if fileInfo, err := entry.Info(); err == nil {
+			info.Size = fileInfo.Size()
+		}
+		snapshots = append(snapshots, info)
+	}
+
+	sort.Slice(snapshots, func(i, j int) bool {
+		if !snapshots[i].CreatedAt.Equal(snapshots[j].CreatedAt) {
+			return snapshots[i].CreatedAt.After(snapshots[j].CreatedAt)
+		}
+		return snapshots[i].seq > snapshots[j].seq
+	})
+	return snapshots, nil
+}
+
+// LoadSnapshot decrypts a snapshot with the given master password
+func (s *StorageService) LoadSnapshot(id, password string) ([]byte, error) {
+	encryptedVault, err := s.readSnapshot(id)
+	if err != nil {
+		return nil, err
+	}
+
+	key, _, err := s.openDataKey(encryptedVault, []byte(password), []string{KeySlotPassword})
+	if err != nil {
+		return nil, err
+	}
+	defer s.cryptoService.ClearKey(key)
+
+	plaintext, err := s.cryptoService.Decrypt(encryptedVault.Data, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt snapshot (invalid password?): %w", err)
+	}
+	return plaintext, nil
+}
+
+// LoadSnapshotWithKey decrypts a snapshot with the vault's data key, which
+// stays the same across password changes once the vault uses key slots
+func (s *StorageService) LoadSnapshotWithKey(id string, dataKey []byte) ([]byte, error) {
+	encryptedVault, err := s.readSnapshot(id)
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := s.cryptoService.Decrypt(encryptedVault.Data, dataKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt snapshot: %w", err)
+	}
+	return plaintext, nil
+}
+
+// RestoreSnapshot replaces the vault file with a snapshot.
+// The current retention policy is kept, and the replaced vault is snapshotted
+// first (unless the newest snapshot already holds it) so the restore can be undone.
+func (s *StorageService) RestoreSnapshot(id string) error {
+	return s.restoreSnapshot(id, nil)
+}
+
+// RestoreSnapshotWithKey restores a snapshot encrypted with the vault's data
+// key but keeps the current key slots, so passwords and key files changed
+// since the snapshot stay in effect
+func (s *StorageService) RestoreSnapshotWithKey(id string, dataKey []byte) error {
+	return s.restoreSnapshot(id, dataKey)
+}
+
+func (s *StorageService) restoreSnapshot(id string, dataKey []byte) error {
+	snapshot, err := s.readSnapshot(id)
+	if err != nil {
+		return err
+	}
+
+	current, err := s.loadEncryptedVault()
+	if err != nil {
+		return err
+	}
+
+	if dataKey != nil {
+		if len(snapshot.KeySlots) == 0 || len(current.KeySlots) == 0 {
+			return ErrNotEnvelopeVault
+		}
+		plaintext, err := s.cryptoService.Decrypt(snapshot.Data, dataKey)
+		if err != nil {
+			return fmt.Errorf("failed to decrypt snapshot: %w", err)
+		}
+		crypto.ClearBytes(plaintext)
+		snapshot.KeySlots = current.KeySlots
+	}
+	retention := snapshotRetention(current.Metadata)
+	if retention.Keep > 0 && !s.latestSnapshotIsCurrent() {
+		if err := s.createSnapshot(retention); err != nil {
+			return fmt.Errorf("failed to snapshot current vault: %w", err)
+		}
+	}
+
+	snapshot.Metadata.SnapshotKeep = current.Metadata.SnapshotKeep
+	snapshot.Metadata.SnapshotMaxAge = current.Metadata.SnapshotMaxAge
+	snapshot.Metadata.UpdatedAt = time.Now()
+
+	jsonData, err := json.Marshal(snapshot)
+	if err != nil {
+		return fmt.Errorf("failed to marshal vault data: %w", err)
+	}
+
+	if err := s.createBackup(); err != nil {
+		return fmt.Errorf("failed to create backup: %w", err)
+	}
+	if err := s.atomicWrite(s.vaultPath, jsonData); err != nil {
+		return fmt.Errorf("failed to restore snapshot: %w", err)
+	}
+	return nil
+}
+
+// Private helper methods
+
+// snapshotRetention resolves the stored retention fields to an effective policy
+func snapshotRetention(metadata VaultMetadata) SnapshotRetention {
+	retention := SnapshotRetention{Keep: metadata.SnapshotKeep, MaxAge: metadata.SnapshotMaxAge}
+	switch {
+	case metadata.SnapshotKeep == 0:
+		retention.Keep = DefaultSnapshotKeep
+	case metadata.SnapshotKeep < 0:
+		retention.Keep = 0 // Disabled
+	}
+	return retention
+}
+
+// snapshotAfterSave records the vault just written and applies retention.
+// Failures only warn: the vault itself was saved successfully.
+func (s *StorageService) snapshotAfterSave(metadata VaultMetadata) {
+	retention := snapshotRetention(metadata)
+	if retention.Keep == 0 {
+		return
+	}
+
+	if err := s.createSnapshot(retention); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to create vault snapshot: %v\n", err)
+	}
+}
+
+// createSnapshot copies the encrypted vault file into the snapshot directory and prunes old snapshots
+func (s *StorageService) createSnapshot(retention SnapshotRetention) error {
+	data, err := os.ReadFile(s.vaultPath)
+	if err != nil {
+		return fmt.Errorf("failed to read vault file: %w", err)
+	}
+
+	dir := s.SnapshotDir()
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+
+	// Saves within the same second get a sequence suffix
+	base := time.Now().UTC().Format(snapshotIDLayout)
+	id := base
+	for seq := 2; ; seq++ {
+		if _, err := os.Stat(filepath.Join(dir, id+snapshotExt)); os.IsNotExist(err) {
+			break
+		}
+		id = fmt.Sprintf("%s-%d", base, seq)
+	}
+
+	if err := s.atomicWrite(filepath.Join(dir, id+snapshotExt), data); err != nil {
+		return err
+	}
+	return s.pruneSnapshots(retention)
+}
+
+// pruneSnapshots removes snapshots beyond the retention count or older than the maximum age.
+// The newest snapshot is always kept while snapshots are enabled.
+func (s *StorageService) pruneSnapshots(retention SnapshotRetention) error {
+	snapshots, err := s.ListSnapshots()
+	if err != nil {
+		return err
+	}
+
+	cutoff := time.Time{}
+	if retention.MaxAge > 0 {
+		cutoff = time.Now().Add(-retention.MaxAge)
+	}
+
+	for i, snapshot := range snapshots {
+		expired := !cutoff.IsZero() && snapshot.CreatedAt.Before(cutoff) && i > 0
+		if i < retention.Keep && !expired {
+			continue
+		}
+		if err := os.Remove(s.snapshotPath(snapshot.ID)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove snapshot %s: %w", snapshot.ID, err)
+		}
+	}
+	return nil
+}
+
+// rekeySnapshots re-encrypts the snapshots that oldKey opens with newKey and
+// the given key slots, and removes the others
+func (s *StorageService) rekeySnapshots(oldKey, newKey []byte, slots []KeySlot) error {
+	snapshots, err := s.ListSnapshots()
+	if err != nil {
+		return err
+	}
+
+	for _, info := range snapshots {
+		path := s.snapshotPath(info.ID)
+		snapshot, err := s.readSnapshot(info.ID)
+		if err == nil && len(snapshot.KeySlots) > 0 {
+			var plaintext []byte
+			if plaintext, err = s.cryptoService.Decrypt(snapshot.Data, oldKey); err == nil {
+				snapshot.Data, err = s.cryptoService.Encrypt(plaintext, newKey)
+				crypto.ClearBytes(plaintext)
+			}
+			if err == nil {
+				snapshot.KeySlots = slots
+				var jsonData []byte
+				if jsonData, err = json.Marshal(snapshot); err == nil {
+					err = s.atomicWrite(path, jsonData)
+				}
+			}
+			if err == nil {
+				continue
+			}
+		}
+
+		// Version 1 snapshots and those under another key open with old secrets
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove snapshot %s: %w", info.ID, err)
+		}
+	}
+	return nil
+}
+
+// latestSnapshotIsCurrent reports whether the newest snapshot is identical to the vault file
+func (s *StorageService) latestSnapshotIsCurrent() bool {
+	snapshots, err := s.ListSnapshots()
+	if err != nil || len(snapshots) == 0 {
+		return false
+	}
+
+	latest, err := os.ReadFile(s.snapshotPath(snapshots[0].ID))
+	if err != nil {
+		return false
+	}
+	current, err := os.ReadFile(s.vaultPath)
+	if err != nil {
+		return false
+	}
+	return bytes.Equal(latest, current)
+}
+
+// readSnapshot loads and parses a snapshot file
+func (s *StorageService) readSnapshot(id string) (*EncryptedVault, error) {
+	if _, ok := parseSnapshotID(id); !ok {
+		return nil, fmt.Errorf("%w: %s", ErrSnapshotNotFound, id)
+	}
+
+	data, err := os.ReadFile(s.snapshotPath(id))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("%w: %s", ErrSnapshotNotFound, id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot: %w", err)
+	}
+
+	var encryptedVault EncryptedVault
+	if err := json.Unmarshal(data, &encryptedVault); err != nil {
+		return nil, fmt.Errorf("%w: snapshot %s: %v", ErrVaultCorrupted, id, err)
+	}
+	if len(encryptedVault.KeySlots) == 0 && encryptedVault.Metadata.usesPBKDF2() && encryptedVault.Metadata.Iterations == 0 {
+		encryptedVault.Metadata.Iterations = 100000 // Legacy default
+	}
+	return &encryptedVault, nil
+}
+
+// snapshotPath returns the file path of a snapshot ID
+func (s *StorageService) snapshotPath(id string) string {
+	return filepath.Join(s.SnapshotDir(), id+snapshotExt)
+}
+
+// parseSnapshotID parses "20060102-150405" or "20060102-150405-N"
+func parseSnapshotID(id string) (SnapshotInfo, bool) {
+	if len(id) < len(snapshotIDLayout) {
+		return SnapshotInfo{}, false
+	}
+
+	createdAt, err := time.ParseInLocation(snapshotIDLayout, id[:len(snapshotIDLayout)], time.UTC)
+	if err != nil {
+		return SnapshotInfo{}, false
+	}
+
+	seq := 1
+	if rest := id[len(snapshotIDLayout):]; rest != "" {
+		n, err := strconv.Atoi(strings.TrimPrefix(rest, "-"))
+		if !strings.HasPrefix(rest, "-") || err != nil || n < 2 {
+			return SnapshotInfo{}, false
+		}
+		seq = n
+	}
+
+	return SnapshotInfo{ID: id, CreatedAt: createdAt, seq: seq}, true
+}
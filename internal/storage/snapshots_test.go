@@ -0,0 +1,205 @@
+package storage
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"pass-cli/internal/crypto"
+)
+
+func setupSnapshotStorage(t *testing.T) (*StorageService, string) {
+	t.Helper()
+
+	vaultPath := filepath.Join(t.TempDir(), "test_vault.enc")
+	storage, err := NewStorageService(crypto.NewCryptoService(), vaultPath)
+	if err != nil {
+		t.Fatalf("NewStorageService failed: %v", err)
+	}
+
+	password := "test-password"
+	if err := storage.InitializeVault(password); err != nil {
+		t.Fatalf("InitializeVault failed: %v", err)
+	}
+	return storage, password
+}
+
+func TestStorageService_SnapshotsOnSave(t *testing.T) {
+	storage, password := setupSnapshotStorage(t)
+
+	if err := storage.SetSnapshotRetention(SnapshotRetention{Keep: 2}); err != nil {
+		t.Fatalf("SetSnapshotRetention failed: %v", err)
+	}
+
+	for _, data := range []string{`{"n":1}`, `{"n":2}`, `{"n":3}`} {
+		if err := storage.SaveVault([]byte(data), password); err != nil {
+			t.Fatalf("SaveVault failed: %v", err)
+		}
+	}
+
+	snapshots, err := storage.ListSnapshots()
+	if err != nil {
+		t.Fatalf("ListSnapshots failed: %v", err)
+	}
+	if len(snapshots) != 2 {
+		t.Fatalf("Expected 2 snapshots after pruning, got %d", len(snapshots))
+	}
+
+	// Newest first: the latest save, then the one before it
+	for i, want := range []string{`{"n":3}`, `{"n":2}`} {
+		data, err := storage.LoadSnapshot(snapshots[i].ID, password)
+		if err != nil {
+			t.Fatalf("LoadSnapshot(%s) failed: %v", snapshots[i].ID, err)
+		}
+		if string(data) != want {
+			t.Errorf("Snapshot %d = %s, want %s", i, data, want)
+		}
+	}
+
+	if _, err := storage.LoadSnapshot(snapshots[0].ID, "wrong-password"); !errors.Is(err, crypto.ErrDecryptionFailed) {
+		t.Errorf("Expected ErrDecryptionFailed, got %v", err)
+	}
+	if _, err := storage.LoadSnapshot("../test_vault", password); !errors.Is(err, ErrSnapshotNotFound) {
+		t.Errorf("Expected ErrSnapshotNotFound for invalid ID, got %v", err)
+	}
+
+	info, err := os.Stat(storage.SnapshotDir())
+	if err != nil {
+		t.Fatalf("Stat snapshot dir failed: %v", err)
+	}
+	if info.Mode().Perm() != 0700 {
+		t.Errorf("Snapshot dir permissions = %o, want 0700", info.Mode().Perm())
+	}
+}
+
+func TestStorageService_SnapshotRetention(t *testing.T) {
+	storage, password := setupSnapshotStorage(t)
+
+	retention, err := storage.GetSnapshotRetention()
+	if err != nil {
+		t.Fatalf("GetSnapshotRetention failed: %v", err)
+	}
+	if retention.Keep != DefaultSnapshotKeep || retention.MaxAge != 0 {
+		t.Errorf("Default retention = %+v", retention)
+	}
+
+	// Plant snapshots of various ages
+	if err := os.MkdirAll(storage.SnapshotDir(), 0700); err != nil {
+		t.Fatalf("MkdirAll failed: %v", err)
+	}
+	vaultData, err := os.ReadFile(storage.vaultPath)
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	for _, age := range []time.Duration{48 * time.Hour, 72 * time.Hour, 30 * 24 * time.Hour} {
+		id := time.Now().Add(-age).UTC().Format(snapshotIDLayout)
+		if err := os.WriteFile(storage.snapshotPath(id), vaultData, VaultPermissions); err != nil {
+			t.Fatalf("WriteFile failed: %v", err)
+		}
+	}
+
+	// Age limit removes everything older than 7 days
+	if err := storage.SetSnapshotRetention(SnapshotRetention{Keep: 5, MaxAge: 7 * 24 * time.Hour}); err != nil {
+		t.Fatalf("SetSnapshotRetention failed: %v", err)
+	}
+	snapshots, _ := storage.ListSnapshots()
+	if len(snapshots) != 2 {
+		t.Fatalf("Expected 2 snapshots within the age limit, got %d", len(snapshots))
+	}
+
+	// The retention policy survives saves
+	if err := storage.SaveVault([]byte(`{}`), password); err != nil {
+		t.Fatalf("SaveVault failed: %v", err)
+	}
+	retention, _ = storage.GetSnapshotRetention()
+	if retention.Keep != 5 || retention.MaxAge != 7*24*time.Hour {
+		t.Errorf("Retention after save = %+v", retention)
+	}
+	if snapshots, _ := storage.ListSnapshots(); len(snapshots) != 3 {
+		t.Errorf("Expected 3 snapshots after save, got %d", len(snapshots))
+	}
+
+	// Keep 0 disables snapshots and removes existing ones
+	if err := storage.SetSnapshotRetention(SnapshotRetention{}); err != nil {
+		t.Fatalf("SetSnapshotRetention failed: %v", err)
+	}
+	if err := storage.SaveVault([]byte(`{}`), password); err != nil {
+		t.Fatalf("SaveVault failed: %v", err)
+	}
+	if snapshots, _ := storage.ListSnapshots(); len(snapshots) != 0 {
+		t.Errorf("Expected no snapshots when disabled, got %d", len(snapshots))
+	}
+}
+
+func TestStorageService_RestoreSnapshot(t *testing.T) {
+	storage, password := setupSnapshotStorage(t)
+
+	if err := storage.SaveVault([]byte(`{"n":1}`), password); err != nil {
+		t.Fatalf("SaveVault failed: %v", err)
+	}
+	snapshots, _ := storage.ListSnapshots()
+	if len(snapshots) != 1 {
+		t.Fatalf("Expected 1 snapshot, got %d", len(snapshots))
+	}
+	target := snapshots[0].ID
+
+	if err := storage.SaveVault([]byte(`{"n":2}`), password); err != nil {
+		t.Fatalf("SaveVault failed: %v", err)
+	}
+	if err := storage.SetSnapshotRetention(SnapshotRetention{Keep: 4}); err != nil {
+		t.Fatalf("SetSnapshotRetention failed: %v", err)
+	}
+
+	if err := storage.RestoreSnapshot(target); err != nil {
+		t.Fatalf("RestoreSnapshot failed: %v", err)
+	}
+
+	data, err := storage.LoadVault(password)
+	if err != nil {
+		t.Fatalf("LoadVault failed: %v", err)
+	}
+	if !bytes.Equal(data, []byte(`{"n":1}`)) {
+		t.Errorf("Restored data = %s", data)
+	}
+
+	// The current retention is kept, and the replaced vault was snapshotted
+	if retention, _ := storage.GetSnapshotRetention(); retention.Keep != 4 {
+		t.Errorf("Retention after restore = %+v", retention)
+	}
+	if snapshots, _ := storage.ListSnapshots(); len(snapshots) != 3 {
+		t.Errorf("Expected 3 snapshots after restore, got %d", len(snapshots))
+	}
+
+	if err := storage.RestoreSnapshot("20000101-000000"); !errors.Is(err, ErrSnapshotNotFound) {
+		t.Errorf("Expected ErrSnapshotNotFound, got %v", err)
+	}
+}
+
+func TestParseSnapshotID(t *testing.T) {
+	tests := []struct {
+		id    string
+		valid bool
+		seq   int
+	}{
+		{"20261017-034101", true, 1},
+		{"20261017-034101-3", true, 3},
+		{"20261017-034101-1", false, 0},
+		{"20261017-034101x", false, 0},
+		{"20261017", false, 0},
+		{"../vault", false, 0},
+	}
+
+	for _, tt := range tests {
+		info, ok := parseSnapshotID(tt.id)
+		if ok != tt.valid {
+			t.Errorf("parseSnapshotID(%q) valid = %v, want %v", tt.id, ok, tt.valid)
+			continue
+		}
+		if ok && info.seq != tt.seq {
+			t.Errorf("parseSnapshotID(%q) seq = %d, want %d", tt.id, info.seq, tt.seq)
+		}
+	}
+}
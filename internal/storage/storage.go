@@ -17,14 +17,21 @@ const (
 	DefaultVaultName = "vault.enc"
 	BackupSuffix     = ".backup"
 	TempSuffix       = ".tmp"
+
+	// MaxAttachmentSize is the largest file that can be attached to a credential
+	MaxAttachmentSize = 10 << 20 // 10 MiB
+	// MaxVaultDataSize caps the decrypted vault payload, attachments included
+	MaxVaultDataSize = 64 << 20 // 64 MiB
 )
 
 var (
-	ErrVaultNotFound     = errors.New("vault file not found")
-	ErrVaultCorrupted    = errors.New("vault file corrupted")
-	ErrInvalidVaultPath  = errors.New("invalid vault path")
-	ErrBackupFailed      = errors.New("backup operation failed")
-	ErrAtomicWriteFailed = errors.New("atomic write operation failed")
+	ErrVaultNotFound      = errors.New("vault file not found")
+	ErrVaultCorrupted     = errors.New("vault file corrupted")
+	ErrInvalidVaultPath   = errors.New("invalid vault path")
+	ErrBackupFailed       = errors.New("backup operation failed")
+	ErrAtomicWriteFailed  = errors.New("atomic write operation failed")
+	ErrAttachmentTooLarge = errors.New("attachment exceeds size limit")
+	ErrVaultTooLarge      = errors.New("vault data exceeds size limit")
 )
 
 type VaultMetadata struct {
@@ -33,11 +40,46 @@ type VaultMetadata struct {
 	UpdatedAt  time.Time `json:"updated_at"`
 	Salt       []byte    `json:"salt"`
 	Iterations int       `json:"iterations"` // PBKDF2 iteration count (FR-007)
+	// Key derivation function; empty means PBKDF2-SHA256 for vaults created
+	// before Argon2id support. The Argon2id cost is recorded alongside it.
+	KDF         string `json:"kdf,omitempty"`
+	Memory      uint32 `json:"memory,omitempty"`      // Argon2id memory in KiB
+	TimeCost    uint32 `json:"time_cost,omitempty"`   // Argon2id passes
+	Parallelism uint8  `json:"parallelism,omitempty"` // Argon2id threads
+	// Snapshot retention (0 = default, -1 = disabled) and maximum age (0 = no limit)
+	SnapshotKeep   int           `json:"snapshot_keep,omitempty"`
+	SnapshotMaxAge time.Duration `json:"snapshot_max_age,omitempty"`
+}
+
+// KDFParams returns the key derivation parameters recorded in the metadata
+func (m VaultMetadata) KDFParams() crypto.KDFParams {
+	if m.KDF == crypto.KDFArgon2id {
+		return crypto.KDFParams{Algorithm: crypto.KDFArgon2id, Memory: m.Memory, Time: m.TimeCost, Threads: m.Parallelism}
+	}
+	return crypto.PBKDF2Params(m.Iterations)
+}
+
+// setKDFParams records key derivation parameters, clearing those of the other algorithm
+func (m *VaultMetadata) setKDFParams(params crypto.KDFParams) {
+	m.KDF = params.Algorithm
+	m.Iterations, m.Memory, m.TimeCost, m.Parallelism = 0, 0, 0, 0
+	if params.Algorithm == crypto.KDFArgon2id {
+		m.Memory, m.TimeCost, m.Parallelism = params.Memory, params.Time, params.Threads
+	} else {
+		m.Iterations = params.Iterations
+	}
+}
+
+// usesPBKDF2 reports whether the metadata describes a PBKDF2 vault
+func (m VaultMetadata) usesPBKDF2() bool {
+	return m.KDF == "" || m.KDF == crypto.KDFPBKDF2
 }
 
 type EncryptedVault struct {
 	Metadata VaultMetadata `json:"metadata"`
 	Data     []byte        `json:"data"`
+	// Data key wrapped per unlock secret (version 2); empty for version 1 vaults
+	KeySlots []KeySlot `json:"key_slots,omitempty"`
 }
 
 type StorageService struct {
@@ -67,36 +109,59 @@ func NewStorageService(cryptoService *crypto.CryptoService, vaultPath string) (*
 }
 
 func (s *StorageService) InitializeVault(password string) error {
+	return s.InitializeVaultWithKDF(password, crypto.DefaultKDFParams())
+}
+
+// InitializeVaultWithKDF creates a new vault whose key is derived with params
+func (s *StorageService) InitializeVaultWithKDF(password string, params crypto.KDFParams) error {
+	dataKey, err := s.InitializeVaultWithKeySlot(KeySlotPassword, []byte(password), params)
+	if err != nil {
+		return err
+	}
+	s.cryptoService.ClearKey(dataKey)
+	return nil
+}
+
+// InitializeVaultWithKeySlot creates a new vault unlocked by a single slot of
+// slotType, derived from secret with params, and returns its data key
+func (s *StorageService) InitializeVaultWithKeySlot(slotType string, secret []byte, params crypto.KDFParams) ([]byte, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
 	// Check if vault already exists
 	if s.VaultExists() {
-		return errors.New("vault already exists")
+		return nil, errors.New("vault already exists")
+	}
+
+	// Generate the random data key and wrap it in the first slot
+	dataKey, err := s.cryptoService.SecureRandom(crypto.KeyLength)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate data key: %w", err)
 	}
 
-	// Generate salt for key derivation
-	salt, err := s.cryptoService.GenerateSalt()
+	slot, err := s.newKeySlot(1, slotType, "", secret, params, dataKey)
 	if err != nil {
-		return fmt.Errorf("failed to generate salt: %w", err)
+		s.cryptoService.ClearKey(dataKey)
+		return nil, fmt.Errorf("failed to initialize vault: %w", err)
 	}
 
 	// Create initial empty vault data
 	emptyVault := []byte("{}")
 
-	// T032/T034: Create vault metadata with configurable iterations (FR-007, FR-010)
-	// Uses PASS_CLI_ITERATIONS env var if set, otherwise defaults to 600k (OWASP 2023)
 	metadata := VaultMetadata{
-		Version:    1,
-		CreatedAt:  time.Now(),
-		UpdatedAt:  time.Now(),
-		Salt:       salt,
-		Iterations: crypto.GetIterations(), // Configurable via env var (T034)
+		Version:   EnvelopeVersion,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
 	}
 
 	// Encrypt and save vault
-	if err := s.saveEncryptedVault(emptyVault, metadata, password); err != nil {
-		return fmt.Errorf("failed to initialize vault: %w", err)
+	if err := s.writeVault(emptyVault, dataKey, metadata, []KeySlot{*slot}); err != nil {
+		s.cryptoService.ClearKey(dataKey)
+		return nil, fmt.Errorf("failed to initialize vault: %w", err)
 	}
 
-	return nil
+	return dataKey, nil
 }
 
 func (s *StorageService) LoadVault(password string) ([]byte, error) {
@@ -105,10 +170,10 @@ func (s *StorageService) LoadVault(password string) ([]byte, error) {
 		return nil, err
 	}
 
-	// T031: Derive key from password and salt with iterations from metadata (FR-007)
-	key, err := s.cryptoService.DeriveKey([]byte(password), encryptedVault.Metadata.Salt, encryptedVault.Metadata.Iterations)
+	// T031: Derive key with the algorithm and cost recorded in the metadata or key slot (FR-007)
+	key, _, err := s.openDataKey(encryptedVault, []byte(password), []string{KeySlotPassword})
 	if err != nil {
-		return nil, fmt.Errorf("failed to derive key: %w", err)
+		return nil, err
 	}
 	defer s.cryptoService.ClearKey(key)
 
@@ -128,6 +193,16 @@ func (s *StorageService) SaveVault(data []byte, password string) error {
 		return err
 	}
 
+	// Envelope vaults keep their data key; the password only unwraps it
+	if len(encryptedVault.KeySlots) > 0 {
+		dataKey, _, err := s.openDataKey(encryptedVault, []byte(password), []string{KeySlotPassword})
+		if err != nil {
+			return err
+		}
+		defer s.cryptoService.ClearKey(dataKey)
+		return s.SaveVaultWithKey(data, dataKey)
+	}
+
 	// Update metadata
 	encryptedVault.Metadata.UpdatedAt = time.Now()
 
@@ -145,46 +220,47 @@ func (s *StorageService) SaveVault(data []byte, password string) error {
 		return fmt.Errorf("failed to save vault: %w", err)
 	}
 
+	s.snapshotAfterSave(encryptedVault.Metadata)
 	return nil
 }
 
 // SaveVaultWithIterations saves vault data with an updated iteration count.
 // Used for migration from legacy iteration counts (T033).
 func (s *StorageService) SaveVaultWithIterations(data []byte, password string, iterations int) error {
-	if iterations < crypto.MinIterations {
-		return fmt.Errorf("iterations must be >= %d", crypto.MinIterations)
-	}
+	return s.SaveVaultWithKDF(data, password, crypto.PBKDF2Params(iterations))
+}
 
-	// T036d: Pre-flight checks before migration (FR-012)
-	if err := s.preflightChecks(); err != nil {
-		return fmt.Errorf("pre-flight check failed: %w", err)
+// SaveVaultWithKDF saves vault data with the password re-derived using new
+// key derivation parameters. Envelope vaults rewrite the matching password
+// slot; version 1 vaults are converted to key slots.
+func (s *StorageService) SaveVaultWithKDF(data []byte, password string, params crypto.KDFParams) error {
+	if err := params.Validate(); err != nil {
+		return err
 	}
 
-	// Load existing vault to get metadata
-	encryptedVault, err := s.loadEncryptedVault()
+	hasSlots, err := s.HasKeySlots()
 	if err != nil {
 		return err
 	}
-
-	// Update metadata with new iterations
-	encryptedVault.Metadata.UpdatedAt = time.Now()
-	encryptedVault.Metadata.Iterations = iterations
-
-	// Create backup before saving
-	if err := s.createBackup(); err != nil {
-		return fmt.Errorf("failed to create backup: %w", err)
+	if !hasSlots {
+		dataKey, err := s.ConvertToKeySlots(data, KeySlotPassword, []byte(password), params)
+		if err != nil {
+			return err
+		}
+		s.cryptoService.ClearKey(dataKey)
+		return nil
 	}
 
-	// Save encrypted vault
-	if err := s.saveEncryptedVault(data, encryptedVault.Metadata, password); err != nil {
-		// Restore from backup on failure
-		if restoreErr := s.restoreFromBackup(); restoreErr != nil {
-			return fmt.Errorf("save failed and backup restore failed: %v (original error: %w)", restoreErr, err)
-		}
-		return fmt.Errorf("failed to save vault: %w", err)
+	dataKey, id, err := s.OpenDataKey([]byte(password), KeySlotPassword)
+	if err != nil {
+		return err
 	}
+	defer s.cryptoService.ClearKey(dataKey)
 
-	return nil
+	if err := s.UpdateKeySlot(dataKey, id, KeySlotPassword, []byte(password), params); err != nil {
+		return err
+	}
+	return s.SaveVaultWithKey(data, dataKey)
 }
 
 // SaveVaultWithIterationsUnsafe saves vault data with a specific iteration count without validation.
@@ -197,9 +273,17 @@ func (s *StorageService) SaveVaultWithIterationsUnsafe(data []byte, password str
 		return err
 	}
 
-	// Update metadata with new iterations (no validation)
+	// Update metadata with new iterations (no validation), writing a version 1 vault
 	encryptedVault.Metadata.UpdatedAt = time.Now()
-	encryptedVault.Metadata.Iterations = iterations
+	encryptedVault.Metadata.Version = 1
+	encryptedVault.Metadata.setKDFParams(crypto.PBKDF2Params(iterations))
+	if len(encryptedVault.Metadata.Salt) != crypto.SaltLength {
+		salt, err := s.cryptoService.GenerateSalt()
+		if err != nil {
+			return fmt.Errorf("failed to generate salt: %w", err)
+		}
+		encryptedVault.Metadata.Salt = salt
+	}
 
 	// Create backup before saving
 	if err := s.createBackup(); err != nil {
@@ -221,11 +305,33 @@ func (s *StorageService) SaveVaultWithIterationsUnsafe(data []byte, password str
 // GetIterations returns the current PBKDF2 iteration count from vault metadata.
 // Returns 0 if vault doesn't exist or error occurs.
 func (s *StorageService) GetIterations() int {
-	encryptedVault, err := s.loadEncryptedVault()
+	params, err := s.GetKDFParams()
 	if err != nil {
 		return 0
 	}
-	return encryptedVault.Metadata.Iterations
+	return params.Iterations
+}
+
+// GetKDFParams returns the key derivation parameters of the master password:
+// those of the first password slot, or of the metadata for version 1 vaults
+func (s *StorageService) GetKDFParams() (crypto.KDFParams, error) {
+	encryptedVault, err := s.loadEncryptedVault()
+	if err != nil {
+		return crypto.KDFParams{}, err
+	}
+	return encryptedVault.kdfParams(), nil
+}
+
+func (v *EncryptedVault) kdfParams() crypto.KDFParams {
+	for _, slot := range v.KeySlots {
+		if slot.Type == KeySlotPassword || slot.Type == KeySlotPasswordKeyFile {
+			return slot.KDFParams()
+		}
+	}
+	if len(v.KeySlots) > 0 {
+		return v.KeySlots[0].KDFParams()
+	}
+	return v.Metadata.KDFParams()
 }
 
 // SetIterations updates the PBKDF2 iteration count in vault metadata.
@@ -266,6 +372,7 @@ func (s *StorageService) GetVaultInfo() (*VaultMetadata, error) {
 		UpdatedAt: encryptedVault.Metadata.UpdatedAt,
 		Salt:      nil, // Don't expose salt
 	}
+	info.setKDFParams(encryptedVault.kdfParams())
 
 	return &info, nil
 }
@@ -281,9 +388,15 @@ func (s *StorageService) ValidateVault() error {
 		return ErrVaultCorrupted
 	}
 
-	if len(encryptedVault.Metadata.Salt) != 32 {
+	// Version 1 vaults derive the payload key from the metadata salt
+	if len(encryptedVault.KeySlots) == 0 && len(encryptedVault.Metadata.Salt) != 32 {
 		return ErrVaultCorrupted
 	}
+	for _, slot := range encryptedVault.KeySlots {
+		if len(slot.Salt) != crypto.SaltLength || len(slot.WrappedKey) == 0 {
+			return fmt.Errorf("%w: key slot %d", ErrVaultCorrupted, slot.ID)
+		}
+	}
 
 	if len(encryptedVault.Data) == 0 {
 		return ErrVaultCorrupted
@@ -299,8 +412,17 @@ func (s *StorageService) ValidateVault() error {
 
 	// Validate Iterations field (T025 - FR-007)
 	// Allow 0 for backward compatibility (will default to 100000 on load)
-	if encryptedVault.Metadata.Iterations != 0 && encryptedVault.Metadata.Iterations < crypto.MinIterations {
-		return fmt.Errorf("%w: iterations must be >= %d", ErrVaultCorrupted, crypto.MinIterations)
+	// Key derivation parameters of envelope vaults live in the key slots
+	switch {
+	case len(encryptedVault.KeySlots) > 0:
+	case encryptedVault.Metadata.usesPBKDF2():
+		if encryptedVault.Metadata.Iterations != 0 && encryptedVault.Metadata.Iterations < crypto.MinIterations {
+			return fmt.Errorf("%w: iterations must be >= %d", ErrVaultCorrupted, crypto.MinIterations)
+		}
+	default:
+		if err := encryptedVault.Metadata.KDFParams().Validate(); err != nil {
+			return fmt.Errorf("%w: %v", ErrVaultCorrupted, err)
+		}
 	}
 
 	return nil
@@ -341,33 +463,55 @@ func (s *StorageService) loadEncryptedVault() (*EncryptedVault, error) {
 	}
 
 	// T026: Backward compatibility for legacy vaults without Iterations field (FR-008)
-	if encryptedVault.Metadata.Iterations == 0 {
+	if len(encryptedVault.KeySlots) == 0 && encryptedVault.Metadata.usesPBKDF2() && encryptedVault.Metadata.Iterations == 0 {
 		encryptedVault.Metadata.Iterations = 100000 // Legacy default
 	}
 
 	return &encryptedVault, nil
 }
 
+// CheckAttachmentSize rejects attachments larger than MaxAttachmentSize
+func CheckAttachmentSize(size int64) error {
+	if size > MaxAttachmentSize {
+		return fmt.Errorf("%w: %d bytes (max %d bytes)", ErrAttachmentTooLarge, size, MaxAttachmentSize)
+	}
+	return nil
+}
+
+// saveEncryptedVault writes a version 1 vault, encrypted with the key derived from password
 func (s *StorageService) saveEncryptedVault(data []byte, metadata VaultMetadata, password string) error {
-	// T030: Derive key from password and salt with iterations from metadata (FR-007)
-	key, err := s.cryptoService.DeriveKey([]byte(password), metadata.Salt, metadata.Iterations)
+	// T030: Derive key with the algorithm and cost recorded in the metadata (FR-007)
+	key, err := s.cryptoService.DeriveKeyWithParams([]byte(password), metadata.Salt, metadata.KDFParams())
 	if err != nil {
 		return fmt.Errorf("failed to derive key: %w", err)
 	}
 	defer s.cryptoService.ClearKey(key)
 
+	return s.writeVault(data, key, metadata, nil)
+}
+
+// writeVault encrypts data with key and atomically writes it with the metadata and key slots
+func (s *StorageService) writeVault(data []byte, key []byte, metadata VaultMetadata, slots []KeySlot) error {
+	// Refuse to write vaults that would be slow to decrypt on every unlock
+	if len(data) > MaxVaultDataSize {
+		return fmt.Errorf("%w: %d bytes (max %d bytes)", ErrVaultTooLarge, len(data), MaxVaultDataSize)
+	}
+
 	// Encrypt vault data
 	encryptedData, err := s.cryptoService.Encrypt(data, key)
 	if err != nil {
 		return fmt.Errorf("failed to encrypt vault data: %w", err)
 	}
 
-	// Create encrypted vault structure
-	encryptedVault := EncryptedVault{
+	return s.writeEncryptedVault(&EncryptedVault{
 		Metadata: metadata,
 		Data:     encryptedData,
-	}
+		KeySlots: slots,
+	})
+}
 
+// writeEncryptedVault atomically writes an already encrypted vault
+func (s *StorageService) writeEncryptedVault(encryptedVault *EncryptedVault) error {
 	// Marshal to JSON
 	jsonData, err := json.Marshal(encryptedVault)
 	if err != nil {
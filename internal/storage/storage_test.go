@@ -2,6 +2,7 @@ package storage
 
 import (
 	"bytes"
+	"errors"
 	"os"
 	"path/filepath"
 	"testing"
@@ -166,8 +167,8 @@ func TestStorageService_GetVaultInfo(t *testing.T) {
 	}
 
 	// Verify metadata
-	if info.Version != 1 {
-		t.Errorf("Expected version 1, got %d", info.Version)
+	if info.Version != EnvelopeVersion {
+		t.Errorf("Expected version %d, got %d", EnvelopeVersion, info.Version)
 	}
 
 	if info.CreatedAt.IsZero() {
@@ -394,6 +395,36 @@ func TestStorageService_EmptyData(t *testing.T) {
 	}
 }
 
+func TestStorageService_SizeLimits(t *testing.T) {
+	if err := CheckAttachmentSize(MaxAttachmentSize); err != nil {
+		t.Errorf("CheckAttachmentSize at limit failed: %v", err)
+	}
+	if err := CheckAttachmentSize(MaxAttachmentSize + 1); !errors.Is(err, ErrAttachmentTooLarge) {
+		t.Errorf("Expected ErrAttachmentTooLarge, got %v", err)
+	}
+
+	cryptoService := crypto.NewCryptoService()
+	vaultPath := filepath.Join(t.TempDir(), "test_vault.enc")
+
+	storage, err := NewStorageService(cryptoService, vaultPath)
+	if err != nil {
+		t.Fatalf("NewStorageService failed: %v", err)
+	}
+
+	password := "test-password"
+	if err := storage.InitializeVault(password); err != nil {
+		t.Fatalf("InitializeVault failed: %v", err)
+	}
+
+	// Oversized data is rejected and the previous vault stays readable
+	if err := storage.SaveVault(make([]byte, MaxVaultDataSize+1), password); !errors.Is(err, ErrVaultTooLarge) {
+		t.Fatalf("Expected ErrVaultTooLarge, got %v", err)
+	}
+	if _, err := storage.LoadVault(password); err != nil {
+		t.Errorf("LoadVault after rejected save failed: %v", err)
+	}
+}
+
 // Test corruption detection scenarios
 func TestStorageService_CorruptionDetection(t *testing.T) {
 	cryptoService := crypto.NewCryptoService()
@@ -1134,3 +1165,116 @@ func TestStorageService_BackwardCompatibleIterations(t *testing.T) {
 	//
 	// t.Logf("Legacy vault loaded with iterations: %d", info.Iterations)
 }
+
+func TestStorageService_KDFMigration(t *testing.T) {
+	cryptoService := crypto.NewCryptoService()
+	vaultPath := filepath.Join(t.TempDir(), "kdf_vault.enc")
+
+	storage, err := NewStorageService(cryptoService, vaultPath)
+	if err != nil {
+		t.Fatalf("NewStorageService failed: %v", err)
+	}
+
+	password := "test-password"
+	data := []byte(`{"credentials":{}}`)
+
+	// Simulate a vault written before Argon2id support: no kdf field
+	salt, err := cryptoService.GenerateSalt()
+	if err != nil {
+		t.Fatalf("GenerateSalt failed: %v", err)
+	}
+	legacy := VaultMetadata{Version: 1, CreatedAt: time.Now(), UpdatedAt: time.Now(), Salt: salt, Iterations: crypto.MinIterations}
+	if err := storage.saveEncryptedVault(data, legacy, password); err != nil {
+		t.Fatalf("saveEncryptedVault failed: %v", err)
+	}
+
+	params, err := storage.GetKDFParams()
+	if err != nil {
+		t.Fatalf("GetKDFParams failed: %v", err)
+	}
+	if params != crypto.PBKDF2Params(crypto.MinIterations) {
+		t.Errorf("Expected legacy vault to use PBKDF2, got %s", params)
+	}
+	if _, err := storage.LoadVault(password); err != nil {
+		t.Fatalf("LoadVault failed for PBKDF2 vault: %v", err)
+	}
+
+	// Invalid parameters are rejected without touching the vault
+	weak := crypto.KDFParams{Algorithm: crypto.KDFArgon2id, Memory: 1024, Time: 1, Threads: 1}
+	if err := storage.SaveVaultWithKDF(data, password, weak); !errors.Is(err, crypto.ErrInvalidKDFParams) {
+		t.Errorf("Expected ErrInvalidKDFParams, got %v", err)
+	}
+
+	// Upgrade to Argon2id: the vault moves to a password key slot with a fresh salt
+	if err := storage.SaveVaultWithKDF(data, password, crypto.DefaultKDFParams()); err != nil {
+		t.Fatalf("SaveVaultWithKDF failed: %v", err)
+	}
+	encryptedVault, err := storage.loadEncryptedVault()
+	if err != nil {
+		t.Fatalf("loadEncryptedVault failed: %v", err)
+	}
+	if encryptedVault.Metadata.Version != EnvelopeVersion || encryptedVault.Metadata.Salt != nil || encryptedVault.Metadata.KDF != "" {
+		t.Errorf("Expected envelope metadata without key derivation, got %+v", encryptedVault.Metadata)
+	}
+	if len(encryptedVault.KeySlots) != 1 {
+		t.Fatalf("Expected 1 key slot, got %d", len(encryptedVault.KeySlots))
+	}
+	slot := encryptedVault.KeySlots[0]
+	if slot.Type != KeySlotPassword || slot.KDFParams() != crypto.DefaultKDFParams() {
+		t.Errorf("Unexpected key slot: %+v", slot)
+	}
+	if bytes.Equal(slot.Salt, salt) {
+		t.Error("Expected a new salt after changing key derivation")
+	}
+	if err := storage.ValidateVault(); err != nil {
+		t.Errorf("ValidateVault failed after upgrade: %v", err)
+	}
+
+	loaded, err := storage.LoadVault(password)
+	if err != nil {
+		t.Fatalf("LoadVault failed after upgrade: %v", err)
+	}
+	if !bytes.Equal(loaded, data) {
+		t.Errorf("Expected %s after upgrade, got %s", data, loaded)
+	}
+	if _, err := storage.LoadVault("wrong-password"); err == nil {
+		t.Error("Expected error for wrong password")
+	}
+
+	// Ordinary saves keep the Argon2id parameters
+	if err := storage.SaveVault(data, password); err != nil {
+		t.Fatalf("SaveVault failed: %v", err)
+	}
+	if params, _ := storage.GetKDFParams(); params != crypto.DefaultKDFParams() {
+		t.Errorf("Expected Argon2id parameters kept after save, got %s", params)
+	}
+}
+
+func TestStorageService_InitializeVaultWithKDF(t *testing.T) {
+	storage, err := NewStorageService(crypto.NewCryptoService(), filepath.Join(t.TempDir(), "vault.enc"))
+	if err != nil {
+		t.Fatalf("NewStorageService failed: %v", err)
+	}
+
+	if err := storage.InitializeVaultWithKDF("test-password", crypto.PBKDF2Params(crypto.LegacyIterations)); !errors.Is(err, crypto.ErrInvalidKDFParams) {
+		t.Errorf("Expected ErrInvalidKDFParams for legacy iterations, got %v", err)
+	}
+
+	params := crypto.KDFParams{Algorithm: crypto.KDFArgon2id, Memory: 32 * 1024, Time: 2, Threads: 1}
+	if err := storage.InitializeVaultWithKDF("test-password", params); err != nil {
+		t.Fatalf("InitializeVaultWithKDF failed: %v", err)
+	}
+	if got, _ := storage.GetKDFParams(); got != params {
+		t.Errorf("Expected %s, got %s", params, got)
+	}
+	info, err := storage.GetVaultInfo()
+	if err != nil {
+		t.Fatalf("GetVaultInfo failed: %v", err)
+	}
+	if info.KDFParams() != params {
+		t.Errorf("GetVaultInfo KDF = %s, want %s", info.KDFParams(), params)
+	}
+	if _, err := storage.LoadVault("test-password"); err != nil {
+		t.Errorf("LoadVault failed: %v", err)
+	}
+}
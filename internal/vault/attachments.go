@@ -0,0 +1,174 @@
+package vault
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"pass-cli/internal/crypto"
+	"pass-cli/internal/security"
+	"pass-cli/internal/storage"
+)
+
+// Attachment is a file stored encrypted inside the vault alongside a credential
+type Attachment struct {
+	Name    string      `json:"name"`     // File name (unique per credential)
+	Data    []byte      `json:"data"`     // File contents
+	Mode    os.FileMode `json:"mode"`     // Permission bits of the original file
+	AddedAt time.Time   `json:"added_at"` // When the file was attached
+}
+
+// Size returns the attachment size in bytes
+func (a Attachment) Size() int64 {
+	return int64(len(a.Data))
+}
+
+// AddAttachment stores a file with a credential
+// An attachment with the same name is only replaced when replace is true
+func (v *VaultService) AddAttachment(service, name string, data []byte, mode os.FileMode, replace bool) error {
+	if !v.unlocked {
+		return ErrVaultLocked
+	}
+
+	credential, exists := v.vaultData.Credentials[service]
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrCredentialNotFound, service)
+	}
+
+	if err := validateAttachmentName(name); err != nil {
+		return err
+	}
+	if err := storage.CheckAttachmentSize(int64(len(data))); err != nil {
+		return err
+	}
+
+	index := findAttachment(credential.Attachments, name)
+	if index >= 0 && !replace {
+		return fmt.Errorf("%w: attachment %q already exists on %s", ErrInvalidCredential, name, service)
+	}
+
+	dataCopy := make([]byte, len(data))
+	copy(dataCopy, data)
+	attachment := Attachment{
+		Name:    name,
+		Data:    dataCopy,
+		Mode:    mode.Perm(),
+		AddedAt: time.Now(),
+	}
+
+	previous := credential
+	attachments := copyAttachments(credential.Attachments)
+	if index >= 0 {
+		attachments[index] = attachment
+	} else {
+		attachments = append(attachments, attachment)
+	}
+	credential.Attachments = attachments
+	credential.UpdatedAt = attachment.AddedAt
+	credential.ModifiedCount++
+	v.vaultData.Credentials[service] = credential
+
+	if err := v.save(); err != nil {
+		// Keep the in-memory vault consistent with disk (e.g. size limit exceeded)
+		v.vaultData.Credentials[service] = previous
+		return err
+	}
+
+	v.logAudit(security.EventCredentialUpdate, security.OutcomeSuccess, service)
+	return nil
+}
+
+// GetAttachment returns a copy of a credential's attachment
+func (v *VaultService) GetAttachment(service, name string) (*Attachment, error) {
+	if !v.unlocked {
+		return nil, ErrVaultLocked
+	}
+
+	credential, exists := v.vaultData.Credentials[service]
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrCredentialNotFound, service)
+	}
+
+	index := findAttachment(credential.Attachments, name)
+	if index < 0 {
+		return nil, fmt.Errorf("%w: attachment %q not found on %s", ErrInvalidCredential, name, service)
+	}
+
+	v.logAudit(security.EventCredentialAccess, security.OutcomeSuccess, service)
+
+	attachment := copyAttachments(credential.Attachments[index : index+1])[0]
+	return &attachment, nil
+}
+
+// RemoveAttachment deletes an attachment from a credential
+func (v *VaultService) RemoveAttachment(service, name string) error {
+	if !v.unlocked {
+		return ErrVaultLocked
+	}
+
+	credential, exists := v.vaultData.Credentials[service]
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrCredentialNotFound, service)
+	}
+
+	index := findAttachment(credential.Attachments, name)
+	if index < 0 {
+		return fmt.Errorf("%w: attachment %q not found on %s", ErrInvalidCredential, name, service)
+	}
+
+	crypto.ClearBytes(credential.Attachments[index].Data)
+	attachments := make([]Attachment, 0, len(credential.Attachments)-1)
+	attachments = append(attachments, credential.Attachments[:index]...)
+	attachments = append(attachments, credential.Attachments[index+1:]...)
+	if len(attachments) == 0 {
+		attachments = nil
+	}
+	credential.Attachments = attachments
+	credential.UpdatedAt = time.Now()
+	credential.ModifiedCount++
+	v.vaultData.Credentials[service] = credential
+
+	if err := v.save(); err != nil {
+		return err
+	}
+
+	v.logAudit(security.EventCredentialUpdate, security.OutcomeSuccess, service)
+	return nil
+}
+
+// validateAttachmentName rejects names that could escape the output directory
+func validateAttachmentName(name string) error {
+	if strings.TrimSpace(name) == "" {
+		return fmt.Errorf("%w: attachment name cannot be empty", ErrInvalidCredential)
+	}
+	if name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return fmt.Errorf("%w: attachment name %q cannot contain path separators", ErrInvalidCredential, name)
+	}
+	return nil
+}
+
+// findAttachment returns the index of the named attachment, or -1
+func findAttachment(attachments []Attachment, name string) int {
+	for i, attachment := range attachments {
+		if attachment.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// copyAttachments deep-copies attachments (nil stays nil)
+func copyAttachments(attachments []Attachment) []Attachment {
+	if attachments == nil {
+		return nil
+	}
+	copied := make([]Attachment, len(attachments))
+	for i, attachment := range attachments {
+		data := make([]byte, len(attachment.Data))
+		copy(data, attachment.Data)
+		copied[i] = attachment
+		copied[i].Data = data
+	}
+	return copied
+}
@@ -0,0 +1,185 @@
+package vault
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"pass-cli/internal/security"
+)
+
+// BulkUpdate describes the changes applied to every matching credential
+type BulkUpdate struct {
+	Username   *string // nil = don't change, non-nil = set to value (even if empty)
+	Category   *string
+	URL        *string
+	Notes      *string
+	Type       *CredentialType
+	AddTags    []string
+	RemoveTags []string
+}
+
+// BulkChange records one credential affected by a bulk operation
+type BulkChange struct {
+	Before CredentialMetadata
+	After  CredentialMetadata // Zero value for deletions
+	Err    error              // Type requirement the update would break (nothing is saved while any change has one)
+}
+
+// UpdateMatching applies update to every credential match accepts, in a single save.
+// Credentials the update would leave unchanged are skipped and not reported.
+// With dryRun the changes are computed but the vault is not modified.
+// Each changed credential must still meet its type's template; if any would
+// not, the changes are returned with their Err set and nothing is saved.
+func (v *VaultService) UpdateMatching(match func(*CredentialMetadata) bool, update BulkUpdate, dryRun bool) ([]BulkChange, error) {
+	if !v.unlocked {
+		return nil, ErrVaultLocked
+	}
+	if update.Type != nil {
+		if _, ok := GetTemplate(*update.Type); !ok {
+			return nil, fmt.Errorf("%w: unknown credential type %q", ErrInvalidCredential, *update.Type)
+		}
+	}
+	addTags, err := normalizeTags(update.AddTags)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var changes []BulkChange
+	invalid := 0
+	updated := make(map[string]Credential)
+	for _, service := range v.sortedServices() {
+		original := v.vaultData.Credentials[service]
+		before := credentialMetadata(original)
+		if !match(&before) {
+			continue
+		}
+
+		credential := original
+		changed := applyBulkUpdate(&credential, update, addTags)
+		if !changed {
+			continue
+		}
+		credential.ModifiedCount++
+		credential.UpdatedAt = now
+
+		err := validateTemplateChange(original, credential)
+		if err != nil {
+			invalid++
+		}
+		updated[service] = credential
+		changes = append(changes, BulkChange{Before: before, After: credentialMetadata(credential), Err: err})
+	}
+
+	if dryRun || len(updated) == 0 {
+		return changes, nil
+	}
+	if invalid > 0 {
+		return changes, fmt.Errorf("%w: %d credential(s) would not meet their type's requirements", ErrInvalidCredential, invalid)
+	}
+
+	previous := make(map[string]Credential, len(updated))
+	for service, credential := range updated {
+		previous[service] = v.vaultData.Credentials[service]
+		v.vaultData.Credentials[service] = credential
+	}
+	if err := v.save(); err != nil {
+		// Keep the in-memory vault consistent with disk
+		for service, credential := range previous {
+			v.vaultData.Credentials[service] = credential
+		}
+		return nil, err
+	}
+
+	for _, change := range changes {
+		v.logAudit(security.EventCredentialUpdate, security.OutcomeSuccess, change.Before.Service)
+	}
+	return changes, nil
+}
+
+// applyBulkUpdate changes a credential in place and reports whether anything differs
+func applyBulkUpdate(credential *Credential, update BulkUpdate, addTags []string) bool {
+	changed := false
+	setString := func(target *string, value *string) {
+		if value != nil && *target != *value {
+			*target = *value
+			changed = true
+		}
+	}
+	setString(&credential.Username, update.Username)
+	setString(&credential.Category, update.Category)
+	setString(&credential.URL, update.URL)
+	setString(&credential.Notes, update.Notes)
+
+	if update.Type != nil && credential.EffectiveType() != *update.Type {
+		credential.Type = *update.Type
+		changed = true
+	}
+
+	if len(addTags) > 0 || len(update.RemoveTags) > 0 {
+		tags := MergeTags(credential.Tags, addTags, update.RemoveTags)
+		if !slices.Equal(tags, credential.Tags) {
+			credential.Tags = tags
+			changed = true
+		}
+	}
+	return changed
+}
+
+// DeleteMatching moves every credential match accepts to the trash, in a single save.
+// With dryRun the affected credentials are reported but the vault is not modified.
+func (v *VaultService) DeleteMatching(match func(*CredentialMetadata) bool, dryRun bool) ([]BulkChange, error) {
+	if !v.unlocked {
+		return nil, ErrVaultLocked
+	}
+
+	var changes []BulkChange
+	for _, service := range v.sortedServices() {
+		meta := credentialMetadata(v.vaultData.Credentials[service])
+		if match(&meta) {
+			changes = append(changes, BulkChange{Before: meta})
+		}
+	}
+	if dryRun || len(changes) == 0 {
+		return changes, nil
+	}
+
+	previousCredentials := make(map[string]Credential, len(v.vaultData.Credentials))
+	for service, credential := range v.vaultData.Credentials {
+		previousCredentials[service] = credential
+	}
+	previousTrash := v.vaultData.Trash
+
+	now := time.Now()
+	trash := append([]TrashedCredential(nil), v.vaultData.Trash...)
+	for _, change := range changes {
+		service := change.Before.Service
+		trash = append(trash, TrashedCredential{Credential: v.vaultData.Credentials[service], DeletedAt: now})
+		delete(v.vaultData.Credentials, service)
+	}
+	v.vaultData.Trash = trash
+
+	if err := v.save(); err != nil {
+		// Keep the in-memory vault consistent with disk
+		v.vaultData.Credentials = previousCredentials
+		v.vaultData.Trash = previousTrash
+		return nil, err
+	}
+
+	for _, change := range changes {
+		v.logAudit(security.EventCredentialDelete, security.OutcomeSuccess, change.Before.Service)
+	}
+	return changes, nil
+}
+
+// sortedServices returns the service names in the vault in sorted order
+func (v *VaultService) sortedServices() []string {
+	services := make([]string, 0, len(v.vaultData.Credentials))
+	for service := range v.vaultData.Credentials {
+		services = append(services, service)
+	}
+	sort.Strings(services)
+	return services
+}
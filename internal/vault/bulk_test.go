@@ -0,0 +1,172 @@
+package vault
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func setupBulkTestVault(t *testing.T) (*VaultService, func()) {
+	t.Helper()
+	vault, _, cleanup := setupUnlockedTestVault(t)
+
+	credentials := []struct {
+		service, category, url string
+		tags                   []string
+	}{
+		{"aws-prod", "Cloud", "https://aws.amazon.com", []string{"prod"}},
+		{"aws-staging", "Cloud", "https://aws.amazon.com", nil},
+		{"gcp", "Cloud", "https://cloud.google.com", nil},
+		{"github", "Dev", "https://github.com", nil},
+	}
+	for _, c := range credentials {
+		if err := vault.AddCredentialWithOpts(c.service, "admin", []byte("pw"), c.category, c.url, "", AddOpts{Tags: c.tags}); err != nil {
+			cleanup()
+			t.Fatalf("AddCredentialWithOpts(%s) failed: %v", c.service, err)
+		}
+	}
+	return vault, cleanup
+}
+
+func matchCategoryURL(category, url string) func(*CredentialMetadata) bool {
+	return func(m *CredentialMetadata) bool {
+		return m.Category == category && strings.Contains(m.URL, url)
+	}
+}
+
+func TestUpdateMatching(t *testing.T) {
+	vault, cleanup := setupBulkTestVault(t)
+	defer cleanup()
+
+	category := "AWS"
+	update := BulkUpdate{Category: &category, AddTags: []string{"cloud"}}
+
+	changes, err := vault.UpdateMatching(matchCategoryURL("Cloud", "aws"), update, true)
+	if err != nil {
+		t.Fatalf("UpdateMatching(dry run) failed: %v", err)
+	}
+	if len(changes) != 2 || changes[0].Before.Service != "aws-prod" || changes[1].After.Category != "AWS" {
+		t.Fatalf("dry run changes = %+v", changes)
+	}
+	if cred, _ := vault.GetCredential("aws-prod", false); cred.Category != "Cloud" {
+		t.Fatal("dry run changed the vault")
+	}
+
+	if _, err := vault.UpdateMatching(matchCategoryURL("Cloud", "aws"), update, false); err != nil {
+		t.Fatalf("UpdateMatching failed: %v", err)
+	}
+	cred, _ := vault.GetCredential("aws-prod", false)
+	if cred.Category != "AWS" || !HasTag(cred.Tags, "prod") || !HasTag(cred.Tags, "cloud") || cred.ModifiedCount != 1 {
+		t.Errorf("aws-prod = category %q, tags %v, modified %d", cred.Category, cred.Tags, cred.ModifiedCount)
+	}
+	if gcp, _ := vault.GetCredential("gcp", false); gcp.Category != "Cloud" {
+		t.Errorf("gcp category = %q, want unchanged", gcp.Category)
+	}
+
+	// Running again changes nothing
+	changes, err = vault.UpdateMatching(func(m *CredentialMetadata) bool { return m.Category == "AWS" }, update, false)
+	if err != nil || len(changes) != 0 {
+		t.Errorf("repeat UpdateMatching = %+v, %v", changes, err)
+	}
+}
+
+func TestUpdateMatching_RemoveTags(t *testing.T) {
+	vault, cleanup := setupBulkTestVault(t)
+	defer cleanup()
+
+	changes, err := vault.UpdateMatching(func(*CredentialMetadata) bool { return true }, BulkUpdate{RemoveTags: []string{"PROD"}}, false)
+	if err != nil {
+		t.Fatalf("UpdateMatching failed: %v", err)
+	}
+	if len(changes) != 1 || changes[0].Before.Service != "aws-prod" || len(changes[0].After.Tags) != 0 {
+		t.Errorf("changes = %+v", changes)
+	}
+}
+
+func TestUpdateMatching_Invalid(t *testing.T) {
+	vault, cleanup := setupBulkTestVault(t)
+	defer cleanup()
+
+	all := func(*CredentialMetadata) bool { return true }
+	bogus := CredentialType("bogus")
+	if _, err := vault.UpdateMatching(all, BulkUpdate{Type: &bogus}, false); err == nil {
+		t.Error("Expected an error for an unknown type")
+	}
+	if _, err := vault.UpdateMatching(all, BulkUpdate{AddTags: []string{"two words"}}, false); err == nil {
+		t.Error("Expected an error for an invalid tag")
+	}
+
+	vault.Lock()
+	if _, err := vault.UpdateMatching(all, BulkUpdate{}, false); err != ErrVaultLocked {
+		t.Errorf("locked vault error = %v", err)
+	}
+}
+
+func TestUpdateMatching_TemplateRequirements(t *testing.T) {
+	vault, cleanup := setupBulkTestVault(t)
+	defer cleanup()
+	if err := vault.AddCredentialWithOpts("cloud-notes", "", nil, "Cloud", "", "text", AddOpts{Type: TypeSecureNote}); err != nil {
+		t.Fatalf("AddCredentialWithOpts() failed: %v", err)
+	}
+
+	cloud := func(m *CredentialMetadata) bool { return m.Category == "Cloud" }
+
+	// A secure note has no password, which a login requires
+	login := TypeLogin
+	changes, err := vault.UpdateMatching(cloud, BulkUpdate{Type: &login}, true)
+	if err != nil {
+		t.Fatalf("UpdateMatching(dry run) error = %v", err)
+	}
+	if len(changes) != 1 || changes[0].Err == nil {
+		t.Fatalf("dry run changes = %+v, want the secure note with an error", changes)
+	}
+
+	// Clearing a required username is refused for every credential, and nothing is saved
+	empty := ""
+	changes, err = vault.UpdateMatching(cloud, BulkUpdate{Username: &empty, Category: new(string)}, false)
+	if !errors.Is(err, ErrInvalidCredential) {
+		t.Fatalf("UpdateMatching() error = %v, want ErrInvalidCredential", err)
+	}
+	failed := 0
+	for _, change := range changes {
+		if change.Err != nil {
+			failed++
+		}
+	}
+	if failed != 3 {
+		t.Errorf("%d changes failed, want the 3 logins", failed)
+	}
+	cred, _ := vault.GetCredential("aws-prod", false)
+	if cred.Username != "admin" || cred.Category != "Cloud" {
+		t.Errorf("aws-prod was changed to %q/%q despite the refused update", cred.Username, cred.Category)
+	}
+}
+
+func TestDeleteMatching(t *testing.T) {
+	vault, cleanup := setupBulkTestVault(t)
+	defer cleanup()
+
+	cloud := func(m *CredentialMetadata) bool { return m.Category == "Cloud" }
+	changes, err := vault.DeleteMatching(cloud, true)
+	if err != nil || len(changes) != 3 {
+		t.Fatalf("DeleteMatching(dry run) = %+v, %v", changes, err)
+	}
+	if services, _ := vault.ListCredentials(); len(services) != 4 {
+		t.Fatalf("dry run deleted credentials: %v", services)
+	}
+
+	if _, err := vault.DeleteMatching(cloud, false); err != nil {
+		t.Fatalf("DeleteMatching failed: %v", err)
+	}
+	services, _ := vault.ListCredentials()
+	if len(services) != 1 || services[0] != "github" {
+		t.Errorf("remaining = %v", services)
+	}
+	trash, _ := vault.ListTrash()
+	if len(trash) != 3 {
+		t.Errorf("trash = %+v", trash)
+	}
+	if err := vault.RestoreCredential("gcp"); err != nil {
+		t.Errorf("RestoreCredential(gcp) failed: %v", err)
+	}
+}
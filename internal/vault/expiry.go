@@ -0,0 +1,102 @@
+package vault
+
+import (
+	"fmt"
+	"time"
+)
+
+// DefaultExpiryWarning is how far ahead upcoming expiries are flagged by default
+const DefaultExpiryWarning = 14 * 24 * time.Hour
+
+// ExpiryStatus classifies a credential's expiry relative to a warning window
+type ExpiryStatus int
+
+const (
+	// ExpiryNone means the credential has no expiry date or rotation interval
+	ExpiryNone ExpiryStatus = iota
+	// ExpiryOK means the credential expires outside the warning window
+	ExpiryOK
+	// ExpirySoon means the credential expires within the warning window
+	ExpirySoon
+	// ExpiryExpired means the expiry date has passed
+	ExpiryExpired
+)
+
+// String returns the status name used in CLI output
+func (s ExpiryStatus) String() string {
+	switch s {
+	case ExpiryOK:
+		return "ok"
+	case ExpirySoon:
+		return "expiring"
+	case ExpiryExpired:
+		return "expired"
+	default:
+		return "none"
+	}
+}
+
+// GetExpiryStatus classifies an expiry time (zero = never expires) at now
+// Credentials expiring within the window are reported as ExpirySoon
+func GetExpiryStatus(expiresAt, now time.Time, within time.Duration) ExpiryStatus {
+	switch {
+	case expiresAt.IsZero():
+		return ExpiryNone
+	case !expiresAt.After(now):
+		return ExpiryExpired
+	case !expiresAt.After(now.Add(within)):
+		return ExpirySoon
+	default:
+		return ExpiryOK
+	}
+}
+
+// LastPasswordChange returns when the password was last set
+// Vaults created before this was tracked fall back to the creation time
+func (c *Credential) LastPasswordChange() time.Time {
+	if c.PasswordChangedAt.IsZero() {
+		return c.CreatedAt
+	}
+	return c.PasswordChangedAt
+}
+
+// RotationDue returns when the password is due for rotation
+// Returns the zero time when no rotation interval is configured
+func (c *Credential) RotationDue() time.Time {
+	if c.RotationDays <= 0 {
+		return time.Time{}
+	}
+	return c.LastPasswordChange().AddDate(0, 0, c.RotationDays)
+}
+
+// EffectiveExpiry returns the earlier of the explicit expiry date and the rotation due date
+// Returns the zero time when neither is configured
+func (c *Credential) EffectiveExpiry() time.Time {
+	var expiry time.Time
+	if c.ExpiresAt != nil {
+		expiry = *c.ExpiresAt
+	}
+
+	if due := c.RotationDue(); !due.IsZero() && (expiry.IsZero() || due.Before(expiry)) {
+		expiry = due
+	}
+
+	return expiry
+}
+
+// validateRotationDays rejects negative rotation intervals
+func validateRotationDays(days int) error {
+	if days < 0 {
+		return fmt.Errorf("%w: rotation interval cannot be negative", ErrInvalidCredential)
+	}
+	return nil
+}
+
+// copyTime returns a copy of an optional timestamp (nil or zero becomes nil)
+func copyTime(t *time.Time) *time.Time {
+	if t == nil || t.IsZero() {
+		return nil
+	}
+	copied := *t
+	return &copied
+}
@@ -0,0 +1,106 @@
+package vault
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGetExpiryStatus(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	window := 14 * 24 * time.Hour
+
+	tests := []struct {
+		name      string
+		expiresAt time.Time
+		want      ExpiryStatus
+	}{
+		{"no expiry", time.Time{}, ExpiryNone},
+		{"expired", now.Add(-time.Hour), ExpiryExpired},
+		{"expires now", now, ExpiryExpired},
+		{"within window", now.AddDate(0, 0, 10), ExpirySoon},
+		{"outside window", now.AddDate(0, 0, 30), ExpiryOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GetExpiryStatus(tt.expiresAt, now, window); got != tt.want {
+				t.Errorf("GetExpiryStatus() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEffectiveExpiry(t *testing.T) {
+	created := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	explicit := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		cred Credential
+		want time.Time
+	}{
+		{"none", Credential{CreatedAt: created}, time.Time{}},
+		{"explicit only", Credential{CreatedAt: created, ExpiresAt: &explicit}, explicit},
+		{"rotation falls back to created", Credential{CreatedAt: created, RotationDays: 10}, created.AddDate(0, 0, 10)},
+		{"rotation earlier than explicit", Credential{CreatedAt: created, ExpiresAt: &explicit, RotationDays: 10}, created.AddDate(0, 0, 10)},
+		{"explicit earlier than rotation", Credential{CreatedAt: created, ExpiresAt: &explicit, RotationDays: 90}, explicit},
+		{"rotation from last change", Credential{CreatedAt: created, PasswordChangedAt: explicit, RotationDays: 30}, explicit.AddDate(0, 0, 30)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cred.EffectiveExpiry(); !got.Equal(tt.want) {
+				t.Errorf("EffectiveExpiry() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCredentialExpiry(t *testing.T) {
+	vault, _, cleanup := setupUnlockedTestVault(t)
+	defer cleanup()
+
+	expires := time.Now().AddDate(0, 0, 5).Truncate(time.Second)
+	opts := AddOpts{ExpiresAt: expires, RotationDays: 90}
+	if err := vault.AddCredentialWithOpts("stripe", "api", []byte("key"), "", "", "", opts); err != nil {
+		t.Fatalf("AddCredentialWithOpts() failed: %v", err)
+	}
+
+	metadata, err := vault.ListCredentialsWithMetadata()
+	if err != nil {
+		t.Fatalf("ListCredentialsWithMetadata() failed: %v", err)
+	}
+	if !metadata[0].ExpiresAt.Equal(expires) || metadata[0].RotationDue {
+		t.Errorf("ExpiresAt = %v (rotation due %v), want explicit %v", metadata[0].ExpiresAt, metadata[0].RotationDue, expires)
+	}
+
+	// Clearing the expiry date leaves the rotation due date
+	cleared := time.Time{}
+	if err := vault.UpdateCredential("stripe", UpdateOpts{ExpiresAt: &cleared}); err != nil {
+		t.Fatalf("UpdateCredential() failed: %v", err)
+	}
+	cred, _ := vault.GetCredential("stripe", false)
+	if cred.ExpiresAt != nil {
+		t.Errorf("ExpiresAt = %v, want nil", cred.ExpiresAt)
+	}
+	if want := cred.PasswordChangedAt.AddDate(0, 0, 90); !cred.EffectiveExpiry().Equal(want) {
+		t.Errorf("EffectiveExpiry() = %v, want %v", cred.EffectiveExpiry(), want)
+	}
+
+	// A password change restarts the rotation interval
+	before := cred.PasswordChangedAt
+	time.Sleep(10 * time.Millisecond)
+	password := []byte("new-key")
+	if err := vault.UpdateCredential("stripe", UpdateOpts{Password: &password}); err != nil {
+		t.Fatalf("UpdateCredential() failed: %v", err)
+	}
+	cred, _ = vault.GetCredential("stripe", false)
+	if !cred.PasswordChangedAt.After(before) {
+		t.Errorf("PasswordChangedAt = %v, want after %v", cred.PasswordChangedAt, before)
+	}
+
+	negative := -1
+	if err := vault.UpdateCredential("stripe", UpdateOpts{RotationDays: &negative}); err == nil {
+		t.Error("UpdateCredential() should reject a negative rotation interval")
+	}
+}
@@ -0,0 +1,47 @@
+package vault
+
+import (
+	"sort"
+
+	"pass-cli/internal/crypto"
+	"pass-cli/internal/security"
+)
+
+// ExportCredentials hands copies of every credential, sorted by service name, to
+// write. The export is recorded in the audit log whether or not write succeeds,
+// with the format in place of a credential name. Passwords in the copies are
+// cleared once write returns.
+func (v *VaultService) ExportCredentials(format string, write func([]Credential) error) error {
+	if !v.unlocked {
+		return ErrVaultLocked
+	}
+
+	services := make([]string, 0, len(v.vaultData.Credentials))
+	for service := range v.vaultData.Credentials {
+		services = append(services, service)
+	}
+	sort.Strings(services)
+
+	credentials := make([]Credential, 0, len(services))
+	for _, service := range services {
+		credentials = append(credentials, cloneCredential(v.vaultData.Credentials[service]))
+	}
+	defer clearExportedCredentials(credentials)
+
+	if err := write(credentials); err != nil {
+		v.logAudit(security.EventVaultExport, security.OutcomeFailure, format)
+		return err
+	}
+	v.logAudit(security.EventVaultExport, security.OutcomeSuccess, format)
+	return nil
+}
+
+// clearExportedCredentials zeroes the passwords held by exported copies
+func clearExportedCredentials(credentials []Credential) {
+	for i := range credentials {
+		crypto.ClearBytes(credentials[i].Password)
+		for j := range credentials[i].PasswordHistory {
+			crypto.ClearBytes(credentials[i].PasswordHistory[j].Password)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package vault
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestExportCredentials(t *testing.T) {
+	vault, _, cleanup := setupUnlockedTestVault(t)
+	defer cleanup()
+
+	for _, service := range []string{"zeta", "alpha", "mid"} {
+		if err := vault.AddCredential(service, "user", []byte(service+"-pass"), "", "", ""); err != nil {
+			t.Fatalf("AddCredential() failed: %v", err)
+		}
+	}
+
+	var exported []Credential
+	err := vault.ExportCredentials("csv", func(credentials []Credential) error {
+		if len(credentials) != 3 || credentials[0].Service != "alpha" || credentials[2].Service != "zeta" {
+			t.Errorf("credentials not sorted: %+v", credentials)
+		}
+		if string(credentials[0].Password) != "alpha-pass" {
+			t.Errorf("password = %q", credentials[0].Password)
+		}
+		credentials[0].Password[0] = 'X' // Must not reach the vault
+		exported = credentials
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ExportCredentials() failed: %v", err)
+	}
+
+	// Exported copies are cleared once write returns
+	for _, b := range exported[1].Password {
+		if b != 0 {
+			t.Fatalf("exported password not cleared: %q", exported[1].Password)
+		}
+	}
+
+	cred, err := vault.GetCredential("alpha", false)
+	if err != nil {
+		t.Fatalf("GetCredential() failed: %v", err)
+	}
+	if string(cred.Password) != "alpha-pass" {
+		t.Errorf("vault password changed by export: %q", cred.Password)
+	}
+}
+
+func TestExportCredentials_Errors(t *testing.T) {
+	vault, _, cleanup := setupUnlockedTestVault(t)
+	defer cleanup()
+
+	writeErr := errors.New("disk full")
+	if err := vault.ExportCredentials("json", func([]Credential) error { return writeErr }); !errors.Is(err, writeErr) {
+		t.Errorf("ExportCredentials() = %v, want write error", err)
+	}
+
+	vault.Lock()
+	if err := vault.ExportCredentials("json", func([]Credential) error { return nil }); !errors.Is(err, ErrVaultLocked) {
+		t.Errorf("ExportCredentials() on locked vault = %v, want ErrVaultLocked", err)
+	}
+}
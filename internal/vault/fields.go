@@ -0,0 +1,102 @@
+package vault
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CustomField is a user-defined key/value pair stored on a credential
+// Secret fields are treated like the password: masked in display output
+type CustomField struct {
+	Key    string `json:"key"`
+	Value  string `json:"value"`
+	Secret bool   `json:"secret,omitempty"`
+}
+
+// reservedFieldKeys are the built-in credential fields that custom fields cannot shadow
+var reservedFieldKeys = []string{"service", "username", "password", "category", "url", "notes"}
+
+// GetCustomField looks up a custom field by key (case-insensitive)
+func (c *Credential) GetCustomField(key string) (CustomField, bool) {
+	return FindCustomField(c.CustomFields, key)
+}
+
+// FindCustomField looks up a field in a field list by key (case-insensitive)
+func FindCustomField(fields []CustomField, key string) (CustomField, bool) {
+	for _, field := range fields {
+		if strings.EqualFold(field.Key, key) {
+			return field, true
+		}
+	}
+	return CustomField{}, false
+}
+
+// MergeCustomFields applies updates and removals to an existing field list
+// Updated keys keep their position, new keys are appended, removed keys are dropped
+func MergeCustomFields(existing, updates []CustomField, remove []string) []CustomField {
+	merged := copyCustomFields(existing)
+
+	for _, update := range updates {
+		replaced := false
+		for i := range merged {
+			if strings.EqualFold(merged[i].Key, update.Key) {
+				merged[i] = update
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			merged = append(merged, update)
+		}
+	}
+
+	if len(remove) == 0 {
+		return merged
+	}
+
+	kept := make([]CustomField, 0, len(merged))
+	for _, field := range merged {
+		removed := false
+		for _, key := range remove {
+			if strings.EqualFold(field.Key, key) {
+				removed = true
+				break
+			}
+		}
+		if !removed {
+			kept = append(kept, field)
+		}
+	}
+	return kept
+}
+
+// validateCustomFields checks that keys are present, unique, and don't shadow built-in fields
+func validateCustomFields(fields []CustomField) error {
+	seen := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		key := strings.ToLower(strings.TrimSpace(field.Key))
+		if key == "" {
+			return fmt.Errorf("%w: custom field key cannot be empty", ErrInvalidCredential)
+		}
+		for _, reserved := range reservedFieldKeys {
+			if key == reserved {
+				return fmt.Errorf("%w: custom field key %q is reserved", ErrInvalidCredential, field.Key)
+			}
+		}
+		if seen[key] {
+			return fmt.Errorf("%w: duplicate custom field key %q", ErrInvalidCredential, field.Key)
+		}
+		seen[key] = true
+	}
+	return nil
+}
+
+// copyCustomFields returns a copy of the field list (nil stays nil)
+func copyCustomFields(fields []CustomField) []CustomField {
+	if fields == nil {
+		return nil
+	}
+	copied := make([]CustomField, len(fields))
+	copy(copied, fields)
+	return copied
+}
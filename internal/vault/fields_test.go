@@ -0,0 +1,131 @@
+package vault
+
+import (
+	"errors"
+	"testing"
+)
+
+// setupUnlockedTestVault creates an initialized and unlocked vault for tests
+func setupUnlockedTestVault(t *testing.T) (*VaultService, string, func()) {
+	t.Helper()
+
+	vault, vaultPath, cleanup := setupTestVault(t)
+
+	password := "TestPassword123!"
+	if err := vault.Initialize([]byte(password), false, "", ""); err != nil {
+		cleanup()
+		t.Fatalf("Initialize() failed: %v", err)
+	}
+	if err := vault.Unlock([]byte(password)); err != nil {
+		cleanup()
+		t.Fatalf("Unlock() failed: %v", err)
+	}
+
+	return vault, vaultPath, cleanup
+}
+
+func TestAddCredentialWithCustomFields(t *testing.T) {
+	vault, vaultPath, cleanup := setupUnlockedTestVault(t)
+	defer cleanup()
+
+	fields := []CustomField{
+		{Key: "client_id", Value: "AKIA123"},
+		{Key: "region", Value: "us-east-1"},
+		{Key: "session_token", Value: "s3cr3t", Secret: true},
+	}
+	err := vault.AddCredentialWithOpts("aws", "deploy", []byte("pass"), "Cloud", "", "", AddOpts{CustomFields: fields})
+	if err != nil {
+		t.Fatalf("AddCredentialWithOpts() failed: %v", err)
+	}
+
+	// Fields must survive a save/reload round trip in order
+	vault.Lock()
+	reopened, err := New(vaultPath)
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	if err := reopened.Unlock([]byte("TestPassword123!")); err != nil {
+		t.Fatalf("Unlock() failed: %v", err)
+	}
+	defer reopened.Lock()
+
+	cred, err := reopened.GetCredential("aws", false)
+	if err != nil {
+		t.Fatalf("GetCredential() failed: %v", err)
+	}
+	if len(cred.CustomFields) != len(fields) {
+		t.Fatalf("CustomFields length = %d, want %d", len(cred.CustomFields), len(fields))
+	}
+	for i, field := range fields {
+		if cred.CustomFields[i] != field {
+			t.Errorf("CustomFields[%d] = %+v, want %+v", i, cred.CustomFields[i], field)
+		}
+	}
+
+	field, ok := cred.GetCustomField("SESSION_TOKEN")
+	if !ok || field.Value != "s3cr3t" || !field.Secret {
+		t.Errorf("GetCustomField() = %+v, %v; want secret session_token", field, ok)
+	}
+}
+
+func TestUpdateCredentialCustomFields(t *testing.T) {
+	vault, _, cleanup := setupUnlockedTestVault(t)
+	defer cleanup()
+
+	initial := []CustomField{{Key: "region", Value: "us-east-1"}, {Key: "account", Value: "1234"}}
+	if err := vault.AddCredentialWithOpts("aws", "deploy", []byte("pass"), "", "", "", AddOpts{CustomFields: initial}); err != nil {
+		t.Fatalf("AddCredentialWithOpts() failed: %v", err)
+	}
+
+	cred, _ := vault.GetCredential("aws", false)
+	merged := MergeCustomFields(cred.CustomFields,
+		[]CustomField{{Key: "region", Value: "eu-west-1"}, {Key: "token", Value: "xyz", Secret: true}},
+		[]string{"account"})
+	if err := vault.UpdateCredential("aws", UpdateOpts{CustomFields: &merged}); err != nil {
+		t.Fatalf("UpdateCredential() failed: %v", err)
+	}
+
+	cred, _ = vault.GetCredential("aws", false)
+	want := []CustomField{{Key: "region", Value: "eu-west-1"}, {Key: "token", Value: "xyz", Secret: true}}
+	if len(cred.CustomFields) != len(want) {
+		t.Fatalf("CustomFields = %+v, want %+v", cred.CustomFields, want)
+	}
+	for i := range want {
+		if cred.CustomFields[i] != want[i] {
+			t.Errorf("CustomFields[%d] = %+v, want %+v", i, cred.CustomFields[i], want[i])
+		}
+	}
+	if cred.ModifiedCount != 1 {
+		t.Errorf("ModifiedCount = %d, want 1", cred.ModifiedCount)
+	}
+
+	// Mutating the returned copy must not leak into the vault
+	cred.CustomFields[0].Value = "tampered"
+	again, _ := vault.GetCredential("aws", false)
+	if again.CustomFields[0].Value != "eu-west-1" {
+		t.Error("GetCredential() should return a copy of custom fields")
+	}
+}
+
+func TestCustomFieldValidation(t *testing.T) {
+	vault, _, cleanup := setupUnlockedTestVault(t)
+	defer cleanup()
+
+	tests := []struct {
+		name   string
+		fields []CustomField
+	}{
+		{"empty key", []CustomField{{Key: " ", Value: "x"}}},
+		{"reserved key", []CustomField{{Key: "Password", Value: "x"}}},
+		{"duplicate key", []CustomField{{Key: "region", Value: "a"}, {Key: "REGION", Value: "b"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := vault.AddCredentialWithOpts("svc-"+tt.name, "user", []byte("pass"), "", "", "", AddOpts{CustomFields: tt.fields})
+			if !errors.Is(err, ErrInvalidCredential) {
+				t.Errorf("AddCredentialWithOpts() error = %v, want ErrInvalidCredential", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package vault
+
+import (
+	"crypto/sha1" // #nosec G505 -- SHA-1 is the hash format of breach lists, not used for security
+	"crypto/sha256"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"pass-cli/internal/security"
+)
+
+// HealthSeverity ranks how urgently a health issue should be fixed
+type HealthSeverity int
+
+const (
+	SeverityLow HealthSeverity = iota
+	SeverityMedium
+	SeverityHigh
+	SeverityCritical
+)
+
+func (s HealthSeverity) String() string {
+	switch s {
+	case SeverityLow:
+		return "low"
+	case SeverityMedium:
+		return "medium"
+	case SeverityHigh:
+		return "high"
+	case SeverityCritical:
+		return "critical"
+	}
+	return "unknown"
+}
+
+// ParseHealthSeverity converts a user-supplied severity name
+func ParseHealthSeverity(value string) (HealthSeverity, error) {
+	for s := SeverityLow; s <= SeverityCritical; s++ {
+		if strings.EqualFold(strings.TrimSpace(value), s.String()) {
+			return s, nil
+		}
+	}
+	return SeverityLow, fmt.Errorf("invalid severity: %s (valid: low, medium, high, critical)", value)
+}
+
+// HealthIssueKind identifies a password problem found by CheckHealth
+type HealthIssueKind string
+
+const (
+	HealthBreached HealthIssueKind = "breached" // Password appears in the breach list
+	HealthReused   HealthIssueKind = "reused"   // Same password as another credential
+	HealthWeak     HealthIssueKind = "weak"     // Rated weak by the password policy
+	HealthOld      HealthIssueKind = "old"      // Unchanged for longer than the maximum age
+)
+
+// healthSeverities maps each issue kind to its severity
+var healthSeverities = map[HealthIssueKind]HealthSeverity{
+	HealthBreached: SeverityCritical,
+	HealthReused:   SeverityHigh,
+	HealthWeak:     SeverityMedium,
+	HealthOld:      SeverityLow,
+}
+
+// HealthIssue is one problem with one credential's password
+type HealthIssue struct {
+	Service  string
+	Username string
+	Kind     HealthIssueKind
+	Severity HealthSeverity
+	Detail   string   // Human-readable explanation, e.g. "unchanged for 400 days"
+	Related  []string // Other services sharing a reused password
+}
+
+// HealthReport is the result of a password health scan
+type HealthReport struct {
+	Checked int           // Credentials with a password that were scanned
+	Issues  []HealthIssue // Most severe first
+}
+
+// HealthOptions configures CheckHealth
+type HealthOptions struct {
+	MaxAge time.Duration // Passwords unchanged for longer are reported as old; 0 disables the check
+	// Breached looks up SHA-1 password hashes in a breach list and returns how
+	// often each was seen; nil skips the check
+	Breached func(hashes [][sha1.Size]byte) (map[[sha1.Size]byte]int, error)
+}
+
+// CheckHealth scans every credential's password for reuse (compared by hash),
+// weak strength, age, and breach list matches. Credentials without a password,
+// such as secure notes, are skipped.
+func (v *VaultService) CheckHealth(opts HealthOptions) (*HealthReport, error) {
+	if !v.unlocked {
+		return nil, ErrVaultLocked
+	}
+
+	now := time.Now()
+	report := &HealthReport{}
+	byHash := make(map[[sha256.Size]byte][]string)
+	breachHashes := make(map[string][sha1.Size]byte)
+	add := func(credential Credential, kind HealthIssueKind, detail string, related []string) {
+		report.Issues = append(report.Issues, HealthIssue{
+			Service:  credential.Service,
+			Username: credential.Username,
+			Kind:     kind,
+			Severity: healthSeverities[kind],
+			Detail:   detail,
+			Related:  related,
+		})
+	}
+
+	services := v.sortedServices()
+	for _, service := range services {
+		credential := v.vaultData.Credentials[service]
+		if len(credential.Password) == 0 {
+			continue
+		}
+		report.Checked++
+
+		hash := sha256.Sum256(credential.Password)
+		byHash[hash] = append(byHash[hash], service)
+		if opts.Breached != nil {
+			breachHashes[service] = sha1.Sum(credential.Password) // #nosec G401 -- breach list lookup only
+		}
+
+		if security.DefaultPasswordPolicy.Strength(credential.Password) == security.PasswordStrengthWeak {
+			add(credential, HealthWeak, "weak password", nil)
+		}
+
+		if changed := credential.LastPasswordChange(); opts.MaxAge > 0 && !changed.IsZero() && now.Sub(changed) > opts.MaxAge {
+			add(credential, HealthOld, fmt.Sprintf("unchanged for %d days", int(now.Sub(changed).Hours()/24)), nil)
+		}
+	}
+
+	for _, group := range byHash {
+		if len(group) < 2 {
+			continue
+		}
+		for _, service := range group {
+			others := make([]string, 0, len(group)-1)
+			for _, other := range group {
+				if other != service {
+					others = append(others, other)
+				}
+			}
+			add(v.vaultData.Credentials[service], HealthReused, "same password as "+strings.Join(others, ", "), others)
+		}
+	}
+
+	if opts.Breached != nil && len(breachHashes) > 0 {
+		hashes := make([][sha1.Size]byte, 0, len(breachHashes))
+		for _, hash := range breachHashes {
+			hashes = append(hashes, hash)
+		}
+		counts, err := opts.Breached(hashes)
+		if err != nil {
+			return nil, err
+		}
+		for _, service := range services {
+			hash, ok := breachHashes[service]
+			if !ok || counts[hash] == 0 {
+				continue
+			}
+			add(v.vaultData.Credentials[service], HealthBreached, fmt.Sprintf("seen %d times in breaches", counts[hash]), nil)
+		}
+	}
+
+	// Most severe first, then by service
+	sort.SliceStable(report.Issues, func(i, j int) bool {
+		a, b := report.Issues[i], report.Issues[j]
+		if a.Severity != b.Severity {
+			return a.Severity > b.Severity
+		}
+		return a.Service < b.Service
+	})
+	return report, nil
+}
@@ -0,0 +1,131 @@
+package vault
+
+import (
+	"crypto/sha1" // #nosec G505 -- matches the breach list hash format
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestCheckHealth(t *testing.T) {
+	v, _, cleanup := setupUnlockedTestVault(t)
+	defer cleanup()
+
+	// AddCredential clears the password it is given, so each gets its own copy
+	for service, password := range map[string]string{
+		"github": "Correct-Horse-Battery-Staple-42!",
+		"gitlab": "Correct-Horse-Battery-Staple-42!",
+		"bank":   "password1",
+		"email":  "An0ther-Str0ng-P@ssphrase-2024",
+		"old":    "Y3t-An0ther-Str0ng-P@ssphrase!",
+	} {
+		if err := v.AddCredential(service, "user", []byte(password), "", "", ""); err != nil {
+			t.Fatalf("AddCredential(%s) failed: %v", service, err)
+		}
+	}
+	if err := v.AddCredentialWithOpts("note", "", nil, "", "", "text", AddOpts{Type: TypeSecureNote}); err != nil {
+		t.Fatalf("AddCredentialWithOpts(note) failed: %v", err)
+	}
+	old := v.vaultData.Credentials["old"]
+	old.PasswordChangedAt = time.Now().AddDate(0, 0, -400)
+	v.vaultData.Credentials["old"] = old
+
+	breached := sha1.Sum([]byte("password1")) // #nosec G401 -- test data
+	var lookedUp int
+	report, err := v.CheckHealth(HealthOptions{
+		MaxAge: 365 * 24 * time.Hour,
+		Breached: func(hashes [][sha1.Size]byte) (map[[sha1.Size]byte]int, error) {
+			lookedUp = len(hashes)
+			return map[[sha1.Size]byte]int{breached: 42}, nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("CheckHealth() failed: %v", err)
+	}
+
+	if report.Checked != 5 {
+		t.Errorf("Checked = %d, want 5 (secure note skipped)", report.Checked)
+	}
+	if lookedUp != 5 {
+		t.Errorf("breach lookup got %d hashes, want 5", lookedUp)
+	}
+
+	type issue struct {
+		service string
+		kind    HealthIssueKind
+	}
+	var got []issue
+	for _, i := range report.Issues {
+		got = append(got, issue{i.Service, i.Kind})
+	}
+	want := []issue{
+		{"bank", HealthBreached},
+		{"github", HealthReused},
+		{"gitlab", HealthReused},
+		{"bank", HealthWeak},
+		{"old", HealthOld},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("issues = %v, want %v", got, want)
+	}
+
+	if report.Issues[0].Severity != SeverityCritical || report.Issues[0].Detail != "seen 42 times in breaches" {
+		t.Errorf("breached issue = %+v", report.Issues[0])
+	}
+	if !slices.Equal(report.Issues[1].Related, []string{"gitlab"}) {
+		t.Errorf("github reuse related = %v, want [gitlab]", report.Issues[1].Related)
+	}
+	if report.Issues[4].Severity != SeverityLow || report.Issues[4].Detail != "unchanged for 400 days" {
+		t.Errorf("old issue = %+v", report.Issues[4])
+	}
+}
+
+func TestCheckHealth_OptionalChecks(t *testing.T) {
+	v, _, cleanup := setupUnlockedTestVault(t)
+	defer cleanup()
+
+	if err := v.AddCredential("old", "user", []byte("Y3t-An0ther-Str0ng-P@ssphrase!"), "", "", ""); err != nil {
+		t.Fatalf("AddCredential failed: %v", err)
+	}
+	old := v.vaultData.Credentials["old"]
+	old.PasswordChangedAt = time.Now().AddDate(-5, 0, 0)
+	v.vaultData.Credentials["old"] = old
+
+	// No max age and no breach list: nothing to report
+	report, err := v.CheckHealth(HealthOptions{})
+	if err != nil {
+		t.Fatalf("CheckHealth() failed: %v", err)
+	}
+	if len(report.Issues) != 0 {
+		t.Errorf("issues = %+v, want none", report.Issues)
+	}
+
+	lookupErr := errors.New("boom")
+	_, err = v.CheckHealth(HealthOptions{Breached: func([][sha1.Size]byte) (map[[sha1.Size]byte]int, error) {
+		return nil, lookupErr
+	}})
+	if !errors.Is(err, lookupErr) {
+		t.Errorf("CheckHealth() error = %v, want breach lookup error", err)
+	}
+
+	v.Lock()
+	if _, err := v.CheckHealth(HealthOptions{}); !errors.Is(err, ErrVaultLocked) {
+		t.Errorf("CheckHealth() on locked vault error = %v, want ErrVaultLocked", err)
+	}
+}
+
+func TestParseHealthSeverity(t *testing.T) {
+	for _, s := range []HealthSeverity{SeverityLow, SeverityMedium, SeverityHigh, SeverityCritical} {
+		got, err := ParseHealthSeverity(" " + s.String())
+		if err != nil || got != s {
+			t.Errorf("ParseHealthSeverity(%q) = %v, %v", s.String(), got, err)
+		}
+	}
+	if got, err := ParseHealthSeverity("HIGH"); err != nil || got != SeverityHigh {
+		t.Errorf("ParseHealthSeverity(HIGH) = %v, %v", got, err)
+	}
+	if _, err := ParseHealthSeverity("urgent"); err == nil {
+		t.Error("ParseHealthSeverity(urgent) succeeded, want error")
+	}
+}
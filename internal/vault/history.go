@@ -0,0 +1,143 @@
+package vault
+
+import (
+	"fmt"
+	"time"
+
+	"pass-cli/internal/crypto"
+)
+
+// DefaultPasswordHistoryLimit is the number of previous passwords kept per credential
+// when the vault has no explicit retention limit configured
+const DefaultPasswordHistoryLimit = 10
+
+// PasswordHistoryEntry is a previous password value kept for rollback
+type PasswordHistoryEntry struct {
+	Password  []byte    `json:"password"`   // Previous password value
+	ChangedAt time.Time `json:"changed_at"` // When this value was replaced
+}
+
+// GetPasswordHistory returns previous passwords for a credential, newest first
+// Version N in the CLI corresponds to index N-1 in the returned slice
+func (v *VaultService) GetPasswordHistory(service string) ([]PasswordHistoryEntry, error) {
+	if !v.unlocked {
+		return nil, ErrVaultLocked
+	}
+
+	credential, exists := v.vaultData.Credentials[service]
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrCredentialNotFound, service)
+	}
+
+	return copyPasswordHistory(credential.PasswordHistory), nil
+}
+
+// RestorePasswordVersion rolls a credential back to a previous password
+// The current password is pushed onto the history like any other password change
+func (v *VaultService) RestorePasswordVersion(service string, version int) error {
+	restored, err := v.PasswordVersion(service, version)
+	if err != nil {
+		return err
+	}
+	return v.UpdateCredential(service, UpdateOpts{Password: &restored})
+}
+
+// PasswordVersion returns a copy of entry N (1 = most recent) of a credential's
+// password history, so it can be restored together with other changes through
+// UpdateOpts.Password
+func (v *VaultService) PasswordVersion(service string, version int) ([]byte, error) {
+	if !v.unlocked {
+		return nil, ErrVaultLocked
+	}
+
+	credential, exists := v.vaultData.Credentials[service]
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrCredentialNotFound, service)
+	}
+
+	if version < 1 || version > len(credential.PasswordHistory) {
+		return nil, fmt.Errorf("%w: password version %d does not exist (history has %d entries)",
+			ErrInvalidCredential, version, len(credential.PasswordHistory))
+	}
+
+	// UpdateCredential clears the password it is given, so hand out a copy
+	entry := credential.PasswordHistory[version-1]
+	restored := make([]byte, len(entry.Password))
+	copy(restored, entry.Password)
+	return restored, nil
+}
+
+// GetPasswordHistoryLimit returns the per-credential retention limit for password history
+func (v *VaultService) GetPasswordHistoryLimit() int {
+	if v.vaultData == nil || v.vaultData.PasswordHistoryLimit == 0 {
+		return DefaultPasswordHistoryLimit
+	}
+	if v.vaultData.PasswordHistoryLimit < 0 {
+		return 0 // History disabled
+	}
+	return v.vaultData.PasswordHistoryLimit
+}
+
+// SetPasswordHistoryLimit changes the retention limit and trims existing histories
+// A limit of 0 disables password history and discards stored entries
+func (v *VaultService) SetPasswordHistoryLimit(limit int) error {
+	if !v.unlocked {
+		return ErrVaultLocked
+	}
+	if limit < 0 {
+		return fmt.Errorf("password history limit cannot be negative")
+	}
+
+	// Persist 0 as -1 so "disabled" is distinguishable from "use default"
+	if limit == 0 {
+		v.vaultData.PasswordHistoryLimit = -1
+	} else {
+		v.vaultData.PasswordHistoryLimit = limit
+	}
+
+	for service, credential := range v.vaultData.Credentials {
+		credential.PasswordHistory = trimPasswordHistory(credential.PasswordHistory, limit)
+		v.vaultData.Credentials[service] = credential
+	}
+
+	return v.save()
+}
+
+// pushPasswordHistory prepends the replaced password and enforces the retention limit
+func pushPasswordHistory(history []PasswordHistoryEntry, previous []byte, changedAt time.Time, limit int) []PasswordHistoryEntry {
+	if limit <= 0 {
+		return trimPasswordHistory(history, 0)
+	}
+
+	entry := PasswordHistoryEntry{Password: previous, ChangedAt: changedAt}
+	history = append([]PasswordHistoryEntry{entry}, history...)
+	return trimPasswordHistory(history, limit)
+}
+
+// trimPasswordHistory drops entries beyond limit, clearing their password bytes
+func trimPasswordHistory(history []PasswordHistoryEntry, limit int) []PasswordHistoryEntry {
+	if len(history) <= limit {
+		return history
+	}
+	for _, dropped := range history[limit:] {
+		crypto.ClearBytes(dropped.Password)
+	}
+	if limit == 0 {
+		return nil
+	}
+	return history[:limit]
+}
+
+// copyPasswordHistory deep-copies history entries (nil stays nil)
+func copyPasswordHistory(history []PasswordHistoryEntry) []PasswordHistoryEntry {
+	if history == nil {
+		return nil
+	}
+	copied := make([]PasswordHistoryEntry, len(history))
+	for i, entry := range history {
+		password := make([]byte, len(entry.Password))
+		copy(password, entry.Password)
+		copied[i] = PasswordHistoryEntry{Password: password, ChangedAt: entry.ChangedAt}
+	}
+	return copied
+}
@@ -0,0 +1,147 @@
+package vault
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestUpdateCredentialRecordsPasswordHistory(t *testing.T) {
+	vault, _, cleanup := setupUnlockedTestVault(t)
+	defer cleanup()
+
+	if err := vault.AddCredential("github", "alice", []byte("first"), "", "", ""); err != nil {
+		t.Fatalf("AddCredential() failed: %v", err)
+	}
+
+	for _, password := range []string{"second", "third"} {
+		pw := []byte(password)
+		if err := vault.UpdateCredential("github", UpdateOpts{Password: &pw}); err != nil {
+			t.Fatalf("UpdateCredential() failed: %v", err)
+		}
+	}
+
+	// Setting the same password again must not add an entry
+	same := []byte("third")
+	if err := vault.UpdateCredential("github", UpdateOpts{Password: &same}); err != nil {
+		t.Fatalf("UpdateCredential() failed: %v", err)
+	}
+
+	history, err := vault.GetPasswordHistory("github")
+	if err != nil {
+		t.Fatalf("GetPasswordHistory() failed: %v", err)
+	}
+	if len(history) != 2 {
+		t.Fatalf("history length = %d, want 2", len(history))
+	}
+	if string(history[0].Password) != "second" || string(history[1].Password) != "first" {
+		t.Errorf("history = [%s %s], want newest first [second first]", history[0].Password, history[1].Password)
+	}
+	if history[0].ChangedAt.Before(history[1].ChangedAt) {
+		t.Error("history timestamps should be newest first")
+	}
+}
+
+func TestRestorePasswordVersion(t *testing.T) {
+	vault, _, cleanup := setupUnlockedTestVault(t)
+	defer cleanup()
+
+	if err := vault.AddCredential("github", "alice", []byte("good"), "", "", ""); err != nil {
+		t.Fatalf("AddCredential() failed: %v", err)
+	}
+	bad := []byte("bad")
+	if err := vault.UpdateCredential("github", UpdateOpts{Password: &bad}); err != nil {
+		t.Fatalf("UpdateCredential() failed: %v", err)
+	}
+
+	if err := vault.RestorePasswordVersion("github", 1); err != nil {
+		t.Fatalf("RestorePasswordVersion() failed: %v", err)
+	}
+
+	cred, _ := vault.GetCredential("github", false)
+	if string(cred.Password) != "good" {
+		t.Errorf("Password = %s, want good", cred.Password)
+	}
+	if len(cred.PasswordHistory) != 2 || string(cred.PasswordHistory[0].Password) != "bad" {
+		t.Errorf("replaced password should be version 1 in history, got %d entries", len(cred.PasswordHistory))
+	}
+
+	if err := vault.RestorePasswordVersion("github", 5); !errors.Is(err, ErrInvalidCredential) {
+		t.Errorf("RestorePasswordVersion(5) error = %v, want ErrInvalidCredential", err)
+	}
+}
+
+func TestPasswordVersionWithOtherChanges(t *testing.T) {
+	vault, _, cleanup := setupUnlockedTestVault(t)
+	defer cleanup()
+
+	if err := vault.AddCredential("github", "alice", []byte("good"), "", "", ""); err != nil {
+		t.Fatalf("AddCredential() failed: %v", err)
+	}
+	bad := []byte("bad")
+	if err := vault.UpdateCredential("github", UpdateOpts{Password: &bad}); err != nil {
+		t.Fatalf("UpdateCredential() failed: %v", err)
+	}
+
+	// Looking up a version changes nothing until it is applied
+	restored, err := vault.PasswordVersion("github", 1)
+	if err != nil {
+		t.Fatalf("PasswordVersion() failed: %v", err)
+	}
+	if cred, _ := vault.GetCredential("github", false); string(cred.Password) != "bad" {
+		t.Errorf("Password = %s before the update, want bad", cred.Password)
+	}
+
+	notes := "rolled back"
+	if err := vault.UpdateCredential("github", UpdateOpts{Password: &restored, Notes: &notes}); err != nil {
+		t.Fatalf("UpdateCredential() failed: %v", err)
+	}
+	cred, _ := vault.GetCredential("github", false)
+	if string(cred.Password) != "good" || cred.Notes != notes {
+		t.Errorf("got password %s, notes %q; want the restored password and new notes", cred.Password, cred.Notes)
+	}
+
+	if _, err := vault.PasswordVersion("github", 0); !errors.Is(err, ErrInvalidCredential) {
+		t.Errorf("PasswordVersion(0) error = %v, want ErrInvalidCredential", err)
+	}
+}
+
+func TestPasswordHistoryLimit(t *testing.T) {
+	vault, _, cleanup := setupUnlockedTestVault(t)
+	defer cleanup()
+
+	if got := vault.GetPasswordHistoryLimit(); got != DefaultPasswordHistoryLimit {
+		t.Errorf("GetPasswordHistoryLimit() = %d, want %d", got, DefaultPasswordHistoryLimit)
+	}
+
+	if err := vault.AddCredential("github", "alice", []byte("v0"), "", "", ""); err != nil {
+		t.Fatalf("AddCredential() failed: %v", err)
+	}
+	for _, password := range []string{"v1", "v2", "v3", "v4"} {
+		pw := []byte(password)
+		if err := vault.UpdateCredential("github", UpdateOpts{Password: &pw}); err != nil {
+			t.Fatalf("UpdateCredential() failed: %v", err)
+		}
+	}
+
+	// Lowering the limit trims existing history
+	if err := vault.SetPasswordHistoryLimit(2); err != nil {
+		t.Fatalf("SetPasswordHistoryLimit() failed: %v", err)
+	}
+	history, _ := vault.GetPasswordHistory("github")
+	if len(history) != 2 || string(history[0].Password) != "v3" || string(history[1].Password) != "v2" {
+		t.Errorf("history after trim has %d entries, want [v3 v2]", len(history))
+	}
+
+	// Zero disables history entirely
+	if err := vault.SetPasswordHistoryLimit(0); err != nil {
+		t.Fatalf("SetPasswordHistoryLimit() failed: %v", err)
+	}
+	pw := []byte("v5")
+	if err := vault.UpdateCredential("github", UpdateOpts{Password: &pw}); err != nil {
+		t.Fatalf("UpdateCredential() failed: %v", err)
+	}
+	history, _ = vault.GetPasswordHistory("github")
+	if len(history) != 0 {
+		t.Errorf("history length = %d, want 0 when disabled", len(history))
+	}
+}
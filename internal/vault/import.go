@@ -0,0 +1,301 @@
+package vault
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+
+	"pass-cli/internal/crypto"
+	"pass-cli/internal/security"
+)
+
+// ImportEntry is a credential read from another password manager
+type ImportEntry struct {
+	Service      string
+	Username     string
+	Password     []byte
+	Type         CredentialType // Empty = login
+	Category     string
+	Tags         []string
+	URL          string
+	Notes        string
+	CustomFields []CustomField
+	TOTP         *TOTPConfig
+	ExpiresAt    *time.Time // Optional hard expiry date
+}
+
+// DuplicateMode decides what happens when an imported service name already exists
+type DuplicateMode string
+
+const (
+	DuplicateSkip      DuplicateMode = "skip"      // Keep the existing credential
+	DuplicateOverwrite DuplicateMode = "overwrite" // Replace the existing credential's fields
+	DuplicateRename    DuplicateMode = "rename"    // Import under a new name ("github-2")
+)
+
+// DuplicateModes lists the valid duplicate modes in display order
+var DuplicateModes = []DuplicateMode{DuplicateSkip, DuplicateOverwrite, DuplicateRename}
+
+// ImportAction is the outcome for one imported entry
+type ImportAction string
+
+const (
+	ImportAdded       ImportAction = "added"
+	ImportOverwritten ImportAction = "overwritten"
+	ImportRenamed     ImportAction = "renamed"
+	ImportSkipped     ImportAction = "skipped"
+	ImportFailed      ImportAction = "failed"
+)
+
+// ImportOptions controls an import run
+type ImportOptions struct {
+	Duplicates DuplicateMode // Empty = skip
+	DryRun     bool          // Compute the report without changing the vault
+}
+
+// ImportResult records what happened to one entry
+type ImportResult struct {
+	Source  string // Service name as read from the import file
+	Service string // Service name in the vault (differs from Source when renamed)
+	Action  ImportAction
+	Err     error // Why the entry failed (ImportFailed only)
+}
+
+// ImportReport summarizes an import
+type ImportReport struct {
+	Results []ImportResult
+}
+
+// Count returns the number of entries with the given outcome
+func (r *ImportReport) Count(action ImportAction) int {
+	count := 0
+	for _, result := range r.Results {
+		if result.Action == action {
+			count++
+		}
+	}
+	return count
+}
+
+// ImportCredentials adds entries read from another password manager in a single save.
+// Existing service names are handled by opts.Duplicates; names repeated within the
+// import itself are always renamed so no entry is lost. Invalid entries are reported
+// as failed without stopping the import.
+func (v *VaultService) ImportCredentials(entries []ImportEntry, opts ImportOptions) (*ImportReport, error) {
+	return v.importCredentials(entries, opts, nil)
+}
+
+// importCredentials implements ImportCredentials. When something will be merged,
+// beforeSave is called with the report so callers can change vaultData in the
+// same save; it returns a func that undoes the change if the save fails.
+func (v *VaultService) importCredentials(entries []ImportEntry, opts ImportOptions, beforeSave func(*ImportReport) (undo func())) (*ImportReport, error) {
+	if !v.unlocked {
+		return nil, ErrVaultLocked
+	}
+	if opts.Duplicates == "" {
+		opts.Duplicates = DuplicateSkip
+	}
+	if !isValidDuplicateMode(opts.Duplicates) {
+		return nil, fmt.Errorf("invalid duplicate mode: %s", opts.Duplicates)
+	}
+
+	report := &ImportReport{}
+	credentials := make(map[string]Credential, len(v.vaultData.Credentials)+len(entries))
+	for service, credential := range v.vaultData.Credentials {
+		credentials[service] = credential
+	}
+	imported := make(map[string]bool, len(entries))
+	now := time.Now()
+
+	for _, entry := range entries {
+		source := strings.TrimSpace(entry.Service)
+		credential, err := importedCredential(entry, now)
+		if err != nil {
+			report.Results = append(report.Results, ImportResult{Source: source, Service: source, Action: ImportFailed, Err: err})
+			continue
+		}
+
+		service := credential.Service
+		action := ImportAdded
+		existing, exists := credentials[service]
+		switch {
+		case !exists:
+		case imported[service] || opts.Duplicates == DuplicateRename:
+			service = uniqueServiceName(credentials, service)
+			credential.Service = service
+			action = ImportRenamed
+		case opts.Duplicates == DuplicateSkip:
+			report.Results = append(report.Results, ImportResult{Source: source, Service: service, Action: ImportSkipped})
+			continue
+		default:
+			credential = overwriteCredential(existing, credential, now, v.GetPasswordHistoryLimit())
+			action = ImportOverwritten
+		}
+
+		credentials[service] = credential
+		imported[service] = true
+		report.Results = append(report.Results, ImportResult{Source: source, Service: service, Action: action})
+	}
+
+	if opts.DryRun || len(imported) == 0 {
+		return report, nil
+	}
+
+	undo := func() {}
+	if beforeSave != nil {
+		undo = beforeSave(report)
+	}
+	previous := v.vaultData.Credentials
+	v.vaultData.Credentials = credentials
+	if err := v.save(); err != nil {
+		// Keep the in-memory vault consistent with disk
+		v.vaultData.Credentials = previous
+		undo()
+		return nil, err
+	}
+
+	for _, result := range report.Results {
+		switch result.Action {
+		case ImportAdded, ImportRenamed:
+			v.logAudit(security.EventCredentialAdd, security.OutcomeSuccess, result.Service)
+		case ImportOverwritten:
+			v.logAudit(security.EventCredentialUpdate, security.OutcomeSuccess, result.Service)
+		}
+	}
+	return report, nil
+}
+
+// importedCredential validates an entry and builds a new credential from it.
+// Other managers are less strict than credential templates (logins without a
+// password are common), so only structural problems are rejected; field keys and
+// tags are adjusted rather than refused.
+func importedCredential(entry ImportEntry, now time.Time) (Credential, error) {
+	service := strings.TrimSpace(entry.Service)
+	if err := validateServiceName(service); err != nil {
+		return Credential{}, err
+	}
+	if _, ok := GetTemplate(entry.Type); !ok {
+		return Credential{}, fmt.Errorf("%w: unknown credential type %q", ErrInvalidCredential, entry.Type)
+	}
+
+	var totp *TOTPConfig
+	if entry.TOTP != nil {
+		totp = copyTOTP(entry.TOTP)
+		if err := totp.normalize(); err != nil {
+			return Credential{}, err
+		}
+	}
+
+	return Credential{
+		Service:           service,
+		Username:          entry.Username,
+		Password:          append([]byte(nil), entry.Password...),
+		Type:              entry.Type,
+		Category:          strings.TrimSpace(entry.Category),
+		Tags:              sanitizeImportedTags(entry.Tags),
+		URL:               entry.URL,
+		Notes:             entry.Notes,
+		CustomFields:      sanitizeImportedFields(entry.CustomFields),
+		TOTP:              totp,
+		ExpiresAt:         copyTime(entry.ExpiresAt),
+		CreatedAt:         now,
+		PasswordChangedAt: now,
+		UpdatedAt:         now,
+		UsageRecord:       make(map[string]UsageRecord),
+	}, nil
+}
+
+// overwriteCredential replaces an existing credential's fields with imported ones.
+// Timestamps, usage, and attachments are kept; a replaced password goes to the history.
+func overwriteCredential(existing, imported Credential, now time.Time, historyLimit int) Credential {
+	updated := existing
+	if !bytes.Equal(existing.Password, imported.Password) {
+		updated.PasswordHistory = pushPasswordHistory(existing.PasswordHistory, existing.Password, now, historyLimit)
+		updated.PasswordChangedAt = now
+	}
+	updated.Username = imported.Username
+	updated.Password = imported.Password
+	updated.Type = imported.Type
+	updated.Category = imported.Category
+	updated.Tags = imported.Tags
+	updated.URL = imported.URL
+	updated.Notes = imported.Notes
+	updated.CustomFields = imported.CustomFields
+	updated.TOTP = imported.TOTP
+	updated.ExpiresAt = imported.ExpiresAt
+	updated.UpdatedAt = now
+	updated.ModifiedCount++
+	return updated
+}
+
+// uniqueServiceName appends the first free numeric suffix ("github-2", "github-3", ...)
+func uniqueServiceName(credentials map[string]Credential, service string) string {
+	for n := 2; ; n++ {
+		candidate := fmt.Sprintf("%s-%d", service, n)
+		if _, exists := credentials[candidate]; !exists {
+			return candidate
+		}
+	}
+}
+
+// sanitizeImportedFields makes custom field keys valid: empty keys get a generic
+// name, and keys that are reserved or repeated get a numeric suffix
+func sanitizeImportedFields(fields []CustomField) []CustomField {
+	if len(fields) == 0 {
+		return nil
+	}
+
+	seen := make(map[string]bool, len(fields)+len(reservedFieldKeys))
+	for _, reserved := range reservedFieldKeys {
+		seen[reserved] = true
+	}
+
+	sanitized := make([]CustomField, 0, len(fields))
+	for _, field := range fields {
+		key := strings.TrimSpace(field.Key)
+		if key == "" {
+			key = "field"
+		}
+		candidate := key
+		for n := 2; seen[strings.ToLower(candidate)]; n++ {
+			candidate = fmt.Sprintf("%s-%d", key, n)
+		}
+		seen[strings.ToLower(candidate)] = true
+
+		field.Key = candidate
+		sanitized = append(sanitized, field)
+	}
+	return sanitized
+}
+
+// sanitizeImportedTags replaces whitespace and commas, which tags cannot contain, with dashes
+func sanitizeImportedTags(tags []string) []string {
+	var sanitized []string
+	for _, tag := range tags {
+		tag = strings.Join(strings.FieldsFunc(tag, func(r rune) bool {
+			return r == ',' || r == ' ' || r == '\t' || r == '\n' || r == '\r'
+		}), "-")
+		if tag != "" && !HasTag(sanitized, tag) {
+			sanitized = append(sanitized, tag)
+		}
+	}
+	return sanitized
+}
+
+// isValidDuplicateMode reports whether m is a known duplicate mode
+func isValidDuplicateMode(m DuplicateMode) bool {
+	for _, mode := range DuplicateModes {
+		if m == mode {
+			return true
+		}
+	}
+	return false
+}
+
+// ClearImportEntries zeroes the passwords of imported entries once they are no longer needed
+func ClearImportEntries(entries []ImportEntry) {
+	for i := range entries {
+		crypto.ClearBytes(entries[i].Password)
+	}
+}
@@ -0,0 +1,151 @@
+package vault
+
+import (
+	"errors"
+	"testing"
+)
+
+func importTestEntries() []ImportEntry {
+	return []ImportEntry{
+		{Service: "github", Username: "octocat", Password: []byte("new-pass"), Category: "Dev", Tags: []string{"work stuff"}},
+		{Service: "aws", Username: "admin", Password: []byte("s3cret"), CustomFields: []CustomField{{Key: "url", Value: "x"}, {Key: "", Value: "y"}}},
+		{Service: "aws", Username: "other", Password: []byte("s3cret2")},
+		{Service: "  ", Password: []byte("orphan")},
+	}
+}
+
+func TestImportCredentials_DuplicateModes(t *testing.T) {
+	tests := []struct {
+		mode         DuplicateMode
+		wantGithub   string // Password of "github" after the import
+		wantAction   ImportAction
+		wantServices int
+	}{
+		{DuplicateSkip, "old-pass", ImportSkipped, 3},
+		{DuplicateOverwrite, "new-pass", ImportOverwritten, 3},
+		{DuplicateRename, "old-pass", ImportRenamed, 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.mode), func(t *testing.T) {
+			vault, _, cleanup := setupUnlockedTestVault(t)
+			defer cleanup()
+
+			if err := vault.AddCredential("github", "octocat", []byte("old-pass"), "", "", ""); err != nil {
+				t.Fatalf("AddCredential() failed: %v", err)
+			}
+
+			report, err := vault.ImportCredentials(importTestEntries(), ImportOptions{Duplicates: tt.mode})
+			if err != nil {
+				t.Fatalf("ImportCredentials() failed: %v", err)
+			}
+			if len(report.Results) != 4 {
+				t.Fatalf("Results = %+v", report.Results)
+			}
+			if report.Results[0].Action != tt.wantAction {
+				t.Errorf("github action = %s, want %s", report.Results[0].Action, tt.wantAction)
+			}
+
+			// The second "aws" in the same import is always renamed
+			if got := report.Results[2]; got.Action != ImportRenamed || got.Service != "aws-2" {
+				t.Errorf("repeated aws = %+v", got)
+			}
+			if got := report.Results[3]; got.Action != ImportFailed || !errors.Is(got.Err, ErrInvalidCredential) {
+				t.Errorf("empty service = %+v", got)
+			}
+			if report.Count(ImportFailed) != 1 {
+				t.Errorf("Count(failed) = %d", report.Count(ImportFailed))
+			}
+
+			github, err := vault.GetCredential("github", false)
+			if err != nil {
+				t.Fatalf("GetCredential() failed: %v", err)
+			}
+			if string(github.Password) != tt.wantGithub {
+				t.Errorf("github password = %q, want %q", github.Password, tt.wantGithub)
+			}
+
+			services, _ := vault.ListCredentials()
+			if len(services) != tt.wantServices {
+				t.Errorf("services = %v, want %d", services, tt.wantServices)
+			}
+
+			if tt.mode == DuplicateRename {
+				renamed, err := vault.GetCredential("github-2", false)
+				if err != nil || string(renamed.Password) != "new-pass" {
+					t.Errorf("github-2 = %+v, %v", renamed, err)
+				}
+			}
+			if tt.mode == DuplicateOverwrite {
+				if len(github.PasswordHistory) != 1 || string(github.PasswordHistory[0].Password) != "old-pass" {
+					t.Errorf("PasswordHistory = %+v", github.PasswordHistory)
+				}
+				if github.Category != "Dev" || len(github.Tags) != 1 || github.Tags[0] != "work-stuff" {
+					t.Errorf("github category/tags = %q/%v", github.Category, github.Tags)
+				}
+			}
+		})
+	}
+}
+
+func TestImportCredentials_SanitizesFields(t *testing.T) {
+	vault, _, cleanup := setupUnlockedTestVault(t)
+	defer cleanup()
+
+	if _, err := vault.ImportCredentials(importTestEntries()[1:2], ImportOptions{}); err != nil {
+		t.Fatalf("ImportCredentials() failed: %v", err)
+	}
+
+	aws, err := vault.GetCredential("aws", false)
+	if err != nil {
+		t.Fatalf("GetCredential() failed: %v", err)
+	}
+	if len(aws.CustomFields) != 2 || aws.CustomFields[0].Key != "url-2" || aws.CustomFields[1].Key != "field" {
+		t.Errorf("CustomFields = %+v", aws.CustomFields)
+	}
+}
+
+func TestImportCredentials_DryRun(t *testing.T) {
+	vault, vaultPath, cleanup := setupUnlockedTestVault(t)
+	defer cleanup()
+
+	report, err := vault.ImportCredentials(importTestEntries(), ImportOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("ImportCredentials() failed: %v", err)
+	}
+	if report.Count(ImportAdded) != 2 || report.Count(ImportRenamed) != 1 {
+		t.Errorf("Results = %+v", report.Results)
+	}
+
+	if services, _ := vault.ListCredentials(); len(services) != 0 {
+		t.Errorf("Dry run changed the vault: %v", services)
+	}
+
+	// Nothing must reach disk either
+	vault.Lock()
+	reopened, err := New(vaultPath)
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	if err := reopened.Unlock([]byte("TestPassword123!")); err != nil {
+		t.Fatalf("Unlock() failed: %v", err)
+	}
+	defer reopened.Lock()
+	if services, _ := reopened.ListCredentials(); len(services) != 0 {
+		t.Errorf("Dry run saved the vault: %v", services)
+	}
+}
+
+func TestImportCredentials_Errors(t *testing.T) {
+	vault, _, cleanup := setupUnlockedTestVault(t)
+	defer cleanup()
+
+	if _, err := vault.ImportCredentials(nil, ImportOptions{Duplicates: "merge"}); err == nil {
+		t.Error("Expected an error for an invalid duplicate mode")
+	}
+
+	vault.Lock()
+	if _, err := vault.ImportCredentials(importTestEntries(), ImportOptions{}); !errors.Is(err, ErrVaultLocked) {
+		t.Errorf("ImportCredentials() on locked vault = %v, want ErrVaultLocked", err)
+	}
+}
@@ -0,0 +1,487 @@
+package vault
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"pass-cli/internal/crypto"
+	"pass-cli/internal/security"
+	"pass-cli/internal/storage"
+)
+
+const (
+	keyFileSize       = 64 // Random bytes written by CreateKeyFile
+	recoveryCodeBytes = 20 // 160 bits, 32 base32 characters
+)
+
+var (
+	ErrEmptyKeyFile    = errors.New("key file is empty")
+	ErrKeyFileRequired = errors.New("vault requires a key file in addition to the master password")
+)
+
+// SetKeyFile sets the key file combined with the master password by slots
+// that require one. Initialize binds a new vault to it; Unlock and
+// ChangePassword use it for such slots and ignore it otherwise.
+func (v *VaultService) SetKeyFile(path string) {
+	v.keyFile = path
+}
+
+// ListKeySlots returns the vault's key slots without their key material.
+// Slots are read from the vault header, so the vault does not need to be unlocked.
+func (v *VaultService) ListKeySlots() ([]storage.KeySlot, error) {
+	return v.storageService.ListKeySlots()
+}
+
+// AddPasswordSlot lets another password unlock the vault. The password must
+// meet the same policy as the master password.
+func (v *VaultService) AddPasswordSlot(password []byte, label string, params crypto.KDFParams) (int, error) {
+	defer crypto.ClearBytes(password)
+
+	if !v.unlocked {
+		return 0, ErrVaultLocked
+	}
+
+	passwordPolicy := &security.PasswordPolicy{
+		MinLength:        12,
+		RequireUppercase: true,
+		RequireLowercase: true,
+		RequireDigit:     true,
+		RequireSymbol:    true,
+	}
+	if err := passwordPolicy.Validate(password); err != nil {
+		if rateLimitErr := v.rateLimiter.CheckAndRecordFailure(); rateLimitErr != nil {
+			return 0, rateLimitErr
+		}
+		return 0, fmt.Errorf("password does not meet requirements: %w", err)
+	}
+	v.rateLimiter.Reset()
+
+	return v.addKeySlot(storage.KeySlotPassword, label, password, params)
+}
+
+// AddKeyFileSlot lets the contents of a key file unlock the vault
+func (v *VaultService) AddKeyFileSlot(path, label string, params crypto.KDFParams) (int, error) {
+	if !v.unlocked {
+		return 0, ErrVaultLocked
+	}
+
+	secret, err := ReadKeyFile(path)
+	if err != nil {
+		return 0, err
+	}
+	defer crypto.ClearBytes(secret)
+
+	return v.addKeySlot(storage.KeySlotKeyFile, label, secret, params)
+}
+
+// AddRecoverySlot generates a recovery code that can be typed instead of the
+// master password, and returns it with the slot ID. The code is not stored
+// anywhere and cannot be shown again.
+func (v *VaultService) AddRecoverySlot(label string, params crypto.KDFParams) (string, int, error) {
+	if !v.unlocked {
+		return "", 0, ErrVaultLocked
+	}
+
+	code, err := GenerateRecoveryCode()
+	if err != nil {
+		return "", 0, err
+	}
+	secret := normalizeRecoveryCode([]byte(code))
+	defer crypto.ClearBytes(secret)
+
+	id, err := v.addKeySlot(storage.KeySlotRecovery, label, secret, params)
+	if err != nil {
+		return "", 0, err
+	}
+	return code, id, nil
+}
+
+func (v *VaultService) addKeySlot(slotType, label string, secret []byte, params crypto.KDFParams) (int, error) {
+	if err := params.Validate(); err != nil {
+		return 0, err
+	}
+	if err := v.ensureKeySlots(); err != nil {
+		return 0, err
+	}
+
+	id, err := v.storageService.AddKeySlot(v.dataKey, slotType, label, secret, params)
+	if err != nil {
+		v.logAudit(security.EventKeySlotAdd, security.OutcomeFailure, "")
+		return 0, fmt.Errorf("failed to add key slot: %w", err)
+	}
+
+	v.logAudit(security.EventKeySlotAdd, security.OutcomeSuccess, "")
+	return id, nil
+}
+
+// ensureKeySlots converts a version 1 vault to key slots, keeping the master
+// password in slot 1. PBKDF2 vaults are upgraded to Argon2id on the way.
+func (v *VaultService) ensureKeySlots() error {
+	if v.unlockedSlot != 0 {
+		return nil
+	}
+	hasSlots, err := v.storageService.HasKeySlots()
+	if err != nil || hasSlots {
+		return err
+	}
+
+	params, err := v.storageService.GetKDFParams()
+	if err != nil {
+		return err
+	}
+	if params.Algorithm != crypto.KDFArgon2id {
+		params = crypto.DefaultKDFParams()
+	}
+	_, err = v.setPasswordSlot(nil, nil, storage.KeySlotPassword, v.slotSecret, &params)
+	if err != nil {
+		return fmt.Errorf("failed to convert vault to key slots: %w", err)
+	}
+	v.unlockedSlot = 1
+	return nil
+}
+
+// SlotSecrets holds the secrets of key slots by slot ID, from KeySlotSecret,
+// so a rekey can keep those slots
+type SlotSecrets map[int][]byte
+
+// Clear wipes the secrets from memory
+func (s SlotSecrets) Clear() {
+	for id, secret := range s {
+		crypto.ClearBytes(secret)
+		delete(s, id)
+	}
+}
+
+// UnlockedSlot returns the ID of the slot whose secret the vault holds (the one
+// it was unlocked with or whose password was last changed), or 0 if none
+func (v *VaultService) UnlockedSlot() int {
+	return v.unlockedSlot
+}
+
+// KeySlotSecret checks what the user gave for slot id and returns the slot's
+// secret for a rekey: a password or recovery code as typed, the path of a key
+// file, or the key combined from recovery shares. Slots combining a password
+// with a key file use the one set by SetKeyFile.
+func (v *VaultService) KeySlotSecret(id int, input []byte) ([]byte, error) {
+	if !v.unlocked {
+		return nil, ErrVaultLocked
+	}
+	slot, err := v.keySlot(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var secret []byte
+	switch slot.Type {
+	case storage.KeySlotPasswordKeyFile:
+		if v.keyFile == "" {
+			return nil, ErrKeyFileRequired
+		}
+		secret, err = compositeSecret(input, v.keyFile)
+	case storage.KeySlotKeyFile:
+		secret, err = ReadKeyFile(string(input))
+	case storage.KeySlotRecovery:
+		secret = normalizeRecoveryCode(input)
+	default:
+		secret = append([]byte(nil), input...)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	dataKey, opened, err := v.storageService.OpenDataKey(secret, slot.Type)
+	if err == nil && (opened != id || !bytes.Equal(dataKey, v.dataKey)) {
+		err = storage.ErrNoMatchingSlot
+	}
+	crypto.ClearBytes(dataKey)
+	if err != nil {
+		crypto.ClearBytes(secret)
+		return nil, fmt.Errorf("key slot %d: %w", id, err)
+	}
+	return secret, nil
+}
+
+// Rekey re-encrypts the vault with a new random data key, wrapped in the slot
+// whose secret the vault holds and in the slots in secrets. Other slots are
+// removed. Snapshots are re-encrypted or deleted and the backup is deleted, so
+// the old key, and the secrets of removed or replaced slots, open nothing left
+// on disk. Version 1 vaults are converted to key slots first.
+func (v *VaultService) Rekey(secrets SlotSecrets) error {
+	if !v.unlocked {
+		return ErrVaultLocked
+	}
+	if err := v.ensureKeySlots(); err != nil {
+		return err
+	}
+	return v.rekey(v.keptSlots(secrets))
+}
+
+// keptSlots adds the secret the vault holds to secrets for a rekey
+func (v *VaultService) keptSlots(secrets SlotSecrets) SlotSecrets {
+	kept := make(SlotSecrets, len(secrets)+1)
+	for id, secret := range secrets {
+		kept[id] = secret
+	}
+	if v.unlockedSlot != 0 {
+		kept[v.unlockedSlot] = v.slotSecret
+	}
+	return kept
+}
+
+// rekey replaces the data key, keeping exactly the slots in kept
+func (v *VaultService) rekey(kept SlotSecrets) error {
+	data, err := json.Marshal(v.vaultData)
+	if err != nil {
+		return fmt.Errorf("failed to marshal vault data: %w", err)
+	}
+	defer crypto.ClearBytes(data)
+
+	dataKey, err := v.storageService.Rekey(data, v.dataKey, kept)
+	if err != nil {
+		v.logAudit(security.EventVaultRekey, security.OutcomeFailure, "")
+		return fmt.Errorf("failed to replace data key: %w", err)
+	}
+	crypto.ClearBytes(v.dataKey)
+	v.dataKey = dataKey
+	if _, ok := kept[v.unlockedSlot]; !ok {
+		v.unlockedSlot = 0
+		crypto.ClearBytes(v.slotSecret)
+		v.slotSecret = nil
+	}
+
+	v.logAudit(security.EventVaultRekey, security.OutcomeSuccess, "")
+	return nil
+}
+
+// RemoveKeySlot stops a key slot from unlocking the vault, then rekeys it (see
+// Rekey) so copies of the vault from before the removal do not open with the
+// removed slot's secret either. Other slots are kept if the vault holds their
+// secret or it is in secrets. The last slot cannot be removed.
+func (v *VaultService) RemoveKeySlot(id int, secrets SlotSecrets) error {
+	if !v.unlocked {
+		return ErrVaultLocked
+	}
+
+	slots, err := v.storageService.ListKeySlots()
+	if err != nil {
+		return err
+	}
+	if _, err := v.keySlot(id); err != nil {
+		return err
+	}
+	if len(slots) == 1 {
+		return storage.ErrLastKeySlot
+	}
+
+	kept := v.keptSlots(secrets)
+	delete(kept, id)
+	if len(kept) == 0 {
+		return fmt.Errorf("%w: no secret given for the remaining slots", storage.ErrLastKeySlot)
+	}
+	if err := v.rekey(kept); err != nil {
+		return err
+	}
+
+	v.logAudit(security.EventKeySlotRemove, security.OutcomeSuccess, "")
+	return nil
+}
+
+// keySlot returns the slot with the given ID
+func (v *VaultService) keySlot(id int) (*storage.KeySlot, error) {
+	slots, err := v.storageService.ListKeySlots()
+	if err != nil {
+		return nil, err
+	}
+	for i := range slots {
+		if slots[i].ID == id {
+			return &slots[i], nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %d", storage.ErrKeySlotNotFound, id)
+}
+
+// openPasswordSlot opens the data key with a typed password. Slots combining
+// it with the key file are tried first, then password slots, then recovery
+// codes, ignoring case and dashes. Returns the secret that opened the slot,
+// which the caller must clear.
+func (v *VaultService) openPasswordSlot(password []byte) ([]byte, int, []byte, error) {
+	slots, err := v.storageService.ListKeySlots()
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	requiresKeyFile := false
+	for _, slot := range slots {
+		requiresKeyFile = requiresKeyFile || slot.Type == storage.KeySlotPasswordKeyFile
+	}
+
+	if requiresKeyFile && v.keyFile != "" {
+		secret, err := compositeSecret(password, v.keyFile)
+		if err != nil {
+			return nil, 0, nil, err
+		}
+		dataKey, id, err := v.storageService.OpenDataKey(secret, storage.KeySlotPasswordKeyFile)
+		if err == nil {
+			return dataKey, id, secret, nil
+		}
+		crypto.ClearBytes(secret)
+		if !errors.Is(err, storage.ErrNoMatchingSlot) {
+			return nil, 0, nil, err
+		}
+	}
+
+	secret := append([]byte(nil), password...)
+	dataKey, id, err := v.storageService.OpenDataKey(secret, storage.KeySlotPassword)
+	if errors.Is(err, storage.ErrNoMatchingSlot) {
+		crypto.ClearBytes(secret)
+		secret = normalizeRecoveryCode(password)
+		dataKey, id, err = v.storageService.OpenDataKey(secret, storage.KeySlotRecovery)
+	}
+	if err != nil {
+		crypto.ClearBytes(secret)
+		if errors.Is(err, storage.ErrNoMatchingSlot) && requiresKeyFile && v.keyFile == "" {
+			return nil, 0, nil, ErrKeyFileRequired
+		}
+		return nil, 0, nil, err
+	}
+	return dataKey, id, secret, nil
+}
+
+// passwordSlotSecret returns the slot type and secret for a master password,
+// combined with keyFile when it is not empty
+func (v *VaultService) passwordSlotSecret(password []byte, keyFile string) (string, []byte, error) {
+	if keyFile == "" {
+		return storage.KeySlotPassword, append([]byte(nil), password...), nil
+	}
+	secret, err := compositeSecret(password, keyFile)
+	if err != nil {
+		return "", nil, err
+	}
+	return storage.KeySlotPasswordKeyFile, secret, nil
+}
+
+// compositeSecret combines a password with a key file, like KeePass composite
+// keys: SHA-256(password) followed by the key file secret. Neither factor
+// alone reveals anything about the other.
+func compositeSecret(password []byte, keyFile string) ([]byte, error) {
+	keyFileSecret, err := ReadKeyFile(keyFile)
+	if err != nil {
+		return nil, err
+	}
+	defer crypto.ClearBytes(keyFileSecret)
+
+	passwordHash := sha256.Sum256(password)
+	secret := append(passwordHash[:], keyFileSecret...)
+	crypto.ClearBytes(passwordHash[:])
+	return secret, nil
+}
+
+// UnlockWithKeyFile opens the vault with a key file registered in a key slot
+func (v *VaultService) UnlockWithKeyFile(path string) error {
+	if v.unlocked {
+		return nil // Already unlocked
+	}
+
+	secret, err := ReadKeyFile(path)
+	if err != nil {
+		return err
+	}
+	defer crypto.ClearBytes(secret)
+
+	if err := v.recoverIncompleteMigration(); err != nil {
+		return err
+	}
+
+	dataKey, slotID, err := v.storageService.OpenDataKey(secret, storage.KeySlotKeyFile)
+	if err == nil && slotID == 0 {
+		// Version 1 vaults only accept the master password
+		crypto.ClearBytes(dataKey)
+		err = storage.ErrNotEnvelopeVault
+	}
+	if err != nil {
+		v.logAudit(security.EventVaultUnlock, security.OutcomeFailure, "")
+		return fmt.Errorf("failed to unlock vault with key file: %w", err)
+	}
+
+	return v.unlockWithDataKey(dataKey, slotID, secret)
+}
+
+// ReadKeyFile returns the secret a key file contributes: the SHA-256 of its
+// contents, so any file can serve as a key file
+func ReadKeyFile(path string) ([]byte, error) {
+	f, err := os.Open(path) // #nosec G304 -- Key file path is chosen by the user
+	if err != nil {
+		return nil, fmt.Errorf("failed to open key file: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	h := sha256.New()
+	n, err := io.Copy(h, f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key file: %w", err)
+	}
+	if n == 0 {
+		return nil, ErrEmptyKeyFile
+	}
+	return h.Sum(nil), nil
+}
+
+// CreateKeyFile writes a new key file of random bytes, readable only by the
+// owner. An existing file is never overwritten.
+func CreateKeyFile(path string) error {
+	key := make([]byte, keyFileSize)
+	if _, err := rand.Read(key); err != nil {
+		return fmt.Errorf("failed to generate key file: %w", err)
+	}
+	defer crypto.ClearBytes(key)
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600) // #nosec G304 -- Key file path is chosen by the user
+	if err != nil {
+		return fmt.Errorf("failed to create key file: %w", err)
+	}
+	if _, err := f.Write(key); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to write key file: %w", err)
+	}
+	return f.Close()
+}
+
+// GenerateRecoveryCode returns a random recovery code such as
+// "ABCD-EFGH-...", eight groups of four base32 characters
+func GenerateRecoveryCode() (string, error) {
+	raw := make([]byte, recoveryCodeBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("failed to generate recovery code: %w", err)
+	}
+	encoded := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(raw)
+
+	groups := make([]string, 0, len(encoded)/4)
+	for i := 0; i < len(encoded); i += 4 {
+		groups = append(groups, encoded[i:i+4])
+	}
+	return strings.Join(groups, "-"), nil
+}
+
+// normalizeRecoveryCode strips separators and upper-cases a recovery code so
+// it matches however it was typed
+func normalizeRecoveryCode(code []byte) []byte {
+	normalized := make([]byte, 0, len(code))
+	for _, c := range code {
+		switch {
+		case c == '-' || c == ' ':
+			continue
+		case c >= 'a' && c <= 'z':
+			normalized = append(normalized, c-'a'+'A')
+		default:
+			normalized = append(normalized, c)
+		}
+	}
+	return normalized
+}
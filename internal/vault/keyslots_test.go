@@ -0,0 +1,472 @@
+package vault
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"pass-cli/internal/crypto"
+	"pass-cli/internal/storage"
+)
+
+// testSlotKDF keeps the extra key slots cheap to derive
+var testSlotKDF = crypto.KDFParams{Algorithm: crypto.KDFArgon2id, Memory: crypto.MinArgon2Memory, Time: crypto.MinArgon2Time, Threads: 1}
+
+func TestKeySlots(t *testing.T) {
+	vault, vaultPath, cleanup := setupUnlockedTestVault(t)
+	defer cleanup()
+
+	if err := vault.AddCredential("github", "user", []byte("pass"), "", "", ""); err != nil {
+		t.Fatalf("AddCredential() failed: %v", err)
+	}
+
+	passwordID, err := vault.AddPasswordSlot([]byte("SecondPassword456!"), "laptop", testSlotKDF)
+	if err != nil {
+		t.Fatalf("AddPasswordSlot() failed: %v", err)
+	}
+	keyFile := filepath.Join(filepath.Dir(vaultPath), "vault.key")
+	if err := CreateKeyFile(keyFile); err != nil {
+		t.Fatalf("CreateKeyFile() failed: %v", err)
+	}
+	if err := CreateKeyFile(keyFile); err == nil {
+		t.Error("CreateKeyFile() should not overwrite an existing file")
+	}
+	keyFileID, err := vault.AddKeyFileSlot(keyFile, "usb", testSlotKDF)
+	if err != nil {
+		t.Fatalf("AddKeyFileSlot() failed: %v", err)
+	}
+	code, recoveryID, err := vault.AddRecoverySlot("", testSlotKDF)
+	if err != nil {
+		t.Fatalf("AddRecoverySlot() failed: %v", err)
+	}
+
+	slots, err := vault.ListKeySlots()
+	if err != nil {
+		t.Fatalf("ListKeySlots() failed: %v", err)
+	}
+	if len(slots) != 4 {
+		t.Fatalf("expected 4 key slots, got %d", len(slots))
+	}
+	wantTypes := map[int]string{1: storage.KeySlotPassword, passwordID: storage.KeySlotPassword, keyFileID: storage.KeySlotKeyFile, recoveryID: storage.KeySlotRecovery}
+	for _, slot := range slots {
+		if slot.Type != wantTypes[slot.ID] {
+			t.Errorf("slot %d: type %q, want %q", slot.ID, slot.Type, wantTypes[slot.ID])
+		}
+		if slot.Salt != nil || slot.WrappedKey != nil {
+			t.Errorf("slot %d: ListKeySlots() exposed key material", slot.ID)
+		}
+	}
+
+	// Every slot unlocks the same credentials
+	unlocks := map[string]func() error{
+		"master password": func() error { return vault.Unlock([]byte("TestPassword123!")) },
+		"second password": func() error { return vault.Unlock([]byte("SecondPassword456!")) },
+		"key file":        func() error { return vault.UnlockWithKeyFile(keyFile) },
+		"recovery code":   func() error { return vault.Unlock([]byte(strings.ToLower(strings.ReplaceAll(code, "-", " ")))) },
+	}
+	for name, unlock := range unlocks {
+		vault.Lock()
+		if err := unlock(); err != nil {
+			t.Fatalf("unlock with %s failed: %v", name, err)
+		}
+		if _, err := vault.GetCredential("github", false); err != nil {
+			t.Errorf("unlock with %s: %v", name, err)
+		}
+	}
+
+	// A key file is not a password
+	vault.Lock()
+	other := filepath.Join(filepath.Dir(vaultPath), "other.key")
+	if err := CreateKeyFile(other); err != nil {
+		t.Fatalf("CreateKeyFile() failed: %v", err)
+	}
+	if err := vault.UnlockWithKeyFile(other); !errors.Is(err, storage.ErrNoMatchingSlot) {
+		t.Errorf("expected ErrNoMatchingSlot for an unknown key file, got %v", err)
+	}
+
+	// Changing the password rewrites only the password slot that unlocked the vault
+	if err := vault.Unlock([]byte("SecondPassword456!")); err != nil {
+		t.Fatalf("Unlock() failed: %v", err)
+	}
+	if err := vault.ChangePassword([]byte("ChangedPassword789!")); err != nil {
+		t.Fatalf("ChangePassword() failed: %v", err)
+	}
+	vault.Lock()
+	if err := vault.Unlock([]byte("SecondPassword456!")); err == nil {
+		t.Error("expected the replaced password to fail")
+	}
+	for _, password := range []string{"TestPassword123!", "ChangedPassword789!"} {
+		vault.Lock()
+		if err := vault.Unlock([]byte(password)); err != nil {
+			t.Errorf("Unlock(%q) after password change failed: %v", password, err)
+		}
+	}
+
+	// Removed slots stop unlocking the vault; other slots are kept by their secret
+	secrets := SlotSecrets{}
+	for id, input := range map[int]string{1: "TestPassword123!", recoveryID: code} {
+		secret, err := vault.KeySlotSecret(id, []byte(input))
+		if err != nil {
+			t.Fatalf("KeySlotSecret(%d) failed: %v", id, err)
+		}
+		secrets[id] = secret
+	}
+	if _, err := vault.KeySlotSecret(1, []byte("WrongPassword123!")); !errors.Is(err, storage.ErrNoMatchingSlot) {
+		t.Errorf("expected ErrNoMatchingSlot for a wrong secret, got %v", err)
+	}
+	if err := vault.RemoveKeySlot(keyFileID, secrets); err != nil {
+		t.Fatalf("RemoveKeySlot() failed: %v", err)
+	}
+	if err := vault.RemoveKeySlot(keyFileID, secrets); !errors.Is(err, storage.ErrKeySlotNotFound) {
+		t.Errorf("expected ErrKeySlotNotFound, got %v", err)
+	}
+	vault.Lock()
+	if err := vault.UnlockWithKeyFile(keyFile); err == nil {
+		t.Error("expected the removed key file to fail")
+	}
+	for _, password := range []string{"TestPassword123!", "ChangedPassword789!", code} {
+		vault.Lock()
+		if err := vault.Unlock([]byte(password)); err != nil {
+			t.Errorf("Unlock(%q) after removal failed: %v", password, err)
+		}
+	}
+}
+
+func TestRekey(t *testing.T) {
+	vault, vaultPath, cleanup := setupUnlockedTestVault(t)
+	defer cleanup()
+
+	keyFile := filepath.Join(filepath.Dir(vaultPath), "vault.key")
+	if err := CreateKeyFile(keyFile); err != nil {
+		t.Fatalf("CreateKeyFile() failed: %v", err)
+	}
+	keyFileID, err := vault.AddKeyFileSlot(keyFile, "", testSlotKDF)
+	if err != nil {
+		t.Fatalf("AddKeyFileSlot() failed: %v", err)
+	}
+	code, recoveryID, err := vault.AddRecoverySlot("", testSlotKDF)
+	if err != nil {
+		t.Fatalf("AddRecoverySlot() failed: %v", err)
+	}
+	if err := vault.AddCredential("github", "user", []byte("pass"), "", "", ""); err != nil {
+		t.Fatalf("AddCredential() failed: %v", err)
+	}
+	oldKey := append([]byte(nil), vault.dataKey...)
+	keyFileSecret, err := ReadKeyFile(keyFile)
+	if err != nil {
+		t.Fatalf("ReadKeyFile() failed: %v", err)
+	}
+
+	// The recovery code is kept; the key file slot, whose secret is not given, is dropped
+	recoverySecret, err := vault.KeySlotSecret(recoveryID, []byte(code))
+	if err != nil {
+		t.Fatalf("KeySlotSecret() failed: %v", err)
+	}
+	if err := vault.Rekey(SlotSecrets{recoveryID: recoverySecret}); err != nil {
+		t.Fatalf("Rekey() failed: %v", err)
+	}
+	if bytes.Equal(vault.dataKey, oldKey) {
+		t.Fatal("Rekey() kept the data key")
+	}
+	slots, err := vault.ListKeySlots()
+	if err != nil {
+		t.Fatalf("ListKeySlots() failed: %v", err)
+	}
+	if len(slots) != 2 || slots[0].ID != 1 || slots[1].ID != recoveryID {
+		t.Errorf("unexpected key slots after rekey: %+v", slots)
+	}
+
+	// Neither the old key nor the dropped slot opens any copy left on disk
+	if _, err := os.Stat(vaultPath + storage.BackupSuffix); !os.IsNotExist(err) {
+		t.Errorf("expected the backup to be removed, got %v", err)
+	}
+	snapshots, err := filepath.Glob(filepath.Join(vaultPath+storage.SnapshotDirSuffix, "*"))
+	if err != nil || len(snapshots) == 0 {
+		t.Fatalf("expected snapshots, got %v (%v)", snapshots, err)
+	}
+	for _, path := range append(snapshots, vaultPath) {
+		copyService, err := storage.NewStorageService(crypto.NewCryptoService(), path)
+		if err != nil {
+			t.Fatalf("NewStorageService() failed: %v", err)
+		}
+		if _, err := copyService.LoadVaultWithKey(oldKey); err == nil {
+			t.Errorf("%s opens with the old data key", filepath.Base(path))
+		}
+		if _, _, err := copyService.OpenDataKey(keyFileSecret, storage.KeySlotKeyFile); !errors.Is(err, storage.ErrNoMatchingSlot) {
+			t.Errorf("%s opens with the dropped key file slot: %v", filepath.Base(path), err)
+		}
+		if data, err := copyService.LoadVaultWithKey(vault.dataKey); err != nil {
+			t.Errorf("%s does not open with the new data key: %v", filepath.Base(path), err)
+		} else {
+			crypto.ClearBytes(data)
+		}
+	}
+
+	for _, unlock := range []func() error{
+		func() error { return vault.Unlock([]byte("TestPassword123!")) },
+		func() error { return vault.Unlock([]byte(code)) },
+	} {
+		vault.Lock()
+		if err := unlock(); err != nil {
+			t.Fatalf("unlock after rekey failed: %v", err)
+		}
+		if _, err := vault.GetCredential("github", false); err != nil {
+			t.Errorf("credential lost in rekey: %v", err)
+		}
+	}
+	vault.Lock()
+	if err := vault.UnlockWithKeyFile(keyFile); err == nil {
+		t.Errorf("expected dropped key file slot %d to fail", keyFileID)
+	}
+}
+
+func TestRemoveLastKeySlot(t *testing.T) {
+	vault, _, cleanup := setupUnlockedTestVault(t)
+	defer cleanup()
+
+	if err := vault.RemoveKeySlot(1, nil); !errors.Is(err, storage.ErrLastKeySlot) {
+		t.Errorf("expected ErrLastKeySlot, got %v", err)
+	}
+}
+
+func TestAddKeySlotRequiresUnlock(t *testing.T) {
+	vault, _, cleanup := setupTestVault(t)
+	defer cleanup()
+
+	if err := vault.Initialize([]byte("TestPassword123!"), false, "", ""); err != nil {
+		t.Fatalf("Initialize() failed: %v", err)
+	}
+	if _, err := vault.AddPasswordSlot([]byte("SecondPassword456!"), "", testSlotKDF); err != ErrVaultLocked {
+		t.Errorf("expected ErrVaultLocked, got %v", err)
+	}
+	if err := vault.RemoveKeySlot(1, nil); err != ErrVaultLocked {
+		t.Errorf("expected ErrVaultLocked, got %v", err)
+	}
+}
+
+func TestAddPasswordSlotPolicy(t *testing.T) {
+	vault, _, cleanup := setupUnlockedTestVault(t)
+	defer cleanup()
+
+	if _, err := vault.AddPasswordSlot([]byte("weak"), "", testSlotKDF); err == nil {
+		t.Error("expected a weak password to be rejected")
+	}
+}
+
+func TestAddKeySlotConvertsLegacyVault(t *testing.T) {
+	vault, storageService, cleanup := setupTestVaultWithStorage(t)
+	defer cleanup()
+
+	password := "TestPassword123!"
+	if err := vault.Initialize([]byte(password), false, "", ""); err != nil {
+		t.Fatalf("Initialize() failed: %v", err)
+	}
+	if err := vault.Unlock([]byte(password)); err != nil {
+		t.Fatalf("Unlock() failed: %v", err)
+	}
+	if err := vault.AddCredential("github", "user", []byte("pass"), "", "", ""); err != nil {
+		t.Fatalf("AddCredential() failed: %v", err)
+	}
+
+	// Rewrite as a version 1 vault, whose key is derived from the password
+	data, err := json.Marshal(vault.vaultData)
+	if err != nil {
+		t.Fatalf("Failed to marshal vault data: %v", err)
+	}
+	if err := storageService.SaveVaultWithIterationsUnsafe(data, password, 100000); err != nil {
+		t.Fatalf("Failed to save legacy vault: %v", err)
+	}
+	vault.Lock()
+	if err := vault.Unlock([]byte(password)); err != nil {
+		t.Fatalf("Unlock() of legacy vault failed: %v", err)
+	}
+	if vault.unlockedSlot != 0 {
+		t.Fatalf("expected no key slot for a legacy vault, got %d", vault.unlockedSlot)
+	}
+
+	keyFile := filepath.Join(t.TempDir(), "vault.key")
+	if err := os.WriteFile(keyFile, []byte("any file works"), 0600); err != nil {
+		t.Fatalf("Failed to write key file: %v", err)
+	}
+	if _, err := vault.AddKeyFileSlot(keyFile, "", testSlotKDF); err != nil {
+		t.Fatalf("AddKeyFileSlot() failed: %v", err)
+	}
+
+	// The vault now has key slots, with the master password upgraded to Argon2id
+	slots, err := vault.ListKeySlots()
+	if err != nil {
+		t.Fatalf("ListKeySlots() failed: %v", err)
+	}
+	if len(slots) != 2 || slots[0].Type != storage.KeySlotPassword || slots[0].KDF != crypto.KDFArgon2id {
+		t.Fatalf("unexpected key slots after conversion: %+v", slots)
+	}
+	for _, unlock := range []func() error{
+		func() error { return vault.Unlock([]byte(password)) },
+		func() error { return vault.UnlockWithKeyFile(keyFile) },
+	} {
+		vault.Lock()
+		if err := unlock(); err != nil {
+			t.Fatalf("unlock after conversion failed: %v", err)
+		}
+		if _, err := vault.GetCredential("github", false); err != nil {
+			t.Errorf("credential lost in conversion: %v", err)
+		}
+	}
+}
+
+func TestKeyFileRequirement(t *testing.T) {
+	vault, vaultPath, cleanup := setupTestVault(t)
+	defer cleanup()
+
+	dir := filepath.Dir(vaultPath)
+	keyFile := filepath.Join(dir, "vault.key")
+	other := filepath.Join(dir, "other.key")
+	for _, path := range []string{keyFile, other} {
+		if err := CreateKeyFile(path); err != nil {
+			t.Fatalf("CreateKeyFile() failed: %v", err)
+		}
+	}
+
+	password := "TestPassword123!"
+	vault.SetKeyFile(keyFile)
+	if err := vault.Initialize([]byte(password), false, "", ""); err != nil {
+		t.Fatalf("Initialize() failed: %v", err)
+	}
+	slots, err := vault.ListKeySlots()
+	if err != nil {
+		t.Fatalf("ListKeySlots() failed: %v", err)
+	}
+	if len(slots) != 1 || slots[0].Type != storage.KeySlotPasswordKeyFile {
+		t.Fatalf("expected a single password+keyfile slot, got %+v", slots)
+	}
+
+	// Both factors are needed
+	if err := vault.Unlock([]byte(password)); err != nil {
+		t.Fatalf("Unlock() with both factors failed: %v", err)
+	}
+	if err := vault.AddCredential("github", "user", []byte("pass"), "", "", ""); err != nil {
+		t.Fatalf("AddCredential() failed: %v", err)
+	}
+	vault.Lock()
+	if err := vault.UnlockWithKeyFile(keyFile); err == nil {
+		t.Error("expected the key file alone to fail")
+	}
+	vault.SetKeyFile(other)
+	if err := vault.Unlock([]byte(password)); !errors.Is(err, storage.ErrNoMatchingSlot) {
+		t.Errorf("expected ErrNoMatchingSlot with the wrong key file, got %v", err)
+	}
+	vault.SetKeyFile("")
+	if err := vault.Unlock([]byte(password)); !errors.Is(err, ErrKeyFileRequired) {
+		t.Errorf("expected ErrKeyFileRequired without a key file, got %v", err)
+	}
+
+	// A password change keeps the requirement unless asked otherwise
+	vault.SetKeyFile(keyFile)
+	if err := vault.Unlock([]byte(password)); err != nil {
+		t.Fatalf("Unlock() failed: %v", err)
+	}
+	if err := vault.ChangePassword([]byte("ChangedPassword789!")); err != nil {
+		t.Fatalf("ChangePassword() failed: %v", err)
+	}
+	vault.Lock()
+	vault.SetKeyFile("")
+	if err := vault.Unlock([]byte("ChangedPassword789!")); !errors.Is(err, ErrKeyFileRequired) {
+		t.Errorf("expected the key file to stay required, got %v", err)
+	}
+
+	// Removing the requirement leaves the password alone
+	vault.SetKeyFile(keyFile)
+	if err := vault.Unlock([]byte("ChangedPassword789!")); err != nil {
+		t.Fatalf("Unlock() failed: %v", err)
+	}
+	if err := vault.ChangePasswordWithOpts([]byte("ChangedPassword789!"), ChangePasswordOpts{RemoveKeyFile: true}); err != nil {
+		t.Fatalf("ChangePasswordWithOpts(RemoveKeyFile) failed: %v", err)
+	}
+	vault.Lock()
+	vault.SetKeyFile("")
+	if err := vault.Unlock([]byte("ChangedPassword789!")); err != nil {
+		t.Fatalf("Unlock() without key file after removal failed: %v", err)
+	}
+	if err := vault.AddCredential("gitlab", "user", []byte("pass"), "", "", ""); err != nil {
+		t.Fatalf("AddCredential() failed: %v", err)
+	}
+
+	// ...and adding it back binds the vault to the new file
+	if err := vault.ChangePasswordWithOpts([]byte("ChangedPassword789!"), ChangePasswordOpts{KeyFile: other}); err != nil {
+		t.Fatalf("ChangePasswordWithOpts(KeyFile) failed: %v", err)
+	}
+	// No snapshot from the password-only period opens without the key file
+	snapshots, err := filepath.Glob(filepath.Join(vaultPath+storage.SnapshotDirSuffix, "*"))
+	if err != nil || len(snapshots) == 0 {
+		t.Fatalf("expected snapshots, got %v (%v)", snapshots, err)
+	}
+	for _, path := range append(snapshots, vaultPath+storage.BackupSuffix) {
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			continue
+		}
+		copyService, err := storage.NewStorageService(crypto.NewCryptoService(), path)
+		if err != nil {
+			t.Fatalf("NewStorageService() failed: %v", err)
+		}
+		if _, _, err := copyService.OpenDataKey([]byte("ChangedPassword789!"), storage.KeySlotPassword); err == nil {
+			t.Errorf("%s opens with the password alone", filepath.Base(path))
+		}
+	}
+	vault.Lock()
+	vault.SetKeyFile(keyFile)
+	if err := vault.Unlock([]byte("ChangedPassword789!")); err == nil {
+		t.Error("expected the previous key file to fail")
+	}
+	vault.SetKeyFile(other)
+	if err := vault.Unlock([]byte("ChangedPassword789!")); err != nil {
+		t.Fatalf("Unlock() with the new key file failed: %v", err)
+	}
+	if _, err := vault.GetCredential("github", false); err != nil {
+		t.Errorf("credential lost across key file changes: %v", err)
+	}
+
+	if err := vault.ChangePasswordWithOpts([]byte("ChangedPassword789!"), ChangePasswordOpts{KeyFile: other, RemoveKeyFile: true}); err == nil {
+		t.Error("expected adding and removing a key file at once to fail")
+	}
+}
+
+func TestReadKeyFileEmpty(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "empty.key")
+	if err := os.WriteFile(path, nil, 0600); err != nil {
+		t.Fatalf("Failed to write key file: %v", err)
+	}
+	if _, err := ReadKeyFile(path); !errors.Is(err, ErrEmptyKeyFile) {
+		t.Errorf("expected ErrEmptyKeyFile, got %v", err)
+	}
+}
+
+func TestGenerateRecoveryCode(t *testing.T) {
+	code, err := GenerateRecoveryCode()
+	if err != nil {
+		t.Fatalf("GenerateRecoveryCode() failed: %v", err)
+	}
+	groups := strings.Split(code, "-")
+	if len(groups) != 8 {
+		t.Fatalf("expected 8 groups, got %q", code)
+	}
+	for _, group := range groups {
+		if len(group) != 4 || strings.ToUpper(group) != group {
+			t.Errorf("unexpected group %q in %q", group, code)
+		}
+	}
+
+	other, err := GenerateRecoveryCode()
+	if err != nil {
+		t.Fatalf("GenerateRecoveryCode() failed: %v", err)
+	}
+	if other == code {
+		t.Error("recovery codes should be random")
+	}
+	if got := string(normalizeRecoveryCode([]byte(strings.ToLower(code)))); got != strings.ReplaceAll(code, "-", "") {
+		t.Errorf("normalizeRecoveryCode() = %q", got)
+	}
+}
@@ -0,0 +1,94 @@
+package vault
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// PathSeparator separates folders in hierarchical service names such as "work/aws/prod/deploy-bot"
+const PathSeparator = "/"
+
+// SplitServicePath splits a service name into its path segments, ignoring empty segments
+func SplitServicePath(service string) []string {
+	parts := strings.Split(service, PathSeparator)
+	segments := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if part != "" {
+			segments = append(segments, part)
+		}
+	}
+	return segments
+}
+
+// NormalizeServicePath trims surrounding separators from a folder path ("work/aws/" -> "work/aws")
+func NormalizeServicePath(path string) string {
+	return strings.Trim(strings.TrimSpace(path), PathSeparator)
+}
+
+// ServiceInPath reports whether a service is the given path or lies below it
+// The empty path matches every service
+func ServiceInPath(service, path string) bool {
+	path = NormalizeServicePath(path)
+	if path == "" {
+		return true
+	}
+	return service == path || strings.HasPrefix(service, path+PathSeparator)
+}
+
+// ServiceFolder returns the folder part of a service name ("work/aws/bot" -> "work/aws", "github" -> "")
+func ServiceFolder(service string) string {
+	index := strings.LastIndex(service, PathSeparator)
+	if index < 0 {
+		return ""
+	}
+	return service[:index]
+}
+
+// ServiceBaseName returns the last path segment of a service name ("work/aws/bot" -> "bot")
+func ServiceBaseName(service string) string {
+	index := strings.LastIndex(service, PathSeparator)
+	if index < 0 {
+		return service
+	}
+	return service[index+1:]
+}
+
+// validateServiceName rejects empty service names and paths with empty
+// segments ("/x", "work/", "a//b", "a/ /b"), which would show up as unnamed
+// folders in the tree listing and the sidebar
+func validateServiceName(service string) error {
+	if strings.TrimSpace(service) == "" {
+		return fmt.Errorf("%w: service name cannot be empty", ErrInvalidCredential)
+	}
+	for _, segment := range strings.Split(service, PathSeparator) {
+		if strings.TrimSpace(segment) == "" {
+			return fmt.Errorf("%w: service name %q has an empty path segment (leading, trailing or repeated %q)", ErrInvalidCredential, service, PathSeparator)
+		}
+	}
+	return nil
+}
+
+// CompleteServicePath returns completions for a partially typed service path
+// Candidates are completed up to the next separator, so folders are offered as
+// "work/aws/" and credentials by their full name. Results are sorted and unique.
+func CompleteServicePath(services []string, prefix string) []string {
+	seen := make(map[string]bool)
+	completions := make([]string, 0)
+	for _, service := range services {
+		if !strings.HasPrefix(service, prefix) {
+			continue
+		}
+
+		candidate := service
+		if index := strings.Index(service[len(prefix):], PathSeparator); index >= 0 {
+			candidate = service[:len(prefix)+index+1]
+		}
+		if !seen[candidate] {
+			seen[candidate] = true
+			completions = append(completions, candidate)
+		}
+	}
+	sort.Strings(completions)
+	return completions
+}
@@ -0,0 +1,129 @@
+package vault
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestServiceInPath(t *testing.T) {
+	tests := []struct {
+		service string
+		path    string
+		want    bool
+	}{
+		{"work/aws/prod/deploy-bot", "work/aws", true},
+		{"work/aws/prod/deploy-bot", "work/aws/", true},
+		{"work/aws", "work/aws", true},
+		{"work/aws-legacy", "work/aws", false},
+		{"personal/github", "work", false},
+		{"github", "", true},
+	}
+
+	for _, tt := range tests {
+		if got := ServiceInPath(tt.service, tt.path); got != tt.want {
+			t.Errorf("ServiceInPath(%q, %q) = %v, want %v", tt.service, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestServicePathParts(t *testing.T) {
+	if got := SplitServicePath("/work//aws/bot"); !reflect.DeepEqual(got, []string{"work", "aws", "bot"}) {
+		t.Errorf("SplitServicePath() = %v", got)
+	}
+	if got := ServiceFolder("work/aws/bot"); got != "work/aws" {
+		t.Errorf("ServiceFolder() = %q", got)
+	}
+	if got := ServiceBaseName("work/aws/bot"); got != "bot" {
+		t.Errorf("ServiceBaseName() = %q", got)
+	}
+	if got := ServiceFolder("github"); got != "" {
+		t.Errorf("ServiceFolder(github) = %q", got)
+	}
+}
+
+func TestCompleteServicePath(t *testing.T) {
+	services := []string{"github", "work/aws/prod/deploy-bot", "work/aws/staging", "work/gitlab"}
+
+	tests := []struct {
+		prefix string
+		want   []string
+	}{
+		{"", []string{"github", "work/"}},
+		{"w", []string{"work/"}},
+		{"work/", []string{"work/aws/", "work/gitlab"}},
+		{"work/aws/", []string{"work/aws/prod/", "work/aws/staging"}},
+		{"work/aws/prod/", []string{"work/aws/prod/deploy-bot"}},
+		{"nope", []string{}},
+	}
+
+	for _, tt := range tests {
+		if got := CompleteServicePath(services, tt.prefix); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("CompleteServicePath(%q) = %v, want %v", tt.prefix, got, tt.want)
+		}
+	}
+}
+
+func TestServiceNameValidation(t *testing.T) {
+	tests := []struct {
+		service string
+		valid   bool
+	}{
+		{"github", true},
+		{"work/aws/prod/deploy-bot", true},
+		{"my service/api key", true},
+		{"", false},
+		{"   ", false},
+		{"work/", false},
+		{"/x", false},
+		{"a//b", false},
+		{"a/ /b", false},
+		{"/", false},
+	}
+
+	vault, _, cleanup := setupUnlockedTestVault(t)
+	defer cleanup()
+	if err := vault.AddCredential("source", "user", []byte("pass"), "", "", ""); err != nil {
+		t.Fatalf("AddCredential() failed: %v", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.service, func(t *testing.T) {
+			check := func(op string, err error) {
+				t.Helper()
+				if tt.valid && err != nil {
+					t.Errorf("%s(%q) failed: %v", op, tt.service, err)
+				}
+				if !tt.valid && !errors.Is(err, ErrInvalidCredential) {
+					t.Errorf("%s(%q) = %v, want ErrInvalidCredential", op, tt.service, err)
+				}
+			}
+
+			check("AddCredential", vault.AddCredential(tt.service, "user", []byte("pass"), "", "", ""))
+			if tt.valid {
+				if err := vault.DeleteCredential(tt.service); err != nil {
+					t.Fatalf("DeleteCredential() failed: %v", err)
+				}
+			}
+			check("CopyCredential", vault.CopyCredential("source", tt.service))
+			if tt.valid {
+				if err := vault.DeleteCredential(tt.service); err != nil {
+					t.Fatalf("DeleteCredential() failed: %v", err)
+				}
+			}
+
+			report, err := vault.ImportCredentials([]ImportEntry{{Service: tt.service, Password: []byte("pass")}}, ImportOptions{DryRun: true})
+			if err != nil {
+				t.Fatalf("ImportCredentials() failed: %v", err)
+			}
+			if failed := report.Count(ImportFailed) == 1; failed == tt.valid {
+				t.Errorf("import of %q: %+v", tt.service, report.Results)
+			}
+		})
+	}
+
+	// Renaming checks the same rules
+	if err := vault.RenameCredential("source", "work/"); !errors.Is(err, ErrInvalidCredential) {
+		t.Errorf("RenameCredential(work/) = %v, want ErrInvalidCredential", err)
+	}
+}
@@ -0,0 +1,100 @@
+package vault
+
+import (
+	"crypto/rand"
+	"fmt"
+
+	"pass-cli/internal/crypto"
+	"pass-cli/internal/security"
+	"pass-cli/internal/shamir"
+	"pass-cli/internal/storage"
+)
+
+// SetupRecoveryShares generates a recovery key, stores it in a key slot and
+// splits it into shares, any threshold of which unlock the vault. The key
+// itself is never stored. Slots of an earlier setup are removed and the vault
+// is rekeyed (see Rekey), so the earlier shares open neither the vault nor its
+// snapshots; other slots are then kept if the vault holds their secret or it
+// is in secrets.
+func (v *VaultService) SetupRecoveryShares(shares, threshold int, params crypto.KDFParams, secrets SlotSecrets) ([]shamir.Share, int, error) {
+	if !v.unlocked {
+		return nil, 0, ErrVaultLocked
+	}
+
+	key := make([]byte, recoveryCodeBytes)
+	if _, err := rand.Read(key); err != nil {
+		return nil, 0, fmt.Errorf("failed to generate recovery key: %w", err)
+	}
+	defer crypto.ClearBytes(key)
+
+	split, err := shamir.Split(key, shares, threshold)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	previous, err := v.storageService.ListKeySlots()
+	if err != nil {
+		return nil, 0, err
+	}
+	label := fmt.Sprintf("%d of %d shares", threshold, shares)
+	id, err := v.addKeySlot(storage.KeySlotShares, label, key, params)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Only one set of shares is valid at a time
+	kept := v.keptSlots(secrets)
+	replaced := false
+	for _, slot := range previous {
+		if slot.Type == storage.KeySlotShares {
+			delete(kept, slot.ID)
+			replaced = true
+		}
+	}
+	if !replaced {
+		return split, id, nil
+	}
+	kept[id] = key
+	if err := v.rekey(kept); err != nil {
+		// The new shares are never shown, so their slot must not stay behind
+		_ = v.storageService.RemoveKeySlot(v.dataKey, id)
+		return nil, 0, fmt.Errorf("failed to remove previous recovery shares: %w", err)
+	}
+	if v.unlockedSlot == 0 {
+		// Unlocked with the replaced shares: hold the new ones instead
+		v.unlockedSlot = id
+		v.slotSecret = append([]byte(nil), key...)
+	}
+	return split, id, nil
+}
+
+// UnlockWithRecoveryShares opens the vault with enough recovery shares from
+// SetupRecoveryShares. Set a new master password afterwards with ChangePassword.
+func (v *VaultService) UnlockWithRecoveryShares(shares []shamir.Share) error {
+	if v.unlocked {
+		return nil // Already unlocked
+	}
+
+	key, err := shamir.Combine(shares)
+	if err != nil {
+		return err
+	}
+	defer crypto.ClearBytes(key)
+
+	if err := v.recoverIncompleteMigration(); err != nil {
+		return err
+	}
+
+	dataKey, slotID, err := v.storageService.OpenDataKey(key, storage.KeySlotShares)
+	if err == nil && slotID == 0 {
+		// Version 1 vaults only accept the master password
+		crypto.ClearBytes(dataKey)
+		err = storage.ErrNotEnvelopeVault
+	}
+	if err != nil {
+		v.logAudit(security.EventVaultUnlock, security.OutcomeFailure, "")
+		return fmt.Errorf("failed to unlock vault with recovery shares: %w", err)
+	}
+
+	return v.unlockWithDataKey(dataKey, slotID, key)
+}
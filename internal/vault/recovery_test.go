@@ -0,0 +1,100 @@
+package vault
+
+import (
+	"errors"
+	"testing"
+
+	"pass-cli/internal/shamir"
+	"pass-cli/internal/storage"
+)
+
+func TestRecoveryShares(t *testing.T) {
+	vault, _, cleanup := setupUnlockedTestVault(t)
+	defer cleanup()
+
+	if err := vault.AddCredential("github", "user", []byte("pass"), "", "", ""); err != nil {
+		t.Fatalf("AddCredential() failed: %v", err)
+	}
+
+	shares, id, err := vault.SetupRecoveryShares(5, 3, testSlotKDF, nil)
+	if err != nil {
+		t.Fatalf("SetupRecoveryShares() failed: %v", err)
+	}
+	if len(shares) != 5 {
+		t.Fatalf("expected 5 shares, got %d", len(shares))
+	}
+	slot, err := vault.keySlot(id)
+	if err != nil {
+		t.Fatalf("keySlot() failed: %v", err)
+	}
+	if slot.Type != storage.KeySlotShares || slot.Label != "3 of 5 shares" {
+		t.Errorf("unexpected slot: type %q, label %q", slot.Type, slot.Label)
+	}
+
+	// Shares survive being written down and typed back in
+	var parsed []shamir.Share
+	for _, i := range []int{4, 1, 2} {
+		share, err := shamir.ParseMnemonic(shares[i].Mnemonic())
+		if err != nil {
+			t.Fatalf("ParseMnemonic() failed: %v", err)
+		}
+		parsed = append(parsed, share)
+	}
+
+	vault.Lock()
+	if err := vault.UnlockWithRecoveryShares(parsed[:2]); !errors.Is(err, shamir.ErrNotEnoughShares) {
+		t.Errorf("expected ErrNotEnoughShares, got %v", err)
+	}
+	if err := vault.UnlockWithRecoveryShares(parsed); err != nil {
+		t.Fatalf("UnlockWithRecoveryShares() failed: %v", err)
+	}
+	if _, err := vault.GetCredential("github", false); err != nil {
+		t.Errorf("GetCredential() after recovery failed: %v", err)
+	}
+
+	// A forgotten password is replaced, not added to
+	if err := vault.ChangePassword([]byte("RecoveredPassword789!")); err != nil {
+		t.Fatalf("ChangePassword() after recovery failed: %v", err)
+	}
+	vault.Lock()
+	if err := vault.Unlock([]byte("TestPassword123!")); err == nil {
+		t.Error("expected the forgotten password to stop working")
+	}
+	if err := vault.Unlock([]byte("RecoveredPassword789!")); err != nil {
+		t.Fatalf("Unlock() with the new password failed: %v", err)
+	}
+
+	// A new setup invalidates the old shares
+	if _, _, err := vault.SetupRecoveryShares(3, 2, testSlotKDF, nil); err != nil {
+		t.Fatalf("SetupRecoveryShares() failed: %v", err)
+	}
+	slots, err := vault.ListKeySlots()
+	if err != nil {
+		t.Fatalf("ListKeySlots() failed: %v", err)
+	}
+	count := 0
+	for _, slot := range slots {
+		if slot.Type == storage.KeySlotShares {
+			count++
+		}
+	}
+	if count != 1 {
+		t.Errorf("expected a single shares slot, got %d", count)
+	}
+	vault.Lock()
+	if err := vault.UnlockWithRecoveryShares(parsed); !errors.Is(err, storage.ErrNoMatchingSlot) {
+		t.Errorf("expected old shares to fail with ErrNoMatchingSlot, got %v", err)
+	}
+}
+
+func TestSetupRecoverySharesRequiresUnlock(t *testing.T) {
+	vault, _, cleanup := setupTestVault(t)
+	defer cleanup()
+
+	if err := vault.Initialize([]byte("TestPassword123!"), false, "", ""); err != nil {
+		t.Fatalf("Initialize() failed: %v", err)
+	}
+	if _, _, err := vault.SetupRecoveryShares(5, 3, testSlotKDF, nil); err != ErrVaultLocked {
+		t.Errorf("expected ErrVaultLocked, got %v", err)
+	}
+}
@@ -0,0 +1,98 @@
+package vault
+
+import (
+	"fmt"
+	"time"
+
+	"pass-cli/internal/security"
+)
+
+// RenameCredential changes a credential's service name
+// Usage records, timestamps, history, and attachments are kept as they are
+func (v *VaultService) RenameCredential(oldService, newService string) error {
+	if !v.unlocked {
+		return ErrVaultLocked
+	}
+
+	credential, err := v.checkRenameTarget(oldService, newService)
+	if err != nil {
+		return err
+	}
+
+	credential.Service = newService
+	credential.UpdatedAt = time.Now()
+	credential.ModifiedCount++
+
+	previous := v.vaultData.Credentials[oldService]
+	delete(v.vaultData.Credentials, oldService)
+	v.vaultData.Credentials[newService] = credential
+
+	if err := v.save(); err != nil {
+		// Keep the in-memory vault consistent with disk
+		delete(v.vaultData.Credentials, newService)
+		v.vaultData.Credentials[oldService] = previous
+		return err
+	}
+
+	v.logAudit(security.EventCredentialRename, security.OutcomeSuccess, oldService+" -> "+newService)
+	return nil
+}
+
+// CopyCredential duplicates a credential under a new service name
+// The copy gets fresh timestamps and no usage records; all stored values are deep-copied
+func (v *VaultService) CopyCredential(srcService, dstService string) error {
+	if !v.unlocked {
+		return ErrVaultLocked
+	}
+
+	source, err := v.checkRenameTarget(srcService, dstService)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	password := make([]byte, len(source.Password))
+	copy(password, source.Password)
+
+	credential := source
+	credential.Service = dstService
+	credential.Password = password
+	credential.CustomFields = copyCustomFields(source.CustomFields)
+	credential.TOTP = copyTOTP(source.TOTP)
+	credential.Tags = copyTags(source.Tags)
+	credential.ExpiresAt = copyTime(source.ExpiresAt)
+	credential.PasswordHistory = copyPasswordHistory(source.PasswordHistory)
+	credential.Attachments = copyAttachments(source.Attachments)
+	credential.CreatedAt = now
+	credential.UpdatedAt = now
+	credential.ModifiedCount = 0
+	credential.UsageRecord = make(map[string]UsageRecord)
+
+	v.vaultData.Credentials[dstService] = credential
+
+	if err := v.save(); err != nil {
+		delete(v.vaultData.Credentials, dstService)
+		return err
+	}
+
+	v.logAudit(security.EventCredentialCopy, security.OutcomeSuccess, srcService+" -> "+dstService)
+	return nil
+}
+
+// checkRenameTarget validates a rename or copy and returns the source credential
+func (v *VaultService) checkRenameTarget(source, target string) (Credential, error) {
+	credential, exists := v.vaultData.Credentials[source]
+	if !exists {
+		return Credential{}, fmt.Errorf("%w: %s", ErrCredentialNotFound, source)
+	}
+	if err := validateServiceName(target); err != nil {
+		return Credential{}, err
+	}
+	if target == source {
+		return Credential{}, fmt.Errorf("%w: %s is already named %s", ErrInvalidCredential, source, target)
+	}
+	if _, exists := v.vaultData.Credentials[target]; exists {
+		return Credential{}, fmt.Errorf("%w: %s", ErrCredentialExists, target)
+	}
+	return credential, nil
+}
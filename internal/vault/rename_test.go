@@ -0,0 +1,114 @@
+package vault
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRenameCredential(t *testing.T) {
+	vault, vaultPath, cleanup := setupUnlockedTestVault(t)
+	defer cleanup()
+
+	if err := vault.AddCredential("github", "user", []byte("pass"), "Code", "", ""); err != nil {
+		t.Fatalf("AddCredential() failed: %v", err)
+	}
+	if err := vault.RecordFieldAccess("github", "password"); err != nil {
+		t.Fatalf("RecordFieldAccess() failed: %v", err)
+	}
+	before, _ := vault.GetCredential("github", false)
+
+	if err := vault.RenameCredential("github", "work/github"); err != nil {
+		t.Fatalf("RenameCredential() failed: %v", err)
+	}
+
+	if _, err := vault.GetCredential("github", false); !errors.Is(err, ErrCredentialNotFound) {
+		t.Errorf("expected old name to be gone, got %v", err)
+	}
+
+	reopened, err := New(vaultPath)
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	if err := reopened.Unlock([]byte("TestPassword123!")); err != nil {
+		t.Fatalf("Unlock() failed: %v", err)
+	}
+	after, err := reopened.GetCredential("work/github", false)
+	if err != nil {
+		t.Fatalf("GetCredential() failed: %v", err)
+	}
+	if after.Service != "work/github" {
+		t.Errorf("Service = %q, want %q", after.Service, "work/github")
+	}
+	if !after.CreatedAt.Equal(before.CreatedAt) {
+		t.Errorf("CreatedAt changed: %v -> %v", before.CreatedAt, after.CreatedAt)
+	}
+	if len(after.UsageRecord) != 1 {
+		t.Errorf("expected usage records to be kept, got %d", len(after.UsageRecord))
+	}
+	if after.ModifiedCount != before.ModifiedCount+1 {
+		t.Errorf("ModifiedCount = %d, want %d", after.ModifiedCount, before.ModifiedCount+1)
+	}
+
+	// Invalid targets
+	if err := vault.AddCredential("gitlab", "user", []byte("pass"), "", "", ""); err != nil {
+		t.Fatalf("AddCredential() failed: %v", err)
+	}
+	if err := vault.RenameCredential("gitlab", "work/github"); !errors.Is(err, ErrCredentialExists) {
+		t.Errorf("expected ErrCredentialExists, got %v", err)
+	}
+	if err := vault.RenameCredential("gitlab", " "); !errors.Is(err, ErrInvalidCredential) {
+		t.Errorf("expected ErrInvalidCredential for empty name, got %v", err)
+	}
+	if err := vault.RenameCredential("missing", "other"); !errors.Is(err, ErrCredentialNotFound) {
+		t.Errorf("expected ErrCredentialNotFound, got %v", err)
+	}
+}
+
+func TestCopyCredential(t *testing.T) {
+	vault, _, cleanup := setupUnlockedTestVault(t)
+	defer cleanup()
+
+	opts := AddOpts{
+		CustomFields: []CustomField{{Key: "region", Value: "eu-west-1"}},
+		Tags:         []string{"prod"},
+	}
+	if err := vault.AddCredentialWithOpts("aws-prod", "admin", []byte("pass"), "Cloud", "", "", opts); err != nil {
+		t.Fatalf("AddCredentialWithOpts() failed: %v", err)
+	}
+	if err := vault.RecordFieldAccess("aws-prod", "password"); err != nil {
+		t.Fatalf("RecordFieldAccess() failed: %v", err)
+	}
+
+	if err := vault.CopyCredential("aws-prod", "aws-staging"); err != nil {
+		t.Fatalf("CopyCredential() failed: %v", err)
+	}
+
+	copied, err := vault.GetCredential("aws-staging", false)
+	if err != nil {
+		t.Fatalf("GetCredential() failed: %v", err)
+	}
+	if copied.Username != "admin" || string(copied.Password) != "pass" || copied.Category != "Cloud" {
+		t.Errorf("copy did not keep values: %+v", copied)
+	}
+	if len(copied.CustomFields) != 1 || len(copied.Tags) != 1 {
+		t.Errorf("copy did not keep custom fields and tags: %+v", copied)
+	}
+	if len(copied.UsageRecord) != 0 || copied.ModifiedCount != 0 {
+		t.Errorf("copy should start without usage, got %d records, %d modifications",
+			len(copied.UsageRecord), copied.ModifiedCount)
+	}
+
+	// Updating the copy leaves the source alone
+	region := []CustomField{{Key: "region", Value: "us-east-1"}}
+	if err := vault.UpdateCredential("aws-staging", UpdateOpts{CustomFields: &region}); err != nil {
+		t.Fatalf("UpdateCredential() failed: %v", err)
+	}
+	source, _ := vault.GetCredential("aws-prod", false)
+	if source.CustomFields[0].Value != "eu-west-1" {
+		t.Errorf("source custom field changed to %q", source.CustomFields[0].Value)
+	}
+
+	if err := vault.CopyCredential("aws-prod", "aws-staging"); !errors.Is(err, ErrCredentialExists) {
+		t.Errorf("expected ErrCredentialExists, got %v", err)
+	}
+}
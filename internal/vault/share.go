@@ -0,0 +1,179 @@
+package vault
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"filippo.io/age"
+
+	"pass-cli/internal/security"
+)
+
+// ShareDirection tells whether credentials were shared by or with the vault owner
+type ShareDirection string
+
+const (
+	ShareSent     ShareDirection = "sent"
+	ShareReceived ShareDirection = "received"
+)
+
+// ShareRecord records one share bundle sent to or received from other users
+type ShareRecord struct {
+	Direction  ShareDirection `json:"direction"`
+	Peers      []string       `json:"peers"`                 // Recipients' public keys (sent) or the verified sender's (received)
+	Services   []string       `json:"services"`              // Service names as they were shared
+	SharedAt   time.Time      `json:"shared_at"`             // When the bundle was created
+	ReceivedAt *time.Time     `json:"received_at,omitempty"` // When the bundle was merged (received only)
+}
+
+// ShareIdentity returns the vault owner's X25519 identity, generating it on
+// first use. Others share credentials to its public key (Recipient).
+func (v *VaultService) ShareIdentity() (*age.X25519Identity, error) {
+	if !v.unlocked {
+		return nil, ErrVaultLocked
+	}
+
+	if v.vaultData.ShareIdentity != "" {
+		identity, err := age.ParseX25519Identity(v.vaultData.ShareIdentity)
+		if err != nil {
+			return nil, fmt.Errorf("invalid share identity: %w", err)
+		}
+		return identity, nil
+	}
+
+	identity, err := age.GenerateX25519Identity()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate share identity: %w", err)
+	}
+	v.vaultData.ShareIdentity = identity.String()
+	if err := v.save(); err != nil {
+		v.vaultData.ShareIdentity = ""
+		return nil, err
+	}
+	return identity, nil
+}
+
+// ShareCredentials hands copies of the named credentials and the vault owner's
+// identity to write, which encrypts them to recipients (public keys). The share
+// is recorded in the vault and, per credential, in the audit log. Passwords in
+// the copies are cleared once write returns.
+func (v *VaultService) ShareCredentials(services, recipients []string, write func(identity *age.X25519Identity, credentials []Credential) error) error {
+	if !v.unlocked {
+		return ErrVaultLocked
+	}
+	if len(services) == 0 {
+		return errors.New("no credentials to share")
+	}
+
+	var credentials []Credential
+	defer func() { clearExportedCredentials(credentials) }()
+	var shared []string
+	for _, service := range services {
+		credential, exists := v.vaultData.Credentials[service]
+		if !exists {
+			return fmt.Errorf("%w: %s", ErrCredentialNotFound, service)
+		}
+		if containsString(shared, service) {
+			continue
+		}
+		credentials = append(credentials, cloneCredential(credential))
+		shared = append(shared, service)
+	}
+
+	identity, err := v.ShareIdentity()
+	if err != nil {
+		return err
+	}
+
+	if err := write(identity, credentials); err != nil {
+		for _, service := range shared {
+			v.logAudit(security.EventShareSend, security.OutcomeFailure, service)
+		}
+		return err
+	}
+
+	v.vaultData.Shares = append(v.vaultData.Shares, ShareRecord{
+		Direction: ShareSent,
+		Peers:     append([]string(nil), recipients...),
+		Services:  shared,
+		SharedAt:  time.Now(),
+	})
+	if err := v.save(); err != nil {
+		v.vaultData.Shares = v.vaultData.Shares[:len(v.vaultData.Shares)-1]
+		return fmt.Errorf("failed to record share: %w", err)
+	}
+
+	for _, service := range shared {
+		v.logAudit(security.EventShareSend, security.OutcomeSuccess, service)
+	}
+	return nil
+}
+
+// ReceiveShare merges credentials from another user's share bundle like
+// ImportCredentials, and records who shared them (from, a public key), when, and
+// the names they were merged under in the same save. Nothing is recorded when no
+// credential was merged.
+func (v *VaultService) ReceiveShare(from string, sharedAt time.Time, entries []ImportEntry, opts ImportOptions) (*ImportReport, error) {
+	if !v.unlocked {
+		return nil, ErrVaultLocked
+	}
+
+	report, err := v.importCredentials(entries, opts, func(report *ImportReport) func() {
+		var services []string
+		for _, result := range report.Results {
+			switch result.Action {
+			case ImportAdded, ImportRenamed, ImportOverwritten:
+				services = append(services, result.Service)
+			}
+		}
+		now := time.Now()
+
+		previous := v.vaultData.Shares
+		v.vaultData.Shares = append(append([]ShareRecord(nil), previous...), ShareRecord{
+			Direction:  ShareReceived,
+			Peers:      []string{from},
+			Services:   services,
+			SharedAt:   sharedAt,
+			ReceivedAt: &now,
+		})
+		return func() { v.vaultData.Shares = previous }
+	})
+	if err != nil || opts.DryRun {
+		return report, err
+	}
+
+	for _, result := range report.Results {
+		switch result.Action {
+		case ImportAdded, ImportRenamed, ImportOverwritten:
+			v.logAudit(security.EventShareReceive, security.OutcomeSuccess, result.Service)
+		}
+	}
+	return report, nil
+}
+
+// ListShares returns the share bundles sent and received, oldest first
+func (v *VaultService) ListShares() ([]ShareRecord, error) {
+	if !v.unlocked {
+		return nil, ErrVaultLocked
+	}
+
+	records := make([]ShareRecord, len(v.vaultData.Shares))
+	for i, record := range v.vaultData.Shares {
+		record.Peers = append([]string(nil), record.Peers...)
+		record.Services = append([]string(nil), record.Services...)
+		record.ReceivedAt = copyTime(record.ReceivedAt)
+		records[i] = record
+	}
+	return records, nil
+}
+
+// containsString reports whether values contains s
+func containsString(values []string, s string) bool {
+	for _, value := range values {
+		if value == s {
+			return true
+		}
+	}
+	return false
+}
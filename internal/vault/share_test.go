@@ -0,0 +1,171 @@
+package vault
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"filippo.io/age"
+)
+
+func TestShareIdentityPersists(t *testing.T) {
+	vault, vaultPath, cleanup := setupUnlockedTestVault(t)
+	defer cleanup()
+
+	identity, err := vault.ShareIdentity()
+	if err != nil {
+		t.Fatalf("ShareIdentity() failed: %v", err)
+	}
+
+	reopened, err := New(vaultPath)
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	if err := reopened.Unlock([]byte("TestPassword123!")); err != nil {
+		t.Fatalf("Unlock() failed: %v", err)
+	}
+	again, err := reopened.ShareIdentity()
+	if err != nil {
+		t.Fatalf("ShareIdentity() failed: %v", err)
+	}
+	if again.String() != identity.String() {
+		t.Error("expected the same identity after reopening the vault")
+	}
+
+	reopened.Lock()
+	if _, err := reopened.ShareIdentity(); err != ErrVaultLocked {
+		t.Errorf("expected ErrVaultLocked, got %v", err)
+	}
+}
+
+func TestShareCredentials(t *testing.T) {
+	vault, _, cleanup := setupUnlockedTestVault(t)
+	defer cleanup()
+
+	for _, service := range []string{"github", "aws"} {
+		if err := vault.AddCredential(service, "user", []byte("pass-"+service), "", "", ""); err != nil {
+			t.Fatalf("AddCredential() failed: %v", err)
+		}
+	}
+
+	err := vault.ShareCredentials([]string{"github", "missing"}, []string{"age1peer"}, func(*age.X25519Identity, []Credential) error {
+		t.Error("write called despite a missing credential")
+		return nil
+	})
+	if !errors.Is(err, ErrCredentialNotFound) {
+		t.Errorf("expected ErrCredentialNotFound, got %v", err)
+	}
+
+	var shared []string
+	err = vault.ShareCredentials([]string{"github", "aws", "github"}, []string{"age1peer"}, func(identity *age.X25519Identity, credentials []Credential) error {
+		if identity == nil {
+			t.Error("expected the sender's identity")
+		}
+		for _, credential := range credentials {
+			shared = append(shared, credential.Service+":"+string(credential.Password))
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ShareCredentials() failed: %v", err)
+	}
+	if len(shared) != 2 || shared[0] != "github:pass-github" || shared[1] != "aws:pass-aws" {
+		t.Errorf("unexpected shared credentials: %v", shared)
+	}
+
+	// A failed write is not recorded
+	_ = vault.ShareCredentials([]string{"aws"}, []string{"age1peer"}, func(*age.X25519Identity, []Credential) error {
+		return errors.New("disk full")
+	})
+
+	records, err := vault.ListShares()
+	if err != nil {
+		t.Fatalf("ListShares() failed: %v", err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("expected 1 share record, got %d", len(records))
+	}
+	record := records[0]
+	if record.Direction != ShareSent || len(record.Peers) != 1 || record.Peers[0] != "age1peer" ||
+		len(record.Services) != 2 || record.SharedAt.IsZero() || record.ReceivedAt != nil {
+		t.Errorf("unexpected share record: %+v", record)
+	}
+
+	// The vault's own passwords are untouched by clearing the shared copies
+	credential, err := vault.GetCredential("github", false)
+	if err != nil {
+		t.Fatalf("GetCredential() failed: %v", err)
+	}
+	if string(credential.Password) != "pass-github" {
+		t.Errorf("vault password changed to %q", credential.Password)
+	}
+}
+
+func TestReceiveShare(t *testing.T) {
+	vault, _, cleanup := setupUnlockedTestVault(t)
+	defer cleanup()
+
+	if err := vault.AddCredential("github", "me", []byte("mine"), "", "", ""); err != nil {
+		t.Fatalf("AddCredential() failed: %v", err)
+	}
+	sharedAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	entries := func() []ImportEntry {
+		return []ImportEntry{
+			{Service: "github", Username: "team", Password: []byte("theirs")},
+			{Service: "vpn", Username: "team", Password: []byte("vpn-pass")},
+		}
+	}
+
+	// A dry run records nothing
+	if _, err := vault.ReceiveShare("age1sender", sharedAt, entries(), ImportOptions{DryRun: true}); err != nil {
+		t.Fatalf("ReceiveShare(dry run) failed: %v", err)
+	}
+	if records, _ := vault.ListShares(); len(records) != 0 {
+		t.Errorf("dry run recorded %d share(s)", len(records))
+	}
+
+	report, err := vault.ReceiveShare("age1sender", sharedAt, entries(), ImportOptions{Duplicates: DuplicateSkip})
+	if err != nil {
+		t.Fatalf("ReceiveShare() failed: %v", err)
+	}
+	if report.Count(ImportAdded) != 1 || report.Count(ImportSkipped) != 1 {
+		t.Errorf("unexpected report: %d added, %d skipped", report.Count(ImportAdded), report.Count(ImportSkipped))
+	}
+	if credential, err := vault.GetCredential("github", false); err != nil || string(credential.Password) != "mine" {
+		t.Errorf("existing credential should be kept, got %v", err)
+	}
+	if credential, err := vault.GetCredential("vpn", false); err != nil || string(credential.Password) != "vpn-pass" {
+		t.Errorf("shared credential not merged: %v", err)
+	}
+
+	records, err := vault.ListShares()
+	if err != nil {
+		t.Fatalf("ListShares() failed: %v", err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("expected 1 share record, got %d", len(records))
+	}
+	record := records[0]
+	if record.Direction != ShareReceived || record.Peers[0] != "age1sender" || !record.SharedAt.Equal(sharedAt) ||
+		record.ReceivedAt == nil || !slices.Equal(record.Services, []string{"vpn"}) {
+		t.Errorf("unexpected share record: %+v", record)
+	}
+
+	// Receiving the same bundle again changes nothing and records nothing
+	if _, err := vault.ReceiveShare("age1sender", sharedAt, entries(), ImportOptions{Duplicates: DuplicateSkip}); err != nil {
+		t.Fatalf("ReceiveShare() failed: %v", err)
+	}
+	if records, _ := vault.ListShares(); len(records) != 1 {
+		t.Errorf("expected 1 share record after a no-op import, got %d", len(records))
+	}
+
+	// Renamed credentials are recorded under the name they were merged as
+	if _, err := vault.ReceiveShare("age1sender", sharedAt, entries(), ImportOptions{Duplicates: DuplicateRename}); err != nil {
+		t.Fatalf("ReceiveShare(rename) failed: %v", err)
+	}
+	records, _ = vault.ListShares()
+	if len(records) != 2 || !slices.Equal(records[1].Services, []string{"github-2", "vpn-2"}) {
+		t.Errorf("unexpected share records after rename: %+v", records)
+	}
+}
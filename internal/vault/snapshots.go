@@ -0,0 +1,260 @@
+package vault
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+
+	"pass-cli/internal/crypto"
+	"pass-cli/internal/security"
+	"pass-cli/internal/storage"
+)
+
+// SnapshotChangeKind describes how a credential differs between a snapshot and the vault
+type SnapshotChangeKind string
+
+const (
+	SnapshotAdded   SnapshotChangeKind = "added"   // Created after the snapshot
+	SnapshotRemoved SnapshotChangeKind = "removed" // In the snapshot, deleted since
+	SnapshotChanged SnapshotChangeKind = "changed" // Present in both with different fields
+)
+
+// SnapshotChange is one credential that differs between a snapshot and the current vault.
+// Only field names are reported, never values.
+type SnapshotChange struct {
+	Service string
+	Kind    SnapshotChangeKind
+	Fields  []string // Changed fields (SnapshotChanged only)
+}
+
+// ListSnapshots returns the vault's snapshots, newest first.
+// Snapshot listing only reads file names, so the vault does not need to be unlocked.
+func (v *VaultService) ListSnapshots() ([]storage.SnapshotInfo, error) {
+	return v.storageService.ListSnapshots()
+}
+
+// GetSnapshotRetention returns the vault's snapshot retention policy
+func (v *VaultService) GetSnapshotRetention() (storage.SnapshotRetention, error) {
+	return v.storageService.GetSnapshotRetention()
+}
+
+// SetSnapshotRetention changes the snapshot retention policy and prunes existing snapshots.
+// A Keep of 0 disables snapshots; existing snapshots are then removed.
+func (v *VaultService) SetSnapshotRetention(retention storage.SnapshotRetention) error {
+	if !v.unlocked {
+		return ErrVaultLocked
+	}
+	return v.storageService.SetSnapshotRetention(retention)
+}
+
+// DiffSnapshot compares a snapshot with the current vault.
+// password decrypts the snapshot; nil uses the current data key or master password.
+func (v *VaultService) DiffSnapshot(id string, password []byte) ([]SnapshotChange, error) {
+	if !v.unlocked {
+		return nil, ErrVaultLocked
+	}
+
+	snapshot, err := v.loadSnapshot(id, password)
+	if err != nil {
+		return nil, err
+	}
+	return diffCredentials(snapshot.Credentials, v.vaultData.Credentials), nil
+}
+
+// RestoreSnapshot replaces the vault with a snapshot.
+// password decrypts the snapshot; nil uses the current data key or master password.
+// Snapshots sharing the vault's data key keep the current key slots; older ones
+// bring back their own password, which becomes the master password again.
+func (v *VaultService) RestoreSnapshot(id string, password []byte) error {
+	if !v.unlocked {
+		return ErrVaultLocked
+	}
+
+	snapshot, err := v.loadSnapshot(id, password)
+	if err != nil {
+		return err
+	}
+
+	// Snapshots taken since the vault moved to key slots share its data key;
+	// restoring them keeps the current key slots and master password
+	if v.unlockedSlot != 0 {
+		if data, err := v.storageService.LoadSnapshotWithKey(id, v.dataKey); err == nil {
+			crypto.ClearBytes(data)
+			if err := v.storageService.RestoreSnapshotWithKey(id, v.dataKey); err != nil {
+				return err
+			}
+			v.vaultData = snapshot
+			v.logAudit(security.EventVaultRestore, security.OutcomeSuccess, "")
+			return nil
+		}
+	}
+
+	if err := v.storageService.RestoreSnapshot(id); err != nil {
+		return err
+	}
+
+	v.vaultData = snapshot
+	if err := v.refreshDataKey(password); err != nil {
+		return err
+	}
+	if password != nil {
+		crypto.ClearBytes(v.masterPassword)
+		v.masterPassword = make([]byte, len(password))
+		copy(v.masterPassword, password)
+
+		// Keep the keychain in step with the restored master password
+		if v.keychainService.IsAvailable() {
+			if err := v.keychainService.Store(string(password)); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to update password in keychain: %v\n", err)
+			}
+		}
+	}
+
+	v.logAudit(security.EventVaultRestore, security.OutcomeSuccess, "")
+	return nil
+}
+
+// refreshDataKey reopens the data key after the vault file was replaced by a
+// snapshot. Snapshots taken since the vault moved to key slots share its data
+// key; older ones need the password.
+func (v *VaultService) refreshDataKey(password []byte) error {
+	if _, err := v.storageService.LoadVaultWithKey(v.dataKey); err == nil {
+		return nil
+	}
+	if password == nil {
+		password = v.masterPassword
+	}
+
+	dataKey, slotID, err := v.storageService.OpenDataKey(password, storage.KeySlotPassword)
+	if err != nil {
+		return fmt.Errorf("failed to open restored vault: %w", err)
+	}
+	crypto.ClearBytes(v.dataKey)
+	crypto.ClearBytes(v.slotSecret)
+	v.dataKey = dataKey
+	v.unlockedSlot = slotID
+	v.slotSecret = make([]byte, len(password))
+	copy(v.slotSecret, password)
+	return nil
+}
+
+// loadSnapshot decrypts and parses a snapshot. Without a password the current
+// data key is tried first, then the current master password.
+func (v *VaultService) loadSnapshot(id string, password []byte) (*VaultData, error) {
+	var data []byte
+	var err error
+	if password == nil {
+		data, err = v.storageService.LoadSnapshotWithKey(id, v.dataKey)
+		if err != nil && v.masterPassword != nil {
+			data, err = v.storageService.LoadSnapshot(id, string(v.masterPassword))
+		}
+	} else {
+		data, err = v.storageService.LoadSnapshot(id, string(password))
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer crypto.ClearBytes(data)
+
+	var snapshot VaultData
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to parse snapshot data: %w", err)
+	}
+	if snapshot.Credentials == nil {
+		snapshot.Credentials = make(map[string]Credential)
+	}
+	return &snapshot, nil
+}
+
+// diffCredentials lists the credentials that differ between two credential sets, sorted by service
+func diffCredentials(before, after map[string]Credential) []SnapshotChange {
+	var changes []SnapshotChange
+	for _, service := range syncServiceNames(before, after) {
+		old, inBefore := before[service]
+		current, inAfter := after[service]
+
+		switch {
+		case !inBefore:
+			changes = append(changes, SnapshotChange{Service: service, Kind: SnapshotAdded})
+		case !inAfter:
+			changes = append(changes, SnapshotChange{Service: service, Kind: SnapshotRemoved})
+		default:
+			if fields := changedFields(old, current); len(fields) > 0 {
+				changes = append(changes, SnapshotChange{Service: service, Kind: SnapshotChanged, Fields: fields})
+			}
+		}
+	}
+	return changes
+}
+
+// changedFields names the fields that differ between two versions of a credential.
+// Usage tracking and timestamps are ignored.
+func changedFields(a, b Credential) []string {
+	var fields []string
+	add := func(name string, changed bool) {
+		if changed {
+			fields = append(fields, name)
+		}
+	}
+
+	add("username", a.Username != b.Username)
+	add("password", !bytes.Equal(a.Password, b.Password))
+	add("type", a.Type != b.Type)
+	add("category", a.Category != b.Category)
+	add("tags", !reflect.DeepEqual(normalizeEmpty(a.Tags), normalizeEmpty(b.Tags)))
+	add("url", a.URL != b.URL)
+	add("notes", a.Notes != b.Notes)
+	add("totp", !reflect.DeepEqual(a.TOTP, b.TOTP))
+	add("expires", !reflect.DeepEqual(a.ExpiresAt, b.ExpiresAt))
+	add("rotation", a.RotationDays != b.RotationDays)
+
+	// Custom fields are reported individually by key
+	oldFields := make(map[string]CustomField, len(a.CustomFields))
+	for _, field := range a.CustomFields {
+		oldFields[field.Key] = field
+	}
+	newFields := make(map[string]CustomField, len(b.CustomFields))
+	for _, field := range b.CustomFields {
+		newFields[field.Key] = field
+	}
+	var fieldKeys []string
+	for key, field := range oldFields {
+		if other, ok := newFields[key]; !ok || !reflect.DeepEqual(field, other) {
+			fieldKeys = append(fieldKeys, key)
+		}
+	}
+	for key := range newFields {
+		if _, ok := oldFields[key]; !ok {
+			fieldKeys = append(fieldKeys, key)
+		}
+	}
+	sort.Strings(fieldKeys)
+	for _, key := range fieldKeys {
+		fields = append(fields, "field:"+key)
+	}
+
+	oldAttachments := make(map[string][]byte, len(a.Attachments))
+	for _, attachment := range a.Attachments {
+		oldAttachments[attachment.Name] = attachment.Data
+	}
+	attachmentsChanged := len(a.Attachments) != len(b.Attachments)
+	for _, attachment := range b.Attachments {
+		if data, ok := oldAttachments[attachment.Name]; !ok || !bytes.Equal(data, attachment.Data) {
+			attachmentsChanged = true
+		}
+	}
+	add("attachments", attachmentsChanged)
+
+	return fields
+}
+
+// normalizeEmpty treats nil and empty slices as equal
+func normalizeEmpty(values []string) []string {
+	if len(values) == 0 {
+		return nil
+	}
+	return values
+}
@@ -0,0 +1,166 @@
+package vault
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestSnapshotDiffAndRestore(t *testing.T) {
+	vault, vaultPath, cleanup := setupUnlockedTestVault(t)
+	defer cleanup()
+
+	if err := vault.AddCredential("github", "user", []byte("pass"), "", "", ""); err != nil {
+		t.Fatalf("AddCredential() failed: %v", err)
+	}
+	if err := vault.AddCredential("aws", "admin", []byte("pass"), "", "", ""); err != nil {
+		t.Fatalf("AddCredential() failed: %v", err)
+	}
+
+	snapshots, err := vault.ListSnapshots()
+	if err != nil {
+		t.Fatalf("ListSnapshots() failed: %v", err)
+	}
+	if len(snapshots) == 0 {
+		t.Fatal("expected a snapshot after saving")
+	}
+	target := snapshots[0].ID
+
+	newPassword := []byte("rotated")
+	fields := []CustomField{{Key: "token", Value: "secret-token", Secret: true}}
+	if err := vault.UpdateCredential("github", UpdateOpts{Password: &newPassword, CustomFields: &fields}); err != nil {
+		t.Fatalf("UpdateCredential() failed: %v", err)
+	}
+	if err := vault.DeleteCredential("aws"); err != nil {
+		t.Fatalf("DeleteCredential() failed: %v", err)
+	}
+	if err := vault.AddCredential("gitlab", "user", []byte("pass"), "", "", ""); err != nil {
+		t.Fatalf("AddCredential() failed: %v", err)
+	}
+
+	changes, err := vault.DiffSnapshot(target, nil)
+	if err != nil {
+		t.Fatalf("DiffSnapshot() failed: %v", err)
+	}
+	if len(changes) != 3 {
+		t.Fatalf("expected 3 changes, got %+v", changes)
+	}
+	if changes[0].Service != "aws" || changes[0].Kind != SnapshotRemoved {
+		t.Errorf("changes[0] = %+v", changes[0])
+	}
+	if changes[1].Service != "github" || changes[1].Kind != SnapshotChanged {
+		t.Errorf("changes[1] = %+v", changes[1])
+	}
+	if fields := changes[1].Fields; len(fields) != 2 || fields[0] != "password" || fields[1] != "field:token" {
+		t.Errorf("github changed fields = %v", fields)
+	}
+	if changes[2].Service != "gitlab" || changes[2].Kind != SnapshotAdded {
+		t.Errorf("changes[2] = %+v", changes[2])
+	}
+
+	if err := vault.RestoreSnapshot(target, nil); err != nil {
+		t.Fatalf("RestoreSnapshot() failed: %v", err)
+	}
+	cred, err := vault.GetCredential("github", false)
+	if err != nil {
+		t.Fatalf("GetCredential() failed: %v", err)
+	}
+	if string(cred.Password) != "pass" || len(cred.CustomFields) != 0 {
+		t.Errorf("restored github = %+v", cred)
+	}
+	if _, err := vault.GetCredential("gitlab", false); !errors.Is(err, ErrCredentialNotFound) {
+		t.Errorf("expected gitlab to be gone after restore, got %v", err)
+	}
+
+	// The restored vault is what's on disk
+	reopened, err := New(vaultPath)
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	if err := reopened.Unlock([]byte("TestPassword123!")); err != nil {
+		t.Fatalf("Unlock() failed: %v", err)
+	}
+	defer reopened.Lock()
+	if _, err := reopened.GetCredential("aws", false); err != nil {
+		t.Errorf("expected aws after restore, got %v", err)
+	}
+}
+
+func TestSnapshotAfterPasswordChange(t *testing.T) {
+	vault, _, cleanup := setupUnlockedTestVault(t)
+	defer cleanup()
+
+	if err := vault.AddCredential("github", "user", []byte("pass"), "", "", ""); err != nil {
+		t.Fatalf("AddCredential() failed: %v", err)
+	}
+	snapshots, _ := vault.ListSnapshots()
+	target := snapshots[0].ID
+
+	if err := vault.ChangePassword([]byte("NewPassword456!")); err != nil {
+		t.Fatalf("ChangePassword() failed: %v", err)
+	}
+
+	// Snapshots share the data key, so they open without the old password
+	if _, err := vault.DiffSnapshot(target, nil); err != nil {
+		t.Fatalf("DiffSnapshot() after password change failed: %v", err)
+	}
+	if err := vault.RestoreSnapshot(target, nil); err != nil {
+		t.Fatalf("RestoreSnapshot() failed: %v", err)
+	}
+
+	// Restoring keeps the current key slots: the new password stays in effect
+	if err := vault.AddCredential("gitlab", "user", []byte("pass"), "", "", ""); err != nil {
+		t.Fatalf("AddCredential() after restore failed: %v", err)
+	}
+	vault.Lock()
+	if err := vault.Unlock([]byte("TestPassword123!")); err == nil {
+		t.Fatal("expected the old password to stay replaced after restore")
+	}
+	if err := vault.Unlock([]byte("NewPassword456!")); err != nil {
+		t.Fatalf("Unlock() with the new password failed: %v", err)
+	}
+	if _, err := vault.GetCredential("gitlab", false); err != nil {
+		t.Errorf("expected gitlab after restore and save, got %v", err)
+	}
+}
+
+func TestSnapshotSkipsReadsAndUnlocks(t *testing.T) {
+	vault, vaultPath, cleanup := setupUnlockedTestVault(t)
+	defer cleanup()
+
+	// Audit logging needs a keychain for its key; without one, unlocks don't save
+	if err := vault.EnableAudit(filepath.Join(filepath.Dir(vaultPath), "audit.log"), vaultPath); err != nil {
+		t.Logf("audit logging unavailable: %v", err)
+	}
+	if err := vault.AddCredential("github", "user", []byte("pass"), "", "", ""); err != nil {
+		t.Fatalf("AddCredential() failed: %v", err)
+	}
+	before, _ := vault.ListSnapshots()
+
+	// Unlocking restores audit logging and getting a credential records its
+	// usage; both save the vault, but neither changes a credential
+	for i := 0; i < 3; i++ {
+		vault.Lock()
+		if err := vault.Unlock([]byte("TestPassword123!")); err != nil {
+			t.Fatalf("Unlock() failed: %v", err)
+		}
+		if _, err := vault.GetCredential("github", false); err != nil {
+			t.Fatalf("GetCredential() failed: %v", err)
+		}
+		if err := vault.RecordFieldAccess("github", "password"); err != nil {
+			t.Fatalf("RecordFieldAccess() failed: %v", err)
+		}
+	}
+
+	after, _ := vault.ListSnapshots()
+	if len(after) != len(before) || after[0].ID != before[0].ID {
+		t.Errorf("expected no new snapshots, had %d, now %d", len(before), len(after))
+	}
+
+	if err := vault.AddCredential("gitlab", "user", []byte("pass"), "", "", ""); err != nil {
+		t.Fatalf("AddCredential() failed: %v", err)
+	}
+	if after, _ = vault.ListSnapshots(); len(after) != len(before)+1 {
+		t.Errorf("expected a snapshot for the new credential, got %d", len(after))
+	}
+}
@@ -0,0 +1,486 @@
+package vault
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"pass-cli/internal/crypto"
+	"pass-cli/internal/security"
+)
+
+// SyncStrategy decides how conflicting changes are resolved
+type SyncStrategy string
+
+const (
+	SyncStrategyAsk    SyncStrategy = "ask"    // Ask the resolver about every conflict
+	SyncStrategyLocal  SyncStrategy = "local"  // Keep this vault's version
+	SyncStrategyRemote SyncStrategy = "remote" // Take the other vault's version
+	SyncStrategyNewest SyncStrategy = "newest" // Take the most recently changed version
+)
+
+// SyncStrategies lists the valid strategies in display order
+var SyncStrategies = []SyncStrategy{SyncStrategyAsk, SyncStrategyLocal, SyncStrategyRemote, SyncStrategyNewest}
+
+// SyncConflictKind describes how the two sides diverged
+type SyncConflictKind string
+
+const (
+	ConflictBothModified    SyncConflictKind = "modified on both sides"
+	ConflictBothAdded       SyncConflictKind = "added on both sides"
+	ConflictDeletedLocally  SyncConflictKind = "deleted locally, modified remotely"
+	ConflictDeletedRemotely SyncConflictKind = "modified locally, deleted remotely"
+)
+
+// SyncChoice is the side picked for a conflict
+type SyncChoice string
+
+const (
+	SyncKeepLocal  SyncChoice = "local"
+	SyncTakeRemote SyncChoice = "remote"
+)
+
+// ErrSyncAborted is returned by a resolver to cancel the sync without writing anything
+var ErrSyncAborted = errors.New("sync aborted")
+
+// SyncVersion describes one side of a conflict without exposing secrets
+type SyncVersion struct {
+	Exists        bool
+	Username      string
+	UpdatedAt     time.Time // Deletion time when the credential was deleted (zero if unknown)
+	ModifiedCount int
+}
+
+// SyncConflict is a credential changed differently on both sides since the last sync
+type SyncConflict struct {
+	Service string
+	Kind    SyncConflictKind
+	Local   SyncVersion
+	Remote  SyncVersion
+}
+
+// SyncResolver picks a side for a conflict (used with SyncStrategyAsk)
+type SyncResolver func(conflict SyncConflict) (SyncChoice, error)
+
+// SyncOptions controls a sync run
+type SyncOptions struct {
+	Strategy SyncStrategy
+	Resolve  SyncResolver // Required for SyncStrategyAsk
+	DryRun   bool         // Compute the report without writing the vault
+}
+
+// SyncResolution records how a conflict was decided
+type SyncResolution struct {
+	Conflict SyncConflict
+	Choice   SyncChoice
+}
+
+// SyncReport summarizes what a sync changed in this vault
+type SyncReport struct {
+	Added     []string // New in the other vault, copied here
+	Updated   []string // Changed in the other vault, copied here
+	Deleted   []string // Deleted in the other vault, moved to the trash here
+	Resolved  []SyncResolution
+	Unchanged int  // Credentials already identical on both sides (or only changed here)
+	HasBase   bool // Whether a common ancestor from a previous sync was used
+}
+
+// Changed reports whether the sync changed this vault's credentials
+func (r *SyncReport) Changed() bool {
+	if len(r.Added)+len(r.Updated)+len(r.Deleted) > 0 {
+		return true
+	}
+	for _, res := range r.Resolved {
+		if res.Choice == SyncTakeRemote {
+			return true
+		}
+	}
+	return false
+}
+
+// SyncSnapshot records each credential's version at the last sync (the common ancestor)
+type SyncSnapshot struct {
+	SyncedAt    time.Time             `json:"synced_at"`
+	Credentials map[string]SyncRecord `json:"credentials"`
+}
+
+// SyncRecord identifies a credential version by its modification stamp
+type SyncRecord struct {
+	UpdatedAt     time.Time `json:"updated_at"`
+	ModifiedCount int       `json:"modified_count"`
+}
+
+// OpenPeer opens another copy of the vault read-only for syncing.
+// When password is nil the current master password is tried, since copies of a
+// vault normally share it. Unlike Unlock, nothing is written: no migration
+// recovery, no backup cleanup and no audit settings save, so the other file
+// stays exactly as it was. The caller must Lock the returned service.
+func (v *VaultService) OpenPeer(path string, password []byte) (*VaultService, error) {
+	if !v.unlocked {
+		return nil, ErrVaultLocked
+	}
+
+	peer, err := New(path)
+	if err != nil {
+		return nil, err
+	}
+	peer.SetKeyFile(v.keyFile)
+
+	if password == nil {
+		password = make([]byte, len(v.masterPassword))
+		copy(password, v.masterPassword)
+	}
+	defer crypto.ClearBytes(password)
+
+	dataKey, slotID, slotSecret, err := peer.openPasswordSlot(password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unlock vault: %w", err)
+	}
+	defer crypto.ClearBytes(slotSecret)
+
+	if err := peer.loadWithDataKey(dataKey, slotID, slotSecret); err != nil {
+		return nil, fmt.Errorf("failed to unlock vault: %w", err)
+	}
+	return peer, nil
+}
+
+// Sync merges another copy of the vault into this one, credential by credential.
+// Changes are detected against the snapshot stored by the previous sync: a side
+// whose version still matches the snapshot is unchanged, so the other side wins.
+// Credentials changed on both sides are conflicts, decided by opts.Strategy.
+// Without a shared snapshot (first sync) nothing is treated as deleted.
+// The merged vault is written once, atomically, and records a new snapshot.
+func (v *VaultService) Sync(peer *VaultService, opts SyncOptions) (*SyncReport, error) {
+	if !v.unlocked || peer == nil || !peer.unlocked {
+		return nil, ErrVaultLocked
+	}
+	if opts.Strategy == "" {
+		opts.Strategy = SyncStrategyAsk
+	}
+	if !isValidSyncStrategy(opts.Strategy) {
+		return nil, fmt.Errorf("invalid sync strategy: %s", opts.Strategy)
+	}
+	if opts.Strategy == SyncStrategyAsk && opts.Resolve == nil {
+		return nil, fmt.Errorf("sync strategy %s requires a conflict resolver", opts.Strategy)
+	}
+
+	local := v.vaultData
+	remote := peer.vaultData
+	base := commonSyncBase(local.SyncBase, remote.SyncBase)
+	report := &SyncReport{HasBase: base != nil}
+
+	merged := make(map[string]Credential, len(local.Credentials))
+	for service, credential := range local.Credentials {
+		merged[service] = credential
+	}
+	var deleted []TrashedCredential
+
+	for _, service := range syncServiceNames(local.Credentials, remote.Credentials) {
+		localCred, inLocal := local.Credentials[service]
+		remoteCred, inRemote := remote.Credentials[service]
+		baseRecord, inBase := SyncRecord{}, false
+		if base != nil {
+			baseRecord, inBase = base.Credentials[service]
+		}
+
+		localChanged := inLocal && (!inBase || !matchesSyncRecord(localCred, baseRecord))
+		remoteChanged := inRemote && (!inBase || !matchesSyncRecord(remoteCred, baseRecord))
+
+		var kind SyncConflictKind
+		switch {
+		case inLocal && inRemote:
+			if sameVersion(localCred, remoteCred) || !remoteChanged {
+				merged[service] = mergeUsage(localCred, remoteCred)
+				report.Unchanged++
+				continue
+			}
+			if !localChanged {
+				merged[service] = mergeUsage(cloneCredential(remoteCred), localCred)
+				report.Updated = append(report.Updated, service)
+				continue
+			}
+			kind = ConflictBothModified
+			if !inBase {
+				kind = ConflictBothAdded
+			}
+
+		case inLocal:
+			if !inBase {
+				report.Unchanged++ // Added here only
+				continue
+			}
+			if !localChanged {
+				delete(merged, service)
+				deleted = append(deleted, trashCopy(localCred, remote.Trash))
+				report.Deleted = append(report.Deleted, service)
+				continue
+			}
+			kind = ConflictDeletedRemotely
+
+		default: // Only in the other vault
+			if !inBase {
+				merged[service] = cloneCredential(remoteCred)
+				report.Added = append(report.Added, service)
+				continue
+			}
+			if !remoteChanged {
+				continue // Deleted here, untouched there
+			}
+			kind = ConflictDeletedLocally
+		}
+
+		conflict := SyncConflict{
+			Service: service,
+			Kind:    kind,
+			Local:   syncVersion(localCred, inLocal, service, local.Trash),
+			Remote:  syncVersion(remoteCred, inRemote, service, remote.Trash),
+		}
+		choice, err := resolveConflict(conflict, opts)
+		if err != nil {
+			return nil, err
+		}
+		report.Resolved = append(report.Resolved, SyncResolution{Conflict: conflict, Choice: choice})
+
+		if choice == SyncKeepLocal {
+			continue
+		}
+		switch {
+		case inRemote:
+			merged[service] = mergeUsage(cloneCredential(remoteCred), localCred)
+		case inLocal:
+			delete(merged, service)
+			deleted = append(deleted, trashCopy(localCred, remote.Trash))
+		}
+	}
+
+	if opts.DryRun {
+		return report, nil
+	}
+
+	previousCredentials := local.Credentials
+	previousTrash := local.Trash
+	previousBase := local.SyncBase
+
+	local.Credentials = merged
+	local.Trash = mergeTrash(append(copyTrash(local.Trash), deleted...), remote.Trash)
+	local.SyncBase = newSyncSnapshot(merged)
+
+	if err := v.save(); err != nil {
+		// Keep the in-memory vault consistent with disk
+		local.Credentials = previousCredentials
+		local.Trash = previousTrash
+		local.SyncBase = previousBase
+		return nil, err
+	}
+
+	v.logAudit(security.EventVaultSync, security.OutcomeSuccess, "")
+	return report, nil
+}
+
+// resolveConflict applies the strategy to a conflict
+func resolveConflict(conflict SyncConflict, opts SyncOptions) (SyncChoice, error) {
+	switch opts.Strategy {
+	case SyncStrategyLocal:
+		return SyncKeepLocal, nil
+	case SyncStrategyRemote:
+		return SyncTakeRemote, nil
+	case SyncStrategyNewest:
+		// Ties keep the local version
+		if conflict.Remote.UpdatedAt.After(conflict.Local.UpdatedAt) {
+			return SyncTakeRemote, nil
+		}
+		if conflict.Remote.UpdatedAt.Equal(conflict.Local.UpdatedAt) && conflict.Remote.ModifiedCount > conflict.Local.ModifiedCount {
+			return SyncTakeRemote, nil
+		}
+		return SyncKeepLocal, nil
+	}
+
+	choice, err := opts.Resolve(conflict)
+	if err != nil {
+		return "", err
+	}
+	if choice != SyncKeepLocal && choice != SyncTakeRemote {
+		return "", fmt.Errorf("invalid sync choice for %s: %s", conflict.Service, choice)
+	}
+	return choice, nil
+}
+
+// isValidSyncStrategy reports whether s is a known strategy
+func isValidSyncStrategy(s SyncStrategy) bool {
+	for _, strategy := range SyncStrategies {
+		if s == strategy {
+			return true
+		}
+	}
+	return false
+}
+
+// commonSyncBase returns the snapshot both copies descend from, or nil.
+// A snapshot is only trusted when both copies carry one; if they differ the
+// older one is used, since the newer was taken after the copies diverged.
+func commonSyncBase(local, remote *SyncSnapshot) *SyncSnapshot {
+	if local == nil || remote == nil {
+		return nil
+	}
+	if remote.SyncedAt.Before(local.SyncedAt) {
+		return remote
+	}
+	return local
+}
+
+// newSyncSnapshot records the current version of every credential
+func newSyncSnapshot(credentials map[string]Credential) *SyncSnapshot {
+	snapshot := &SyncSnapshot{
+		SyncedAt:    time.Now(),
+		Credentials: make(map[string]SyncRecord, len(credentials)),
+	}
+	for service, credential := range credentials {
+		snapshot.Credentials[service] = SyncRecord{
+			UpdatedAt:     credential.UpdatedAt,
+			ModifiedCount: credential.ModifiedCount,
+		}
+	}
+	return snapshot
+}
+
+// matchesSyncRecord reports whether a credential is still the version recorded in the snapshot
+func matchesSyncRecord(credential Credential, record SyncRecord) bool {
+	return credential.UpdatedAt.Equal(record.UpdatedAt) && credential.ModifiedCount == record.ModifiedCount
+}
+
+// sameVersion reports whether two copies of a credential carry the same modification stamp
+func sameVersion(a, b Credential) bool {
+	return a.UpdatedAt.Equal(b.UpdatedAt) && a.ModifiedCount == b.ModifiedCount
+}
+
+// syncServiceNames returns the union of service names in sorted order
+func syncServiceNames(local, remote map[string]Credential) []string {
+	seen := make(map[string]bool, len(local)+len(remote))
+	names := make([]string, 0, len(local)+len(remote))
+	for _, credentials := range []map[string]Credential{local, remote} {
+		for service := range credentials {
+			if !seen[service] {
+				seen[service] = true
+				names = append(names, service)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// syncVersion describes one side of a conflict; deleted sides report their deletion time
+func syncVersion(credential Credential, exists bool, service string, trash []TrashedCredential) SyncVersion {
+	if exists {
+		return SyncVersion{
+			Exists:        true,
+			Username:      credential.Username,
+			UpdatedAt:     credential.UpdatedAt,
+			ModifiedCount: credential.ModifiedCount,
+		}
+	}
+
+	version := SyncVersion{}
+	if index := findTrashed(trash, service); index >= 0 {
+		version.Username = trash[index].Credential.Username
+		version.UpdatedAt = trash[index].DeletedAt
+	}
+	return version
+}
+
+// trashCopy moves a credential deleted on the other side into the trash,
+// keeping the other side's deletion time when it is known
+func trashCopy(credential Credential, remoteTrash []TrashedCredential) TrashedCredential {
+	deletedAt := time.Now()
+	if index := findTrashed(remoteTrash, credential.Service); index >= 0 {
+		deletedAt = remoteTrash[index].DeletedAt
+	}
+	return TrashedCredential{Credential: credential, DeletedAt: deletedAt}
+}
+
+// mergeTrash adds the other vault's trash entries that are not already present
+func mergeTrash(local, remote []TrashedCredential) []TrashedCredential {
+	type trashKey struct {
+		service   string
+		deletedAt int64
+	}
+	seen := make(map[trashKey]bool, len(local))
+	for _, trashed := range local {
+		seen[trashKey{trashed.Credential.Service, trashed.DeletedAt.UnixNano()}] = true
+	}
+
+	merged := local
+	for _, trashed := range remote {
+		key := trashKey{trashed.Credential.Service, trashed.DeletedAt.UnixNano()}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		merged = append(merged, TrashedCredential{Credential: cloneCredential(trashed.Credential), DeletedAt: trashed.DeletedAt})
+	}
+
+	if len(merged) == 0 {
+		return nil
+	}
+	return merged
+}
+
+// copyTrash returns a shallow copy of the trash slice so appends don't alias the original
+func copyTrash(trash []TrashedCredential) []TrashedCredential {
+	if trash == nil {
+		return nil
+	}
+	return append(make([]TrashedCredential, 0, len(trash)), trash...)
+}
+
+// mergeUsage returns kept with usage records from other added; per location the most recent record wins.
+// Usage tracking never bumps UpdatedAt, so it is merged rather than treated as a change.
+func mergeUsage(kept, other Credential) Credential {
+	if len(other.UsageRecord) == 0 {
+		return kept
+	}
+
+	usage := make(map[string]UsageRecord, len(kept.UsageRecord)+len(other.UsageRecord))
+	for location, record := range kept.UsageRecord {
+		usage[location] = record
+	}
+	for location, record := range other.UsageRecord {
+		if existing, ok := usage[location]; ok && !record.Timestamp.After(existing.Timestamp) {
+			continue
+		}
+		usage[location] = copyUsageRecord(record)
+	}
+	kept.UsageRecord = usage
+	return kept
+}
+
+// cloneCredential deep-copies a credential taken from another vault
+func cloneCredential(c Credential) Credential {
+	clone := c
+	clone.Password = append([]byte(nil), c.Password...)
+	clone.CustomFields = copyCustomFields(c.CustomFields)
+	clone.TOTP = copyTOTP(c.TOTP)
+	clone.Tags = copyTags(c.Tags)
+	clone.ExpiresAt = copyTime(c.ExpiresAt)
+	clone.PasswordHistory = copyPasswordHistory(c.PasswordHistory)
+	clone.Attachments = copyAttachments(c.Attachments)
+
+	clone.UsageRecord = make(map[string]UsageRecord, len(c.UsageRecord))
+	for location, record := range c.UsageRecord {
+		clone.UsageRecord[location] = copyUsageRecord(record)
+	}
+	return clone
+}
+
+// copyUsageRecord deep-copies a usage record's field access counts
+func copyUsageRecord(record UsageRecord) UsageRecord {
+	if record.FieldAccess != nil {
+		fieldAccess := make(map[string]int, len(record.FieldAccess))
+		for field, count := range record.FieldAccess {
+			fieldAccess[field] = count
+		}
+		record.FieldAccess = fieldAccess
+	}
+	return record
+}
@@ -0,0 +1,319 @@
+package vault
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"pass-cli/internal/storage"
+)
+
+// copyVaultForSync copies the vault file and opens the copy as a sync peer
+func copyVaultForSync(t *testing.T, vault *VaultService, vaultPath string) (*VaultService, string) {
+	t.Helper()
+
+	data, err := os.ReadFile(vaultPath)
+	if err != nil {
+		t.Fatalf("ReadFile() failed: %v", err)
+	}
+	peerPath := filepath.Join(filepath.Dir(vaultPath), "peer.vault")
+	if err := os.WriteFile(peerPath, data, 0600); err != nil {
+		t.Fatalf("WriteFile() failed: %v", err)
+	}
+
+	peer, err := vault.OpenPeer(peerPath, nil)
+	if err != nil {
+		t.Fatalf("OpenPeer() failed: %v", err)
+	}
+	return peer, peerPath
+}
+
+func TestSyncThreeWayMerge(t *testing.T) {
+	vault, vaultPath, cleanup := setupUnlockedTestVault(t)
+	defer cleanup()
+
+	for _, service := range []string{"both", "local-edit", "remote-edit", "remote-delete"} {
+		if err := vault.AddCredential(service, "user", []byte("pass"), "", "", ""); err != nil {
+			t.Fatalf("AddCredential() failed: %v", err)
+		}
+	}
+
+	// First sync with an identical copy records the common ancestor
+	peer, _ := copyVaultForSync(t, vault, vaultPath)
+	report, err := vault.Sync(peer, SyncOptions{Strategy: SyncStrategyLocal})
+	if err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	if report.HasBase || report.Changed() || report.Unchanged != 4 {
+		t.Fatalf("first sync report = %+v", report)
+	}
+	peer.Lock()
+
+	peer, peerPath := copyVaultForSync(t, vault, vaultPath)
+	defer peer.Lock()
+
+	localUser := "local"
+	remoteUser := "remote"
+	for _, edit := range []struct {
+		v       *VaultService
+		service string
+		user    *string
+	}{
+		{vault, "local-edit", &localUser},
+		{vault, "both", &localUser},
+		{peer, "remote-edit", &remoteUser},
+		{peer, "both", &remoteUser},
+	} {
+		if err := edit.v.UpdateCredential(edit.service, UpdateOpts{Username: edit.user}); err != nil {
+			t.Fatalf("UpdateCredential(%s) failed: %v", edit.service, err)
+		}
+	}
+	if err := peer.DeleteCredential("remote-delete"); err != nil {
+		t.Fatalf("DeleteCredential() failed: %v", err)
+	}
+	if err := peer.AddCredential("remote-new", "remote", []byte("pass"), "", "", ""); err != nil {
+		t.Fatalf("AddCredential() failed: %v", err)
+	}
+
+	var asked []SyncConflict
+	report, err = vault.Sync(peer, SyncOptions{
+		Strategy: SyncStrategyAsk,
+		Resolve: func(conflict SyncConflict) (SyncChoice, error) {
+			asked = append(asked, conflict)
+			return SyncTakeRemote, nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+
+	if !report.HasBase {
+		t.Error("expected the previous sync to be used as the common ancestor")
+	}
+	if len(report.Added) != 1 || report.Added[0] != "remote-new" {
+		t.Errorf("Added = %v", report.Added)
+	}
+	if len(report.Updated) != 1 || report.Updated[0] != "remote-edit" {
+		t.Errorf("Updated = %v", report.Updated)
+	}
+	if len(report.Deleted) != 1 || report.Deleted[0] != "remote-delete" {
+		t.Errorf("Deleted = %v", report.Deleted)
+	}
+	if len(asked) != 1 || asked[0].Service != "both" || asked[0].Kind != ConflictBothModified {
+		t.Fatalf("conflicts = %+v", asked)
+	}
+	if asked[0].Local.Username != "local" || asked[0].Remote.Username != "remote" {
+		t.Errorf("conflict versions = %+v / %+v", asked[0].Local, asked[0].Remote)
+	}
+
+	expectUser := map[string]string{
+		"both":        "remote",
+		"local-edit":  "local",
+		"remote-edit": "remote",
+		"remote-new":  "remote",
+	}
+	for service, user := range expectUser {
+		cred, err := vault.GetCredential(service, false)
+		if err != nil {
+			t.Fatalf("GetCredential(%s) failed: %v", service, err)
+		}
+		if cred.Username != user {
+			t.Errorf("%s username = %q, want %q", service, cred.Username, user)
+		}
+	}
+	if _, err := vault.GetCredential("remote-delete", false); !errors.Is(err, ErrCredentialNotFound) {
+		t.Errorf("expected remote-delete to be removed, got %v", err)
+	}
+	if entries, _ := vault.ListTrash(); len(entries) != 1 || entries[0].Service != "remote-delete" {
+		t.Errorf("trash = %+v", entries)
+	}
+
+	// The other vault file is left untouched
+	other, err := New(peerPath)
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	if err := other.Unlock([]byte("TestPassword123!")); err != nil {
+		t.Fatalf("Unlock() failed: %v", err)
+	}
+	defer other.Lock()
+	if cred, err := other.GetCredential("local-edit", false); err != nil || cred.Username != "user" {
+		t.Errorf("peer local-edit = %+v, %v", cred, err)
+	}
+
+	// The merge is persisted, so syncing again changes nothing
+	reopened, err := New(vaultPath)
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	if err := reopened.Unlock([]byte("TestPassword123!")); err != nil {
+		t.Fatalf("Unlock() failed: %v", err)
+	}
+	defer reopened.Lock()
+	if cred, err := reopened.GetCredential("both", false); err != nil || cred.Username != "remote" {
+		t.Fatalf("reloaded both = %+v, %v", cred, err)
+	}
+}
+
+func TestSyncDeletionConflicts(t *testing.T) {
+	vault, vaultPath, cleanup := setupUnlockedTestVault(t)
+	defer cleanup()
+
+	for _, service := range []string{"deleted-here", "deleted-there"} {
+		if err := vault.AddCredential(service, "user", []byte("pass"), "", "", ""); err != nil {
+			t.Fatalf("AddCredential() failed: %v", err)
+		}
+	}
+	peer, _ := copyVaultForSync(t, vault, vaultPath)
+	if _, err := vault.Sync(peer, SyncOptions{Strategy: SyncStrategyLocal}); err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	peer.Lock()
+	peer, _ = copyVaultForSync(t, vault, vaultPath)
+	defer peer.Lock()
+
+	edited := "edited"
+	if err := vault.DeleteCredential("deleted-here"); err != nil {
+		t.Fatalf("DeleteCredential() failed: %v", err)
+	}
+	if err := peer.UpdateCredential("deleted-here", UpdateOpts{Username: &edited}); err != nil {
+		t.Fatalf("UpdateCredential() failed: %v", err)
+	}
+	if err := vault.UpdateCredential("deleted-there", UpdateOpts{Username: &edited}); err != nil {
+		t.Fatalf("UpdateCredential() failed: %v", err)
+	}
+	if err := peer.DeleteCredential("deleted-there"); err != nil {
+		t.Fatalf("DeleteCredential() failed: %v", err)
+	}
+
+	// Dry runs report the conflicts without touching the vault
+	report, err := vault.Sync(peer, SyncOptions{Strategy: SyncStrategyRemote, DryRun: true})
+	if err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	kinds := map[string]SyncConflictKind{}
+	for _, res := range report.Resolved {
+		kinds[res.Conflict.Service] = res.Conflict.Kind
+	}
+	if kinds["deleted-here"] != ConflictDeletedLocally || kinds["deleted-there"] != ConflictDeletedRemotely {
+		t.Fatalf("conflict kinds = %v", kinds)
+	}
+	if _, err := vault.GetCredential("deleted-there", false); err != nil {
+		t.Fatalf("dry run changed the vault: %v", err)
+	}
+
+	if _, err := vault.Sync(peer, SyncOptions{Strategy: SyncStrategyRemote}); err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	if cred, err := vault.GetCredential("deleted-here", false); err != nil || cred.Username != "edited" {
+		t.Errorf("deleted-here = %+v, %v", cred, err)
+	}
+	if _, err := vault.GetCredential("deleted-there", false); !errors.Is(err, ErrCredentialNotFound) {
+		t.Errorf("expected deleted-there to be removed, got %v", err)
+	}
+}
+
+func TestSyncAbortAndValidation(t *testing.T) {
+	vault, vaultPath, cleanup := setupUnlockedTestVault(t)
+	defer cleanup()
+
+	if err := vault.AddCredential("github", "local", []byte("pass"), "", "", ""); err != nil {
+		t.Fatalf("AddCredential() failed: %v", err)
+	}
+	peer, _ := copyVaultForSync(t, vault, vaultPath)
+	defer peer.Lock()
+
+	remote := "remote"
+	if err := peer.UpdateCredential("github", UpdateOpts{Username: &remote}); err != nil {
+		t.Fatalf("UpdateCredential() failed: %v", err)
+	}
+
+	if _, err := vault.Sync(peer, SyncOptions{Strategy: "theirs"}); err == nil {
+		t.Error("expected an error for an unknown strategy")
+	}
+	if _, err := vault.Sync(peer, SyncOptions{}); err == nil {
+		t.Error("expected an error for ask without a resolver")
+	}
+
+	// Without a common ancestor, differing copies are a conflict
+	_, err := vault.Sync(peer, SyncOptions{
+		Resolve: func(conflict SyncConflict) (SyncChoice, error) {
+			if conflict.Kind != ConflictBothAdded {
+				t.Errorf("conflict kind = %s", conflict.Kind)
+			}
+			return "", ErrSyncAborted
+		},
+	})
+	if !errors.Is(err, ErrSyncAborted) {
+		t.Fatalf("expected ErrSyncAborted, got %v", err)
+	}
+	if cred, _ := vault.GetCredential("github", false); cred.Username != "local" {
+		t.Errorf("aborted sync changed the vault: %+v", cred)
+	}
+
+	report, err := vault.Sync(peer, SyncOptions{Strategy: SyncStrategyNewest})
+	if err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	if len(report.Resolved) != 1 || report.Resolved[0].Choice != SyncTakeRemote {
+		t.Fatalf("newest resolution = %+v", report.Resolved)
+	}
+}
+
+func TestSyncLeavesPeerUntouched(t *testing.T) {
+	vault, vaultPath, cleanup := setupUnlockedTestVault(t)
+	defer cleanup()
+
+	if err := vault.AddCredential("github", "user", []byte("pass"), "", "", ""); err != nil {
+		t.Fatalf("AddCredential() failed: %v", err)
+	}
+	peer, peerPath := copyVaultForSync(t, vault, vaultPath)
+	if err := peer.AddCredential("gitlab", "user", []byte("pass"), "", "", ""); err != nil {
+		t.Fatalf("AddCredential() failed: %v", err)
+	}
+	peer.Lock()
+
+	// A backup left next to the other copy must survive too
+	backupPath := peerPath + storage.BackupSuffix
+	if err := os.WriteFile(backupPath, []byte("backup"), 0600); err != nil {
+		t.Fatalf("WriteFile() failed: %v", err)
+	}
+	past := time.Now().Add(-time.Hour).Truncate(time.Second)
+	if err := os.Chtimes(peerPath, past, past); err != nil {
+		t.Fatalf("Chtimes() failed: %v", err)
+	}
+	before, err := os.ReadFile(peerPath)
+	if err != nil {
+		t.Fatalf("ReadFile() failed: %v", err)
+	}
+
+	peer, err = vault.OpenPeer(peerPath, nil)
+	if err != nil {
+		t.Fatalf("OpenPeer() failed: %v", err)
+	}
+	report, err := vault.Sync(peer, SyncOptions{Strategy: SyncStrategyLocal})
+	if err != nil {
+		t.Fatalf("Sync() failed: %v", err)
+	}
+	peer.Lock()
+	if len(report.Added) != 1 || report.Added[0] != "gitlab" {
+		t.Fatalf("sync report = %+v", report)
+	}
+
+	after, err := os.ReadFile(peerPath)
+	if err != nil {
+		t.Fatalf("ReadFile() failed: %v", err)
+	}
+	if !bytes.Equal(before, after) {
+		t.Error("sync rewrote the other vault file")
+	}
+	if info, err := os.Stat(peerPath); err != nil || !info.ModTime().Equal(past) {
+		t.Errorf("other vault file modified: %v (%v)", info.ModTime(), err)
+	}
+	if _, err := os.Stat(backupPath); err != nil {
+		t.Errorf("other vault's backup removed: %v", err)
+	}
+}
@@ -0,0 +1,85 @@
+package vault
+
+import (
+	"fmt"
+	"strings"
+)
+
+// HasTag reports whether a tag list contains the given tag (case-insensitive)
+func HasTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchTags reports whether a credential's tags satisfy a tag filter
+// matchAll requires every filter tag (AND), otherwise any single tag is enough (OR)
+// An empty filter matches everything
+func MatchTags(tags, filter []string, matchAll bool) bool {
+	if len(filter) == 0 {
+		return true
+	}
+
+	for _, wanted := range filter {
+		found := HasTag(tags, wanted)
+		if matchAll && !found {
+			return false
+		}
+		if !matchAll && found {
+			return true
+		}
+	}
+	return matchAll
+}
+
+// MergeTags adds and removes tags while keeping the existing order
+func MergeTags(existing, add, remove []string) []string {
+	merged := make([]string, 0, len(existing)+len(add))
+	for _, tag := range existing {
+		if !HasTag(remove, tag) {
+			merged = append(merged, tag)
+		}
+	}
+	for _, tag := range add {
+		if !HasTag(merged, tag) && !HasTag(remove, tag) {
+			merged = append(merged, tag)
+		}
+	}
+	return merged
+}
+
+// normalizeTags trims tags, drops empty and duplicate entries, and rejects invalid characters
+// Commas are reserved as the separator in tag filters
+func normalizeTags(tags []string) ([]string, error) {
+	if tags == nil {
+		return nil, nil
+	}
+
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			continue
+		}
+		if strings.ContainsAny(tag, ", \t\n") {
+			return nil, fmt.Errorf("%w: tag %q cannot contain commas or whitespace", ErrInvalidCredential, tag)
+		}
+		if !HasTag(normalized, tag) {
+			normalized = append(normalized, tag)
+		}
+	}
+	return normalized, nil
+}
+
+// copyTags returns a copy of the tag list (nil stays nil)
+func copyTags(tags []string) []string {
+	if tags == nil {
+		return nil
+	}
+	copied := make([]string, len(tags))
+	copy(copied, tags)
+	return copied
+}
@@ -0,0 +1,72 @@
+package vault
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestMatchTags(t *testing.T) {
+	tags := []string{"prod", "AWS"}
+
+	tests := []struct {
+		name     string
+		filter   []string
+		matchAll bool
+		want     bool
+	}{
+		{"empty filter", nil, true, true},
+		{"and all present", []string{"prod", "aws"}, true, true},
+		{"and one missing", []string{"prod", "billing"}, true, false},
+		{"or one present", []string{"staging", "aws"}, false, true},
+		{"or none present", []string{"staging", "billing"}, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MatchTags(tags, tt.filter, tt.matchAll); got != tt.want {
+				t.Errorf("MatchTags(%v, %v) = %v, want %v", tags, tt.filter, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeTags(t *testing.T) {
+	got := MergeTags([]string{"prod", "aws", "old"}, []string{"billing", "PROD"}, []string{"old"})
+	want := []string{"prod", "aws", "billing"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("MergeTags() = %v, want %v", got, want)
+	}
+}
+
+func TestCredentialTags(t *testing.T) {
+	vault, _, cleanup := setupUnlockedTestVault(t)
+	defer cleanup()
+
+	opts := AddOpts{Tags: []string{" prod ", "aws", "Prod", ""}}
+	if err := vault.AddCredentialWithOpts("aws-prod", "admin", []byte("pass"), "Cloud", "", "", opts); err != nil {
+		t.Fatalf("AddCredentialWithOpts() failed: %v", err)
+	}
+
+	metadata, err := vault.ListCredentialsWithMetadata()
+	if err != nil {
+		t.Fatalf("ListCredentialsWithMetadata() failed: %v", err)
+	}
+	if want := []string{"prod", "aws"}; !reflect.DeepEqual(metadata[0].Tags, want) {
+		t.Errorf("Tags = %v, want %v", metadata[0].Tags, want)
+	}
+
+	tags := []string{"billing"}
+	if err := vault.UpdateCredential("aws-prod", UpdateOpts{Tags: &tags}); err != nil {
+		t.Fatalf("UpdateCredential() failed: %v", err)
+	}
+	cred, _ := vault.GetCredential("aws-prod", false)
+	if !reflect.DeepEqual(cred.Tags, tags) {
+		t.Errorf("Tags = %v, want %v", cred.Tags, tags)
+	}
+
+	invalid := []string{"a,b"}
+	if err := vault.UpdateCredential("aws-prod", UpdateOpts{Tags: &invalid}); !errors.Is(err, ErrInvalidCredential) {
+		t.Errorf("UpdateCredential() error = %v, want ErrInvalidCredential", err)
+	}
+}
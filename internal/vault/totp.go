@@ -0,0 +1,245 @@
+package vault
+
+import (
+	"crypto/hmac"
+	"crypto/sha1" // #nosec G505 -- SHA-1 is the RFC 6238 default and safe inside HMAC
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// Default TOTP parameters per RFC 6238 and the Google Authenticator key URI format
+	defaultTOTPAlgorithm = "SHA1"
+	defaultTOTPDigits    = 6
+	defaultTOTPPeriod    = 30
+
+	otpauthScheme = "otpauth"
+)
+
+// TOTPConfig holds a time-based one-time password seed and its generation parameters
+type TOTPConfig struct {
+	Secret    string `json:"secret"`            // Base32-encoded shared secret (normalized, no padding)
+	Issuer    string `json:"issuer,omitempty"`  // Issuer from otpauth:// URI (informational)
+	Account   string `json:"account,omitempty"` // Account label from otpauth:// URI (informational)
+	Algorithm string `json:"algorithm"`         // SHA1, SHA256, or SHA512
+	Digits    int    `json:"digits"`            // Code length (6-8)
+	Period    int    `json:"period"`            // Time step in seconds
+}
+
+// ParseTOTP builds a TOTPConfig from either an otpauth:// URI or a raw base32 secret
+func ParseTOTP(input string) (*TOTPConfig, error) {
+	input = strings.TrimSpace(input)
+	if strings.HasPrefix(strings.ToLower(input), otpauthScheme+"://") {
+		return ParseOTPAuthURI(input)
+	}
+
+	cfg := &TOTPConfig{
+		Secret:    input,
+		Algorithm: defaultTOTPAlgorithm,
+		Digits:    defaultTOTPDigits,
+		Period:    defaultTOTPPeriod,
+	}
+	if err := cfg.normalize(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// ParseOTPAuthURI parses a Key URI such as
+// otpauth://totp/Issuer:alice@example.com?secret=JBSWY3DPEHPK3PXP&issuer=Issuer
+func ParseOTPAuthURI(uri string) (*TOTPConfig, error) {
+	u, err := url.Parse(strings.TrimSpace(uri))
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid otpauth URI: %v", ErrInvalidCredential, err)
+	}
+	if !strings.EqualFold(u.Scheme, otpauthScheme) {
+		return nil, fmt.Errorf("%w: URI scheme must be otpauth://", ErrInvalidCredential)
+	}
+	if !strings.EqualFold(u.Host, "totp") {
+		return nil, fmt.Errorf("%w: unsupported OTP type %q (only totp is supported)", ErrInvalidCredential, u.Host)
+	}
+
+	query := u.Query()
+	cfg := &TOTPConfig{
+		Secret:    query.Get("secret"),
+		Issuer:    query.Get("issuer"),
+		Algorithm: defaultTOTPAlgorithm,
+		Digits:    defaultTOTPDigits,
+		Period:    defaultTOTPPeriod,
+	}
+
+	// Label is "Issuer:Account" or just "Account"
+	label := strings.TrimPrefix(u.Path, "/")
+	if issuer, account, ok := strings.Cut(label, ":"); ok {
+		cfg.Account = strings.TrimSpace(account)
+		if cfg.Issuer == "" {
+			cfg.Issuer = strings.TrimSpace(issuer)
+		}
+	} else {
+		cfg.Account = strings.TrimSpace(label)
+	}
+
+	if algorithm := query.Get("algorithm"); algorithm != "" {
+		cfg.Algorithm = algorithm
+	}
+	if digits := query.Get("digits"); digits != "" {
+		cfg.Digits, err = strconv.Atoi(digits)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid TOTP digits %q", ErrInvalidCredential, digits)
+		}
+	}
+	if period := query.Get("period"); period != "" {
+		cfg.Period, err = strconv.Atoi(period)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid TOTP period %q", ErrInvalidCredential, period)
+		}
+	}
+
+	if err := cfg.normalize(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// URI renders the configuration as an otpauth:// Key URI that ParseOTPAuthURI accepts
+func (c *TOTPConfig) URI() string {
+	label := c.Account
+	if c.Issuer != "" {
+		label = c.Issuer + ":" + c.Account
+	}
+
+	query := url.Values{}
+	query.Set("secret", c.Secret)
+	if c.Issuer != "" {
+		query.Set("issuer", c.Issuer)
+	}
+	query.Set("algorithm", c.Algorithm)
+	query.Set("digits", strconv.Itoa(c.digits()))
+	query.Set("period", strconv.Itoa(c.period()))
+
+	u := url.URL{Scheme: otpauthScheme, Host: "totp", Path: "/" + label, RawQuery: query.Encode()}
+	return u.String()
+}
+
+// GenerateCode returns the RFC 6238 code for the time step containing t
+func (c *TOTPConfig) GenerateCode(t time.Time) (string, error) {
+	key, err := decodeTOTPSecret(c.Secret)
+	if err != nil {
+		return "", err
+	}
+	hashFunc, err := totpHash(c.Algorithm)
+	if err != nil {
+		return "", err
+	}
+
+	var counter [8]byte
+	binary.BigEndian.PutUint64(counter[:], uint64(t.Unix())/uint64(c.period())) // #nosec G115 -- Unix time is positive
+
+	mac := hmac.New(hashFunc, key)
+	mac.Write(counter[:])
+	sum := mac.Sum(nil)
+
+	// Dynamic truncation (RFC 4226 section 5.3)
+	offset := sum[len(sum)-1] & 0x0f
+	binCode := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	digits := c.digits()
+	modulus := uint32(1)
+	for i := 0; i < digits; i++ {
+		modulus *= 10
+	}
+
+	return fmt.Sprintf("%0*d", digits, binCode%modulus), nil
+}
+
+// Remaining returns how long the code for time t stays valid
+func (c *TOTPConfig) Remaining(t time.Time) time.Duration {
+	period := int64(c.period())
+	return time.Duration(period-t.Unix()%period) * time.Second
+}
+
+// PeriodDuration returns the time step as a duration
+func (c *TOTPConfig) PeriodDuration() time.Duration {
+	return time.Duration(c.period()) * time.Second
+}
+
+// normalize validates the configuration and canonicalizes the secret and algorithm
+func (c *TOTPConfig) normalize() error {
+	secret := strings.ToUpper(strings.ReplaceAll(c.Secret, " ", ""))
+	secret = strings.TrimRight(secret, "=")
+	if secret == "" {
+		return fmt.Errorf("%w: TOTP secret cannot be empty", ErrInvalidCredential)
+	}
+	if _, err := decodeTOTPSecret(secret); err != nil {
+		return err
+	}
+	c.Secret = secret
+
+	c.Algorithm = strings.ToUpper(strings.ReplaceAll(c.Algorithm, "-", ""))
+	if _, err := totpHash(c.Algorithm); err != nil {
+		return err
+	}
+	if c.Digits < 6 || c.Digits > 8 {
+		return fmt.Errorf("%w: TOTP digits must be between 6 and 8", ErrInvalidCredential)
+	}
+	if c.Period <= 0 {
+		return fmt.Errorf("%w: TOTP period must be positive", ErrInvalidCredential)
+	}
+	return nil
+}
+
+// digits returns the configured code length, falling back to the default
+func (c *TOTPConfig) digits() int {
+	if c.Digits == 0 {
+		return defaultTOTPDigits
+	}
+	return c.Digits
+}
+
+// period returns the configured time step, falling back to the default
+func (c *TOTPConfig) period() int {
+	if c.Period <= 0 {
+		return defaultTOTPPeriod
+	}
+	return c.Period
+}
+
+// copyTOTP returns a copy of the TOTP config (nil stays nil)
+func copyTOTP(cfg *TOTPConfig) *TOTPConfig {
+	if cfg == nil {
+		return nil
+	}
+	copied := *cfg
+	return &copied
+}
+
+// decodeTOTPSecret decodes an unpadded base32 secret
+func decodeTOTPSecret(secret string) ([]byte, error) {
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(strings.TrimRight(secret, "="))
+	if err != nil || len(key) == 0 {
+		return nil, fmt.Errorf("%w: TOTP secret is not valid base32", ErrInvalidCredential)
+	}
+	return key, nil
+}
+
+// totpHash maps an algorithm name to its hash constructor
+func totpHash(algorithm string) (func() hash.Hash, error) {
+	switch algorithm {
+	case "", "SHA1":
+		return sha1.New, nil
+	case "SHA256":
+		return sha256.New, nil
+	case "SHA512":
+		return sha512.New, nil
+	default:
+		return nil, fmt.Errorf("%w: unsupported TOTP algorithm %q", ErrInvalidCredential, algorithm)
+	}
+}
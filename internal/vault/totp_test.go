@@ -0,0 +1,155 @@
+package vault
+
+import (
+	"encoding/base32"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTOTPGenerateCode_RFC6238Vectors(t *testing.T) {
+	// Seeds from RFC 6238 Appendix B (ASCII, base32-encoded here)
+	encode := func(s string) string {
+		return base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte(s))
+	}
+	seeds := map[string]string{
+		"SHA1":   encode("12345678901234567890"),
+		"SHA256": encode("12345678901234567890123456789012"),
+		"SHA512": encode("1234567890123456789012345678901234567890123456789012345678901234"),
+	}
+
+	tests := []struct {
+		unix      int64
+		algorithm string
+		want      string
+	}{
+		{59, "SHA1", "94287082"},
+		{59, "SHA256", "46119246"},
+		{59, "SHA512", "90693936"},
+		{1111111109, "SHA1", "07081804"},
+		{1234567890, "SHA256", "91819424"},
+		{2000000000, "SHA512", "38618901"},
+	}
+
+	for _, tt := range tests {
+		cfg := &TOTPConfig{Secret: seeds[tt.algorithm], Algorithm: tt.algorithm, Digits: 8, Period: 30}
+		got, err := cfg.GenerateCode(time.Unix(tt.unix, 0))
+		if err != nil {
+			t.Fatalf("GenerateCode() failed: %v", err)
+		}
+		if got != tt.want {
+			t.Errorf("GenerateCode(%d, %s) = %s, want %s", tt.unix, tt.algorithm, got, tt.want)
+		}
+	}
+}
+
+func TestTOTPRemaining(t *testing.T) {
+	cfg := &TOTPConfig{Secret: "JBSWY3DPEHPK3PXP", Period: 30}
+	if got := cfg.Remaining(time.Unix(59, 0)); got != time.Second {
+		t.Errorf("Remaining() = %v, want 1s", got)
+	}
+	if got := cfg.Remaining(time.Unix(60, 0)); got != 30*time.Second {
+		t.Errorf("Remaining() = %v, want 30s", got)
+	}
+}
+
+func TestParseOTPAuthURI(t *testing.T) {
+	cfg, err := ParseOTPAuthURI("otpauth://totp/ACME%20Co:alice@example.com?secret=jbsw%20y3dpehpk3pxp&issuer=ACME%20Co&algorithm=SHA256&digits=8&period=60")
+	if err != nil {
+		t.Fatalf("ParseOTPAuthURI() failed: %v", err)
+	}
+
+	want := TOTPConfig{
+		Secret:    "JBSWY3DPEHPK3PXP",
+		Issuer:    "ACME Co",
+		Account:   "alice@example.com",
+		Algorithm: "SHA256",
+		Digits:    8,
+		Period:    60,
+	}
+	if *cfg != want {
+		t.Errorf("ParseOTPAuthURI() = %+v, want %+v", *cfg, want)
+	}
+
+	// URI must round-trip through the parser
+	parsed, err := ParseOTPAuthURI(cfg.URI())
+	if err != nil {
+		t.Fatalf("ParseOTPAuthURI(URI()) failed: %v", err)
+	}
+	if *parsed != want {
+		t.Errorf("ParseOTPAuthURI(%q) = %+v, want %+v", cfg.URI(), *parsed, want)
+	}
+}
+
+func TestParseTOTP_Defaults(t *testing.T) {
+	cfg, err := ParseTOTP("JBSWY3DPEHPK3PXP")
+	if err != nil {
+		t.Fatalf("ParseTOTP() failed: %v", err)
+	}
+	if cfg.Algorithm != "SHA1" || cfg.Digits != 6 || cfg.Period != 30 {
+		t.Errorf("ParseTOTP() defaults = %+v", cfg)
+	}
+}
+
+func TestParseTOTP_Invalid(t *testing.T) {
+	inputs := []string{
+		"",
+		"not base32!",
+		"otpauth://hotp/Example?secret=JBSWY3DPEHPK3PXP&counter=1",
+		"otpauth://totp/Example",
+		"otpauth://totp/Example?secret=JBSWY3DPEHPK3PXP&algorithm=MD5",
+		"otpauth://totp/Example?secret=JBSWY3DPEHPK3PXP&digits=4",
+		"otpauth://totp/Example?secret=JBSWY3DPEHPK3PXP&period=0",
+	}
+
+	for _, input := range inputs {
+		if _, err := ParseTOTP(input); !errors.Is(err, ErrInvalidCredential) {
+			t.Errorf("ParseTOTP(%q) error = %v, want ErrInvalidCredential", input, err)
+		}
+	}
+}
+
+func TestCredentialTOTPLifecycle(t *testing.T) {
+	vault, _, cleanup := setupUnlockedTestVault(t)
+	defer cleanup()
+
+	cfg, err := ParseTOTP("otpauth://totp/GitHub:alice?secret=JBSWY3DPEHPK3PXP")
+	if err != nil {
+		t.Fatalf("ParseTOTP() failed: %v", err)
+	}
+	if err := vault.AddCredentialWithOpts("github", "alice", []byte("pass"), "", "", "", AddOpts{TOTP: cfg}); err != nil {
+		t.Fatalf("AddCredentialWithOpts() failed: %v", err)
+	}
+
+	cred, _ := vault.GetCredential("github", false)
+	if cred.TOTP == nil || cred.TOTP.Secret != "JBSWY3DPEHPK3PXP" || cred.TOTP.Issuer != "GitHub" {
+		t.Fatalf("TOTP = %+v, want GitHub seed", cred.TOTP)
+	}
+
+	// Returned config must be a copy
+	cred.TOTP.Secret = "AAAAAAAA"
+	again, _ := vault.GetCredential("github", false)
+	if again.TOTP.Secret != "JBSWY3DPEHPK3PXP" {
+		t.Error("GetCredential() should return a copy of the TOTP config")
+	}
+
+	// Unrelated updates keep the seed
+	notes := "2FA enabled"
+	if err := vault.UpdateCredential("github", UpdateOpts{Notes: &notes}); err != nil {
+		t.Fatalf("UpdateCredential() failed: %v", err)
+	}
+	again, _ = vault.GetCredential("github", false)
+	if again.TOTP == nil {
+		t.Fatal("TOTP should survive unrelated updates")
+	}
+
+	// Clearing takes precedence over a new seed in the same update
+	newSeed := &TOTPConfig{Secret: "KRUGS4ZANFZSAYLTMVRXEZLU"}
+	if err := vault.UpdateCredential("github", UpdateOpts{ClearTOTP: true, TOTP: newSeed}); err != nil {
+		t.Fatalf("UpdateCredential() failed: %v", err)
+	}
+	again, _ = vault.GetCredential("github", false)
+	if again.TOTP != nil {
+		t.Errorf("TOTP = %+v, want nil after ClearTOTP", again.TOTP)
+	}
+}
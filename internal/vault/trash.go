@@ -0,0 +1,149 @@
+package vault
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"pass-cli/internal/crypto"
+	"pass-cli/internal/security"
+)
+
+// TrashedCredential is a deleted credential kept in the trash
+type TrashedCredential struct {
+	Credential Credential `json:"credential"`
+	DeletedAt  time.Time  `json:"deleted_at"`
+}
+
+// TrashEntry describes a trashed credential without exposing secrets
+type TrashEntry struct {
+	Service   string
+	Username  string
+	Category  string
+	Type      CredentialType
+	DeletedAt time.Time
+}
+
+// ListTrash returns the trashed credentials, most recently deleted first
+func (v *VaultService) ListTrash() ([]TrashEntry, error) {
+	if !v.unlocked {
+		return nil, ErrVaultLocked
+	}
+
+	entries := make([]TrashEntry, 0, len(v.vaultData.Trash))
+	for _, trashed := range v.vaultData.Trash {
+		entries = append(entries, TrashEntry{
+			Service:   trashed.Credential.Service,
+			Username:  trashed.Credential.Username,
+			Category:  trashed.Credential.Category,
+			Type:      trashed.Credential.EffectiveType(),
+			DeletedAt: trashed.DeletedAt,
+		})
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].DeletedAt.After(entries[j].DeletedAt)
+	})
+	return entries, nil
+}
+
+// RestoreCredential moves the most recently deleted credential with the given service back into the vault
+func (v *VaultService) RestoreCredential(service string) error {
+	if !v.unlocked {
+		return ErrVaultLocked
+	}
+
+	index := findTrashed(v.vaultData.Trash, service)
+	if index < 0 {
+		return fmt.Errorf("%w: %s is not in the trash", ErrCredentialNotFound, service)
+	}
+	if _, exists := v.vaultData.Credentials[service]; exists {
+		return fmt.Errorf("%w: %s (rename or delete it before restoring)", ErrCredentialExists, service)
+	}
+
+	previous := v.vaultData.Trash
+	credential := previous[index].Credential
+	trash := make([]TrashedCredential, 0, len(previous)-1)
+	trash = append(trash, previous[:index]...)
+	trash = append(trash, previous[index+1:]...)
+	if len(trash) == 0 {
+		trash = nil
+	}
+
+	v.vaultData.Trash = trash
+	v.vaultData.Credentials[service] = credential
+
+	if err := v.save(); err != nil {
+		// Keep the in-memory vault consistent with disk
+		v.vaultData.Trash = previous
+		delete(v.vaultData.Credentials, service)
+		return err
+	}
+
+	v.logAudit(security.EventCredentialRestore, security.OutcomeSuccess, service)
+	return nil
+}
+
+// PurgeTrash permanently removes trashed credentials deleted more than olderThan ago
+// An olderThan of 0 empties the trash. Returns the number of credentials purged.
+func (v *VaultService) PurgeTrash(olderThan time.Duration) (int, error) {
+	if !v.unlocked {
+		return 0, ErrVaultLocked
+	}
+
+	cutoff := time.Now().Add(-olderThan)
+	kept := make([]TrashedCredential, 0, len(v.vaultData.Trash))
+	purged := make([]TrashedCredential, 0)
+	for _, trashed := range v.vaultData.Trash {
+		if olderThan > 0 && trashed.DeletedAt.After(cutoff) {
+			kept = append(kept, trashed)
+			continue
+		}
+		purged = append(purged, trashed)
+	}
+
+	if len(purged) == 0 {
+		return 0, nil
+	}
+	if len(kept) == 0 {
+		kept = nil
+	}
+
+	previous := v.vaultData.Trash
+	v.vaultData.Trash = kept
+	if err := v.save(); err != nil {
+		v.vaultData.Trash = previous
+		return 0, err
+	}
+
+	for _, trashed := range purged {
+		clearCredentialSecrets(&trashed.Credential)
+		v.logAudit(security.EventCredentialPurge, security.OutcomeSuccess, trashed.Credential.Service)
+	}
+	return len(purged), nil
+}
+
+// findTrashed returns the index of the most recently deleted trash entry for service, or -1
+func findTrashed(trash []TrashedCredential, service string) int {
+	index := -1
+	for i, trashed := range trash {
+		if trashed.Credential.Service != service {
+			continue
+		}
+		if index < 0 || !trashed.DeletedAt.Before(trash[index].DeletedAt) {
+			index = i
+		}
+	}
+	return index
+}
+
+// clearCredentialSecrets zeroes the byte slices holding a credential's secrets
+func clearCredentialSecrets(credential *Credential) {
+	crypto.ClearBytes(credential.Password)
+	for _, entry := range credential.PasswordHistory {
+		crypto.ClearBytes(entry.Password)
+	}
+	for _, attachment := range credential.Attachments {
+		crypto.ClearBytes(attachment.Data)
+	}
+}
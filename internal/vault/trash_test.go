@@ -0,0 +1,120 @@
+package vault
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTrashRestore(t *testing.T) {
+	vault, vaultPath, cleanup := setupUnlockedTestVault(t)
+	defer cleanup()
+
+	if err := vault.AddCredential("github", "user", []byte("pass"), "Code", "", ""); err != nil {
+		t.Fatalf("AddCredential() failed: %v", err)
+	}
+	if err := vault.DeleteCredential("github"); err != nil {
+		t.Fatalf("DeleteCredential() failed: %v", err)
+	}
+
+	if _, err := vault.GetCredential("github", false); !errors.Is(err, ErrCredentialNotFound) {
+		t.Fatalf("expected deleted credential to be hidden, got %v", err)
+	}
+
+	entries, err := vault.ListTrash()
+	if err != nil {
+		t.Fatalf("ListTrash() failed: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Service != "github" || entries[0].Username != "user" {
+		t.Fatalf("ListTrash() = %+v", entries)
+	}
+
+	// The trash survives a reload
+	reopened, err := New(vaultPath)
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	if err := reopened.Unlock([]byte("TestPassword123!")); err != nil {
+		t.Fatalf("Unlock() failed: %v", err)
+	}
+	if entries, _ := reopened.ListTrash(); len(entries) != 1 {
+		t.Fatalf("expected 1 trashed credential after reload, got %d", len(entries))
+	}
+
+	// Restoring onto an existing service is refused
+	if err := vault.AddCredential("github", "other", []byte("pass2"), "", "", ""); err != nil {
+		t.Fatalf("AddCredential() failed: %v", err)
+	}
+	if err := vault.RestoreCredential("github"); !errors.Is(err, ErrCredentialExists) {
+		t.Fatalf("expected ErrCredentialExists, got %v", err)
+	}
+	if err := vault.DeleteCredential("github"); err != nil {
+		t.Fatalf("DeleteCredential() failed: %v", err)
+	}
+
+	// The most recently deleted copy is restored first
+	if err := vault.RestoreCredential("github"); err != nil {
+		t.Fatalf("RestoreCredential() failed: %v", err)
+	}
+	cred, err := vault.GetCredential("github", false)
+	if err != nil {
+		t.Fatalf("GetCredential() failed: %v", err)
+	}
+	if cred.Username != "other" {
+		t.Errorf("restored Username = %q, want %q", cred.Username, "other")
+	}
+
+	entries, _ = vault.ListTrash()
+	if len(entries) != 1 || entries[0].Username != "user" {
+		t.Errorf("expected the older copy to remain in the trash, got %+v", entries)
+	}
+
+	if err := vault.RestoreCredential("missing"); !errors.Is(err, ErrCredentialNotFound) {
+		t.Errorf("expected ErrCredentialNotFound, got %v", err)
+	}
+}
+
+func TestPurgeTrash(t *testing.T) {
+	vault, _, cleanup := setupUnlockedTestVault(t)
+	defer cleanup()
+
+	for _, service := range []string{"old", "recent"} {
+		if err := vault.AddCredential(service, "user", []byte("pass"), "", "", ""); err != nil {
+			t.Fatalf("AddCredential() failed: %v", err)
+		}
+		if err := vault.DeleteCredential(service); err != nil {
+			t.Fatalf("DeleteCredential() failed: %v", err)
+		}
+	}
+
+	// Backdate one entry
+	for i := range vault.vaultData.Trash {
+		if vault.vaultData.Trash[i].Credential.Service == "old" {
+			vault.vaultData.Trash[i].DeletedAt = time.Now().Add(-45 * 24 * time.Hour)
+		}
+	}
+
+	purged, err := vault.PurgeTrash(30 * 24 * time.Hour)
+	if err != nil {
+		t.Fatalf("PurgeTrash() failed: %v", err)
+	}
+	if purged != 1 {
+		t.Errorf("PurgeTrash(30d) purged %d, want 1", purged)
+	}
+
+	entries, _ := vault.ListTrash()
+	if len(entries) != 1 || entries[0].Service != "recent" {
+		t.Fatalf("expected only 'recent' in the trash, got %+v", entries)
+	}
+
+	purged, err = vault.PurgeTrash(0)
+	if err != nil {
+		t.Fatalf("PurgeTrash(0) failed: %v", err)
+	}
+	if purged != 1 {
+		t.Errorf("PurgeTrash(0) purged %d, want 1", purged)
+	}
+	if entries, _ := vault.ListTrash(); len(entries) != 0 {
+		t.Errorf("expected empty trash, got %+v", entries)
+	}
+}
@@ -0,0 +1,219 @@
+package vault
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// CredentialType selects the template a credential was created from
+// The empty type is treated as TypeLogin so existing vaults keep working
+type CredentialType string
+
+const (
+	TypeLogin      CredentialType = "login"
+	TypeAPIKey     CredentialType = "api-key"
+	TypeSSHKey     CredentialType = "ssh-key"
+	TypeDatabase   CredentialType = "database"
+	TypeSecureNote CredentialType = "secure-note"
+)
+
+// TemplateField is a custom field a credential type expects
+type TemplateField struct {
+	Key      string // Custom field key
+	Label    string // Prompt and form label
+	Secret   bool   // Store as a secret custom field
+	Required bool   // Must be non-empty when adding a credential
+}
+
+// CredentialTemplate describes the fields expected for a credential type
+type CredentialTemplate struct {
+	Type             CredentialType
+	Label            string          // Display name
+	UsernameLabel    string          // Label for the username field ("" = not used)
+	UsernameRequired bool            // Whether forms and prompts require a username
+	PasswordLabel    string          // Label for the password field ("" = not used)
+	PasswordRequired bool            // Whether an empty password is rejected
+	Fields           []TemplateField // Type-specific custom fields, in prompt order
+}
+
+// credentialTemplates lists the built-in templates in display order
+var credentialTemplates = []CredentialTemplate{
+	{
+		Type:             TypeLogin,
+		Label:            "Login",
+		UsernameLabel:    "Username",
+		UsernameRequired: true,
+		PasswordLabel:    "Password",
+		PasswordRequired: true,
+	},
+	{
+		Type:             TypeAPIKey,
+		Label:            "API Key",
+		UsernameLabel:    "Key ID",
+		PasswordLabel:    "Secret",
+		PasswordRequired: true,
+		Fields: []TemplateField{
+			{Key: "scopes", Label: "Scopes"},
+		},
+	},
+	{
+		Type:             TypeSSHKey,
+		Label:            "SSH Key",
+		UsernameLabel:    "User",
+		UsernameRequired: true,
+		PasswordLabel:    "Passphrase",
+		Fields: []TemplateField{
+			{Key: "host", Label: "Host", Required: true},
+			{Key: "port", Label: "Port"},
+			{Key: "fingerprint", Label: "Fingerprint"},
+		},
+	},
+	{
+		Type:             TypeDatabase,
+		Label:            "Database",
+		UsernameLabel:    "Username",
+		UsernameRequired: true,
+		PasswordLabel:    "Password",
+		PasswordRequired: true,
+		Fields: []TemplateField{
+			{Key: "host", Label: "Host", Required: true},
+			{Key: "port", Label: "Port"},
+			{Key: "database", Label: "Database", Required: true},
+		},
+	},
+	{
+		Type:  TypeSecureNote,
+		Label: "Secure Note",
+	},
+}
+
+// CredentialTemplates returns the built-in templates in display order
+func CredentialTemplates() []CredentialTemplate {
+	templates := make([]CredentialTemplate, len(credentialTemplates))
+	copy(templates, credentialTemplates)
+	return templates
+}
+
+// GetTemplate returns the template for a credential type (empty = login)
+func GetTemplate(t CredentialType) (CredentialTemplate, bool) {
+	if t == "" {
+		t = TypeLogin
+	}
+	for _, template := range credentialTemplates {
+		if template.Type == t {
+			return template, true
+		}
+	}
+	return CredentialTemplate{}, false
+}
+
+// ParseCredentialType converts user input to a credential type
+// Accepts type names, labels, and short aliases such as "api", "ssh", "db", and "note"
+func ParseCredentialType(value string) (CredentialType, error) {
+	normalized := strings.ToLower(strings.TrimSpace(value))
+	normalized = strings.NewReplacer("_", "-", " ", "-").Replace(normalized)
+
+	switch normalized {
+	case "", "login":
+		return TypeLogin, nil
+	case "api-key", "apikey", "api":
+		return TypeAPIKey, nil
+	case "ssh-key", "sshkey", "ssh":
+		return TypeSSHKey, nil
+	case "database", "db":
+		return TypeDatabase, nil
+	case "secure-note", "securenote", "note":
+		return TypeSecureNote, nil
+	}
+
+	names := make([]string, 0, len(credentialTemplates))
+	for _, template := range credentialTemplates {
+		names = append(names, string(template.Type))
+	}
+	return "", fmt.Errorf("%w: unknown credential type %q (valid: %s)",
+		ErrInvalidCredential, value, strings.Join(names, ", "))
+}
+
+// EffectiveType returns the credential's type, treating the empty type as login
+func (c *Credential) EffectiveType() CredentialType {
+	if c.Type == "" {
+		return TypeLogin
+	}
+	return c.Type
+}
+
+// EffectiveType returns the listed credential's type, treating the empty type as login
+func (m CredentialMetadata) EffectiveType() CredentialType {
+	if m.Type == "" {
+		return TypeLogin
+	}
+	return m.Type
+}
+
+// MissingFields returns the labels of required template fields without a value
+func (t CredentialTemplate) MissingFields(fields []CustomField) []string {
+	var missing []string
+	for _, field := range t.Fields {
+		if !field.Required {
+			continue
+		}
+		if f, ok := FindCustomField(fields, field.Key); !ok || strings.TrimSpace(f.Value) == "" {
+			missing = append(missing, field.Label)
+		}
+	}
+	return missing
+}
+
+// validateTemplate checks a new credential against its type's template
+func validateTemplate(t CredentialType, username string, password []byte, fields []CustomField) error {
+	template, ok := GetTemplate(t)
+	if !ok {
+		return fmt.Errorf("%w: unknown credential type %q", ErrInvalidCredential, t)
+	}
+	if err := validateTemplateUsername(template, username); err != nil {
+		return err
+	}
+	return validateTemplateValues(template, password, fields)
+}
+
+// validateTemplateUsername checks the username requirement of a template
+func validateTemplateUsername(template CredentialTemplate, username string) error {
+	if template.UsernameRequired && strings.TrimSpace(username) == "" {
+		return fmt.Errorf("%w: %s cannot be empty", ErrInvalidCredential, strings.ToLower(template.UsernameLabel))
+	}
+	return nil
+}
+
+// validateTemplateValues checks the password and required fields of a template
+func validateTemplateValues(template CredentialTemplate, password []byte, fields []CustomField) error {
+	if template.PasswordRequired && len(password) == 0 {
+		return fmt.Errorf("%w: %s cannot be empty", ErrInvalidCredential, strings.ToLower(template.PasswordLabel))
+	}
+	if missing := template.MissingFields(fields); len(missing) > 0 {
+		return fmt.Errorf("%w: %s requires %s", ErrInvalidCredential, template.Label, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// validateTemplateChange checks an edited credential against its (possibly new)
+// type's template. Only requirements the edit touched are checked, so credentials
+// that predate a requirement stay editable until their type or those fields change.
+func validateTemplateChange(before, after Credential) error {
+	template, ok := GetTemplate(after.Type)
+	if !ok {
+		return fmt.Errorf("%w: unknown credential type %q", ErrInvalidCredential, after.Type)
+	}
+	typeChanged := before.EffectiveType() != after.EffectiveType()
+
+	if typeChanged || before.Username != after.Username {
+		if err := validateTemplateUsername(template, after.Username); err != nil {
+			return err
+		}
+	}
+	if typeChanged || !bytes.Equal(before.Password, after.Password) || !slices.Equal(before.CustomFields, after.CustomFields) {
+		return validateTemplateValues(template, after.Password, after.CustomFields)
+	}
+	return nil
+}
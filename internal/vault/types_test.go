@@ -0,0 +1,159 @@
+package vault
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseCredentialType(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    CredentialType
+		wantErr bool
+	}{
+		{"", TypeLogin, false},
+		{"login", TypeLogin, false},
+		{"API Key", TypeAPIKey, false},
+		{"ssh", TypeSSHKey, false},
+		{"DB", TypeDatabase, false},
+		{"secure_note", TypeSecureNote, false},
+		{"wallet", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseCredentialType(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseCredentialType(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseCredentialType(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTemplateMissingFields(t *testing.T) {
+	template, ok := GetTemplate(TypeDatabase)
+	if !ok {
+		t.Fatal("database template not found")
+	}
+
+	missing := template.MissingFields([]CustomField{{Key: "Host", Value: "db.internal"}, {Key: "database", Value: " "}})
+	if len(missing) != 1 || missing[0] != "Database" {
+		t.Errorf("MissingFields() = %v, want [Database]", missing)
+	}
+}
+
+func TestCredentialTypes(t *testing.T) {
+	vault, _, cleanup := setupUnlockedTestVault(t)
+	defer cleanup()
+
+	// Required template fields are enforced
+	opts := AddOpts{Type: TypeDatabase, CustomFields: []CustomField{{Key: "database", Value: "app"}}}
+	err := vault.AddCredentialWithOpts("orders-db", "app", []byte("pass"), "", "", "", opts)
+	if !errors.Is(err, ErrInvalidCredential) {
+		t.Fatalf("expected ErrInvalidCredential for missing host, got %v", err)
+	}
+
+	opts.CustomFields = append(opts.CustomFields, CustomField{Key: "host", Value: "db.internal"})
+	if err := vault.AddCredentialWithOpts("orders-db", "app", []byte("pass"), "", "", "", opts); err != nil {
+		t.Fatalf("AddCredentialWithOpts() failed: %v", err)
+	}
+
+	// Secure notes do not need a password
+	if err := vault.AddCredentialWithOpts("recovery-codes", "", nil, "", "", "codes", AddOpts{Type: TypeSecureNote}); err != nil {
+		t.Fatalf("AddCredentialWithOpts() secure note failed: %v", err)
+	}
+
+	// Logins still require one
+	if err := vault.AddCredentialWithOpts("github", "user", nil, "", "", "", AddOpts{}); !errors.Is(err, ErrInvalidCredential) {
+		t.Errorf("expected ErrInvalidCredential for empty login password, got %v", err)
+	}
+	// ...and a username, like the add prompt and form do
+	if err := vault.AddCredentialWithOpts("github", " ", []byte("pass"), "", "", "", AddOpts{}); !errors.Is(err, ErrInvalidCredential) {
+		t.Errorf("expected ErrInvalidCredential for empty login username, got %v", err)
+	}
+	// API keys have an optional key ID
+	if err := vault.AddCredentialWithOpts("stripe", "", []byte("sk_live"), "", "", "", AddOpts{Type: TypeAPIKey}); err != nil {
+		t.Errorf("AddCredentialWithOpts() API key without key ID failed: %v", err)
+	}
+
+	cred, err := vault.GetCredential("orders-db", false)
+	if err != nil {
+		t.Fatalf("GetCredential() failed: %v", err)
+	}
+	if cred.EffectiveType() != TypeDatabase {
+		t.Errorf("Type = %q, want %q", cred.EffectiveType(), TypeDatabase)
+	}
+
+	// Type can be changed and unknown types are rejected
+	note := TypeSecureNote
+	if err := vault.UpdateCredential("orders-db", UpdateOpts{Type: &note}); err != nil {
+		t.Fatalf("UpdateCredential() failed: %v", err)
+	}
+	unknown := CredentialType("wallet")
+	if err := vault.UpdateCredential("orders-db", UpdateOpts{Type: &unknown}); !errors.Is(err, ErrInvalidCredential) {
+		t.Errorf("expected ErrInvalidCredential for unknown type, got %v", err)
+	}
+
+	metadata, err := vault.ListCredentialsWithMetadata()
+	if err != nil {
+		t.Fatalf("ListCredentialsWithMetadata() failed: %v", err)
+	}
+	for _, meta := range metadata {
+		if meta.Service == "orders-db" && meta.Type != TypeSecureNote {
+			t.Errorf("metadata Type = %q, want %q", meta.Type, TypeSecureNote)
+		}
+	}
+}
+
+func TestUpdateCredentialTypeRequirements(t *testing.T) {
+	vault, _, cleanup := setupUnlockedTestVault(t)
+	defer cleanup()
+
+	if err := vault.AddCredentialWithOpts("notes", "", nil, "", "", "text", AddOpts{Type: TypeSecureNote}); err != nil {
+		t.Fatalf("AddCredentialWithOpts() failed: %v", err)
+	}
+	if err := vault.AddCredential("server", "root", []byte("pass"), "", "", ""); err != nil {
+		t.Fatalf("AddCredential() failed: %v", err)
+	}
+
+	// A secure note has no password, which a login requires
+	login := TypeLogin
+	if err := vault.UpdateCredential("notes", UpdateOpts{Type: &login}); !errors.Is(err, ErrInvalidCredential) {
+		t.Errorf("expected ErrInvalidCredential for a login without password, got %v", err)
+	}
+
+	// An SSH key needs a host
+	ssh := TypeSSHKey
+	if err := vault.UpdateCredential("server", UpdateOpts{Type: &ssh}); !errors.Is(err, ErrInvalidCredential) {
+		t.Errorf("expected ErrInvalidCredential for an SSH key without host, got %v", err)
+	}
+	fields := []CustomField{{Key: "host", Value: "server.internal"}}
+	if err := vault.UpdateCredential("server", UpdateOpts{Type: &ssh, CustomFields: &fields}); err != nil {
+		t.Fatalf("UpdateCredential() with the required field failed: %v", err)
+	}
+
+	// Clearing a required username is rejected
+	empty := ""
+	if err := vault.UpdateCredential("server", UpdateOpts{Username: &empty}); !errors.Is(err, ErrInvalidCredential) {
+		t.Errorf("expected ErrInvalidCredential for an empty SSH user, got %v", err)
+	}
+
+	// Rejected updates leave the credential unchanged
+	cred, err := vault.GetCredential("notes", false)
+	if err != nil {
+		t.Fatalf("GetCredential() failed: %v", err)
+	}
+	if cred.EffectiveType() != TypeSecureNote {
+		t.Errorf("Type = %q after a rejected update, want %q", cred.EffectiveType(), TypeSecureNote)
+	}
+
+	// Edits that do not touch a requirement still work on credentials that predate it
+	vault.vaultData.Credentials["legacy"] = Credential{Service: "legacy", Password: []byte("pass")}
+	notes := "imported without a username"
+	if err := vault.UpdateCredential("legacy", UpdateOpts{Notes: &notes}); err != nil {
+		t.Errorf("UpdateCredential() of an unrelated field failed: %v", err)
+	}
+}
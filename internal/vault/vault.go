@@ -1,6 +1,7 @@
 package vault
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -39,16 +40,25 @@ type UsageRecord struct {
 // Credential represents a stored credential with usage tracking
 // T020c: Password field changed from string to []byte for secure memory handling
 type Credential struct {
-	Service      string                 `json:"service"`
-	Username     string                 `json:"username"`
-	Password     []byte                 `json:"password"` // T020c: Changed to []byte for memory security
-	Category     string                 `json:"category,omitempty"`
-	URL          string                 `json:"url,omitempty"`
-	Notes        string                 `json:"notes"`
-	CreatedAt    time.Time              `json:"created_at"`
-	UpdatedAt    time.Time              `json:"updated_at"`
-	ModifiedCount int                   `json:"modified_count"` // Number of times credential has been modified
-	UsageRecord  map[string]UsageRecord `json:"usage_records"`  // Map of location -> UsageRecord
+	Service           string                 `json:"service"`
+	Username          string                 `json:"username"`
+	Password          []byte                 `json:"password"`       // T020c: Changed to []byte for memory security
+	Type              CredentialType         `json:"type,omitempty"` // Template the credential follows (empty = login)
+	Category          string                 `json:"category,omitempty"`
+	Tags              []string               `json:"tags,omitempty"` // Free-form labels; a credential can have many
+	URL               string                 `json:"url,omitempty"`
+	Notes             string                 `json:"notes"`
+	CustomFields      []CustomField          `json:"custom_fields,omitempty"`    // Ordered user-defined key/value fields
+	TOTP              *TOTPConfig            `json:"totp,omitempty"`             // Optional 2FA seed for RFC 6238 codes
+	PasswordHistory   []PasswordHistoryEntry `json:"password_history,omitempty"` // Previous passwords, newest first
+	ExpiresAt         *time.Time             `json:"expires_at,omitempty"`       // Optional hard expiry date
+	RotationDays      int                    `json:"rotation_days,omitempty"`    // Optional rotation interval from the last password change
+	PasswordChangedAt time.Time              `json:"password_changed_at"`        // When the password was last set (zero for legacy vaults)
+	Attachments       []Attachment           `json:"attachments,omitempty"`      // Encrypted files such as keys and certificates
+	CreatedAt         time.Time              `json:"created_at"`
+	UpdatedAt         time.Time              `json:"updated_at"`
+	ModifiedCount     int                    `json:"modified_count"` // Number of times credential has been modified
+	UsageRecord       map[string]UsageRecord `json:"usage_records"`  // Map of location -> UsageRecord
 }
 
 // VaultData is the decrypted vault structure
@@ -59,6 +69,16 @@ type VaultData struct {
 	AuditEnabled bool   `json:"audit_enabled,omitempty"` // Whether audit logging is enabled
 	AuditLogPath string `json:"audit_log_path,omitempty"` // Path to audit log file
 	VaultID      string `json:"vault_id,omitempty"`       // Vault identifier for audit key
+	// Password history retention (0 = default, -1 = disabled)
+	PasswordHistoryLimit int `json:"password_history_limit,omitempty"`
+	// Deleted credentials kept until restored or purged
+	Trash []TrashedCredential `json:"trash,omitempty"`
+	// Credential versions at the last sync, used as the common ancestor by Sync
+	SyncBase *SyncSnapshot `json:"sync_base,omitempty"`
+	// X25519 identity ("AGE-SECRET-KEY-1...") that other users share credentials to
+	ShareIdentity string `json:"share_identity,omitempty"`
+	// Share bundles sent to and received from other users
+	Shares []ShareRecord `json:"shares,omitempty"`
 }
 
 // VaultService manages credentials with encryption and keychain integration
@@ -73,6 +93,13 @@ type VaultService struct {
 	masterPassword []byte // Byte array for secure memory clearing (T009)
 	vaultData      *VaultData
 
+	// Key that encrypts the vault payload, and the key slot and secret that
+	// unwrapped it. Saves use the key directly, without key derivation.
+	dataKey      []byte
+	unlockedSlot int // 0 for version 1 vaults, which have no key slots
+	slotSecret   []byte
+	keyFile      string // Combined with the password by slots that require a key file
+
 	// T066: Audit logging configuration (FR-025: default disabled)
 	auditEnabled bool
 	auditLogger  *security.AuditLogger
@@ -130,8 +157,9 @@ func (v *VaultService) EnableAudit(auditLogPath, vaultID string) error {
 		v.vaultData.AuditEnabled = true
 		v.vaultData.AuditLogPath = auditLogPath
 		v.vaultData.VaultID = vaultID
-		// Save vault data to persist audit configuration
-		if err := v.save(); err != nil {
+		// Save vault data to persist audit configuration (runs on every unlock,
+		// so it takes no snapshot)
+		if err := v.saveWithoutSnapshot(); err != nil {
 			return fmt.Errorf("failed to persist audit configuration: %w", err)
 		}
 	}
@@ -170,8 +198,18 @@ func (v *VaultService) logAudit(eventType, outcome, credentialName string) {
 // T045: Added password policy validation (FR-016)
 // DISC-013 fix: Added audit parameters to set config during initialization
 func (v *VaultService) Initialize(masterPassword []byte, useKeychain bool, auditLogPath, vaultID string) error {
+	return v.InitializeWithKDF(masterPassword, useKeychain, auditLogPath, vaultID, crypto.DefaultKDFParams())
+}
+
+// InitializeWithKDF creates a new vault whose key is derived with the given
+// parameters, such as ones calibrated by CryptoService.BenchmarkKDF
+func (v *VaultService) InitializeWithKDF(masterPassword []byte, useKeychain bool, auditLogPath, vaultID string, params crypto.KDFParams) error {
 	defer crypto.ClearBytes(masterPassword) // T014: Ensure cleanup even on error
 
+	if err := params.Validate(); err != nil {
+		return err
+	}
+
 	// T045 [US3]: Validate master password against policy (FR-016)
 	// Import security package required at top of file
 	passwordPolicy := &security.PasswordPolicy{
@@ -229,13 +267,22 @@ func (v *VaultService) Initialize(masterPassword []byte, useKeychain bool, audit
 	// Convert to string for storage service (TODO: Phase 4 will update storage.go to accept []byte)
 	masterPasswordStr := string(masterPassword)
 
+	// With a key file set, the vault needs both the password and the key file
+	slotType, secret, err := v.passwordSlotSecret(masterPassword, v.keyFile)
+	if err != nil {
+		return err
+	}
+	defer crypto.ClearBytes(secret)
+
 	// Initialize storage (creates directory and vault file)
-	if err := v.storageService.InitializeVault(masterPasswordStr); err != nil {
+	dataKey, err := v.storageService.InitializeVaultWithKeySlot(slotType, secret, params)
+	if err != nil {
 		return fmt.Errorf("failed to initialize vault: %w", err)
 	}
+	defer crypto.ClearBytes(dataKey)
 
 	// Save initial empty vault
-	if err := v.storageService.SaveVault(data, masterPasswordStr); err != nil {
+	if err := v.storageService.SaveVaultWithKey(data, dataKey); err != nil {
 		return fmt.Errorf("failed to save initial vault: %w", err)
 	}
 
@@ -263,6 +310,92 @@ func (v *VaultService) Unlock(masterPassword []byte) error {
 		return nil // Already unlocked
 	}
 
+	if err := v.recoverIncompleteMigration(); err != nil {
+		return err
+	}
+
+	dataKey, slotID, slotSecret, err := v.openPasswordSlot(masterPassword)
+	if err != nil {
+		// T068: Log unlock failure (FR-019)
+		v.logAudit(security.EventVaultUnlock, security.OutcomeFailure, "")
+		return fmt.Errorf("failed to unlock vault: %w", err)
+	}
+	defer crypto.ClearBytes(slotSecret)
+
+	if err := v.unlockWithDataKey(dataKey, slotID, slotSecret); err != nil {
+		return err
+	}
+
+	// Keep a copy since we're clearing the parameter
+	v.masterPassword = make([]byte, len(masterPassword))
+	copy(v.masterPassword, masterPassword)
+	return nil
+}
+
+// unlockWithDataKey decrypts the vault payload with a key opened from slotID
+// and loads the credentials into memory. The key is kept for saving.
+func (v *VaultService) unlockWithDataKey(dataKey []byte, slotID int, slotSecret []byte) error {
+	if err := v.loadWithDataKey(dataKey, slotID, slotSecret); err != nil {
+		// T068: Log unlock failure (FR-019)
+		v.logAudit(security.EventVaultUnlock, security.OutcomeFailure, "")
+		return fmt.Errorf("failed to unlock vault: %w", err)
+	}
+
+	// DISC-013 fix: Restore audit logging if it was enabled
+	vaultData := v.vaultData
+	if vaultData.AuditEnabled && vaultData.AuditLogPath != "" && vaultData.VaultID != "" {
+		if err := v.EnableAudit(vaultData.AuditLogPath, vaultData.VaultID); err != nil {
+			// Log warning but don't fail unlock - audit logging is optional
+			fmt.Fprintf(os.Stderr, "Warning: failed to restore audit logging: %v\n", err)
+		}
+	}
+
+	// T036f: Remove backup file after successful unlock
+	// This confirms the vault is readable and migration (if any) was successful
+	backupPath := v.vaultPath + storage.BackupSuffix
+	if _, err := os.Stat(backupPath); err == nil {
+		if err := os.Remove(backupPath); err != nil {
+			// Log warning but don't fail unlock - backup cleanup is not critical
+			fmt.Fprintf(os.Stderr, "Warning: failed to remove backup file: %v\n", err)
+		}
+	}
+
+	// T068: Log unlock success (FR-019)
+	v.logAudit(security.EventVaultUnlock, security.OutcomeSuccess, "")
+
+	return nil
+}
+
+// loadWithDataKey decrypts the vault payload and loads it into memory without
+// writing anything. The data key is taken over (and cleared on failure).
+func (v *VaultService) loadWithDataKey(dataKey []byte, slotID int, slotSecret []byte) error {
+	data, err := v.storageService.LoadVaultWithKey(dataKey)
+	if err != nil {
+		crypto.ClearBytes(dataKey)
+		return err
+	}
+	defer crypto.ClearBytes(data)
+
+	// Unmarshal vault data
+	var vaultData VaultData
+	if err := json.Unmarshal(data, &vaultData); err != nil {
+		crypto.ClearBytes(dataKey)
+		return fmt.Errorf("failed to parse vault data: %w", err)
+	}
+
+	// Store in memory (make a copy of the secret since the caller clears it)
+	v.unlocked = true
+	v.vaultData = &vaultData
+	v.dataKey = dataKey
+	v.unlockedSlot = slotID
+	v.slotSecret = make([]byte, len(slotSecret))
+	copy(v.slotSecret, slotSecret)
+	return nil
+}
+
+// recoverIncompleteMigration restores the backup left by a save that was
+// interrupted before the new vault file was in place
+func (v *VaultService) recoverIncompleteMigration() error {
 	// T036e: Check for incomplete migration (vault.tmp exists)
 	vaultTmpPath := v.vaultPath + storage.TempSuffix
 	vaultBackupPath := v.vaultPath + storage.BackupSuffix
@@ -299,51 +432,6 @@ func (v *VaultService) Unlock(masterPassword []byte) error {
 			fmt.Fprintf(os.Stderr, "If you experience issues, please report this immediately.\n\n")
 		}
 	}
-
-	// Convert to string for storage service (TODO: Phase 4 will update storage.go to accept []byte)
-	masterPasswordStr := string(masterPassword)
-
-	// Try to load vault
-	data, err := v.storageService.LoadVault(masterPasswordStr)
-	if err != nil {
-		// T068: Log unlock failure (FR-019)
-		v.logAudit(security.EventVaultUnlock, security.OutcomeFailure, "")
-		return fmt.Errorf("failed to unlock vault: %w", err)
-	}
-
-	// Unmarshal vault data
-	var vaultData VaultData
-	if err := json.Unmarshal(data, &vaultData); err != nil {
-		return fmt.Errorf("failed to parse vault data: %w", err)
-	}
-
-	// Store in memory (make a copy since we're clearing the parameter)
-	v.unlocked = true
-	v.masterPassword = make([]byte, len(masterPassword))
-	copy(v.masterPassword, masterPassword)
-	v.vaultData = &vaultData
-
-	// DISC-013 fix: Restore audit logging if it was enabled
-	if vaultData.AuditEnabled && vaultData.AuditLogPath != "" && vaultData.VaultID != "" {
-		if err := v.EnableAudit(vaultData.AuditLogPath, vaultData.VaultID); err != nil {
-			// Log warning but don't fail unlock - audit logging is optional
-			fmt.Fprintf(os.Stderr, "Warning: failed to restore audit logging: %v\n", err)
-		}
-	}
-
-	// T036f: Remove backup file after successful unlock
-	// This confirms the vault is readable and migration (if any) was successful
-	backupPath := v.vaultPath + storage.BackupSuffix
-	if _, err := os.Stat(backupPath); err == nil {
-		if err := os.Remove(backupPath); err != nil {
-			// Log warning but don't fail unlock - backup cleanup is not critical
-			fmt.Fprintf(os.Stderr, "Warning: failed to remove backup file: %v\n", err)
-		}
-	}
-
-	// T068: Log unlock success (FR-019)
-	v.logAudit(security.EventVaultUnlock, security.OutcomeSuccess, "")
-
 	return nil
 }
 
@@ -375,10 +463,20 @@ func (v *VaultService) Lock() {
 		crypto.ClearBytes(v.masterPassword)
 		v.masterPassword = nil
 	}
+	crypto.ClearBytes(v.dataKey)
+	crypto.ClearBytes(v.slotSecret)
+	v.dataKey = nil
+	v.slotSecret = nil
+	v.unlockedSlot = 0
 
 	v.vaultData = nil
 }
 
+// Path returns the vault file path
+func (v *VaultService) Path() string {
+	return v.vaultPath
+}
+
 // IsUnlocked returns whether the vault is currently unlocked
 func (v *VaultService) IsUnlocked() bool {
 	return v.unlocked
@@ -395,20 +493,53 @@ func (v *VaultService) save() error {
 		return fmt.Errorf("failed to marshal vault data: %w", err)
 	}
 
-	// Convert to string for storage service (TODO: Phase 4 will update storage.go to accept []byte)
-	masterPasswordStr := string(v.masterPassword)
+	if err := v.storageService.SaveVaultWithKey(data, v.dataKey); err != nil {
+		return fmt.Errorf("failed to save vault: %w", err)
+	}
+
+	return nil
+}
+
+// saveWithoutSnapshot persists changes that leave the credentials alone, such
+// as usage tracking and audit settings, without taking a snapshot
+func (v *VaultService) saveWithoutSnapshot() error {
+	if !v.unlocked {
+		return ErrVaultLocked
+	}
+
+	data, err := json.Marshal(v.vaultData)
+	if err != nil {
+		return fmt.Errorf("failed to marshal vault data: %w", err)
+	}
 
-	if err := v.storageService.SaveVault(data, masterPasswordStr); err != nil {
+	if err := v.storageService.SaveVaultWithKeyNoSnapshot(data, v.dataKey); err != nil {
 		return fmt.Errorf("failed to save vault: %w", err)
 	}
 
 	return nil
 }
 
+// AddOpts contains optional fields for creating a credential
+// Zero values mean "not set"
+type AddOpts struct {
+	Type         CredentialType // Empty = login
+	CustomFields []CustomField
+	TOTP         *TOTPConfig
+	Tags         []string
+	ExpiresAt    time.Time // Zero = no expiry date
+	RotationDays int       // Zero = no rotation interval
+}
+
 // AddCredential adds a new credential to the vault
 // T020d: Password parameter changed to []byte for memory security
 // T020e: Added deferred cleanup for password parameter
 func (v *VaultService) AddCredential(service, username string, password []byte, category, url, notes string) error {
+	return v.AddCredentialWithOpts(service, username, password, category, url, notes, AddOpts{})
+}
+
+// AddCredentialWithOpts adds a new credential with optional extended fields
+// Password is cleared after use, same as AddCredential
+func (v *VaultService) AddCredentialWithOpts(service, username string, password []byte, category, url, notes string, opts AddOpts) error {
 	defer crypto.ClearBytes(password) // T020e: Ensure cleanup even on error
 
 	if !v.unlocked {
@@ -416,11 +547,27 @@ func (v *VaultService) AddCredential(service, username string, password []byte,
 	}
 
 	// Validate inputs
-	if service == "" {
-		return fmt.Errorf("%w: service name cannot be empty", ErrInvalidCredential)
+	if err := validateServiceName(service); err != nil {
+		return err
 	}
-	if len(password) == 0 {
-		return fmt.Errorf("%w: password cannot be empty", ErrInvalidCredential)
+
+	if err := validateCustomFields(opts.CustomFields); err != nil {
+		return err
+	}
+	if err := validateTemplate(opts.Type, username, password, opts.CustomFields); err != nil {
+		return err
+	}
+	if opts.TOTP != nil {
+		if err := opts.TOTP.normalize(); err != nil {
+			return err
+		}
+	}
+	tags, err := normalizeTags(opts.Tags)
+	if err != nil {
+		return err
+	}
+	if err := validateRotationDays(opts.RotationDays); err != nil {
+		return err
 	}
 
 	// Check for duplicates
@@ -434,16 +581,23 @@ func (v *VaultService) AddCredential(service, username string, password []byte,
 	copy(passwordCopy, password)
 
 	credential := Credential{
-		Service:       service,
-		Username:      username,
-		Password:      passwordCopy, // T020d: Store []byte password
-		Category:      category,
-		URL:           url,
-		Notes:         notes,
-		CreatedAt:     now,
-		UpdatedAt:     now,
-		ModifiedCount: 0, // Initialize modification counter
-		UsageRecord:   make(map[string]UsageRecord),
+		Service:           service,
+		Username:          username,
+		Password:          passwordCopy, // T020d: Store []byte password
+		Type:              opts.Type,
+		Category:          category,
+		Tags:              tags,
+		URL:               url,
+		Notes:             notes,
+		CustomFields:      copyCustomFields(opts.CustomFields),
+		TOTP:              copyTOTP(opts.TOTP),
+		ExpiresAt:         copyTime(&opts.ExpiresAt),
+		RotationDays:      opts.RotationDays,
+		CreatedAt:         now,
+		PasswordChangedAt: now,
+		UpdatedAt:         now,
+		ModifiedCount:     0, // Initialize modification counter
+		UsageRecord:       make(map[string]UsageRecord),
 	}
 
 	// Add to vault
@@ -480,6 +634,12 @@ func (v *VaultService) GetCredential(service string, trackUsage bool) (*Credenti
 
 	// Return a copy to prevent external modification
 	cred := credential
+	cred.CustomFields = copyCustomFields(credential.CustomFields)
+	cred.TOTP = copyTOTP(credential.TOTP)
+	cred.Tags = copyTags(credential.Tags)
+	cred.ExpiresAt = copyTime(credential.ExpiresAt)
+	cred.PasswordHistory = copyPasswordHistory(credential.PasswordHistory)
+	cred.Attachments = copyAttachments(credential.Attachments)
 	return &cred, nil
 }
 
@@ -527,8 +687,8 @@ func (v *VaultService) RecordFieldAccess(service, field string) error {
 	credential.UsageRecord[location] = record
 	v.vaultData.Credentials[service] = credential
 
-	// Save to persist usage tracking
-	return v.save()
+	// Save to persist usage tracking (not a change worth a snapshot)
+	return v.saveWithoutSnapshot()
 }
 
 // getGitRepo attempts to get the git repository for a directory
@@ -570,23 +730,36 @@ func (v *VaultService) ListCredentials() ([]string, error) {
 // Use pointers to distinguish between "don't change" (nil) and "set to empty/value" (non-nil)
 // T020d: Password changed to *[]byte for memory security
 type UpdateOpts struct {
-	Username *string  // nil = don't change, non-nil = set to value (even if empty)
-	Password *[]byte  // T020d: Changed to *[]byte for memory security
+	Username *string // nil = don't change, non-nil = set to value (even if empty)
+	Password *[]byte // T020d: Changed to *[]byte for memory security
 	Category *string
 	URL      *string
 	Notes    *string
+
+	CustomFields *[]CustomField  // nil = don't change, non-nil = replace the whole list
+	TOTP         *TOTPConfig     // nil = don't change, non-nil = replace the TOTP seed
+	ClearTOTP    bool            // Remove the TOTP seed (takes precedence over TOTP)
+	Tags         *[]string       // nil = don't change, non-nil = replace the whole list
+	Type         *CredentialType // nil = don't change
+	ExpiresAt    *time.Time      // nil = don't change, zero time = clear the expiry date
+	RotationDays *int            // nil = don't change, 0 = clear the rotation interval
 }
 
 // CredentialMetadata contains non-sensitive credential information for listing
 type CredentialMetadata struct {
 	Service       string
 	Username      string
+	Type          CredentialType // Effective type (login when unset)
 	Category      string
+	Tags          []string
 	URL           string
 	Notes         string
 	CreatedAt     time.Time
 	UpdatedAt     time.Time
 	ModifiedCount int       // Number of times credential has been modified
+	ExpiresAt     time.Time // Effective expiry (explicit date or rotation due), zero = never
+	RotationDays  int       // Rotation interval in days, zero = none
+	RotationDue   bool      // True when ExpiresAt is the rotation due date rather than an explicit date
 	UsageCount    int       // Total usage count across all locations
 	LastAccessed  time.Time // Most recent access time
 	Locations     []string  // List of locations where accessed
@@ -600,38 +773,47 @@ func (v *VaultService) ListCredentialsWithMetadata() ([]CredentialMetadata, erro
 
 	metadata := make([]CredentialMetadata, 0, len(v.vaultData.Credentials))
 	for _, cred := range v.vaultData.Credentials {
-		meta := CredentialMetadata{
-			Service:       cred.Service,
-			Username:      cred.Username,
-			Category:      cred.Category,
-			URL:           cred.URL,
-			Notes:         cred.Notes,
-			CreatedAt:     cred.CreatedAt,
-			UpdatedAt:     cred.UpdatedAt,
-			ModifiedCount: cred.ModifiedCount,
-		}
-
-		// Calculate usage statistics
-		var totalCount int
-		var lastAccessed time.Time
-		locations := make([]string, 0, len(cred.UsageRecord))
-
-		for loc, record := range cred.UsageRecord {
-			totalCount += record.Count
-			locations = append(locations, loc)
-			if record.Timestamp.After(lastAccessed) {
-				lastAccessed = record.Timestamp
-			}
-		}
+		metadata = append(metadata, credentialMetadata(cred))
+	}
 
-		meta.UsageCount = totalCount
-		meta.LastAccessed = lastAccessed
-		meta.Locations = locations
+	return metadata, nil
+}
 
-		metadata = append(metadata, meta)
+// credentialMetadata builds the listing view of a credential
+func credentialMetadata(cred Credential) CredentialMetadata {
+	meta := CredentialMetadata{
+		Service:       cred.Service,
+		Username:      cred.Username,
+		Type:          cred.EffectiveType(),
+		Category:      cred.Category,
+		Tags:          copyTags(cred.Tags),
+		URL:           cred.URL,
+		Notes:         cred.Notes,
+		CreatedAt:     cred.CreatedAt,
+		UpdatedAt:     cred.UpdatedAt,
+		ModifiedCount: cred.ModifiedCount,
+		ExpiresAt:     cred.EffectiveExpiry(),
+		RotationDays:  cred.RotationDays,
+		RotationDue:   !cred.RotationDue().IsZero() && cred.EffectiveExpiry().Equal(cred.RotationDue()),
+	}
+
+	// Calculate usage statistics
+	var totalCount int
+	var lastAccessed time.Time
+	locations := make([]string, 0, len(cred.UsageRecord))
+
+	for loc, record := range cred.UsageRecord {
+		totalCount += record.Count
+		locations = append(locations, loc)
+		if record.Timestamp.After(lastAccessed) {
+			lastAccessed = record.Timestamp
+		}
 	}
 
-	return metadata, nil
+	meta.UsageCount = totalCount
+	meta.LastAccessed = lastAccessed
+	meta.Locations = locations
+	return meta
 }
 
 // UpdateCredential updates an existing credential using optional fields
@@ -651,9 +833,11 @@ func (v *VaultService) UpdateCredential(service string, opts UpdateOpts) error {
 	if !exists {
 		return fmt.Errorf("%w: %s", ErrCredentialNotFound, service)
 	}
+	original := credential
 
 	// Track if any field was actually updated
 	fieldUpdated := false
+	now := time.Now()
 
 	// Update fields only if pointer is non-nil
 	if opts.Username != nil {
@@ -661,10 +845,24 @@ func (v *VaultService) UpdateCredential(service string, opts UpdateOpts) error {
 		fieldUpdated = true
 	}
 	if opts.Password != nil {
+		// Keep the replaced password in history (unchanged values are not recorded)
+		if !bytes.Equal(credential.Password, *opts.Password) {
+			credential.PasswordHistory = pushPasswordHistory(credential.PasswordHistory,
+				credential.Password, now, v.GetPasswordHistoryLimit())
+		}
+
 		// T020e: Make a copy before storing to avoid clearing stored password
 		passwordCopy := make([]byte, len(*opts.Password))
 		copy(passwordCopy, *opts.Password)
 		credential.Password = passwordCopy
+		credential.PasswordChangedAt = now
+		fieldUpdated = true
+	}
+	if opts.Type != nil {
+		if _, ok := GetTemplate(*opts.Type); !ok {
+			return fmt.Errorf("%w: unknown credential type %q", ErrInvalidCredential, *opts.Type)
+		}
+		credential.Type = *opts.Type
 		fieldUpdated = true
 	}
 	if opts.Category != nil {
@@ -679,13 +877,56 @@ func (v *VaultService) UpdateCredential(service string, opts UpdateOpts) error {
 		credential.Notes = *opts.Notes
 		fieldUpdated = true
 	}
+	if opts.CustomFields != nil {
+		if err := validateCustomFields(*opts.CustomFields); err != nil {
+			return err
+		}
+		credential.CustomFields = copyCustomFields(*opts.CustomFields)
+		fieldUpdated = true
+	}
+	if opts.Tags != nil {
+		tags, err := normalizeTags(*opts.Tags)
+		if err != nil {
+			return err
+		}
+		credential.Tags = tags
+		fieldUpdated = true
+	}
+	if opts.ExpiresAt != nil {
+		credential.ExpiresAt = copyTime(opts.ExpiresAt)
+		fieldUpdated = true
+	}
+	if opts.RotationDays != nil {
+		if err := validateRotationDays(*opts.RotationDays); err != nil {
+			return err
+		}
+		credential.RotationDays = *opts.RotationDays
+		fieldUpdated = true
+	}
+	if opts.ClearTOTP {
+		if credential.TOTP != nil {
+			credential.TOTP = nil
+			fieldUpdated = true
+		}
+	} else if opts.TOTP != nil {
+		if err := opts.TOTP.normalize(); err != nil {
+			return err
+		}
+		credential.TOTP = copyTOTP(opts.TOTP)
+		fieldUpdated = true
+	}
+
+	// The result must still meet the requirements of its (possibly new) type
+	if err := validateTemplateChange(original, credential); err != nil {
+		return err
+	}
 
 	// Only increment counter if something was actually modified
 	if fieldUpdated {
 		credential.ModifiedCount++
 	}
 
-	credential.UpdatedAt = time.Now()
+	credential.UpdatedAt = now
 	v.vaultData.Credentials[service] = credential
 
 	if err := v.save(); err != nil {
@@ -723,16 +964,22 @@ func (v *VaultService) UpdateCredentialFields(service, username, password, categ
 	return v.UpdateCredential(service, opts)
 }
 
-// DeleteCredential removes a credential from the vault
+// DeleteCredential moves a credential to the trash
+// Use RestoreCredential to bring it back or PurgeTrash to remove it for good
 func (v *VaultService) DeleteCredential(service string) error {
 	if !v.unlocked {
 		return ErrVaultLocked
 	}
 
-	if _, exists := v.vaultData.Credentials[service]; !exists {
+	credential, exists := v.vaultData.Credentials[service]
+	if !exists {
 		return fmt.Errorf("%w: %s", ErrCredentialNotFound, service)
 	}
 
+	v.vaultData.Trash = append(v.vaultData.Trash, TrashedCredential{
+		Credential: credential,
+		DeletedAt:  time.Now(),
+	})
 	delete(v.vaultData.Credentials, service)
 
 	if err := v.save(); err != nil {
@@ -767,20 +1014,50 @@ func (v *VaultService) GetUsageStats(service string) (map[string]UsageRecord, er
 // ChangePassword changes the vault master password
 // T012: Updated signature to accept []byte, T016: Added deferred cleanup
 // T046: Added password policy validation (FR-016)
+// PBKDF2 vaults are upgraded to Argon2id with the default parameters; Argon2id
+// vaults keep their parameters.
 func (v *VaultService) ChangePassword(newPassword []byte) error {
+	return v.ChangePasswordWithKDF(newPassword, nil)
+}
+
+// ChangePasswordWithKDF changes the master password and, when params is not
+// nil, re-derives the key with those parameters
+func (v *VaultService) ChangePasswordWithKDF(newPassword []byte, params *crypto.KDFParams) error {
+	return v.ChangePasswordWithOpts(newPassword, ChangePasswordOpts{KDF: params})
+}
+
+// ChangePasswordOpts contains optional settings for a password change
+// Zero values keep the current key derivation, key file requirement and data key
+type ChangePasswordOpts struct {
+	KDF           *crypto.KDFParams // Re-derive the key with these parameters
+	KeyFile       string            // Require this key file with the new password
+	RemoveKeyFile bool              // Stop requiring a key file
+	Rekey         bool              // Replace the data key afterwards (implied by KeyFile)
+	Secrets       SlotSecrets       // Secrets of the other slots a rekey keeps
+}
+
+// ChangePasswordWithOpts changes the master password, optionally adding or
+// removing the key file requirement. A password slot that requires a key file
+// keeps requiring the one set by SetKeyFile. Requiring a key file always rekeys
+// the vault (see Rekey).
+func (v *VaultService) ChangePasswordWithOpts(newPassword []byte, opts ChangePasswordOpts) error {
 	defer crypto.ClearBytes(newPassword) // T016: Ensure cleanup even on error
 
+	if opts.KeyFile != "" && opts.RemoveKeyFile {
+		return errors.New("cannot both require and remove a key file")
+	}
+
 	if !v.unlocked {
 		return ErrVaultLocked
 	}
 
 	// T046 [US3]: Validate new password against policy (FR-016)
 	passwordPolicy := &security.PasswordPolicy{
-		MinLength:         12,
-		RequireUppercase:  true,
-		RequireLowercase:  true,
-		RequireDigit:      true,
-		RequireSymbol:     true,
+		MinLength:        12,
+		RequireUppercase: true,
+		RequireLowercase: true,
+		RequireDigit:     true,
+		RequireSymbol:    true,
 	}
 	if err := passwordPolicy.Validate(newPassword); err != nil {
 		// T051a: Record failure and check rate limit
@@ -793,46 +1070,50 @@ func (v *VaultService) ChangePassword(newPassword []byte) error {
 	// T051a: Reset rate limiter on successful validation
 	v.rateLimiter.Reset()
 
-	// T033/T034: Check if iteration count needs upgrading
-	// Use configurable iterations from env var if set (T034)
-	targetIterations := crypto.GetIterations()
-	currentIterations := v.storageService.GetIterations()
-	
-	needsMigration := currentIterations < targetIterations
-	if needsMigration {
-		// Migration opportunity: upgrade to stronger KDF
-		fmt.Fprintf(os.Stderr, "Upgrading PBKDF2 iterations from %d to %d for improved security...\n",
-			currentIterations, targetIterations)
+	slots, err := v.storageService.ListKeySlots()
+	if err != nil {
+		return err
 	}
+	slot := v.passwordSlot(slots)
 
-	// Clear old password
-	crypto.ClearBytes(v.masterPassword)
-
-	// Update master password (make a copy since we're clearing the parameter)
-	v.masterPassword = make([]byte, len(newPassword))
-	copy(v.masterPassword, newPassword)
+	keyFile := ""
+	if slot != nil && slot.Type == storage.KeySlotPasswordKeyFile {
+		keyFile = v.keyFile
+	}
+	if opts.KeyFile != "" {
+		keyFile = opts.KeyFile
+	}
+	if opts.RemoveKeyFile {
+		keyFile = ""
+	} else if keyFile == "" && slot != nil && slot.Type == storage.KeySlotPasswordKeyFile {
+		return ErrKeyFileRequired
+	}
 
-	// Marshal vault data
-	data, err := json.Marshal(v.vaultData)
+	slotType, secret, err := v.passwordSlotSecret(newPassword, keyFile)
 	if err != nil {
-		return fmt.Errorf("failed to marshal vault data: %w", err)
+		return err
 	}
+	defer crypto.ClearBytes(secret)
 
-	// Re-save vault with new password and potentially upgraded iterations
-	newPasswordStr := string(newPassword)
-	if needsMigration {
-		if err := v.storageService.SaveVaultWithIterations(data, newPasswordStr, targetIterations); err != nil {
-			return fmt.Errorf("failed to save vault with new password: %w", err)
-		}
-	} else {
-		if err := v.storageService.SaveVault(data, newPasswordStr); err != nil {
-			return fmt.Errorf("failed to save vault with new password: %w", err)
-		}
+	// Only the password slot is rewritten; the data key changes below if requested
+	slotID, err := v.setPasswordSlot(slots, slot, slotType, secret, opts.KDF)
+	if err != nil {
+		return fmt.Errorf("failed to save vault with new password: %w", err)
 	}
+	v.unlockedSlot = slotID
+	v.keyFile = keyFile
+	crypto.ClearBytes(v.slotSecret)
+	v.slotSecret = make([]byte, len(secret))
+	copy(v.slotSecret, secret)
+
+	// Clear old password and keep a copy of the new one (the parameter is cleared)
+	crypto.ClearBytes(v.masterPassword)
+	v.masterPassword = make([]byte, len(newPassword))
+	copy(v.masterPassword, newPassword)
 
 	// Update keychain if available
 	if v.keychainService.IsAvailable() {
-		if err := v.keychainService.Store(newPasswordStr); err != nil {
+		if err := v.keychainService.Store(string(newPassword)); err != nil {
 			fmt.Fprintf(os.Stderr, "Warning: failed to update password in keychain: %v\n", err)
 		}
 	}
@@ -840,5 +1121,138 @@ func (v *VaultService) ChangePassword(newPassword []byte) error {
 	// T070: Log password change (FR-019)
 	v.logAudit(security.EventVaultPasswordChange, security.OutcomeSuccess, "")
 
+	// Snapshots would otherwise still open with the password alone
+	if opts.Rekey || opts.KeyFile != "" {
+		if err := v.rekey(v.keptSlots(opts.Secrets)); err != nil {
+			return fmt.Errorf("password changed, but %w", err)
+		}
+	}
 	return nil
 }
+
+// PasswordSlotID returns the ID of the slot ChangePassword rewrites, or 0 when
+// it adds a new one
+func (v *VaultService) PasswordSlotID() (int, error) {
+	slots, err := v.storageService.ListKeySlots()
+	if err != nil {
+		return 0, err
+	}
+	if slot := v.passwordSlot(slots); slot != nil {
+		return slot.ID, nil
+	}
+	return 0, nil
+}
+
+// passwordSlot returns the password slot a password change rewrites: the one
+// that unlocked the vault, else the first one, or nil if there is none
+func (v *VaultService) passwordSlot(slots []storage.KeySlot) *storage.KeySlot {
+	var slot *storage.KeySlot
+	for i := range slots {
+		if slots[i].Type != storage.KeySlotPassword && slots[i].Type != storage.KeySlotPasswordKeyFile {
+			continue
+		}
+		if slot == nil || slots[i].ID == v.unlockedSlot {
+			slot = &slots[i]
+		}
+	}
+	return slot
+}
+
+// setPasswordSlot wraps the data key with a new password secret in slot (from
+// passwordSlot) as slotType and returns the slot ID. Without a slot a new one
+// is added; version 1 vaults (no slots) are converted to key slots.
+// When params is nil, Argon2id slots keep their parameters and PBKDF2 slots
+// are upgraded to the defaults.
+func (v *VaultService) setPasswordSlot(slots []storage.KeySlot, slot *storage.KeySlot, slotType string, secret []byte, params *crypto.KDFParams) (int, error) {
+	var current crypto.KDFParams
+	var err error
+	switch {
+	case slot != nil:
+		current = slot.KDFParams()
+	case len(slots) == 0:
+		if current, err = v.storageService.GetKDFParams(); err != nil {
+			return 0, err
+		}
+	default:
+		current = crypto.DefaultKDFParams() // Only recovery or key file slots
+	}
+
+	// T033: Check if the key derivation needs upgrading
+	target := current
+	if params != nil {
+		target = *params
+	} else if current.Algorithm != crypto.KDFArgon2id {
+		target = crypto.DefaultKDFParams()
+		fmt.Fprintf(os.Stderr, "Upgrading key derivation from %s to %s for improved security...\n", current, target)
+	}
+	if err := target.Validate(); err != nil {
+		return 0, err
+	}
+
+	switch {
+	case len(slots) == 0:
+		data, err := json.Marshal(v.vaultData)
+		if err != nil {
+			return 0, fmt.Errorf("failed to marshal vault data: %w", err)
+		}
+		dataKey, err := v.storageService.ConvertToKeySlots(data, slotType, secret, target)
+		if err != nil {
+			return 0, err
+		}
+		crypto.ClearBytes(v.dataKey)
+		v.dataKey = dataKey
+		return 1, nil
+	case slot == nil:
+		return v.storageService.AddKeySlot(v.dataKey, slotType, "", secret, target)
+	default:
+		return slot.ID, v.storageService.UpdateKeySlot(v.dataKey, slot.ID, slotType, secret, target)
+	}
+}
+
+// UpgradeKDF re-derives the key of the slot that unlocked the vault with new
+// parameters, keeping its secret, e.g. to move a PBKDF2 vault to Argon2id.
+// Version 1 vaults are converted to key slots.
+func (v *VaultService) UpgradeKDF(params crypto.KDFParams) error {
+	if !v.unlocked {
+		return ErrVaultLocked
+	}
+	if err := params.Validate(); err != nil {
+		return err
+	}
+
+	if v.unlockedSlot == 0 {
+		data, err := json.Marshal(v.vaultData)
+		if err != nil {
+			return fmt.Errorf("failed to marshal vault data: %w", err)
+		}
+		dataKey, err := v.storageService.ConvertToKeySlots(data, storage.KeySlotPassword, v.slotSecret, params)
+		if err != nil {
+			return fmt.Errorf("failed to save vault with new key derivation: %w", err)
+		}
+		crypto.ClearBytes(v.dataKey)
+		v.dataKey = dataKey
+		v.unlockedSlot = 1
+	} else {
+		slot, err := v.keySlot(v.unlockedSlot)
+		if err != nil {
+			return err
+		}
+		if err := v.storageService.UpdateKeySlot(v.dataKey, slot.ID, slot.Type, v.slotSecret, params); err != nil {
+			return fmt.Errorf("failed to save vault with new key derivation: %w", err)
+		}
+	}
+
+	v.logAudit(security.EventVaultKDFUpgrade, security.OutcomeSuccess, "")
+	return nil
+}
+
+// KDFParams returns the key derivation parameters of the slot that unlocked
+// the vault or, when locked, of the first password slot
+func (v *VaultService) KDFParams() (crypto.KDFParams, error) {
+	if v.unlocked && v.unlockedSlot != 0 {
+		if slot, err := v.keySlot(v.unlockedSlot); err == nil {
+			return slot.KDFParams(), nil
+		}
+	}
+	return v.storageService.GetKDFParams()
+}
@@ -3,12 +3,14 @@ package vault
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
+	"pass-cli/internal/crypto"
 	"pass-cli/internal/storage"
 )
 
@@ -870,10 +872,10 @@ func TestBackwardCompatibility(t *testing.T) {
 	}
 }
 
-// T023 [US2]: Test automatic migration from 100k to 600k iterations on password change
-// FR-010: System MUST automatically upgrade legacy vaults to 600k iterations
+// T023 [US2]: Test automatic migration from PBKDF2 to Argon2id on password change
+// FR-010: System MUST automatically upgrade legacy vaults
 func TestIterationsMigrationOnPasswordChange(t *testing.T) {
-	// T023/T036: Test iteration count migration from 100k to 600k during password change
+	// T023/T036: Test migration from 100k PBKDF2 iterations to Argon2id during password change
 	vault, storageService, cleanup := setupTestVaultWithStorage(t)
 	defer cleanup()
 
@@ -919,16 +921,18 @@ func TestIterationsMigrationOnPasswordChange(t *testing.T) {
 		t.Fatalf("Expected initial iterations %d, got %d", legacyIterations, currentIterations)
 	}
 
-	// Change password - should trigger migration to 600k iterations (T033)
+	// Change password - should trigger migration to Argon2id (T033)
 	if err := vault.ChangePassword([]byte(newPassword)); err != nil {
 		t.Fatalf("ChangePassword() failed: %v", err)
 	}
 
-	// Verify iterations were upgraded to 600k
-	newIterations := storageService.GetIterations()
-	expectedIterations := 600000
-	if newIterations != expectedIterations {
-		t.Errorf("Expected iterations upgraded to %d, got %d", expectedIterations, newIterations)
+	// Verify key derivation was upgraded to the Argon2id defaults
+	params, err := storageService.GetKDFParams()
+	if err != nil {
+		t.Fatalf("GetKDFParams() failed: %v", err)
+	}
+	if params != crypto.DefaultKDFParams() {
+		t.Errorf("Expected key derivation upgraded to %s, got %s", crypto.DefaultKDFParams(), params)
 	}
 
 	// Lock and unlock with new password to verify migration worked
@@ -953,7 +957,104 @@ func TestIterationsMigrationOnPasswordChange(t *testing.T) {
 		t.Errorf("Notes = %s, want 'test migration'", cred.Notes)
 	}
 
-	t.Logf("Migration from %dk to 600k iterations successful", legacyIterations/1000)
+	t.Logf("Migration from %dk PBKDF2 iterations to Argon2id successful", legacyIterations/1000)
+}
+
+// TestUpgradeKDF tests moving a PBKDF2 vault to Argon2id without changing the password
+func TestUpgradeKDF(t *testing.T) {
+	vault, storageService, cleanup := setupTestVaultWithStorage(t)
+	defer cleanup()
+
+	password := "TestPassword123!"
+	if err := vault.Initialize([]byte(password), false, "", ""); err != nil {
+		t.Fatalf("Initialize() failed: %v", err)
+	}
+	if err := vault.Unlock([]byte(password)); err != nil {
+		t.Fatalf("Unlock() failed: %v", err)
+	}
+	if err := vault.AddCredential("test", "user", []byte("pass"), "", "", ""); err != nil {
+		t.Fatalf("AddCredential() failed: %v", err)
+	}
+
+	// Simulate a PBKDF2 vault
+	data, err := json.Marshal(vault.vaultData)
+	if err != nil {
+		t.Fatalf("Failed to marshal vault data: %v", err)
+	}
+	if err := storageService.SaveVaultWithIterationsUnsafe(data, password, crypto.MinIterations); err != nil {
+		t.Fatalf("Failed to save PBKDF2 vault: %v", err)
+	}
+	if params, _ := vault.KDFParams(); params.Algorithm != crypto.KDFPBKDF2 {
+		t.Fatalf("Expected PBKDF2 vault, got %s", params)
+	}
+
+	vault.Lock()
+	if err := vault.UpgradeKDF(crypto.DefaultKDFParams()); err != ErrVaultLocked {
+		t.Errorf("UpgradeKDF() on locked vault = %v, want ErrVaultLocked", err)
+	}
+	if err := vault.Unlock([]byte(password)); err != nil {
+		t.Fatalf("Unlock() failed: %v", err)
+	}
+
+	weak := crypto.KDFParams{Algorithm: crypto.KDFArgon2id, Memory: 1024, Time: 1, Threads: 1}
+	if err := vault.UpgradeKDF(weak); !errors.Is(err, crypto.ErrInvalidKDFParams) {
+		t.Errorf("UpgradeKDF() with weak parameters = %v, want ErrInvalidKDFParams", err)
+	}
+
+	if err := vault.UpgradeKDF(crypto.DefaultKDFParams()); err != nil {
+		t.Fatalf("UpgradeKDF() failed: %v", err)
+	}
+	if params, _ := vault.KDFParams(); params != crypto.DefaultKDFParams() {
+		t.Errorf("Expected %s after upgrade, got %s", crypto.DefaultKDFParams(), params)
+	}
+
+	// Same password still unlocks and data survives
+	vault.Lock()
+	if err := vault.Unlock([]byte(password)); err != nil {
+		t.Fatalf("Unlock() after upgrade failed: %v", err)
+	}
+	cred, err := vault.GetCredential("test", false)
+	if err != nil {
+		t.Fatalf("GetCredential() failed after upgrade: %v", err)
+	}
+	if string(cred.Password) != "pass" {
+		t.Errorf("Password = %s, want pass", string(cred.Password))
+	}
+}
+
+// TestChangePasswordWithKDF tests that calibrated Argon2id parameters are kept by later password changes
+func TestChangePasswordWithKDF(t *testing.T) {
+	vault, _, cleanup := setupTestVaultWithStorage(t)
+	defer cleanup()
+
+	password := "TestPassword123!"
+	if err := vault.Initialize([]byte(password), false, "", ""); err != nil {
+		t.Fatalf("Initialize() failed: %v", err)
+	}
+	if err := vault.Unlock([]byte(password)); err != nil {
+		t.Fatalf("Unlock() failed: %v", err)
+	}
+
+	calibrated := crypto.KDFParams{Algorithm: crypto.KDFArgon2id, Memory: 32 * 1024, Time: 4, Threads: 2}
+	if err := vault.ChangePasswordWithKDF([]byte("NewPassword789!"), &calibrated); err != nil {
+		t.Fatalf("ChangePasswordWithKDF() failed: %v", err)
+	}
+	if params, _ := vault.KDFParams(); params != calibrated {
+		t.Errorf("Expected %s, got %s", calibrated, params)
+	}
+
+	// A plain password change keeps the Argon2id parameters
+	if err := vault.ChangePassword([]byte("OtherPassword456!")); err != nil {
+		t.Fatalf("ChangePassword() failed: %v", err)
+	}
+	if params, _ := vault.KDFParams(); params != calibrated {
+		t.Errorf("Expected %s kept, got %s", calibrated, params)
+	}
+
+	vault.Lock()
+	if err := vault.Unlock([]byte("OtherPassword456!")); err != nil {
+		t.Fatalf("Unlock() with new password failed: %v", err)
+	}
 }
 
 // T036h [US2]: Test migration safety with simulated power loss
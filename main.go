@@ -6,12 +6,15 @@ import (
 
 	"pass-cli/cmd"
 	"pass-cli/cmd/tui"
+	"pass-cli/internal/config"
 )
 
 func main() {
 	// Default to TUI if no subcommand provided
 	shouldUseTUI := true
 	vaultPath := ""
+	profile := ""
+	keyFile := ""
 
 	// Parse args to detect subcommands or flags
 	for i := 1; i < len(os.Args); i++ {
@@ -34,11 +37,32 @@ func main() {
 			vaultPath = os.Args[i+1]
 			i++ // Skip next arg (vault path value)
 		}
+
+		// Extract named vault if provided
+		if arg == "--profile" && i+1 < len(os.Args) {
+			profile = os.Args[i+1]
+			i++ // Skip next arg (profile name)
+		}
+
+		// Extract key file if provided
+		if arg == "--key-file" && i+1 < len(os.Args) {
+			keyFile = os.Args[i+1]
+			i++ // Skip next arg (key file path)
+		}
 	}
 
 	// Route to TUI or CLI
 	if shouldUseTUI {
-		if err := tui.Run(vaultPath); err != nil {
+		if profile != "" && vaultPath == "" {
+			path, err := resolveProfile(profile)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			vaultPath = path
+		}
+
+		if err := tui.Run(vaultPath, keyFile); err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
 		}
@@ -46,3 +70,12 @@ func main() {
 		cmd.Execute()
 	}
 }
+
+// resolveProfile looks up a named vault in the vault registry
+func resolveProfile(name string) (string, error) {
+	vaults, err := config.LoadVaults()
+	if err != nil {
+		return "", fmt.Errorf("failed to load vault registry: %w", err)
+	}
+	return vaults.Resolve(name)
+}
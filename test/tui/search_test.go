@@ -320,3 +320,64 @@ func TestSearchState_NewCredentialAppearsInResults(t *testing.T) {
 		t.Error("Expected newly added credential to match active search query")
 	}
 }
+
+// TestMatchesCredential_QueryLanguage verifies field selectors and boolean operators in the search box
+func TestMatchesCredential_QueryLanguage(t *testing.T) {
+	cred := CreateTestCredentialMetadata("Postgres", "alice", "db", "https://pg.internal/admin")
+	cred.UsageCount = 3
+
+	tests := []struct {
+		query string
+		want  bool
+	}{
+		{"user:alice", true},
+		{"user:bob", false},
+		{"cat:db url:*.internal", true},
+		{"user:bob or cat:db", true},
+		{"not cat:db", false},
+		{"postgres uses>2", true},
+		{"uses:0", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			ss := &models.SearchState{Active: true, Query: tt.query}
+			if got := ss.MatchesCredential(cred); got != tt.want {
+				t.Errorf("MatchesCredential() = %v, want %v (query=%q)", got, tt.want, tt.query)
+			}
+			if err := ss.QueryError(); err != nil {
+				t.Errorf("QueryError() = %v, want nil", err)
+			}
+		})
+	}
+}
+
+// TestSearchState_QueryError verifies invalid queries report an error and fall back to substring matching
+func TestSearchState_QueryError(t *testing.T) {
+	cred := CreateTestCredentialMetadata("GitHub", "user", "work", "https://github.com")
+	ss := &models.SearchState{Active: true, Query: "git("}
+
+	if ss.QueryError() == nil {
+		t.Fatal("Expected QueryError() for unbalanced parenthesis")
+	}
+	if ss.MatchesCredential(cred) {
+		t.Error("Expected substring fallback not to match \"git(\"")
+	}
+
+	ss.Query = "git"
+	if err := ss.QueryError(); err != nil {
+		t.Errorf("Expected no error after fixing the query, got %v", err)
+	}
+	if !ss.MatchesCredential(cred) {
+		t.Error("Expected fixed query to match")
+	}
+
+	ss.Query = "user:"
+	if ss.QueryError() == nil {
+		t.Error("Expected QueryError() for a selector without a value")
+	}
+	ss.Deactivate()
+	if err := ss.QueryError(); err != nil {
+		t.Errorf("Expected no error after Deactivate(), got %v", err)
+	}
+}